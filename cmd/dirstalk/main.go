@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stefanoj3/dirstalk/pkg/cmd"
@@ -12,9 +14,12 @@ func main() {
 
 	dirStalkCmd := createCommand(logger)
 
-	if err := dirStalkCmd.Execute(); err != nil {
-		logger.WithField("err", err).Fatal("Execution error")
+	err := dirStalkCmd.Execute()
+	if err != nil && cmd.ExitCode(err) == cmd.ExitCodeError {
+		logger.WithField("err", err).Error("Execution error")
 	}
+
+	os.Exit(cmd.ExitCode(err))
 }
 
 func createCommand(logger *logrus.Logger) *cobra.Command {
@@ -23,8 +28,16 @@ func createCommand(logger *logrus.Logger) *cobra.Command {
 	dirStalkCmd.AddCommand(cmd.NewScanCommand(logger))
 	dirStalkCmd.AddCommand(cmd.NewResultViewCommand(logger.Out))
 	dirStalkCmd.AddCommand(cmd.NewResultDiffCommand(logger.Out))
-	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewScanDiffCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryCommand(logger))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryFromRobotsCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryFromSitemapCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryFromJavascriptCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewMergeDictionariesCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewLintDictionaryCommand(logger.Out))
 	dirStalkCmd.AddCommand(cmd.NewVersionCommand(logger.Out))
+	// the script is meant to be sourced or redirected to a file, while the logs go to the standard error
+	dirStalkCmd.AddCommand(cmd.NewCompletionCommand(os.Stdout))
 
 	return dirStalkCmd
 }
@@ -3,6 +3,7 @@ module github.com/stefanoj3/dirstalk
 go 1.13
 
 require (
+	github.com/Azure/go-ntlmssp v0.0.1
 	github.com/DiSiqueira/GoTree v0.0.0-20180907134536-53a8e837f295
 	github.com/armon/go-socks5 v0.0.0-20160902184237-e75332964ef5
 	github.com/konsorten/go-windows-terminal-sequences v1.0.2 // indirect
@@ -10,7 +11,11 @@ require (
 	github.com/sergi/go-diff v1.0.0
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
+	github.com/spf13/pflag v1.0.3
 	github.com/stretchr/testify v1.3.0
+	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
 	golang.org/x/net v0.0.0-20190628185345-da137c7871d7
-	golang.org/x/sys v0.0.0-20190626221950-04f50cda93cb // indirect
+	golang.org/x/sys v0.0.0-20190626221950-04f50cda93cb
+	golang.org/x/text v0.3.0
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 )
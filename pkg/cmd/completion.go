@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	completionShellBash       = "bash"
+	completionShellZsh        = "zsh"
+	completionShellFish       = "fish"
+	completionShellPowerShell = "powershell"
+)
+
+func NewCompletionCommand(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "completion [bash|zsh|fish|powershell]",
+		Short:     "Generate the shell completion script",
+		Long:      "Generate the completion script for the given shell, it covers all the commands and their flags",
+		Example:   "source <(dirstalk completion bash)",
+		ValidArgs: []string{completionShellBash, completionShellZsh, completionShellFish, completionShellPowerShell},
+		Args:      cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// the completion is generated from the whole command tree, not just from this command
+			root := cmd.Root()
+
+			var err error
+
+			switch args[0] {
+			case completionShellBash:
+				err = root.GenBashCompletion(out)
+			case completionShellZsh:
+				err = root.GenZshCompletion(out)
+			case completionShellFish:
+				err = genFishCompletion(root, out)
+			case completionShellPowerShell:
+				err = root.GenPowerShellCompletion(out)
+			}
+
+			return errors.Wrapf(err, "failed to generate the %s completion", args[0])
+		},
+	}
+
+	return cmd
+}
+
+// genFishCompletion writes the fish completion of the root command and of its direct subcommands, as the version
+// of cobra in use does not support fish
+func genFishCompletion(root *cobra.Command, w io.Writer) error {
+	lines := []string{"# fish completion for " + root.Name()}
+
+	root.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden {
+			lines = append(lines, fishFlagCompletion(root.Name(), "", f))
+		}
+	})
+
+	for _, c := range root.Commands() {
+		if !c.IsAvailableCommand() {
+			continue
+		}
+
+		lines = append(
+			lines,
+			fmt.Sprintf(
+				"complete -c %s -n '__fish_use_subcommand' -f -a %s -d '%s'",
+				root.Name(),
+				c.Name(),
+				escapeForFish(c.Short),
+			),
+		)
+
+		c.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				lines = append(lines, fishFlagCompletion(root.Name(), c.Name(), f))
+			}
+		})
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+
+	return err
+}
+
+// fishFlagCompletion returns the completion of a flag, available only after the given subcommand when not empty
+func fishFlagCompletion(rootName, subcommand string, f *pflag.Flag) string {
+	line := "complete -c " + rootName
+
+	if subcommand != "" {
+		line += fmt.Sprintf(" -n '__fish_seen_subcommand_from %s'", subcommand)
+	}
+
+	line += " -l " + f.Name
+
+	if f.Shorthand != "" {
+		line += " -s " + f.Shorthand
+	}
+
+	// the flags with a value require it, the others (EG --quiet) don't
+	if f.NoOptDefVal == "" {
+		line += " -r"
+	}
+
+	return line + fmt.Sprintf(" -d '%s'", escapeForFish(f.Usage))
+}
+
+func escapeForFish(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
@@ -1,14 +1,27 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
 	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client/cookie"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stefanoj3/dirstalk/pkg/scan/scope"
+	"github.com/stefanoj3/dirstalk/pkg/scan/template"
+	"github.com/stefanoj3/dirstalk/pkg/scan/waf"
 )
 
 const failedToReadPropertyError = "failed to read %s"
@@ -16,89 +29,1365 @@ const failedToReadPropertyError = "failed to read %s"
 func scanConfigFromCmd(cmd *cobra.Command) (*scan.Config, error) {
 	c := &scan.Config{}
 
+	for _, configFromCmd := range []func(*cobra.Command, *scan.Config) error{
+		dictionaryConfigFromCmd,
+		wordsOrderConfigFromCmd,
+		pathsConfigFromCmd,
+		filtersConfigFromCmd,
+		concurrencyConfigFromCmd,
+		pacingConfigFromCmd,
+		recursionConfigFromCmd,
+		scopeConfigFromCmd,
+		proxyConfigFromCmd,
+		requestConfigFromCmd,
+		authConfigFromCmd,
+		outputConfigFromCmd,
+		detectionConfigFromCmd,
+		displayConfigFromCmd,
+		limitsConfigFromCmd,
+		runConfigFromCmd,
+		transportConfigFromCmd,
+	} {
+		if err := configFromCmd(cmd, c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.VHostScan {
+		if err := validateVHostScanConfig(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.RequestTemplate != nil {
+		if err := validateRequestTemplateConfig(cmd, c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.WatchInterval > 0 {
+		if err := validateWatchConfig(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// dictionaryConfigFromCmd sets where the words of the scan are read from
+func dictionaryConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	c.DictionaryPath = cmd.Flag(flagScanDictionary).Value.String()
+
+	// the virtual hosts are scanned using the dictionary of hosts in place of the one of paths
+	if vhostDictionaryPath := cmd.Flag(flagScanVHostDictionary).Value.String(); vhostDictionaryPath != "" {
+		if c.DictionaryPath != "" {
+			return errors.Errorf("%s and %s cannot be used at the same time", flagScanDictionary, flagScanVHostDictionary)
+		}
+
+		c.DictionaryPath = vhostDictionaryPath
+		c.VHostScan = true
+	}
+
+	if c.DictionaryPath == "" {
+		return errors.Errorf("either %s or %s is required", flagScanDictionary, flagScanVHostDictionary)
+	}
+
+	if c.RequestTemplate, err = requestTemplateFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if c.SecondDictionaryPath, err = cmd.Flags().GetString(flagScanSecondDictionary); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanSecondDictionary)
+	}
+
+	if c.SecondDictionaryPath != "" && c.RequestTemplate == nil {
+		return errors.Errorf("%s requires %s", flagScanSecondDictionary, flagScanRequestTemplate)
+	}
+
+	if c.SecondDictionaryPath == dictionary.StdinPath && c.DictionaryPath == dictionary.StdinPath {
+		return errors.Errorf(
+			"%s and %s cannot be both read from the standard input",
+			flagScanDictionary,
+			flagScanSecondDictionary,
+		)
+	}
+
+	if c.DictionaryTimeoutInMilliseconds, err = cmd.Flags().GetInt(flagScanDictionaryGetTimeout); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDictionaryGetTimeout)
+	}
+
+	return nil
+}
+
+// wordsOrderConfigFromCmd sets the order the words are used in
+func wordsOrderConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.TemplateMode, err = cmd.Flags().GetString(flagScanTemplateMode); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTemplateMode)
+	}
+
+	if !producer.IsValidTemplateMode(c.TemplateMode) {
+		return errors.Errorf(
+			"invalid value for %s: %s, the available ones are: %s",
+			flagScanTemplateMode,
+			c.TemplateMode,
+			strings.Join(producer.TemplateModes, ", "),
+		)
+	}
+
+	if cmd.Flags().Changed(flagScanTemplateMode) && c.SecondDictionaryPath == "" {
+		return errors.Errorf("%s requires %s", flagScanTemplateMode, flagScanSecondDictionary)
+	}
+
+	if c.Shuffle, err = cmd.Flags().GetBool(flagScanShuffle); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanShuffle)
+	}
+
+	if c.Seed, err = cmd.Flags().GetInt64(flagScanSeed); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanSeed)
+	}
+
+	if cmd.Flags().Changed(flagScanSeed) && !c.Shuffle {
+		return errors.Errorf("%s requires %s", flagScanSeed, flagScanShuffle)
+	}
+
+	// the words of the two dictionaries are paired by their position
+	if c.Shuffle && c.SecondDictionaryPath != "" && c.TemplateMode == producer.TemplateModePitchfork {
+		return errors.Errorf(
+			"%s cannot be used with %s %s",
+			flagScanShuffle,
+			flagScanTemplateMode,
+			producer.TemplateModePitchfork,
+		)
+	}
+
+	return nil
+}
+
+// pathsConfigFromCmd sets how the requests are generated from the words
+func pathsConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.Extensions, err = cmd.Flags().GetStringArray(flagScanExtension); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanExtension)
+	}
+
+	if c.Extensions, err = normalizeExtensions(c.Extensions); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanExtension)
+	}
+
+	if c.AppendQueries, err = cmd.Flags().GetStringArray(flagScanAppendQuery); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanAppendQuery)
+	}
+
+	if c.AppendQueries, err = normalizeQueries(c.AppendQueries); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanAppendQuery)
+	}
+
+	if c.TrailingSlash, err = cmd.Flags().GetString(flagScanTrailingSlash); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTrailingSlash)
+	}
+
+	if !producer.IsValidTrailingSlashMode(c.TrailingSlash) {
+		return errors.Errorf(
+			"invalid value for %s: %s, the available ones are: %s",
+			flagScanTrailingSlash,
+			c.TrailingSlash,
+			strings.Join(producer.TrailingSlashModes, ", "),
+		)
+	}
+
+	if c.HTTPMethods, err = cmd.Flags().GetStringSlice(flagScanHTTPMethods); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPMethods)
+	}
+
+	if c.HTTPMethods, err = normalizeHTTPMethods(c.HTTPMethods); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanHTTPMethods)
+	}
+
+	if c.HeadFirst, err = cmd.Flags().GetBool(flagScanHeadFirst); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHeadFirst)
+	}
+
+	return nil
+}
+
+// filtersConfigFromCmd sets the filters deciding which results are reported
+func filtersConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.HTTPStatusesToIgnore, err = cmd.Flags().GetIntSlice(flagScanHTTPStatusesToIgnore); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPStatusesToIgnore)
+	}
+
+	if c.HTTPStatusesToInclude, err = httpStatusesFromCmd(cmd, flagScanIncludeStatus); err != nil {
+		return err
+	}
+
+	if c.HTTPStatusesToExclude, err = httpStatusesFromCmd(cmd, flagScanExcludeStatus); err != nil {
+		return err
+	}
+
+	if len(c.HTTPStatusesToInclude) > 0 && len(c.HTTPStatusesToExclude) > 0 {
+		return errors.Errorf(
+			"%s and %s cannot be used at the same time",
+			flagScanIncludeStatus,
+			flagScanExcludeStatus,
+		)
+	}
+
+	if c.ContentLengthsToIgnore, err = contentLengthsFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if c.ContentLengthRangesToIgnore, err = contentLengthRangesFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if matchRegex := cmd.Flag(flagScanMatchRegex).Value.String(); matchRegex != "" {
+		if c.MatchRegex, err = regexp.Compile(matchRegex); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanMatchRegex)
+		}
+	}
+
+	if c.MaxBodySize, err = cmd.Flags().GetInt64(flagScanMaxBodySize); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxBodySize)
+	}
+
+	if c.MaxBodySize < 1 {
+		return errors.Errorf("invalid value for %s: it must be at least 1", flagScanMaxBodySize)
+	}
+
+	if c.HeaderMatchers, err = headerMatchersFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if c.ContentTypesToMatch, err = contentTypesFromCmd(cmd, flagScanMatchContentType); err != nil {
+		return err
+	}
+
+	if c.ContentTypesToExclude, err = contentTypesFromCmd(cmd, flagScanExcludeContentType); err != nil {
+		return err
+	}
+
+	if c.FilterTimeAbove, err = cmd.Flags().GetDuration(flagScanFilterTimeAbove); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanFilterTimeAbove)
+	}
+
+	if c.FilterTimeAbove < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanFilterTimeAbove)
+	}
+
+	if c.DedupResults, err = cmd.Flags().GetBool(flagScanDedupResults); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDedupResults)
+	}
+
+	return nil
+}
+
+// concurrencyConfigFromCmd sets how many requests and connections are used at the same time
+func concurrencyConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.Threads, err = cmd.Flags().GetInt(flagScanThreads); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanThreads)
+	}
+
+	if c.Threads < 1 {
+		return errors.Errorf("invalid value for %s: at least 1 thread is required", flagScanThreads)
+	}
+
+	if c.PerHostThreads, err = cmd.Flags().GetInt(flagScanPerHostThreads); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanPerHostThreads)
+	}
+
+	if c.PerHostThreads < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanPerHostThreads)
+	}
+
+	if c.MaxIdleConns, err = cmd.Flags().GetInt(flagScanMaxIdleConns); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxIdleConns)
+	}
+
+	if c.MaxIdleConns < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanMaxIdleConns)
+	}
+
+	if c.MaxConnsPerHost, err = cmd.Flags().GetInt(flagScanMaxConnsPerHost); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxConnsPerHost)
+	}
+
+	if c.MaxConnsPerHost < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanMaxConnsPerHost)
+	}
+
+	return nil
+}
+
+// pacingConfigFromCmd sets how the requests are paced, retried and timed out
+func pacingConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.RequestsPerSecond, err = cmd.Flags().GetInt(flagScanRate); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRate)
+	}
+
+	if c.RequestsPerSecond < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanRate)
+	}
+
+	if c.DelayInMilliseconds, err = cmd.Flags().GetInt(flagScanDelay); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDelay)
+	}
+
+	if c.DelayInMilliseconds < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanDelay)
+	}
+
+	if c.JitterPercentage, err = cmd.Flags().GetInt(flagScanJitter); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanJitter)
+	}
+
+	if c.JitterPercentage < 0 || c.JitterPercentage > 100 {
+		return errors.Errorf("invalid value for %s: it must be between 0 and 100", flagScanJitter)
+	}
+
+	if c.Retries, err = cmd.Flags().GetInt(flagScanRetries); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRetries)
+	}
+
+	if c.Retries < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanRetries)
+	}
+
+	if c.RetryWaitInMilliseconds, err = cmd.Flags().GetInt(flagScanRetryWait); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRetryWait)
+	}
+
+	if c.RetryWaitInMilliseconds < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanRetryWait)
+	}
+
+	if c.ShouldSkipAdaptiveThrottle, err = cmd.Flags().GetBool(flagScanNoAdaptiveThrottle); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanNoAdaptiveThrottle)
+	}
+
+	if c.TimeoutInMilliseconds, err = timeoutFromCmd(cmd); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// recursionConfigFromCmd sets how the redirects and the folders found are followed
+func recursionConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.CacheRequests, err = cmd.Flags().GetBool(flagScanHTTPCacheRequests); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPCacheRequests)
+	}
+
+	if c.FollowRedirects, err = cmd.Flags().GetBool(flagScanFollowRedirects); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanFollowRedirects)
+	}
+
+	if c.MaxRedirects, err = cmd.Flags().GetInt(flagScanMaxRedirects); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxRedirects)
+	}
+
+	if c.MaxRedirects < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanMaxRedirects)
+	}
+
+	if c.ShouldProbeBackups, err = cmd.Flags().GetBool(flagScanProbeBackups); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanProbeBackups)
+	}
+
+	if c.BackupSuffixes, err = cmd.Flags().GetStringSlice(flagScanBackupSuffixes); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanBackupSuffixes)
+	}
+
+	for _, suffix := range c.BackupSuffixes {
+		if suffix == "" || strings.Contains(suffix, "/") {
+			return errors.Errorf("invalid value for %s: `%s` is not a valid suffix", flagScanBackupSuffixes, suffix)
+		}
+	}
+
+	if c.ScanDepth, err = cmd.Flags().GetInt(flagScanScanDepth); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanScanDepth)
+	}
+
+	if c.ScanDepth < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanScanDepth)
+	}
+
+	if c.RecursionStrategy, err = cmd.Flags().GetString(flagScanRecursionStrategy); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRecursionStrategy)
+	}
+
+	if !scan.IsValidRecursionStrategy(c.RecursionStrategy) {
+		return errors.Errorf(
+			"invalid value for %s: %s, the available ones are: %s",
+			flagScanRecursionStrategy,
+			c.RecursionStrategy,
+			strings.Join(scan.RecursionStrategies, ", "),
+		)
+	}
+
+	return nil
+}
+
+// scopeConfigFromCmd sets the hosts and the paths that can be requested
+func scopeConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.Scope, err = cmd.Flags().GetString(flagScanScope); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanScope)
+	}
+
+	if !scope.IsValidMode(c.Scope) {
+		return errors.Errorf(
+			"invalid value for %s: %s, the available ones are: %s",
+			flagScanScope,
+			c.Scope,
+			strings.Join(scope.Modes, ", "),
+		)
+	}
+
+	if c.ScopeDomains, err = cmd.Flags().GetStringArray(flagScanScopeDomain); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanScopeDomain)
+	}
+
+	for _, domain := range c.ScopeDomains {
+		if domain == "" || strings.ContainsAny(domain, "/:") {
+			return errors.Errorf("invalid value for %s: %s must be a host name", flagScanScopeDomain, domain)
+		}
+	}
+
+	rawExcludePaths, err := cmd.Flags().GetStringArray(flagScanExcludePath)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanExcludePath)
+	}
+
+	if c.ExcludePaths, err = urlpath.NewPatterns(rawExcludePaths); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanExcludePath)
+	}
+
+	return nil
+}
+
+// proxyConfigFromCmd sets the proxies the requests go through
+func proxyConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	socks5Host := cmd.Flag(flagScanSocks5Host).Value.String()
+	if len(socks5Host) > 0 {
+		if c.Socks5Url, err = url.Parse("socks5://" + socks5Host); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanSocks5Host)
+		}
+
+		if _, _, err = net.SplitHostPort(c.Socks5Url.Host); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanSocks5Host)
+		}
+	}
+
+	if c.HTTPProxyUrl, err = httpProxyFromCmd(cmd, flagScanHTTPProxy); err != nil {
+		return err
+	}
+
+	if c.Socks5Url != nil && c.HTTPProxyUrl != nil {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanSocks5Host, flagScanHTTPProxy)
+	}
+
+	if c.ReplayProxyUrl, err = httpProxyFromCmd(cmd, flagScanReplayProxy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// requestConfigFromCmd sets the user agent, the cookies and the rest of what is sent with the requests
+func requestConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	c.UserAgent = cmd.Flag(flagScanUserAgent).Value.String()
+
+	if c.UserAgents, err = userAgentsFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if len(c.UserAgent) > 0 && len(c.UserAgents) > 0 {
+		return errors.Errorf(
+			"%s cannot be used together with %s or %s",
+			flagScanUserAgent,
+			flagScanRandomUserAgent,
+			flagScanUserAgentFile,
+		)
+	}
+
+	if c.UseCookieJar, err = cmd.Flags().GetBool(flagScanCookieJar); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanCookieJar)
+	}
+
+	rawCookies, err := cmd.Flags().GetStringArray(flagScanCookie)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanCookie)
+	}
+
+	if c.Cookies, err = rawCookiesToCookies(rawCookies); err != nil {
+		return errors.Wrap(err, "failed to convert rawCookies to objects")
+	}
+
+	if c.FileCookies, err = fileCookiesFromCmd(cmd); err != nil {
+		return err
+	}
+
+	rawHeaders, err := cmd.Flags().GetStringArray(flagScanHeader)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHeader)
+	}
+
+	if c.Headers, err = rawHeadersToHeaders(rawHeaders); err != nil {
+		return errors.Wrapf(err, "failed to convert rawHeaders (%v)", rawHeaders)
+	}
+
+	c.HostHeader = cmd.Flag(flagScanHostHeader).Value.String()
+
+	if c.Body, err = bodyFromCmd(cmd); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// authConfigFromCmd sets the credentials the requests are authenticated with
+func authConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	basicAuth := cmd.Flag(flagScanBasicAuth).Value.String()
+	if len(basicAuth) > 0 {
+		parts := strings.SplitN(basicAuth, ":", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return errors.Errorf("%s is in invalid format, expected user:password", flagScanBasicAuth)
+		}
+
+		c.BasicAuthUsername, c.BasicAuthPassword = parts[0], parts[1]
+	}
+
+	c.NTLMUsername = cmd.Flag(flagScanNTLMUser).Value.String()
+	c.NTLMPassword = cmd.Flag(flagScanNTLMPassword).Value.String()
+	c.NTLMDomain = cmd.Flag(flagScanNTLMDomain).Value.String()
+
+	if c.NTLMUsername == "" && (c.NTLMPassword != "" || c.NTLMDomain != "") {
+		return errors.Errorf("%s and %s require %s", flagScanNTLMPassword, flagScanNTLMDomain, flagScanNTLMUser)
+	}
+
+	if c.NTLMUsername != "" && c.BasicAuthUsername != "" {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanBasicAuth, flagScanNTLMUser)
+	}
+
+	if c.BearerToken, err = bearerTokenFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if c.BearerToken != "" && c.BasicAuthUsername != "" {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanBasicAuth, flagScanBearerToken)
+	}
+
+	if c.BearerToken != "" && c.NTLMUsername != "" {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanNTLMUser, flagScanBearerToken)
+	}
+
+	return nil
+}
+
+// outputConfigFromCmd sets where the results and the progress of the scan are saved or sent
+func outputConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	c.Out = cmd.Flag(flagScanResultOutput).Value.String()
+	c.OutJSON = cmd.Flag(flagScanResultOutputJSON).Value.String()
+	c.OutCSV = cmd.Flag(flagScanResultOutputCSV).Value.String()
+	c.OutHTML = cmd.Flag(flagScanResultOutputHTML).Value.String()
+
+	if c.JSONLines, err = cmd.Flags().GetBool(flagScanResultOutputJSONLines); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanResultOutputJSONLines)
+	}
+
+	if c.WebhookURL, err = webhookURLFromCmd(cmd, flagScanWebhookURL); err != nil {
+		return err
+	}
+
+	if c.SlackWebhookURL, err = webhookURLFromCmd(cmd, flagScanSlackWebhook); err != nil {
+		return err
+	}
+
+	c.SaveResponsesDir = cmd.Flag(flagScanSaveResponses).Value.String()
+
+	if c.MaxSaveBytes, err = cmd.Flags().GetInt64(flagScanMaxSaveBytes); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxSaveBytes)
+	}
+
+	if c.MaxSaveBytes < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanMaxSaveBytes)
+	}
+
+	if c.WebhookStatuses, err = httpStatusesFromCmd(cmd, flagScanWebhookStatus); err != nil {
+		return err
+	}
+
+	c.ResumeFrom = cmd.Flag(flagScanResumeFrom).Value.String()
+
+	return nil
+}
+
+// detectionConfigFromCmd sets how the responses not telling whether a path exists are detected
+func detectionConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.ShouldSkipWildcardDetection, err = cmd.Flags().GetBool(flagScanNoWildcardDetection); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanNoWildcardDetection)
+	}
+
+	if c.BaselineRequest, err = cmd.Flags().GetBool(flagScanBaselineRequest); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanBaselineRequest)
+	}
+
+	if c.DiffThreshold, err = cmd.Flags().GetInt64(flagScanDiffThreshold); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDiffThreshold)
+	}
+
+	if c.DiffThreshold < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanDiffThreshold)
+	}
+
+	if c.OnWAF, err = cmd.Flags().GetString(flagScanOnWAF); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanOnWAF)
+	}
+
+	if !waf.IsValidAction(c.OnWAF) {
+		return errors.Errorf(
+			"invalid value for %s: %s, the available ones are: %s",
+			flagScanOnWAF,
+			c.OnWAF,
+			strings.Join(waf.Actions, ", "),
+		)
+	}
+
+	if c.WAFWindow, err = cmd.Flags().GetInt(flagScanWAFWindow); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanWAFWindow)
+	}
+
+	if c.WAFWindow < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanWAFWindow)
+	}
+
+	if c.WAFThreshold, err = cmd.Flags().GetInt(flagScanWAFThreshold); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanWAFThreshold)
+	}
+
+	if c.WAFThreshold < 1 || c.WAFThreshold > 100 {
+		return errors.Errorf("invalid value for %s: it must be between 1 and 100", flagScanWAFThreshold)
+	}
+
+	if c.WAFPause, err = cmd.Flags().GetDuration(flagScanWAFPause); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanWAFPause)
+	}
+
+	if c.WAFPause < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanWAFPause)
+	}
+
+	return nil
+}
+
+// displayConfigFromCmd sets what is printed while scanning
+func displayConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.ShouldHideProgress, err = cmd.Flags().GetBool(flagScanNoProgress); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanNoProgress)
+	}
+
+	if c.ProgressInterval, err = cmd.Flags().GetDuration(flagScanProgressInterval); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanProgressInterval)
+	}
+
+	if c.ProgressInterval < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanProgressInterval)
+	}
+
+	if c.ProgressInterval > 0 && c.ShouldHideProgress {
+		return errors.Errorf(
+			"%s and %s cannot be used at the same time",
+			flagScanProgressInterval,
+			flagScanNoProgress,
+		)
+	}
+
+	if c.ShouldHideBanner, err = cmd.Flags().GetBool(flagRootNoBanner); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagRootNoBanner)
+	}
+
+	if c.ShouldDisableColors, err = cmd.Flags().GetBool(flagRootNoColor); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagRootNoColor)
+	}
+
+	// see https://no-color.org
+	if os.Getenv(noColorEnvVariable) != "" {
+		c.ShouldDisableColors = true
+	}
+
+	if c.Quiet, err = cmd.Flags().GetBool(flagScanQuiet); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanQuiet)
+	}
+
+	if c.Quiet && cmd.Flag(flagRootVerbose).Changed {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanQuiet, flagRootVerbose)
+	}
+
+	return nil
+}
+
+// limitsConfigFromCmd sets when the scan stops early and how it exits
+func limitsConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.MaxRequests, err = cmd.Flags().GetInt64(flagScanMaxRequests); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxRequests)
+	}
+
+	if c.MaxRequests < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanMaxRequests)
+	}
+
+	if c.MaxDuration, err = cmd.Flags().GetDuration(flagScanMaxDuration); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxDuration)
+	}
+
+	if c.MaxDuration < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanMaxDuration)
+	}
+
+	if c.ExitOnMatch, err = cmd.Flags().GetBool(flagScanExitOnMatch); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanExitOnMatch)
+	}
+
+	return nil
+}
+
+// runConfigFromCmd sets how the scan is run
+func runConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.WatchInterval, err = cmd.Flags().GetDuration(flagScanWatchInterval); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanWatchInterval)
+	}
+
+	if c.WatchInterval < 0 {
+		return errors.Errorf("invalid value for %s: it cannot be negative", flagScanWatchInterval)
+	}
+
+	if c.WatchDiff, err = cmd.Flags().GetBool(flagScanWatchDiff); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanWatchDiff)
+	}
+
+	if c.WatchDiff && c.WatchInterval == 0 {
+		return errors.Errorf("%s requires %s", flagScanWatchDiff, flagScanWatchInterval)
+	}
+
+	if c.DryRun, err = cmd.Flags().GetBool(flagScanDryRun); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDryRun)
+	}
+
+	if c.SkipConfirmation, err = cmd.Flags().GetBool(flagScanYes); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanYes)
+	}
+
+	if c.StdinTargets, err = cmd.Flags().GetBool(flagScanStdinTargets); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanStdinTargets)
+	}
+
+	if c.StdinTargets && c.DryRun {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanStdinTargets, flagScanDryRun)
+	}
+
+	if c.StdinTargets && (c.DictionaryPath == dictionary.StdinPath || c.SecondDictionaryPath == dictionary.StdinPath) {
+		return errors.Errorf(
+			"%s cannot be used with a dictionary read from the standard input",
+			flagScanStdinTargets,
+		)
+	}
+
+	return nil
+}
+
+// transportConfigFromCmd sets how the connections are established
+func transportConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
 	var err error
 
-	c.DictionaryPath = cmd.Flag(flagScanDictionary).Value.String()
+	c.ShouldSkipSSLCertificatesValidation, err = cmd.Flags().GetBool(flagShouldSkipSSLCertificatesValidation)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagShouldSkipSSLCertificatesValidation)
+	}
 
-	if c.DictionaryTimeoutInMilliseconds, err = cmd.Flags().GetInt(flagScanDictionaryGetTimeout); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanDictionaryGetTimeout)
+	c.ShouldSkipTLSHostnameVerification, err = cmd.Flags().GetBool(flagScanNoTLSVerifyHostname)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanNoTLSVerifyHostname)
 	}
 
-	if c.HTTPMethods, err = cmd.Flags().GetStringSlice(flagScanHTTPMethods); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPMethods)
+	c.ClientCertificatePath = cmd.Flag(flagScanClientCertificate).Value.String()
+	c.ClientKeyPath = cmd.Flag(flagScanClientKey).Value.String()
+
+	if (c.ClientCertificatePath == "") != (c.ClientKeyPath == "") {
+		return errors.Errorf(
+			"%s and %s must be specified together",
+			flagScanClientCertificate,
+			flagScanClientKey,
+		)
 	}
 
-	if c.HTTPStatusesToIgnore, err = cmd.Flags().GetIntSlice(flagScanHTTPStatusesToIgnore); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPStatusesToIgnore)
+	c.CACertificatePath = cmd.Flag(flagScanCACertificate).Value.String()
+
+	c.ForceHTTP2, err = cmd.Flags().GetBool(flagScanHTTP2)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTP2)
 	}
 
-	if c.Threads, err = cmd.Flags().GetInt(flagScanThreads); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanThreads)
+	c.DisableHTTP2, err = cmd.Flags().GetBool(flagScanNoHTTP2)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanNoHTTP2)
 	}
 
-	if c.TimeoutInMilliseconds, err = cmd.Flags().GetInt(flagScanHTTPTimeout); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPTimeout)
+	if c.ForceHTTP2 && c.DisableHTTP2 {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanHTTP2, flagScanNoHTTP2)
 	}
 
-	if c.CacheRequests, err = cmd.Flags().GetBool(flagScanHTTPCacheRequests); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPCacheRequests)
+	c.Resolver = cmd.Flag(flagScanResolver).Value.String()
+	if c.Resolver != "" {
+		if _, _, err := net.SplitHostPort(c.Resolver); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanResolver)
+		}
 	}
 
-	if c.ScanDepth, err = cmd.Flags().GetInt(flagScanScanDepth); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanScanDepth)
+	c.PreferIPv6, err = cmd.Flags().GetBool(flagScanPreferIPv6)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanPreferIPv6)
 	}
 
-	socks5Host := cmd.Flag(flagScanSocks5Host).Value.String()
-	if len(socks5Host) > 0 {
-		if c.Socks5Url, err = url.Parse("socks5://" + socks5Host); err != nil {
-			return nil, errors.Wrapf(err, "invalid value for %s", flagScanSocks5Host)
+	return nil
+}
+
+// requestTemplateFromCmd returns the request template read from the file specified via the command line,
+// nil when none is specified
+func requestTemplateFromCmd(cmd *cobra.Command) (*template.Template, error) {
+	templatePath := cmd.Flag(flagScanRequestTemplate).Value.String()
+	if templatePath == "" {
+		return nil, nil
+	}
+
+	raw, err := ioutil.ReadFile(templatePath) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", templatePath)
+	}
+
+	t, err := template.Parse(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value for %s", flagScanRequestTemplate)
+	}
+
+	return t, nil
+}
+
+// validateRequestTemplateConfig rejects the flags that rely on the targets being paths or that would override the
+// method of the template
+func validateRequestTemplateConfig(cmd *cobra.Command, c *scan.Config) error {
+	if c.VHostScan {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanRequestTemplate, flagScanVHostDictionary)
+	}
+
+	if cmd.Flags().Changed(flagScanHTTPMethods) {
+		return errors.Errorf(
+			"%s and %s cannot be used at the same time, the method is the one of the template",
+			flagScanRequestTemplate,
+			flagScanHTTPMethods,
+		)
+	}
+
+	if c.ResumeFrom != "" {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanRequestTemplate, flagScanResumeFrom)
+	}
+
+	if c.BaselineRequest {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanRequestTemplate, flagScanBaselineRequest)
+	}
+
+	hasSecondPlaceholder := c.RequestTemplate.Contains(template.SecondPlaceholder)
+
+	if c.SecondDictionaryPath != "" && !hasSecondPlaceholder {
+		return errors.Errorf(
+			"the request template doesn't contain the %s placeholder, required by %s",
+			template.SecondPlaceholder,
+			flagScanSecondDictionary,
+		)
+	}
+
+	if c.SecondDictionaryPath == "" && hasSecondPlaceholder {
+		return errors.Errorf(
+			"the request template contains the %s placeholder, it requires %s",
+			template.SecondPlaceholder,
+			flagScanSecondDictionary,
+		)
+	}
+
+	return nil
+}
+
+// validateVHostScanConfig rejects the flags that would override the Host header of the requests or that rely on
+// the targets being paths
+func validateVHostScanConfig(c *scan.Config) error {
+	if c.HostHeader != "" {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanVHostDictionary, flagScanHostHeader)
+	}
+
+	for name := range c.Headers {
+		if http.CanonicalHeaderKey(name) == "Host" {
+			return errors.Errorf("%s cannot be used together with a Host %s", flagScanVHostDictionary, flagScanHeader)
 		}
 	}
 
-	c.UserAgent = cmd.Flag(flagScanUserAgent).Value.String()
+	if c.ResumeFrom != "" {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanVHostDictionary, flagScanResumeFrom)
+	}
 
-	if c.UseCookieJar, err = cmd.Flags().GetBool(flagScanCookieJar); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanCookieJar)
+	if c.BaselineRequest {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanVHostDictionary, flagScanBaselineRequest)
 	}
 
-	rawCookies, err := cmd.Flags().GetStringArray(flagScanCookie)
+	return nil
+}
+
+// validateWatchConfig rejects the options that can't be applied to a scan repeated over and over,
+// EG the standard input can be read only once
+func validateWatchConfig(c *scan.Config) error {
+	if c.DictionaryPath == dictionary.StdinPath || c.SecondDictionaryPath == dictionary.StdinPath {
+		return errors.Errorf("%s cannot be used with a dictionary read from the standard input", flagScanWatchInterval)
+	}
+
+	if c.StdinTargets {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanWatchInterval, flagScanStdinTargets)
+	}
+
+	if c.DryRun {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanWatchInterval, flagScanDryRun)
+	}
+
+	if c.ResumeFrom != "" {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanWatchInterval, flagScanResumeFrom)
+	}
+
+	if c.ExitOnMatch {
+		return errors.Errorf("%s and %s cannot be used at the same time", flagScanWatchInterval, flagScanExitOnMatch)
+	}
+
+	return nil
+}
+
+func bodyFromCmd(cmd *cobra.Command) ([]byte, error) {
+	body := cmd.Flag(flagScanBody).Value.String()
+	bodyFile := cmd.Flag(flagScanBodyFile).Value.String()
+
+	if len(body) > 0 && len(bodyFile) > 0 {
+		return nil, errors.Errorf("%s and %s cannot be used at the same time", flagScanBody, flagScanBodyFile)
+	}
+
+	if len(body) > 0 {
+		return []byte(body), nil
+	}
+
+	if len(bodyFile) > 0 {
+		b, err := ioutil.ReadFile(bodyFile) // #nosec
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", bodyFile)
+		}
+
+		return b, nil
+	}
+
+	return nil, nil
+}
+
+// bearerTokenFromCmd returns the bearer token specified via the command line or read from the token file,
+// empty when none is specified
+func bearerTokenFromCmd(cmd *cobra.Command) (string, error) {
+	token := cmd.Flag(flagScanBearerToken).Value.String()
+	tokenFile := cmd.Flag(flagScanBearerTokenFile).Value.String()
+
+	if len(token) > 0 && len(tokenFile) > 0 {
+		return "", errors.Errorf("%s and %s cannot be used at the same time", flagScanBearerToken, flagScanBearerTokenFile)
+	}
+
+	if len(tokenFile) == 0 {
+		return token, nil
+	}
+
+	b, err := ioutil.ReadFile(tokenFile) // #nosec
 	if err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanCookie)
+		return "", errors.Wrapf(err, "failed to read %s", tokenFile)
 	}
 
-	if c.Cookies, err = rawCookiesToCookies(rawCookies); err != nil {
-		return nil, errors.Wrap(err, "failed to convert rawCookies to objects")
+	// the trailing new line of the file is not part of the token
+	token = strings.TrimSpace(string(b))
+	if token == "" {
+		return "", errors.Errorf("invalid value for %s: %s is empty", flagScanBearerTokenFile, tokenFile)
 	}
 
-	rawHeaders, err := cmd.Flags().GetStringArray(flagScanHeader)
+	return token, nil
+}
+
+// fileCookiesFromCmd returns the cookies of the file exported by a browser, nil when no file is specified
+func fileCookiesFromCmd(cmd *cobra.Command) ([]*http.Cookie, error) {
+	cookieFile := cmd.Flag(flagScanCookieFile).Value.String()
+	if len(cookieFile) == 0 {
+		return nil, nil
+	}
+
+	file, err := os.Open(cookieFile) // #nosec
 	if err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHeader)
+		return nil, errors.Wrapf(err, "failed to read %s", flagScanCookieFile)
 	}
 
-	if c.Headers, err = rawHeadersToHeaders(rawHeaders); err != nil {
-		return nil, errors.Wrapf(err, "failed to convert rawHeaders (%v)", rawHeaders)
+	defer file.Close() //nolint:errcheck
+
+	cookies, err := cookie.ParseNetscapeFile(file, time.Now())
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value for %s: %s", flagScanCookieFile, cookieFile)
 	}
 
-	c.Out = cmd.Flag(flagScanResultOutput).Value.String()
+	if len(cookies) == 0 {
+		return nil, errors.Errorf("invalid value for %s: no valid cookie found in %s", flagScanCookieFile, cookieFile)
+	}
 
-	c.ShouldSkipSSLCertificatesValidation, err = cmd.Flags().GetBool(flagShouldSkipSSLCertificatesValidation)
+	return cookies, nil
+}
+
+// userAgentsFromCmd returns the pool of user agents to rotate, nil when the user agent is not rotated
+func userAgentsFromCmd(cmd *cobra.Command) ([]string, error) {
+	randomUserAgent, err := cmd.Flags().GetBool(flagScanRandomUserAgent)
 	if err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagShouldSkipSSLCertificatesValidation)
+		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanRandomUserAgent)
 	}
 
-	return c, nil
+	userAgentFile := cmd.Flag(flagScanUserAgentFile).Value.String()
+	if len(userAgentFile) == 0 {
+		if randomUserAgent {
+			return client.BrowserUserAgents, nil
+		}
+
+		return nil, nil
+	}
+
+	content, err := ioutil.ReadFile(userAgentFile) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", flagScanUserAgentFile)
+	}
+
+	userAgents := make([]string, 0)
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		userAgents = append(userAgents, line)
+	}
+
+	if len(userAgents) == 0 {
+		return nil, errors.Errorf("invalid value for %s: no user agent found in %s", flagScanUserAgentFile, userAgentFile)
+	}
+
+	return userAgents, nil
+}
+
+func httpStatusesFromCmd(cmd *cobra.Command, flag string) ([]int, error) {
+	rawStatuses, err := cmd.Flags().GetStringSlice(flag)
+	if err != nil {
+		return nil, errors.Wrapf(err, failedToReadPropertyError, flag)
+	}
+
+	statuses, err := parseHTTPStatuses(rawStatuses)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value for %s", flag)
+	}
+
+	return statuses, nil
+}
+
+// parseHTTPStatuses expands a list of http statuses and ranges of http statuses; eg ["200", "301-303"]
+// becomes [200, 301, 302, 303]
+func parseHTTPStatuses(rawStatuses []string) ([]int, error) {
+	statuses := make([]int, 0, len(rawStatuses))
+
+	for _, rawStatus := range rawStatuses {
+		parts := strings.SplitN(rawStatus, "-", 2)
+
+		from, err := parseHTTPStatus(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		to := from
+
+		if len(parts) == 2 {
+			if to, err = parseHTTPStatus(parts[1]); err != nil {
+				return nil, err
+			}
+
+			if from > to {
+				return nil, errors.Errorf("invalid http status range: %s", rawStatus)
+			}
+		}
+
+		for status := from; status <= to; status++ {
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses, nil
+}
+
+func parseHTTPStatus(rawStatus string) (int, error) {
+	status, err := strconv.Atoi(strings.TrimSpace(rawStatus))
+	if err != nil || status < 100 || status > 599 {
+		return 0, errors.Errorf("invalid http status: %s", rawStatus)
+	}
+
+	return status, nil
+}
+
+func contentLengthsFromCmd(cmd *cobra.Command) ([]int64, error) {
+	rawContentLengths, err := cmd.Flags().GetIntSlice(flagScanFilterSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanFilterSize)
+	}
+
+	contentLengths := make([]int64, 0, len(rawContentLengths))
+
+	for _, contentLength := range rawContentLengths {
+		if contentLength < 0 {
+			return nil, errors.Errorf("invalid value for %s: it cannot be negative", flagScanFilterSize)
+		}
+
+		contentLengths = append(contentLengths, int64(contentLength))
+	}
+
+	return contentLengths, nil
+}
+
+func contentLengthRangesFromCmd(cmd *cobra.Command) ([]scan.ContentLengthRange, error) {
+	rawRanges, err := cmd.Flags().GetStringSlice(flagScanFilterSizeRange)
+	if err != nil {
+		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanFilterSizeRange)
+	}
+
+	ranges := make([]scan.ContentLengthRange, 0, len(rawRanges))
+
+	for _, rawRange := range rawRanges {
+		parts := strings.SplitN(rawRange, "-", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid value for %s: %s is not a range", flagScanFilterSizeRange, rawRange)
+		}
+
+		from, fromErr := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+		to, toErr := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+
+		if fromErr != nil || toErr != nil || from < 0 || from > to {
+			return nil, errors.Errorf("invalid value for %s: %s is not a valid range", flagScanFilterSizeRange, rawRange)
+		}
+
+		ranges = append(ranges, scan.ContentLengthRange{From: from, To: to})
+	}
+
+	return ranges, nil
+}
+
+// contentTypesFromCmd returns the regexes matching the content types specified via the flag: a case insensitive
+// substring, or a regex when prefixed with urlpath.RegexPatternPrefix
+func contentTypesFromCmd(cmd *cobra.Command, flag string) ([]*regexp.Regexp, error) {
+	rawContentTypes, err := cmd.Flags().GetStringArray(flag)
+	if err != nil {
+		return nil, errors.Wrapf(err, failedToReadPropertyError, flag)
+	}
+
+	contentTypes := make([]*regexp.Regexp, 0, len(rawContentTypes))
+
+	for _, rawContentType := range rawContentTypes {
+		if !strings.HasPrefix(rawContentType, urlpath.RegexPatternPrefix) {
+			contentTypes = append(contentTypes, regexp.MustCompile("(?i)"+regexp.QuoteMeta(rawContentType)))
+			continue
+		}
+
+		regex, err := regexp.Compile(strings.TrimPrefix(rawContentType, urlpath.RegexPatternPrefix))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for %s", flag)
+		}
+
+		contentTypes = append(contentTypes, regex)
+	}
+
+	return contentTypes, nil
+}
+
+func headerMatchersFromCmd(cmd *cobra.Command) ([]scan.HeaderMatcher, error) {
+	rawHeaderMatchers, err := cmd.Flags().GetStringArray(flagScanMatchHeader)
+	if err != nil {
+		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanMatchHeader)
+	}
+
+	headerMatchers := make([]scan.HeaderMatcher, 0, len(rawHeaderMatchers))
+
+	for _, rawHeaderMatcher := range rawHeaderMatchers {
+		parts := strings.SplitN(rawHeaderMatcher, ":", 2)
+
+		m := scan.HeaderMatcher{Name: strings.TrimSpace(parts[0])}
+		if m.Name == "" {
+			return nil, errors.Errorf("invalid value for %s: %s has no header name", flagScanMatchHeader, rawHeaderMatcher)
+		}
+
+		if len(parts) == 2 {
+			if m.Value, err = regexp.Compile(strings.TrimSpace(parts[1])); err != nil {
+				return nil, errors.Wrapf(err, "invalid value for %s", flagScanMatchHeader)
+			}
+		}
+
+		headerMatchers = append(headerMatchers, m)
+	}
+
+	return headerMatchers, nil
+}
+
+func normalizeExtensions(extensions []string) ([]string, error) {
+	normalizedExtensions := make([]string, 0, len(extensions))
+
+	for _, extension := range extensions {
+		normalizedExtension := strings.TrimPrefix(strings.TrimSpace(extension), ".")
+		if len(normalizedExtension) == 0 || strings.Contains(normalizedExtension, "/") {
+			return nil, errors.Errorf("unsupported extension: %s", extension)
+		}
+
+		normalizedExtensions = append(normalizedExtensions, normalizedExtension)
+	}
+
+	return normalizedExtensions, nil
+}
+
+// normalizeQueries encodes the query strings, they can be specified either encoded (EG q=a%20b) or not (EG q=a b);
+// a query string specified more than once is requested only once
+func normalizeQueries(queries []string) ([]string, error) {
+	normalizedQueries := make([]string, 0, len(queries))
+	alreadyNormalized := make(map[string]struct{}, len(queries))
+
+	for _, query := range queries {
+		params := make([]string, 0, 1)
+
+		for _, param := range strings.Split(strings.TrimPrefix(strings.TrimSpace(query), "?"), "&") {
+			if param == "" {
+				continue
+			}
+
+			keyValue := strings.SplitN(param, "=", 2)
+			for i, s := range keyValue {
+				unescaped, err := url.QueryUnescape(s)
+				if err != nil {
+					return nil, errors.Errorf("unsupported query string: %s", query)
+				}
+
+				keyValue[i] = url.QueryEscape(unescaped)
+			}
+
+			params = append(params, strings.Join(keyValue, "="))
+		}
+
+		if len(params) == 0 {
+			return nil, errors.Errorf("unsupported query string: %s", query)
+		}
+
+		normalizedQuery := strings.Join(params, "&")
+		if _, ok := alreadyNormalized[normalizedQuery]; ok {
+			continue
+		}
+
+		alreadyNormalized[normalizedQuery] = struct{}{}
+		normalizedQueries = append(normalizedQueries, normalizedQuery)
+	}
+
+	return normalizedQueries, nil
+}
+
+func normalizeHTTPMethods(methods []string) ([]string, error) {
+	supportedMethods := map[string]struct{}{
+		http.MethodGet:     {},
+		http.MethodHead:    {},
+		http.MethodPost:    {},
+		http.MethodPut:     {},
+		http.MethodPatch:   {},
+		http.MethodDelete:  {},
+		http.MethodOptions: {},
+		http.MethodTrace:   {},
+	}
+
+	normalizedMethods := make([]string, 0, len(methods))
+
+	for _, method := range methods {
+		normalizedMethod := strings.ToUpper(strings.TrimSpace(method))
+		if _, found := supportedMethods[normalizedMethod]; !found {
+			return nil, errors.Errorf("unsupported http method: %s", method)
+		}
+
+		normalizedMethods = append(normalizedMethods, normalizedMethod)
+	}
+
+	return normalizedMethods, nil
+}
+
+// timeoutFromCmd returns the timeout of the requests in milliseconds, it is read from the deprecated --http-timeout
+// only when --timeout is not specified
+func timeoutFromCmd(cmd *cobra.Command) (int, error) {
+	if cmd.Flags().Changed(flagScanHTTPTimeout) && !cmd.Flags().Changed(flagScanTimeout) {
+		timeoutInMilliseconds, err := cmd.Flags().GetInt(flagScanHTTPTimeout)
+		if err != nil {
+			return 0, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPTimeout)
+		}
+
+		return timeoutInMilliseconds, nil
+	}
+
+	timeout, err := cmd.Flags().GetDuration(flagScanTimeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, failedToReadPropertyError, flagScanTimeout)
+	}
+
+	if timeout < time.Millisecond {
+		return 0, errors.Errorf("invalid value for %s: it must be at least 1ms", flagScanTimeout)
+	}
+
+	return int(timeout / time.Millisecond), nil
 }
 
 func rawHeadersToHeaders(rawHeaders []string) (map[string]string, error) {
 	headers := make(map[string]string, len(rawHeaders)*2)
 
 	for _, rawHeader := range rawHeaders {
-		parts := strings.Split(rawHeader, ":")
-		if len(parts) != 2 {
+		parts := strings.SplitN(rawHeader, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
 			return nil, errors.Errorf("header is in invalid format: %s", rawHeader)
 		}
 
-		headers[parts[0]] = parts[1]
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 	}
 
 	return headers, nil
@@ -108,8 +1397,8 @@ func rawCookiesToCookies(rawCookies []string) ([]*http.Cookie, error) {
 	cookies := make([]*http.Cookie, 0, len(rawCookies))
 
 	for _, rawCookie := range rawCookies {
-		parts := strings.Split(rawCookie, "=")
-		if len(parts) != 2 {
+		parts := strings.SplitN(rawCookie, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
 			return nil, errors.Errorf("cookie format is invalid: %s", rawCookie)
 		}
 
@@ -125,3 +1414,41 @@ func rawCookiesToCookies(rawCookies []string) ([]*http.Cookie, error) {
 
 	return cookies, nil
 }
+
+// httpProxyFromCmd returns the url of the http proxy of the flag, nil when the flag is not set
+func httpProxyFromCmd(cmd *cobra.Command, flag string) (*url.URL, error) {
+	httpProxy := cmd.Flag(flag).Value.String()
+	if len(httpProxy) == 0 {
+		return nil, nil
+	}
+
+	u, err := url.Parse(httpProxy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value for %s", flag)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("invalid value for %s: scheme must be http or https", flag)
+	}
+
+	if u.Host == "" {
+		return nil, errors.Errorf("invalid value for %s: host is missing", flag)
+	}
+
+	return u, nil
+}
+
+// webhookURLFromCmd returns the url of the webhook set via the flag, an empty string when no webhook is configured
+func webhookURLFromCmd(cmd *cobra.Command, flag string) (string, error) {
+	webhookURL := cmd.Flag(flag).Value.String()
+	if webhookURL == "" {
+		return "", nil
+	}
+
+	u, err := url.ParseRequestURI(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", errors.Errorf("invalid value for %s: %s is not an http or https url", flag, webhookURL)
+	}
+
+	return webhookURL, nil
+}
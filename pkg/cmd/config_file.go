@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/common/remote"
+)
+
+// configFileName is the name of the config file searched in the working directory, in the home directory
+// it is searched as a hidden file
+const configFileName = "dirstalk.yaml"
+
+// remoteConfigFileTimeout is the timeout to retrieve a config file (or a scan spec) specified via a url
+const remoteConfigFileTimeout = 10 * time.Second
+
+// configFileEntry is a flag set in the config file, the flags accepting multiple values can have more than one
+type configFileEntry struct {
+	flag   string
+	values []string
+	line   int
+}
+
+// loadConfigFile sets the flags not specified via the command line to the values found in the config file,
+// it does nothing when no config file is specified nor found in one of the default locations
+func loadConfigFile(logger *logrus.Logger, cmd *cobra.Command) error {
+	path := cmd.Flag(flagScanConfig).Value.String()
+	if path == "" {
+		path = findConfigFile()
+	}
+
+	if path == "" {
+		return nil
+	}
+
+	logger.WithField("path", path).Debug("Loading config file")
+
+	content, err := readConfigFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read config file %s", path)
+	}
+
+	entries, err := parseConfigFile(string(content))
+	if err != nil {
+		return errors.Wrapf(err, "invalid config file %s", path)
+	}
+
+	// all the flags are validated before setting any of them
+	for _, entry := range entries {
+		f := cmd.Flags().Lookup(entry.flag)
+		if f == nil || f.Name == flagScanConfig || f.Name == flagScanSpec || cmd.InheritedFlags().Lookup(f.Name) != nil {
+			return errors.Errorf("invalid config file %s: unknown flag %s at line %d", path, entry.flag, entry.line)
+		}
+	}
+
+	for _, entry := range entries {
+		f := cmd.Flags().Lookup(entry.flag)
+
+		// the flags specified via the command line take precedence over the config file
+		if isFlagSet(cmd, f.Name) {
+			continue
+		}
+
+		for _, value := range entry.values {
+			if err := cmd.Flags().Set(f.Name, value); err != nil {
+				return errors.Wrapf(err, "invalid config file %s: invalid value for %s at line %d", path, f.Name, entry.line)
+			}
+		}
+	}
+
+	return nil
+}
+
+// readConfigFile returns the content of the config file (or of the scan spec) at path, when path is a url
+// the file is retrieved via http
+func readConfigFile(path string) ([]byte, error) {
+	if !remote.IsRemote(path) {
+		return ioutil.ReadFile(path) // #nosec
+	}
+
+	reader, err := remote.Open(path, &http.Client{Timeout: remoteConfigFileTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close() //nolint:errcheck
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the response of `%s`", path)
+	}
+
+	return content, nil
+}
+
+// isFlagSet returns true when the flag has already been set, the timeout is set when either --timeout or the
+// deprecated --http-timeout is, so that one of them doesn't override the other one specified via the command line
+func isFlagSet(cmd *cobra.Command, flag string) bool {
+	if flag == flagScanTimeout || flag == flagScanHTTPTimeout {
+		return cmd.Flags().Changed(flagScanTimeout) || cmd.Flags().Changed(flagScanHTTPTimeout)
+	}
+
+	return cmd.Flags().Changed(flag)
+}
+
+// findConfigFile returns the first config file found in the default locations, an empty string when none is found
+func findConfigFile() string {
+	paths := []string{configFileName}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, "."+configFileName))
+	}
+
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// parseConfigFile parses the subset of yaml needed to set the flags: a flag per line in the `flag: value` format,
+// or followed by one `- value` line per value; empty lines and comments are ignored
+func parseConfigFile(content string) ([]configFileEntry, error) {
+	entries := make([]configFileEntry, 0)
+	seen := make(map[string]struct{})
+
+	// list is the entry whose values are being listed, nil when the last flag has a value on its line
+	var list *configFileEntry
+
+	for i, line := range strings.Split(content, "\n") {
+		lineNumber := i + 1
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "-") {
+			if list == nil {
+				return nil, errors.Errorf("list item without a flag at line %d", lineNumber)
+			}
+
+			list.values = append(list.values, parseConfigFileValue(strings.TrimPrefix(line, "-")))
+
+			continue
+		}
+
+		if list != nil && len(list.values) == 0 {
+			return nil, errors.Errorf("no value for %s at line %d", list.flag, list.line)
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf("expected `flag: value` at line %d", lineNumber)
+		}
+
+		flag := strings.TrimSpace(parts[0])
+		if _, found := seen[flag]; found {
+			return nil, errors.Errorf("%s specified more than once at line %d", flag, lineNumber)
+		}
+
+		seen[flag] = struct{}{}
+
+		entries = append(entries, configFileEntry{flag: flag, line: lineNumber})
+		list = &entries[len(entries)-1]
+
+		if rawValue := strings.TrimSpace(parts[1]); rawValue != "" {
+			list.values = []string{parseConfigFileValue(rawValue)}
+			list = nil
+		}
+	}
+
+	if list != nil && len(list.values) == 0 {
+		return nil, errors.Errorf("no value for %s at line %d", list.flag, list.line)
+	}
+
+	return entries, nil
+}
+
+// parseConfigFileValue returns the value without the quotes surrounding it, if any
+func parseConfigFileValue(rawValue string) string {
+	value := strings.TrimSpace(rawValue)
+
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		if value[0] == '"' {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+		}
+
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
@@ -2,6 +2,10 @@ package cmd_test
 
 import (
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stefanoj3/dirstalk/pkg/common/test"
@@ -55,6 +59,199 @@ func TestDictionaryGenerateShouldFailWhenAFilePathIsProvidedInsteadOfADirectory(
 	assert.Contains(t, err.Error(), "the path should be a directory")
 }
 
+func TestDictionaryGenerateCommandWithMangleRules(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.generate", "./termination", "--mangle", "upper,append-digits")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "termination\nTERMINATION\ntermination1\ntermination123\n")
+}
+
+func TestDictionaryGenerateCommandWithCaseInsensitiveDedup(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"dictionary.generate",
+		"./termination",
+		"--mangle",
+		"upper,append-digits",
+		"--case-insensitive-dedup",
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "termination\ntermination1\ntermination123\n")
+	assert.NotContains(t, loggerBuffer.String(), "TERMINATION")
+}
+
+func TestDictionaryGenerateCommandWithJSONOutputFormat(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.generate", "./termination", "--out-format", "json")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), `{"word":"termination","source":"./termination"}`+"\n")
+	assert.Contains(t, loggerBuffer.String(), `{"word":"handler.go","source":"termination/handler.go"}`+"\n")
+}
+
+func TestDictionaryGenerateCommandShouldErrForUnknownOutputFormat(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.generate", "./termination", "--out-format", "csv")
+	assert.Error(t, err)
+
+	assert.Contains(t, err.Error(), "invalid value for out-format: csv, the available ones are: text, json")
+}
+
+func TestDictionaryGenerateCommandShouldSkipTheIgnoredFoldersAndFiles(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"dictionary.generate",
+		"../dictionary/testdata/directory_with_junk_to_generate_dictionary",
+		"--ignore-dir",
+		"cache",
+		"--ignore-ext",
+		"png,woff",
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "assets\napp.js\nindex.php\n")
+
+	for _, ignored := range []string{"node_modules", "left-pad", "vendor", "lib", "autoload.php", "cache", "data.bin"} {
+		assert.NotContains(t, loggerBuffer.String(), ignored)
+	}
+
+	assert.NotContains(t, loggerBuffer.String(), "logo.PNG")
+	assert.NotContains(t, loggerBuffer.String(), "font.woff")
+}
+
+func TestDictionaryGenerateCommandWithoutDefaultIgnores(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"dictionary.generate",
+		"../dictionary/testdata/directory_with_junk_to_generate_dictionary",
+		"--no-default-ignores",
+		"--ignore-dir",
+		"vendor",
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "node_modules\nleft-pad\nindex.js\n")
+	assert.NotContains(t, loggerBuffer.String(), "autoload.php")
+}
+
+func TestDictionaryGenerateCommandWithRespectGitignore(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dir, err := ioutil.TempDir("", "dirstalk-gitignore")
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir) //nolint:errcheck
+	}()
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "dist"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".gitignore"), []byte("dist/\n*.tmp\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "dist", "bundle.js"), []byte{}, 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.php"), []byte{}, 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cache.tmp"), []byte{}, 0600))
+
+	err = executeCommand(c, "dictionary.generate", dir, "--respect-gitignore")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "\n.gitignore\nindex.php\n")
+	assert.NotContains(t, loggerBuffer.String(), "dist")
+	assert.NotContains(t, loggerBuffer.String(), "bundle.js")
+	assert.NotContains(t, loggerBuffer.String(), "cache.tmp")
+}
+
+func TestDictionaryGenerateCommandWithMaxDepth(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.generate", "../dictionary/testdata", "--max-depth", "0")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "directory_to_generate_dictionary\n")
+	assert.NotContains(t, loggerBuffer.String(), "subfolder")
+}
+
+func TestDictionaryGenerateCommandWithFollowSymlinksShouldNotLoop(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dir, err := ioutil.TempDir("", "dirstalk-symlinks")
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir) //nolint:errcheck
+	}()
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "admin"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "admin", "index.php"), []byte{}, 0600))
+	assert.NoError(t, os.Symlink("..", filepath.Join(dir, "admin", "loop")))
+
+	err = executeCommand(c, "dictionary.generate", dir, "--follow-symlinks", "--absolute-only", "-v")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), filepath.Join(dir, "admin", "index.php")+"\n")
+	assert.Contains(t, loggerBuffer.String(), "Skipping symlink, following it would make the walk loop")
+}
+
+func TestDictionaryGenerateCommandShouldErrForUnknownMangleRules(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.generate", "./termination", "--mangle", "upper,reverse")
+	assert.Error(t, err)
+
+	assert.Contains(t, err.Error(), "unknown mangle rule `reverse`")
+}
+
+func TestDictionaryGenerateCommandShouldErrWhenManglingAbsolutePaths(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.generate", "./termination", "--mangle", "upper", "--absolute-only")
+	assert.Error(t, err)
+
+	assert.Contains(t, err.Error(), "absolute-only and mangle cannot be used at the same time")
+}
+
 func TestGenerateDictionaryWithoutOutputPath(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
@@ -80,3 +277,309 @@ func TestGenerateDictionaryWithInvalidDirectory(t *testing.T) {
 	assert.Contains(t, err.Error(), "unable to use the provided path")
 	assert.Contains(t, err.Error(), fakePath)
 }
+
+func TestDictionaryRobotsCommand(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/robots.txt" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /admin/\nDisallow: /cgi-bin/?debug=1\nAllow: /admin/\n")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	testFilePath := "testdata/" + test.RandStringRunes(10)
+	defer removeTestFile(testFilePath)
+
+	err := executeCommand(c, "dictionary.robots", testServer.URL, "-o", testFilePath)
+	assert.NoError(t, err)
+
+	//nolint:gosec
+	content, err := ioutil.ReadFile(testFilePath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/admin/\n/cgi-bin/\n", string(content))
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, "/robots.txt", r.URL.Path)
+	})
+}
+
+func TestDictionaryRobotsCommandShouldErrWhenNoTargetIsProvided(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.robots")
+	assert.Error(t, err)
+
+	assert.Contains(t, err.Error(), "no url or path provided")
+}
+
+func TestDictionaryRobotsCommandShouldErrWhenTheRobotsIsNotFound(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "dictionary.robots", testServer.URL)
+	assert.Error(t, err)
+
+	assert.Contains(t, err.Error(), "status code 404")
+}
+
+func TestDictionarySitemapCommand(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var testServer *httptest.Server
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/sitemap.xml":
+				_, _ = w.Write([]byte( //nolint:errcheck
+					"<sitemapindex><sitemap><loc>" + testServer.URL + "/blog.xml</loc></sitemap></sitemapindex>",
+				))
+			case "/blog.xml":
+				_, _ = w.Write([]byte( //nolint:errcheck
+					"<urlset><url><loc>" + testServer.URL + "/blog/post?id=1</loc></url></urlset>",
+				))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "dictionary.sitemap", testServer.URL)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/blog/post\n", loggerBuffer.String())
+	assert.Equal(t, 2, serverAssertion.Len())
+}
+
+func TestDictionarySitemapCommandShouldUseTheURLProvided(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/sitemaps/main.xml" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write([]byte("<urlset><url><loc>/about</loc></url></urlset>")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "dictionary.sitemap", testServer.URL+"/sitemaps/main.xml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/about\n", loggerBuffer.String())
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestDictionaryJavascriptCommand(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/main.js":
+				_, _ = w.Write([]byte(`fetch("/api/users?page=1"); fetch('/api/admin');`)) //nolint:errcheck
+			case "/vendor.js":
+				_, _ = w.Write([]byte("var u = `/api/users/${id}`; var a = \"/api/admin\";")) //nolint:errcheck
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "dictionary.js", testServer.URL+"/main.js", testServer.URL+"/vendor.js")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/api/admin\n/api/users\n/api/users/\n", loggerBuffer.String())
+	assert.Equal(t, 2, serverAssertion.Len())
+}
+
+func TestDictionaryJavascriptCommandShouldErrWhenNoFileIsProvided(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.js")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no url or path provided")
+}
+
+func TestDictionaryMergeCommand(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	first := test.MustWriteTempFile(t, []byte("/admin\nlogin  \n"))
+	defer removeTempFile(first)
+
+	second := test.MustWriteTempFile(t, []byte("# comment\nadmin\n/backup\n"))
+	defer removeTempFile(second)
+
+	testFilePath := "testdata/" + test.RandStringRunes(10)
+	defer removeTestFile(testFilePath)
+
+	err := executeCommand(c, "dictionary.merge", first, second, "-o", testFilePath)
+	assert.NoError(t, err)
+
+	//nolint:gosec
+	content, err := ioutil.ReadFile(testFilePath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "admin\nbackup\nlogin\n", string(content))
+}
+
+func TestDictionaryMergeCommandShouldPreserveTheFormatWhenRequested(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	first := test.MustWriteTempFile(t, []byte("/admin\nlogin\n"))
+	defer removeTempFile(first)
+
+	second := test.MustWriteTempFile(t, []byte("admin\n"))
+	defer removeTempFile(second)
+
+	err := executeCommand(c, "dictionary.merge", first, second, "--preserve-format")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/admin\nadmin\nlogin\n", loggerBuffer.String())
+}
+
+func TestDictionaryMergeCommandWithCaseInsensitiveDedup(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	first := test.MustWriteTempFile(t, []byte("Admin\nlogin\n"))
+	defer removeTempFile(first)
+
+	second := test.MustWriteTempFile(t, []byte("admin\nLOGIN\n"))
+	defer removeTempFile(second)
+
+	err := executeCommand(c, "dictionary.merge", first, second, "--case-insensitive-dedup")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Admin\nlogin\n", loggerBuffer.String())
+}
+
+func TestDictionaryMergeCommandShouldErrWhenNoFileIsProvided(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.merge")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no url or path provided")
+}
+
+func TestDictionaryLintCommand(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dictionaryPath := test.MustWriteTempFile(t, []byte("admin\n\nmy file\nadmin\nlogin\n"))
+	defer removeTempFile(dictionaryPath)
+
+	testFilePath := "testdata/" + test.RandStringRunes(10)
+	defer removeTestFile(testFilePath)
+
+	err := executeCommand(c, "dictionary.lint", dictionaryPath, "-o", testFilePath)
+	assert.NoError(t, err)
+
+	expectedReport := `Entries: 4
+Blank lines: 1
+Duplicates: 1
+Entries containing spaces: 1
+Entries containing control characters: 0
+Entries longer than 256 characters: 0
+`
+	assert.Equal(t, expectedReport, loggerBuffer.String())
+
+	//nolint:gosec
+	content, err := ioutil.ReadFile(testFilePath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "admin\nlogin\n", string(content))
+}
+
+func TestDictionaryLintCommandInStrictModeShouldErrWhenIssuesAreFound(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dictionaryPath := test.MustWriteTempFile(t, []byte("admin\nadministrator\n"))
+	defer removeTempFile(dictionaryPath)
+
+	err := executeCommand(c, "dictionary.lint", dictionaryPath, "--strict", "--max-length", "5")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1 issues found in the dictionary")
+
+	assert.Contains(t, loggerBuffer.String(), "Entries longer than 5 characters: 1")
+}
+
+func TestDictionaryLintCommandInStrictModeShouldNotErrForAValidDictionary(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dictionaryPath := test.MustWriteTempFile(t, []byte("# comment\nadmin\nlogin\n"))
+	defer removeTempFile(dictionaryPath)
+
+	err := executeCommand(c, "dictionary.lint", dictionaryPath, "--strict")
+	assert.NoError(t, err)
+}
+
+func TestDictionaryLintCommandShouldErrWhenNoFileIsProvided(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "dictionary.lint")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no url or path provided")
+}
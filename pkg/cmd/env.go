@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// envPrefix is the prefix of the environment variables setting the flags of the scan, EG DIRSTALK_USER_AGENT
+// sets --user-agent
+const envPrefix = "DIRSTALK_"
+
+// noColorEnvVariable disables the colors when set to any value, as for several other tools
+const noColorEnvVariable = "NO_COLOR"
+
+// loadEnvironment sets the flags not specified via the command line to the values of the corresponding
+// environment variables, if set
+func loadEnvironment(cmd *cobra.Command) error {
+	flags := make([]string, 0)
+
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		// the scan spec is not loaded from the environment, as it excludes the environment
+		if cmd.InheritedFlags().Lookup(f.Name) == nil && f.Name != flagScanSpec {
+			flags = append(flags, f.Name)
+		}
+	})
+
+	for _, flag := range flags {
+		if err := setFlagFromEnv(cmd, flag, envVariableForFlag(flag)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setFlagFromEnv(cmd *cobra.Command, flag, variable string) error {
+	value, found := os.LookupEnv(variable)
+	if !found || isFlagSet(cmd, flag) {
+		return nil
+	}
+
+	return errors.Wrapf(cmd.Flags().Set(flag, value), "invalid value for %s", variable)
+}
+
+func envVariableForFlag(flag string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(flag, "-", "_", -1))
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/pkg/errors"
+)
+
+// The exit codes of dirstalk
+const (
+	// ExitCodeSuccess is returned when the command completes (and, with --exit-on-match, nothing is found)
+	ExitCodeSuccess = 0
+	// ExitCodeMatchesFound is returned when the scan finds some result and --exit-on-match is specified
+	ExitCodeMatchesFound = 1
+	// ExitCodeError is returned when the command fails
+	ExitCodeError = 2
+)
+
+// ErrMatchesFound is returned by the scan command when some result is found and --exit-on-match is specified,
+// it is not a failure of the command
+var ErrMatchesFound = errors.New("the scan found some result")
+
+// ExitCode returns the exit code for the error returned by a command
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitCodeSuccess
+	case errors.Cause(err) == ErrMatchesFound:
+		return ExitCodeMatchesFound
+	default:
+		return ExitCodeError
+	}
+}
@@ -0,0 +1,18 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, cmd.ExitCode(nil))
+	assert.Equal(t, 1, cmd.ExitCode(cmd.ErrMatchesFound))
+	assert.Equal(t, 1, cmd.ExitCode(errors.Wrap(cmd.ErrMatchesFound, "scan completed")))
+	assert.Equal(t, 2, cmd.ExitCode(errors.New("failed to build config")))
+}
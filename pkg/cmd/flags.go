@@ -4,30 +4,148 @@ const (
 	// Root flags
 	flagRootVerbose      = "verbose"
 	flagRootVerboseShort = "v"
+	flagRootNoBanner     = "no-banner"
+	flagRootNoColor      = "no-color"
 
 	// Scan flags
-	flagScanDictionary                      = "dictionary"
-	flagScanDictionaryShort                 = "d"
-	flagScanDictionaryGetTimeout            = "dictionary-get-timeout"
-	flagScanHTTPMethods                     = "http-methods"
-	flagScanHTTPStatusesToIgnore            = "http-statuses-to-ignore"
-	flagScanHTTPTimeout                     = "http-timeout"
-	flagScanHTTPCacheRequests               = "http-cache-requests"
-	flagScanScanDepth                       = "scan-depth"
-	flagScanThreads                         = "threads"
-	flagScanThreadsShort                    = "t"
-	flagScanSocks5Host                      = "socks5"
-	flagScanUserAgent                       = "user-agent"
-	flagScanCookieJar                       = "use-cookie-jar"
-	flagScanCookie                          = "cookie"
-	flagScanHeader                          = "header"
-	flagScanResultOutput                    = "out"
-	flagShouldSkipSSLCertificatesValidation = "no-check-certificate"
+	flagScanDictionary                           = "dictionary"
+	flagScanVHostDictionary                      = "vhost-dictionary"
+	flagScanRequestTemplate                      = "request-template"
+	flagScanSecondDictionary                     = "dictionary-2"
+	flagScanTemplateMode                         = "mode"
+	flagScanShuffle                              = "shuffle"
+	flagScanSeed                                 = "seed"
+	flagScanDictionaryShort                      = "d"
+	flagScanDictionaryGetTimeout                 = "dictionary-get-timeout"
+	flagScanExtension                            = "extension"
+	flagScanExtensionShort                       = "x"
+	flagScanAppendQuery                          = "append-query"
+	flagScanTrailingSlash                        = "trailing-slash"
+	flagScanHTTPMethods                          = "http-methods"
+	flagScanHTTPStatusesToIgnore                 = "http-statuses-to-ignore"
+	flagScanHeadFirst                            = "head-first"
+	flagScanIncludeStatus                        = "include-status"
+	flagScanExcludeStatus                        = "exclude-status"
+	flagScanFilterSize                           = "filter-size"
+	flagScanFilterSizeRange                      = "filter-size-range"
+	flagScanMatchRegex                           = "match-regex"
+	flagScanMaxBodySize                          = "max-body-size"
+	flagScanMatchHeader                          = "match-header"
+	flagScanMatchContentType                     = "match-content-type"
+	flagScanExcludeContentType                   = "exclude-content-type"
+	flagScanFilterTimeAbove                      = "filter-time-above"
+	flagScanDedupResults                         = "dedup-results"
+	flagScanHTTPTimeout                          = "http-timeout"
+	flagScanTimeout                              = "timeout"
+	flagScanHTTPCacheRequests                    = "http-cache-requests"
+	flagScanFollowRedirects                      = "follow-redirects"
+	flagScanMaxRedirects                         = "max-redirects"
+	flagScanScanDepth                            = "scan-depth"
+	flagScanRecursionDepth                       = "recursion-depth"
+	flagScanRecursionStrategy                    = "recursion-strategy"
+	flagScanThreads                              = "threads"
+	flagScanThreadsShort                         = "t"
+	flagScanPerHostThreads                       = "per-host-threads"
+	flagScanMaxIdleConns                         = "max-idle-conns"
+	flagScanMaxConnsPerHost                      = "max-conns-per-host"
+	flagScanRate                                 = "rate"
+	flagScanDelay                                = "delay"
+	flagScanNoAdaptiveThrottle                   = "no-adaptive-throttle"
+	flagScanJitter                               = "jitter"
+	flagScanRetries                              = "retries"
+	flagScanRetryWait                            = "retry-wait"
+	flagScanSocks5Host                           = "socks5"
+	flagScanHTTPProxy                            = "http-proxy"
+	flagScanReplayProxy                          = "replay-proxy"
+	flagScanUserAgent                            = "user-agent"
+	flagScanRandomUserAgent                      = "random-user-agent"
+	flagScanUserAgentFile                        = "user-agent-file"
+	flagScanCookieJar                            = "use-cookie-jar"
+	flagScanCookie                               = "cookie"
+	flagScanCookieFile                           = "cookie-file"
+	flagScanHeader                               = "header"
+	flagScanHostHeader                           = "host-header"
+	flagScanBasicAuth                            = "basic-auth"
+	flagScanNTLMUser                             = "ntlm-user"
+	flagScanNTLMPassword                         = "ntlm-password"
+	flagScanNTLMDomain                           = "ntlm-domain"
+	flagScanBearerToken                          = "bearer-token"
+	flagScanBearerTokenFile                      = "bearer-token-file"
+	flagScanBody                                 = "body"
+	flagScanBodyFile                             = "body-file"
+	flagScanResultOutput                         = "out"
+	flagScanResultOutputJSON                     = "out-json"
+	flagScanResultOutputCSV                      = "out-csv"
+	flagScanResultOutputHTML                     = "out-html"
+	flagScanResultOutputJSONLines                = "jsonl"
+	flagScanWebhookURL                           = "webhook-url"
+	flagScanWebhookStatus                        = "webhook-status"
+	flagScanSlackWebhook                         = "slack-webhook"
+	flagScanSaveResponses                        = "save-responses"
+	flagScanMaxSaveBytes                         = "max-save-bytes"
+	flagScanResumeFrom                           = "resume-from"
+	flagScanNoProgress                           = "no-progress"
+	flagScanProgressInterval                     = "progress-interval"
+	flagScanQuiet                                = "quiet"
+	flagScanQuietShort                           = "q"
+	flagScanTargetsFile                          = "targets-file"
+	flagScanStdinTargets                         = "stdin-targets"
+	flagShouldSkipSSLCertificatesValidation      = "no-check-certificate"
+	flagShouldSkipSSLCertificatesValidationShort = "k"
+	flagScanInsecure                             = "insecure"
+	flagScanNoTLSVerifyHostname                  = "no-tls-verify-hostname"
+	flagScanClientCertificate                    = "client-cert"
+	flagScanClientKey                            = "client-key"
+	flagScanCACertificate                        = "ca-cert"
+	flagScanHTTP2                                = "http2"
+	flagScanNoHTTP2                              = "no-http2"
+	flagScanResolver                             = "resolver"
+	flagScanPreferIPv6                           = "prefer-ipv6"
+	flagScanNoWildcardDetection                  = "no-wildcard-detection"
+	flagScanBaselineRequest                      = "baseline-request"
+	flagScanDiffThreshold                        = "diff-threshold"
+	flagScanOnWAF                                = "on-waf"
+	flagScanWAFWindow                            = "waf-window"
+	flagScanWAFThreshold                         = "waf-threshold"
+	flagScanWAFPause                             = "waf-pause"
+	flagScanScope                                = "scope"
+	flagScanScopeDomain                          = "scope-domain"
+	flagScanExcludePath                          = "exclude-path"
+	flagScanProbeBackups                         = "probe-backups"
+	flagScanBackupSuffixes                       = "backup-suffixes"
+	flagScanDryRun                               = "dry-run"
+	flagScanYes                                  = "yes"
+	flagScanMaxRequests                          = "max-requests"
+	flagScanMaxDuration                          = "max-duration"
+	flagScanExitOnMatch                          = "exit-on-match"
+	flagScanWatchInterval                        = "watch-interval"
+	flagScanWatchDiff                            = "watch-diff"
+	flagScanConfig                               = "config"
+	flagScanSpec                                 = "scan-spec"
 
 	// Generate dictionary flags
 	flagDictionaryGenerateOutput           = "out"
 	flagDictionaryGenerateOutputShort      = "o"
+	flagDictionaryGenerateOutputFormat     = "out-format"
 	flagDictionaryGenerateAbsolutePathOnly = "absolute-only"
+	flagDictionaryGenerateMangle           = "mangle"
+	flagDictionaryGenerateIgnoreDir        = "ignore-dir"
+	flagDictionaryGenerateIgnoreExt        = "ignore-ext"
+	flagDictionaryGenerateNoDefaultIgnores = "no-default-ignores"
+	flagDictionaryGenerateRespectGitignore = "respect-gitignore"
+	flagDictionaryGenerateMaxDepth         = "max-depth"
+	flagDictionaryGenerateFollowSymlinks   = "follow-symlinks"
+	flagDictionaryCaseInsensitiveDedup     = "case-insensitive-dedup"
+
+	// Flags of the dictionary commands retrieving remote files
+	flagDictionaryHTTPTimeout = "http-timeout"
+
+	// Merge dictionary flags
+	flagDictionaryMergePreserveFormat = "preserve-format"
+
+	// Lint dictionary flags
+	flagDictionaryLintStrict    = "strict"
+	flagDictionaryLintMaxLength = "max-length"
 
 	// Result view flags
 	flagResultViewResultFile      = "result-file"
@@ -38,4 +156,11 @@ const (
 	flagResultDiffFirstFileShort  = "f"
 	flagResultDiffSecondFile      = "second"
 	flagResultDiffSecondFileShort = "s"
+
+	// Scan diff flags
+	flagScanDiffFirstFile       = "first"
+	flagScanDiffFirstFileShort  = "f"
+	flagScanDiffSecondFile      = "second"
+	flagScanDiffSecondFileShort = "s"
+	flagScanDiffOutputFormat    = "out-format"
 )
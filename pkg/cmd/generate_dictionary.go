@@ -4,17 +4,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stefanoj3/dirstalk/pkg/dictionary"
 )
 
-func NewGenerateDictionaryCommand(out io.Writer) *cobra.Command {
+func NewGenerateDictionaryCommand(logger *logrus.Logger) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "dictionary.generate [path]",
 		Short: "Generate a dictionary from the given folder",
-		RunE:  buildGenerateDictionaryFunc(out),
+		RunE:  buildGenerateDictionaryFunc(logger),
 	}
 
 	cmd.Flags().StringP(
@@ -24,6 +27,16 @@ func NewGenerateDictionaryCommand(out io.Writer) *cobra.Command {
 		fmt.Sprintf("where to write the dictionary"),
 	)
 
+	cmd.Flags().String(
+		flagDictionaryGenerateOutputFormat,
+		dictionary.OutputFormatText,
+		fmt.Sprintf(
+			"format of the dictionary (%s): text writes an entry per line, "+
+				"json writes a JSON object per line with the entry (word) and the path where it was found (source)",
+			strings.Join(dictionary.OutputFormats, ", "),
+		),
+	)
+
 	cmd.Flags().BoolP(
 		flagDictionaryGenerateAbsolutePathOnly,
 		"",
@@ -31,17 +44,71 @@ func NewGenerateDictionaryCommand(out io.Writer) *cobra.Command {
 		"determines if the dictionary should contain only the absolute path of the files",
 	)
 
+	cmd.Flags().StringSlice(
+		flagDictionaryGenerateIgnoreDir,
+		[]string{},
+		"comma separated list of names of folders to skip, together with their content (EG node_modules)",
+	)
+
+	cmd.Flags().StringSlice(
+		flagDictionaryGenerateIgnoreExt,
+		[]string{},
+		"comma separated list of extensions of the files to skip (EG png,exe)",
+	)
+
+	cmd.Flags().Bool(
+		flagDictionaryGenerateNoDefaultIgnores,
+		false,
+		fmt.Sprintf(
+			"do not skip the folders skipped by default (%s)",
+			strings.Join(dictionary.DefaultIgnoredDirectories, ","),
+		),
+	)
+
+	cmd.Flags().Bool(
+		flagDictionaryGenerateRespectGitignore,
+		false,
+		"skip the files and folders ignored by the .gitignore files found in the folder",
+	)
+
+	cmd.Flags().Int(
+		flagDictionaryGenerateMaxDepth,
+		-1,
+		"how many folders deep to descend into the path, 0 lists only its entries (a negative value means no limit)",
+	)
+
+	cmd.Flags().Bool(
+		flagDictionaryGenerateFollowSymlinks,
+		false,
+		"follow the symlinks, apart from the ones pointing to a folder containing them (use -v to log the skipped ones)",
+	)
+
+	cmd.Flags().StringSlice(
+		flagDictionaryGenerateMangle,
+		[]string{},
+		fmt.Sprintf(
+			"comma separated list of rules to produce the variants of each entry (available: %s)",
+			strings.Join(dictionary.MangleRules, ","),
+		),
+	)
+
+	cmd.Flags().Bool(
+		flagDictionaryCaseInsensitiveDedup,
+		false,
+		"remove the entries differing from a previous one only by case (applied after the mangling)",
+	)
+
 	return cmd
 }
 
-func buildGenerateDictionaryFunc(out io.Writer) func(cmd *cobra.Command, args []string) error {
+func buildGenerateDictionaryFunc(logger *logrus.Logger) func(cmd *cobra.Command, args []string) error {
 	f := func(cmd *cobra.Command, args []string) error {
 		p, err := getPath(args)
 		if err != nil {
 			return err
 		}
 
-		out, err := getOutputForDictionaryGenerator(cmd, out)
+		out, err := getOutputForDictionaryGenerator(cmd, logger.Out)
 		if err != nil {
 			return err
 		}
@@ -51,7 +118,67 @@ func buildGenerateDictionaryFunc(out io.Writer) func(cmd *cobra.Command, args []
 			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateAbsolutePathOnly)
 		}
 
-		generator := dictionary.NewGenerator(out)
+		format, err := cmd.Flags().GetString(flagDictionaryGenerateOutputFormat)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateOutputFormat)
+		}
+
+		if !dictionary.IsValidOutputFormat(format) {
+			return errors.Errorf(
+				"invalid value for %s: %s, the available ones are: %s",
+				flagDictionaryGenerateOutputFormat,
+				format,
+				strings.Join(dictionary.OutputFormats, ", "),
+			)
+		}
+
+		generator := dictionary.NewGenerator(out).WithOutputFormat(format).WithLogger(logger)
+
+		mangleRules, err := cmd.Flags().GetStringSlice(flagDictionaryGenerateMangle)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateMangle)
+		}
+
+		if len(mangleRules) > 0 {
+			if absolutePathOnly {
+				return errors.Errorf(
+					"%s and %s cannot be used at the same time",
+					flagDictionaryGenerateAbsolutePathOnly,
+					flagDictionaryGenerateMangle,
+				)
+			}
+
+			mangler, err := dictionary.NewMangler(mangleRules, time.Now().Year())
+			if err != nil {
+				return err
+			}
+
+			generator.WithMangler(mangler)
+		}
+
+		if err := setCaseInsensitiveDedup(cmd, generator); err != nil {
+			return err
+		}
+
+		if err := setIgnores(cmd, generator); err != nil {
+			return err
+		}
+
+		maxDepth, err := cmd.Flags().GetInt(flagDictionaryGenerateMaxDepth)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateMaxDepth)
+		}
+
+		generator.WithMaxDepth(maxDepth)
+
+		followSymlinks, err := cmd.Flags().GetBool(flagDictionaryGenerateFollowSymlinks)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateFollowSymlinks)
+		}
+
+		if followSymlinks {
+			generator.WithFollowSymlinks()
+		}
 
 		return generator.GenerateDictionaryFrom(p, absolutePathOnly)
 	}
@@ -59,6 +186,53 @@ func buildGenerateDictionaryFunc(out io.Writer) func(cmd *cobra.Command, args []
 	return f
 }
 
+func setCaseInsensitiveDedup(cmd *cobra.Command, generator *dictionary.Generator) error {
+	caseInsensitiveDedup, err := cmd.Flags().GetBool(flagDictionaryCaseInsensitiveDedup)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryCaseInsensitiveDedup)
+	}
+
+	if caseInsensitiveDedup {
+		generator.WithCaseInsensitiveDedup()
+	}
+
+	return nil
+}
+
+func setIgnores(cmd *cobra.Command, generator *dictionary.Generator) error {
+	ignoredDirectories, err := cmd.Flags().GetStringSlice(flagDictionaryGenerateIgnoreDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateIgnoreDir)
+	}
+
+	ignoredExtensions, err := cmd.Flags().GetStringSlice(flagDictionaryGenerateIgnoreExt)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateIgnoreExt)
+	}
+
+	noDefaultIgnores, err := cmd.Flags().GetBool(flagDictionaryGenerateNoDefaultIgnores)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateNoDefaultIgnores)
+	}
+
+	respectGitignore, err := cmd.Flags().GetBool(flagDictionaryGenerateRespectGitignore)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryGenerateRespectGitignore)
+	}
+
+	if !noDefaultIgnores {
+		generator.WithIgnoredDirectories(dictionary.DefaultIgnoredDirectories)
+	}
+
+	if respectGitignore {
+		generator.WithGitignore()
+	}
+
+	generator.WithIgnoredDirectories(ignoredDirectories).WithIgnoredExtensions(ignoredExtensions)
+
+	return nil
+}
+
 func getOutputForDictionaryGenerator(cmd *cobra.Command, out io.Writer) (io.Writer, error) {
 	output := cmd.Flag(flagDictionaryGenerateOutput).Value.String()
 	if output == "" {
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+)
+
+func NewGenerateDictionaryFromJavascriptCommand(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dictionary.js [url|path...]",
+		Short: "Generate a dictionary from the paths found in the given javascript files (urls or local files)",
+		RunE:  buildGenerateDictionaryFromJavascriptFunc(out),
+	}
+
+	cmd.Flags().StringP(
+		flagDictionaryGenerateOutput,
+		flagDictionaryGenerateOutputShort,
+		"",
+		"where to write the dictionary",
+	)
+
+	cmd.Flags().Int(
+		flagDictionaryHTTPTimeout,
+		5000,
+		"timeout in milliseconds for the requests to retrieve the files",
+	)
+
+	return cmd
+}
+
+func buildGenerateDictionaryFromJavascriptFunc(out io.Writer) func(cmd *cobra.Command, args []string) error {
+	f := func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("no url or path provided")
+		}
+
+		doer, err := getDoerForDictionaryGenerator(cmd)
+		if err != nil {
+			return err
+		}
+
+		out, err := getOutputForDictionaryGenerator(cmd, out)
+		if err != nil {
+			return err
+		}
+
+		return dictionary.NewGenerator(out).GenerateDictionaryFromJavascript(args, doer)
+	}
+
+	return f
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+)
+
+func NewMergeDictionariesCommand(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dictionary.merge [url|path...]",
+		Short: "Merge the given dictionaries (urls or local files) into a sorted dictionary without duplicates",
+		RunE:  buildMergeDictionariesFunc(out),
+	}
+
+	cmd.Flags().StringP(
+		flagDictionaryGenerateOutput,
+		flagDictionaryGenerateOutputShort,
+		"",
+		"where to write the dictionary",
+	)
+
+	cmd.Flags().Bool(
+		flagDictionaryMergePreserveFormat,
+		false,
+		"keep the entries as they are, by default the leading slashes and the trailing whitespaces are removed",
+	)
+
+	cmd.Flags().Bool(
+		flagDictionaryCaseInsensitiveDedup,
+		false,
+		"remove the entries differing from a previous one only by case, the first occurrence is kept",
+	)
+
+	cmd.Flags().Int(
+		flagDictionaryHTTPTimeout,
+		5000,
+		"timeout in milliseconds for the requests to retrieve the files",
+	)
+
+	return cmd
+}
+
+func buildMergeDictionariesFunc(out io.Writer) func(cmd *cobra.Command, args []string) error {
+	f := func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("no url or path provided")
+		}
+
+		preserveFormat, err := cmd.Flags().GetBool(flagDictionaryMergePreserveFormat)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryMergePreserveFormat)
+		}
+
+		doer, err := getDoerForDictionaryGenerator(cmd)
+		if err != nil {
+			return err
+		}
+
+		out, err := getOutputForDictionaryGenerator(cmd, out)
+		if err != nil {
+			return err
+		}
+
+		generator := dictionary.NewGenerator(out)
+
+		if err := setCaseInsensitiveDedup(cmd, generator); err != nil {
+			return err
+		}
+
+		return generator.MergeDictionaries(args, preserveFormat, doer)
+	}
+
+	return f
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+)
+
+const (
+	robotsPath  = "/robots.txt"
+	sitemapPath = "/sitemap.xml"
+)
+
+// generateFromRemoteFunc generates the dictionary from the file at the given path (a url or a local file)
+type generateFromRemoteFunc func(generator *dictionary.Generator, path string, doer dictionary.Doer) error
+
+func NewGenerateDictionaryFromRobotsCommand(out io.Writer) *cobra.Command {
+	return newGenerateDictionaryFromRemoteCommand(
+		&cobra.Command{
+			Use:   "dictionary.robots [url|path]",
+			Short: "Generate a dictionary from the paths listed in the robots.txt of the given url (or a local file)",
+		},
+		out,
+		robotsPath,
+		(*dictionary.Generator).GenerateDictionaryFromRobots,
+	)
+}
+
+func NewGenerateDictionaryFromSitemapCommand(out io.Writer) *cobra.Command {
+	return newGenerateDictionaryFromRemoteCommand(
+		&cobra.Command{
+			Use:   "dictionary.sitemap [url|path]",
+			Short: "Generate a dictionary from the locations listed in the sitemap of the given url (or a local file)",
+		},
+		out,
+		sitemapPath,
+		(*dictionary.Generator).GenerateDictionaryFromSitemap,
+	)
+}
+
+// newGenerateDictionaryFromRemoteCommand completes the command with the flags and the function to generate
+// a dictionary from a file usually available at defaultPath on the target
+func newGenerateDictionaryFromRemoteCommand(
+	cmd *cobra.Command,
+	out io.Writer,
+	defaultPath string,
+	generate generateFromRemoteFunc,
+) *cobra.Command {
+	cmd.RunE = buildGenerateDictionaryFromRemoteFunc(out, defaultPath, generate)
+
+	cmd.Flags().StringP(
+		flagDictionaryGenerateOutput,
+		flagDictionaryGenerateOutputShort,
+		"",
+		"where to write the dictionary",
+	)
+
+	cmd.Flags().Int(
+		flagDictionaryHTTPTimeout,
+		5000,
+		"timeout in milliseconds for the requests to retrieve the files",
+	)
+
+	return cmd
+}
+
+func buildGenerateDictionaryFromRemoteFunc(
+	out io.Writer,
+	defaultPath string,
+	generate generateFromRemoteFunc,
+) func(cmd *cobra.Command, args []string) error {
+	f := func(cmd *cobra.Command, args []string) error {
+		p, err := getRemotePath(args, defaultPath)
+		if err != nil {
+			return err
+		}
+
+		doer, err := getDoerForDictionaryGenerator(cmd)
+		if err != nil {
+			return err
+		}
+
+		out, err := getOutputForDictionaryGenerator(cmd, out)
+		if err != nil {
+			return err
+		}
+
+		return generate(dictionary.NewGenerator(out), p, doer)
+	}
+
+	return f
+}
+
+func getDoerForDictionaryGenerator(cmd *cobra.Command) (dictionary.Doer, error) {
+	timeout, err := cmd.Flags().GetInt(flagDictionaryHTTPTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryHTTPTimeout)
+	}
+
+	if timeout <= 0 {
+		return nil, errors.Errorf("%s must be greater than 0", flagDictionaryHTTPTimeout)
+	}
+
+	return &http.Client{Timeout: time.Duration(timeout) * time.Millisecond}, nil
+}
+
+// getRemotePath returns the url of the file at defaultPath on the target, unless the url provided
+// has already a path, or the path of the local file
+func getRemotePath(args []string, defaultPath string) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("no url or path provided")
+	}
+
+	p := args[0]
+	if !strings.HasPrefix(p, "http") {
+		return p, nil
+	}
+
+	u, err := url.ParseRequestURI(p)
+	if err != nil {
+		return "", errors.Wrap(err, "the first argument must be a valid url")
+	}
+
+	if strings.Trim(u.Path, "/") == "" {
+		u = u.ResolveReference(&url.URL{Path: defaultPath})
+	}
+
+	return u.String(), nil
+}
@@ -0,0 +1,53 @@
+package keyboard
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// pollInterval is how often Listen checks whether it has to return while no key is pressed
+const pollInterval = 100 * time.Millisecond
+
+// Listen invokes the handler of each key pressed on the terminal until ctx is done. Meanwhile the terminal is in
+// raw mode, so that the keys are read as soon as they are pressed and they are not echoed; the signals (EG ctrl+c)
+// are still delivered. It fails when in is not a terminal or when raw mode is not supported by the platform.
+func Listen(ctx context.Context, in *os.File, handlers map[byte]func()) error {
+	fd := int(in.Fd())
+
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return err
+	}
+
+	defer restore()
+
+	key := make([]byte, 1)
+
+	for ctx.Err() == nil {
+		ready, err := waitForInput(fd, pollInterval)
+		if err != nil {
+			return err
+		}
+
+		if !ready {
+			continue
+		}
+
+		n, err := in.Read(key)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if handler, ok := handlers[key[0]]; ok && n == 1 {
+			handler()
+		}
+	}
+
+	return nil
+}
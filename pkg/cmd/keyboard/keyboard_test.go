@@ -0,0 +1,21 @@
+package keyboard_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/cmd/keyboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenShouldFailWhenNotReadingFromATerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	defer r.Close() //nolint:errcheck
+	defer w.Close() //nolint:errcheck
+
+	err = keyboard.Listen(context.Background(), r, map[byte]func(){'p': func() {}})
+	assert.Error(t, err)
+}
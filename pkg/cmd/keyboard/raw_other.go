@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package keyboard
+
+import (
+	"errors"
+	"time"
+)
+
+func makeRaw(int) (func(), error) {
+	return nil, errors.New("the raw mode of the terminal is not supported on this platform")
+}
+
+func waitForInput(int, time.Duration) (bool, error) {
+	return false, errors.New("reading the keyboard is not supported on this platform")
+}
@@ -0,0 +1,48 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package keyboard
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// makeRaw disables the canonical mode and the echo of the terminal, leaving the signals enabled; the returned
+// function restores the previous state
+func makeRaw(fd int) (func(), error) {
+	original, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *original
+	raw.Lflag &^= unix.ICANON | unix.ECHO
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = unix.IoctlSetTermios(fd, ioctlSetTermios, original)
+	}, nil
+}
+
+// waitForInput returns true as soon as fd can be read, false when nothing can be read within timeout
+func waitForInput(fd int, timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+
+	n, err := unix.Poll(fds, int(timeout/time.Millisecond))
+	if err == unix.EINTR {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+)
+
+func NewLintDictionaryCommand(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dictionary.lint [url|path]",
+		Short: "Report the issues of the given dictionary (url or local file) and optionally write a cleaned version",
+		RunE:  buildLintDictionaryFunc(out),
+	}
+
+	cmd.Flags().StringP(
+		flagDictionaryGenerateOutput,
+		flagDictionaryGenerateOutputShort,
+		"",
+		"where to write the cleaned dictionary",
+	)
+
+	cmd.Flags().Bool(
+		flagDictionaryLintStrict,
+		false,
+		"fail if any issue is found",
+	)
+
+	cmd.Flags().Int(
+		flagDictionaryLintMaxLength,
+		256,
+		"entries longer than this amount of characters are reported",
+	)
+
+	cmd.Flags().Int(
+		flagDictionaryHTTPTimeout,
+		5000,
+		"timeout in milliseconds for the requests to retrieve the files",
+	)
+
+	return cmd
+}
+
+func buildLintDictionaryFunc(out io.Writer) func(cmd *cobra.Command, args []string) error {
+	f := func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("no url or path provided")
+		}
+
+		strict, err := cmd.Flags().GetBool(flagDictionaryLintStrict)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryLintStrict)
+		}
+
+		maxLength, err := cmd.Flags().GetInt(flagDictionaryLintMaxLength)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve %s flag", flagDictionaryLintMaxLength)
+		}
+
+		if maxLength <= 0 {
+			return errors.Errorf("%s must be greater than 0", flagDictionaryLintMaxLength)
+		}
+
+		doer, err := getDoerForDictionaryGenerator(cmd)
+		if err != nil {
+			return err
+		}
+
+		report, err := dictionary.Lint(args[0], doer, maxLength)
+		if err != nil {
+			return errors.Wrap(err, "failed to lint dictionary")
+		}
+
+		if _, err := fmt.Fprint(out, lintReportAsString(report, maxLength)); err != nil {
+			return errors.Wrap(err, "failed to write the report")
+		}
+
+		if cmd.Flag(flagDictionaryGenerateOutput).Value.String() != "" {
+			if err := writeCleanedDictionary(cmd, out, report.Cleaned); err != nil {
+				return err
+			}
+		}
+
+		if strict && report.IssuesCount() > 0 {
+			return errors.Errorf("%d issues found in the dictionary", report.IssuesCount())
+		}
+
+		return nil
+	}
+
+	return f
+}
+
+func writeCleanedDictionary(cmd *cobra.Command, out io.Writer, cleaned []string) error {
+	out, err := getOutputForDictionaryGenerator(cmd, out)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range cleaned {
+		if _, err := fmt.Fprintln(out, entry); err != nil {
+			return errors.Wrap(err, "failed to write the cleaned dictionary")
+		}
+	}
+
+	return nil
+}
+
+func lintReportAsString(report dictionary.LintReport, maxLength int) string {
+	return fmt.Sprintf(
+		"Entries: %d\n"+
+			"Blank lines: %d\n"+
+			"Duplicates: %d\n"+
+			"Entries containing spaces: %d\n"+
+			"Entries containing control characters: %d\n"+
+			"Entries longer than %d characters: %d\n",
+		report.Entries,
+		report.BlankLines,
+		report.Duplicates,
+		report.WithSpaces,
+		report.WithControlCharacters,
+		maxLength,
+		report.TooLong,
+	)
+}
@@ -1,8 +1,17 @@
 package cmd
 
-import "github.com/stefanoj3/dirstalk/pkg/scan"
+import (
+	"net/url"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
 
 type OutputSaver interface {
 	Save(scan.Result) error
 	Close() error
 }
+
+type Notifier interface {
+	Notify(target url.URL, r scan.Result)
+	Close()
+}
@@ -0,0 +1,136 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// clearLine moves the cursor to the beginning of the line and erases it
+const clearLine = "\r\033[K"
+
+// UnknownTotal is the total of a bar whose amount of targets is not known yet (see Bar.SetTotal)
+const UnknownTotal int64 = -1
+
+// IsTerminal returns true when the writer is a terminal, the progress bar makes sense only in that case
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func NewBar(out io.Writer, total int64, requestsCount func() int64) *Bar {
+	return &Bar{
+		out:     out,
+		tracker: NewTracker(total, requestsCount),
+	}
+}
+
+// Bar renders the progress of the scan on the last line of the output; everything else written to the output
+// should go through the bar (it implements io.Writer), so that it doesn't get mixed with the progress line
+type Bar struct {
+	mx      sync.Mutex
+	out     io.Writer
+	tracker *Tracker
+}
+
+// Increment records a target as completed
+func (b *Bar) Increment() {
+	b.tracker.Increment()
+}
+
+// SetTotal sets the amount of targets to complete, EG once it has been counted
+func (b *Bar) SetTotal(total int64) {
+	b.tracker.SetTotal(total)
+}
+
+// Write writes p to the output, the progress line is cleared before and rendered again after it
+func (b *Bar) Write(p []byte) (int, error) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if _, err := io.WriteString(b.out, clearLine); err != nil {
+		return 0, err
+	}
+
+	n, err := b.out.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	b.render()
+
+	return n, nil
+}
+
+// Start renders the progress periodically until the returned function is invoked, the progress line is
+// cleared before returning from it
+func (b *Bar) Start(interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				b.mx.Lock()
+				b.render()
+				b.mx.Unlock()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+
+		b.mx.Lock()
+		defer b.mx.Unlock()
+
+		_, _ = io.WriteString(b.out, clearLine)
+	}
+}
+
+// render must be invoked holding the lock
+func (b *Bar) render() {
+	snapshot := b.tracker.Snapshot()
+
+	total := "?"
+	if snapshot.Total != UnknownTotal {
+		total = strconv.FormatInt(snapshot.Total, 10)
+	}
+
+	eta := "-"
+	if snapshot.ETA >= 0 {
+		eta = snapshot.ETA.Round(time.Second).String()
+	}
+
+	_, _ = fmt.Fprintf(
+		b.out,
+		"%s%d/%s completed | %d requests | %.1f req/s | ETA %s",
+		clearLine,
+		snapshot.Completed,
+		total,
+		snapshot.Requests,
+		snapshot.Rate,
+		eta,
+	)
+}
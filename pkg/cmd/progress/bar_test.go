@@ -0,0 +1,83 @@
+package progress_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/cmd/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarShouldRenderTheProgressAfterEachWrite(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+
+	sut := progress.NewBar(out, 4, func() int64 { return 12 })
+	sut.Increment()
+
+	_, err := sut.Write([]byte("log line\n"))
+	assert.NoError(t, err)
+
+	rendered := out.String()
+
+	assert.True(t, strings.HasPrefix(rendered, "\r\033[Klog line\n\r\033[K1/4 completed | 12 requests | "))
+	assert.Contains(t, rendered, "req/s | ETA ")
+	assert.NotContains(t, rendered, "ETA -")
+}
+
+func TestBarShouldNotEstimateTheRemainingTimeBeforeCompletingATarget(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+
+	sut := progress.NewBar(out, 4, func() int64 { return 0 })
+
+	_, err := sut.Write([]byte("log line\n"))
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), "0/4 completed | 0 requests | 0.0 req/s | ETA -")
+}
+
+func TestBarShouldRenderAnUnknownTotalUntilItIsSet(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+
+	sut := progress.NewBar(out, progress.UnknownTotal, func() int64 { return 3 })
+	sut.Increment()
+
+	_, err := sut.Write([]byte("log line\n"))
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "1/? completed | 3 requests | ")
+	assert.Contains(t, out.String(), "ETA -")
+
+	sut.SetTotal(4)
+
+	_, err = sut.Write([]byte("log line\n"))
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "1/4 completed | 3 requests | ")
+}
+
+func TestBarShouldClearTheProgressWhenStopped(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+
+	sut := progress.NewBar(out, 1, func() int64 { return 0 })
+
+	stop := sut.Start(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	assert.Contains(t, out.String(), "0/1 completed")
+	assert.True(t, strings.HasSuffix(out.String(), "\r\033[K"))
+}
+
+func TestIsTerminalShouldBeFalseForNonFiles(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, progress.IsTerminal(&bytes.Buffer{}))
+}
@@ -0,0 +1,84 @@
+package progress
+
+import (
+	"sync"
+	"time"
+)
+
+func NewTracker(total int64, requestsCount func() int64) *Tracker {
+	return &Tracker{
+		total:         total,
+		requestsCount: requestsCount,
+		startedAt:     time.Now(),
+	}
+}
+
+// Tracker keeps track of the targets completed by the scan, it is safe for concurrent use
+type Tracker struct {
+	mx            sync.Mutex
+	total         int64
+	completed     int64
+	requestsCount func() int64
+	startedAt     time.Time
+}
+
+// Increment records a target as completed
+func (t *Tracker) Increment() {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.completed++
+}
+
+// SetTotal sets the amount of targets to complete, EG once it has been counted
+func (t *Tracker) SetTotal(total int64) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.total = total
+}
+
+// Snapshot returns the progress of the scan at this moment
+func (t *Tracker) Snapshot() Snapshot {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	elapsed := time.Since(t.startedAt)
+	requests := t.requestsCount()
+
+	snapshot := Snapshot{Completed: t.completed, Total: t.total, Requests: requests, ETA: -1}
+
+	if elapsed > 0 {
+		snapshot.Rate = float64(requests) / elapsed.Seconds()
+	}
+
+	if t.completed > 0 && t.completed <= t.total {
+		snapshot.ETA = time.Duration(float64(elapsed) / float64(t.completed) * float64(t.total-t.completed))
+	}
+
+	return snapshot
+}
+
+// Snapshot is the progress of the scan at a given moment
+type Snapshot struct {
+	Completed int64
+	// Total is UnknownTotal until the targets have been counted
+	Total    int64
+	Requests int64
+	// Rate is the amount of requests per second
+	Rate float64
+	// ETA is the estimated time to complete the remaining targets, negative when it cannot be estimated yet
+	ETA time.Duration
+}
+
+// Percentage returns the percentage of the targets completed, false when the total is not known
+func (s Snapshot) Percentage() (float64, bool) {
+	switch {
+	case s.Total == UnknownTotal:
+		return 0, false
+	case s.Total == 0:
+		return 100, true
+	default:
+		return float64(s.Completed) / float64(s.Total) * 100, true
+	}
+}
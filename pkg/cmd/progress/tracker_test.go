@@ -0,0 +1,40 @@
+package progress_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/cmd/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerShouldReturnTheProgressOfTheTargetsCompleted(t *testing.T) {
+	t.Parallel()
+
+	sut := progress.NewTracker(progress.UnknownTotal, func() int64 { return 12 })
+	sut.Increment()
+
+	snapshot := sut.Snapshot()
+	assert.Equal(t, int64(1), snapshot.Completed)
+	assert.Equal(t, int64(12), snapshot.Requests)
+	assert.True(t, snapshot.ETA < 0)
+
+	_, ok := snapshot.Percentage()
+	assert.False(t, ok)
+
+	sut.SetTotal(4)
+
+	snapshot = sut.Snapshot()
+	assert.True(t, snapshot.ETA >= 0)
+
+	percentage, ok := snapshot.Percentage()
+	assert.True(t, ok)
+	assert.Equal(t, 25.0, percentage)
+}
+
+func TestTrackerShouldBeCompleteWithoutTargets(t *testing.T) {
+	t.Parallel()
+
+	percentage, ok := progress.NewTracker(0, func() int64 { return 0 }).Snapshot().Percentage()
+	assert.True(t, ok)
+	assert.Equal(t, 100.0, percentage)
+}
@@ -6,25 +6,42 @@ import (
 )
 
 func NewRootCommand(logger *logrus.Logger) *cobra.Command {
-	var verbose bool
+	var verbosity int
 
 	cmd := &cobra.Command{
 		Use:   "dirstalk",
 		Short: "Stalk the given url trying to enumerate files and folders",
 		Long:  `dirstalk is a tool that attempts to enumerate files and folders starting from a given URL`,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			if verbose {
+			switch {
+			case verbosity >= 2:
+				logger.SetLevel(logrus.TraceLevel)
+			case verbosity == 1:
 				logger.SetLevel(logrus.DebugLevel)
 			}
 		},
 	}
 
-	cmd.PersistentFlags().BoolVarP(
-		&verbose,
+	cmd.PersistentFlags().CountVarP(
+		&verbosity,
 		flagRootVerbose,
 		flagRootVerboseShort,
+		"verbose mode, it logs each request performed and whether it is ignored; repeat it (-vv) to log "+
+			"the response headers too",
+	)
+
+	cmd.PersistentFlags().Bool(
+		flagRootNoBanner,
+		false,
+		"do not print the decorative output (EG the settings logged when a scan starts), the results and the "+
+			"summary are still printed",
+	)
+
+	cmd.PersistentFlags().Bool(
+		flagRootNoColor,
 		false,
-		"verbose mode",
+		"do not color the status codes printed (they are colored only when printed to a terminal), "+
+			"setting the NO_COLOR environment variable has the same effect",
 	)
 
 	return cmd
@@ -37,6 +37,55 @@ func TestVersionCommand(t *testing.T) {
 	assert.Contains(t, buf.String(), "Version: ")
 }
 
+func TestCompletionCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		shell := shell // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(shell, func(t *testing.T) {
+			logger, buf := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, "completion", shell)
+			assert.NoError(t, err)
+
+			// the completion should cover the flags of the scan command
+			assert.NotEmpty(t, buf.String())
+			assert.Contains(t, buf.String(), "scan")
+			assert.Contains(t, buf.String(), "dictionary")
+			assert.Contains(t, buf.String(), "max-duration")
+		})
+	}
+}
+
+func TestCompletionCommandShouldErrForUnknownShell(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "completion", "tcsh")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid argument")
+}
+
+func TestVerbosityShouldSetTheLogLevelForEveryCommand(t *testing.T) {
+	logger, _ := test.NewLogger()
+	logger.SetLevel(logrus.InfoLevel)
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "version")
+	assert.NoError(t, err)
+	assert.Equal(t, logrus.InfoLevel, logger.GetLevel())
+
+	err = executeCommand(c, "version", "-vv")
+	assert.NoError(t, err)
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+}
+
 func executeCommand(root *cobra.Command, args ...string) (err error) {
 	buf := new(bytes.Buffer)
 	root.SetOutput(buf)
@@ -49,6 +98,20 @@ func executeCommand(root *cobra.Command, args ...string) (err error) {
 	return err
 }
 
+// executeCommandWithOutput executes the command like executeCommand, returning what has been written
+// to the standard output of the command
+func executeCommandWithOutput(root *cobra.Command, args ...string) (string, error) {
+	buf := new(bytes.Buffer)
+	root.SetOutput(buf)
+
+	a := []string{""}
+	os.Args = append(a, args...) //nolint
+
+	_, err := root.ExecuteC()
+
+	return buf.String(), err
+}
+
 func removeTestFile(path string) {
 	if !strings.Contains(path, "testdata") {
 		return
@@ -63,8 +126,15 @@ func createCommand(logger *logrus.Logger) *cobra.Command {
 	dirStalkCmd.AddCommand(cmd.NewScanCommand(logger))
 	dirStalkCmd.AddCommand(cmd.NewResultViewCommand(logger.Out))
 	dirStalkCmd.AddCommand(cmd.NewResultDiffCommand(logger.Out))
-	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewScanDiffCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryCommand(logger))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryFromRobotsCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryFromSitemapCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryFromJavascriptCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewMergeDictionariesCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewLintDictionaryCommand(logger.Out))
 	dirStalkCmd.AddCommand(cmd.NewVersionCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewCompletionCommand(logger.Out))
 
 	return dirStalkCmd
 }
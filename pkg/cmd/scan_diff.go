@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/common"
+	"github.com/stefanoj3/dirstalk/pkg/result"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+)
+
+// The formats available to print the differences between two scans
+const (
+	// scanDiffFormatText prints a line per difference
+	scanDiffFormatText = "text"
+	// scanDiffFormatJSON prints the differences as a JSON object (see result.Diff)
+	scanDiffFormatJSON = "json"
+)
+
+var scanDiffFormats = []string{scanDiffFormatText, scanDiffFormatJSON}
+
+func NewScanDiffCommand(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan.diff",
+		Short: "Prints the results appeared, disappeared and changing status code between the JSON outputs of 2 scans",
+		RunE:  buildScanDiffCmd(out),
+	}
+
+	cmd.Flags().StringP(
+		flagScanDiffFirstFile,
+		flagScanDiffFirstFileShort,
+		"",
+		"JSON output (see scan --"+flagScanResultOutputJSON+") of the first scan",
+	)
+	common.Must(cmd.MarkFlagFilename(flagScanDiffFirstFile))
+	common.Must(cmd.MarkFlagRequired(flagScanDiffFirstFile))
+
+	cmd.Flags().StringP(
+		flagScanDiffSecondFile,
+		flagScanDiffSecondFileShort,
+		"",
+		"JSON output (see scan --"+flagScanResultOutputJSON+") of the second scan, compared to the first one",
+	)
+	common.Must(cmd.MarkFlagFilename(flagScanDiffSecondFile))
+	common.Must(cmd.MarkFlagRequired(flagScanDiffSecondFile))
+
+	cmd.Flags().String(
+		flagScanDiffOutputFormat,
+		scanDiffFormatText,
+		fmt.Sprintf(
+			"format of the differences (%s): text prints a line per difference, json prints a JSON object "+
+				"with the results appeared, disappeared and changing status code",
+			strings.Join(scanDiffFormats, ", "),
+		),
+	)
+
+	return cmd
+}
+
+func buildScanDiffCmd(out io.Writer) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString(flagScanDiffOutputFormat)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagScanDiffOutputFormat)
+		}
+
+		if format != scanDiffFormatText && format != scanDiffFormatJSON {
+			return errors.Errorf(
+				"invalid value for %s: %s, the available ones are: %s",
+				flagScanDiffOutputFormat,
+				format,
+				strings.Join(scanDiffFormats, ", "),
+			)
+		}
+
+		firstResultFilePath := cmd.Flag(flagScanDiffFirstFile).Value.String()
+
+		resultsFirst, err := result.LoadJSONResultsFromFile(firstResultFilePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load results from %s", firstResultFilePath)
+		}
+
+		secondResultFilePath := cmd.Flag(flagScanDiffSecondFile).Value.String()
+
+		resultsSecond, err := result.LoadJSONResultsFromFile(secondResultFilePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load results from %s", secondResultFilePath)
+		}
+
+		diff := result.NewDiff(resultsFirst, resultsSecond)
+
+		if format == scanDiffFormatJSON {
+			return errors.Wrap(json.NewEncoder(out).Encode(diff), "failed to print the differences")
+		}
+
+		return errors.Wrap(printScanDiff(out, diff), "failed to print the differences")
+	}
+}
+
+// printScanDiff prints a line per difference: + for the results appeared, - for the ones disappeared and ~ for
+// the ones changing status code
+func printScanDiff(out io.Writer, diff result.Diff) error {
+	if diff.IsEmpty() {
+		_, err := fmt.Fprintln(out, "No differences found")
+		return err
+	}
+
+	lines := make([]string, 0, len(diff.Appeared)+len(diff.Disappeared)+len(diff.StatusChanged))
+
+	for _, r := range diff.Appeared {
+		lines = append(lines, fmt.Sprintf("+ [%d] %s", r.StatusCode, scanDiffRequest(r)))
+	}
+
+	for _, r := range diff.Disappeared {
+		lines = append(lines, fmt.Sprintf("- [%d] %s", r.StatusCode, scanDiffRequest(r)))
+	}
+
+	for _, c := range diff.StatusChanged {
+		lines = append(lines, fmt.Sprintf("~ [%d -> %d] %s", c.Old.StatusCode, c.New.StatusCode, scanDiffRequest(c.New)))
+	}
+
+	lines = append(
+		lines,
+		fmt.Sprintf(
+			"%d appeared, %d disappeared, %d changed status code",
+			len(diff.Appeared),
+			len(diff.Disappeared),
+			len(diff.StatusChanged),
+		),
+	)
+
+	_, err := fmt.Fprintln(out, strings.Join(lines, "\n"))
+
+	return err
+}
+
+// scanDiffRequest describes the request of the result: the method, the url and the Host header or the words,
+// when they are set
+func scanDiffRequest(r output.JSONResult) string {
+	line := r.Method + " " + r.URL
+
+	if r.Host != "" {
+		line += " Host: " + r.Host
+	}
+
+	if r.Word != "" {
+		line += " Word: " + r.Word
+	}
+
+	if r.SecondWord != "" {
+		line += ", " + r.SecondWord
+	}
+
+	return line
+}
@@ -0,0 +1,116 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScanDiff(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan.diff", "-f", "testdata/scan_first.json", "-s", "testdata/scan_second.json")
+	assert.NoError(t, err)
+
+	newlineSymbol := fmt.Sprintln()
+
+	expected := "+ [200] POST http://someaddress.url/login" + newlineSymbol +
+		"- [200] GET http://someaddress.url/backup.zip" + newlineSymbol +
+		"~ [200 -> 403] GET http://someaddress.url/admin" + newlineSymbol +
+		"1 appeared, 1 disappeared, 1 changed status code" + newlineSymbol
+
+	assert.Equal(t, expected, loggerBuffer.String())
+}
+
+func TestNewScanDiffWithJSONFormat(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan.diff",
+		"-f", "testdata/scan_first.json",
+		"-s", "testdata/scan_second.json",
+		"--out-format", "json",
+	)
+	assert.NoError(t, err)
+
+	var diff result.Diff
+	assert.NoError(t, json.Unmarshal([]byte(loggerBuffer.String()), &diff))
+
+	assert.Len(t, diff.Appeared, 1)
+	assert.Equal(t, "http://someaddress.url/login", diff.Appeared[0].URL)
+	assert.Equal(t, "POST", diff.Appeared[0].Method)
+
+	assert.Len(t, diff.Disappeared, 1)
+	assert.Equal(t, "http://someaddress.url/backup.zip", diff.Disappeared[0].URL)
+
+	assert.Len(t, diff.StatusChanged, 1)
+	assert.Equal(t, "http://someaddress.url/admin", diff.StatusChanged[0].New.URL)
+	assert.Equal(t, 200, diff.StatusChanged[0].Old.StatusCode)
+	assert.Equal(t, 403, diff.StatusChanged[0].New.StatusCode)
+}
+
+func TestNewScanDiffForSameFileShouldFindNoDifferences(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan.diff", "-f", "testdata/scan_first.json", "-s", "testdata/scan_first.json")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "No differences found"+fmt.Sprintln(), loggerBuffer.String())
+}
+
+func TestNewScanDiffShouldErrWithInvalidFiles(t *testing.T) {
+	testCases := []struct {
+		first  string
+		second string
+	}{
+		{first: "/root/123/bla", second: "testdata/scan_second.json"},
+		{first: "testdata/scan_first.json", second: "/root/123/bla"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.first+"_"+tc.second, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, "scan.diff", "-f", tc.first, "-s", tc.second)
+			assert.Error(t, err)
+
+			assert.Contains(t, err.Error(), "/root/123/bla")
+		})
+	}
+}
+
+func TestNewScanDiffShouldErrWithInvalidFormat(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan.diff",
+		"-f", "testdata/scan_first.json",
+		"-s", "testdata/scan_second.json",
+		"--out-format", "yaml",
+	)
+	assert.Error(t, err)
+
+	assert.Contains(t, err.Error(), "invalid value for out-format: yaml, the available ones are: text, json")
+}
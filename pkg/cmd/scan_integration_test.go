@@ -1,18 +1,35 @@
 package cmd_test
 
 import (
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/pem"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
 
 	"github.com/armon/go-socks5"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/cmd"
 	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stefanoj3/dirstalk/pkg/scan/webhook"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -56,6 +73,7 @@ func TestScanCommand(t *testing.T) {
 		"404",
 		"--http-timeout",
 		"300",
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
@@ -151,158 +169,6401 @@ func TestScanShouldWriteOutput(t *testing.T) {
 
 	expected := `{"Target":{"Path":"home","Method":"GET","Depth":3},"StatusCode":200,"URL":{"Scheme":"http","Opaque":"","User":null,"Host":"` +
 		testServer.Listener.Addr().String() +
-		`","Path":"/home","RawPath":"","ForceQuery":false,"RawQuery":"","Fragment":""}}
+		`","Path":"/home","RawPath":"","ForceQuery":false,"RawQuery":"","Fragment":""},"ContentLength":0,"Location":""}
 `
 	assert.Equal(t, expected, string(b))
 
 	assert.NoError(t, file.Close(), "failed to close file")
 }
 
+func TestScanShouldWriteJSONOutput(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				http.Redirect(w, r, "/home/", http.StatusMovedPermanently)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	outputFilename := "testdata/out/" + test.RandStringRunes(10) + ".json"
+	defer removeTempFile(outputFilename)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--out-json",
+		outputFilename,
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadFile(outputFilename)
+	assert.NoError(t, err, "failed to read file content")
+
+	var results []output.JSONResult
+	assert.NoError(t, json.Unmarshal(b, &results))
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, testServer.URL+"/home", results[0].URL)
+	assert.Equal(t, http.MethodGet, results[0].Method)
+	assert.Equal(t, http.StatusMovedPermanently, results[0].StatusCode)
+	assert.Equal(t, "/home/", results[0].Location)
+}
+
+func TestScanShouldStopWhenTheMaxAmountOfRequestsIsReached(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	secondServer, secondServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer secondServer.Close()
+
+	outputFilename := "testdata/out/" + test.RandStringRunes(10) + ".json"
+	defer removeTempFile(outputFilename)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		secondServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--threads",
+		"1",
+		"--max-requests",
+		"2",
+		"--out-json",
+		outputFilename,
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	assert.Equal(t, 0, secondServerAssertion.Len())
+
+	// the results found before reaching the limit are saved
+	b, err := ioutil.ReadFile(outputFilename)
+	assert.NoError(t, err, "failed to read file content")
+
+	var results []output.JSONResult
+	assert.NoError(t, json.Unmarshal(b, &results))
+	assert.Len(t, results, 2)
+
+	assert.Contains(t, loggerBuffer.String(), "The limit of the scan has been reached, the scan has been stopped")
+	assert.Contains(t, loggerBuffer.String(), "limit=max-requests")
+	assert.Contains(t, loggerBuffer.String(), "requests=2")
+	assert.Contains(t, loggerBuffer.String(), "Results for "+testServer.URL)
+}
+
+func TestScanShouldStopWhenTheMaxDurationIsReached(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--threads",
+		"1",
+		"-x",
+		"php,html,txt",
+		"--max-duration",
+		"150ms",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.True(t, serverAssertion.Len() <= 3, "the scan should have been stopped before completing")
+	assert.Contains(t, loggerBuffer.String(), "The limit of the scan has been reached, the scan has been stopped")
+	assert.Contains(t, loggerBuffer.String(), "limit=max-duration")
+}
+
+func TestScanWithNegativeLimitsShouldErr(t *testing.T) {
+	for _, flag := range []string{"--max-requests", "--max-duration"} {
+		logger, _ := test.NewLogger()
+
+		c := createCommand(logger)
+		assert.NotNil(t, c)
+
+		value := "-1"
+		if flag == "--max-duration" {
+			value = "-1s"
+		}
+
+		err := executeCommand(c, "scan", "http://localhost/", "--dictionary", "testdata/dict.txt", flag, value)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid value for "+strings.TrimPrefix(flag, "--")+": it cannot be negative")
+	}
+}
+
+func TestScanShouldWriteCSVOutput(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	outputFilename := "testdata/out/" + test.RandStringRunes(10) + ".csv"
+	defer removeTempFile(outputFilename)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--out-csv",
+		outputFilename,
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	//nolint:gosec
+	file, err := os.Open(outputFilename)
+	assert.NoError(t, err)
+
+	records, err := csv.NewReader(file).ReadAll()
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close(), "failed to close file")
+
+	assert.Len(t, records, 2)
+	assert.Equal(
+		t,
+		[]string{"URL", "Method", "Status", "Length", "Location", "Duration", "Host", "Word", "SecondWord"},
+		records[0],
+	)
+	assert.Equal(t, []string{testServer.URL + "/home", http.MethodGet, "200", "0", ""}, records[1][:5])
+}
+
+func TestScanShouldWriteHTMLReport(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	outputFilename := "testdata/out/" + test.RandStringRunes(10) + ".html"
+	defer removeTempFile(outputFilename)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--out-html",
+		outputFilename,
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	b, err := ioutil.ReadFile(outputFilename)
+	assert.NoError(t, err, "failed to read file content")
+
+	report := string(b)
+	assert.Contains(t, report, "<dd>"+testServer.URL+"</dd>")
+	assert.Contains(t, report, "<dd>testdata/dict.txt</dd>")
+	assert.Contains(t, report, "<dt>Total requests</dt>\n<dd>3</dd>")
+	assert.Contains(t, report, `<tr class="status-2xx"><td>`+testServer.URL+`/home</td>`)
+}
+
+func TestScanInvalidJSONOutputFileShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--out-json",
+		"/root/blabla/123/gibberish/123.json",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create output saver")
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
 func TestScanInvalidOutputFileShouldErr(t *testing.T) {
 	logger, _ := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, _ := test.NewServerWithAssertion(
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict2.txt",
+		"--out",
+		"/root/blabla/123/gibberish/123",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create output saver")
+}
+
+func TestScanWithInvalidStatusesToIgnoreShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict2.txt",
+		"-v",
+		"--http-statuses-to-ignore",
+		"300,gibberish,404",
+		"--http-timeout",
+		"300",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "strconv.Atoi: parsing")
+	assert.Contains(t, err.Error(), "gibberish")
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestScanWithUnsupportedHTTPMethodShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-methods",
+		"GET,GIBBERISH",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported http method: GIBBERISH")
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestScanWithLowercaseHTTPMethods(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-methods",
+		"head,post",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	methods := map[string]int{}
+	serverAssertion.Range(func(_ int, r http.Request) {
+		methods[r.Method]++
+	})
+
+	assert.Equal(t, map[string]int{http.MethodHead: 3, http.MethodPost: 3}, methods)
+}
+
+func TestScanWithHeadFirstShouldSendTheGetRequestsOnlyForTheResults(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/home" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write([]byte("the home page"))
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--head-first",
+	)
+	assert.NoError(t, err)
+
+	requests := make(map[string]int)
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+	})
+
+	assert.Equal(
+		t,
+		map[string]int{"HEAD /home": 1, "GET /home": 1, "HEAD /home/index.php": 1, "HEAD /blabla": 1},
+		requests,
+	)
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home [200] [GET]")
+}
+
+func TestScanShouldReuseAConnectionPerThread(t *testing.T) {
+	// the amount of connections dialed depends on the scheduling of the threads, hence it is compared with the one
+	// of a scan keeping a single idle connection rather than with the threads
+	perThreadNewConns := newConnsDuringScan(t)
+	singleIdleNewConns := newConnsDuringScan(t, "--max-idle-conns", "1")
+
+	assert.True(t, perThreadNewConns >= 1, "%d connections dialed", perThreadNewConns)
+	assert.True(
+		t,
+		perThreadNewConns < singleIdleNewConns,
+		"%d connections dialed with an idle connection per thread, %d with a single one",
+		perThreadNewConns,
+		singleIdleNewConns,
+	)
+}
+
+// newConnsDuringScan returns the amount of connections dialed by a scan of 60 entries with 6 threads, delaying
+// the requests so that the connections become idle between them
+func newConnsDuringScan(t *testing.T, args ...string) int64 {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var newConns int64
+
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	testServer.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&newConns, 1)
+		}
+	}
+	testServer.Start()
+	defer testServer.Close()
+
+	dict := make([]string, 0, 60)
+	for i := 0; i < 60; i++ {
+		dict = append(dict, "entry"+strconv.Itoa(i))
+	}
+
+	dictionaryFile := test.MustWriteTempFile(t, []byte(strings.Join(dict, "\n")))
+	defer removeTempFile(dictionaryFile)
+
+	err := executeCommand(
+		c,
+		append(
+			[]string{
+				"scan",
+				testServer.URL,
+				"--dictionary",
+				dictionaryFile,
+				"--threads",
+				"6",
+				"--no-wildcard-detection",
+				"--delay",
+				"2",
+			},
+			args...,
+		)...,
+	)
+	assert.NoError(t, err)
+
+	return atomic.LoadInt64(&newConns)
+}
+
+func TestScanWithNegativeConnectionsLimitsShouldErr(t *testing.T) {
+	for _, flag := range []string{"--max-idle-conns", "--max-conns-per-host"} {
+		logger, _ := test.NewLogger()
+
+		c := createCommand(logger)
+		assert.NotNil(t, c)
+
+		err := executeCommand(c, "scan", "http://localhost/", "--dictionary", "testdata/dict.txt", flag, "-1")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid value for "+strings.TrimPrefix(flag, "--")+": it cannot be negative")
+	}
+}
+
+func TestScanWithOneThreadShouldSerializeRequests(t *testing.T) {
+	assert.Equal(t, 1, maxConcurrentRequestsDuringScan(t, "1"))
+}
+
+func TestScanWithMultipleThreadsShouldParallelizeRequests(t *testing.T) {
+	assert.True(t, maxConcurrentRequestsDuringScan(t, "3") > 1)
+}
+
+func TestScanWithPerHostThreadsShouldCapTheConcurrentRequestsToTheHost(t *testing.T) {
+	assert.Equal(t, 1, maxConcurrentRequestsDuringScan(t, "3", "--per-host-threads", "1"))
+}
+
+func maxConcurrentRequestsDuringScan(t *testing.T, threads string, args ...string) int {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var (
+		inFlight    int
+		maxInFlight int
+		mx          sync.Mutex
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mx.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mx.Unlock()
+
+			time.Sleep(time.Millisecond * 50)
+
+			mx.Lock()
+			inFlight--
+			mx.Unlock()
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		append(
+			[]string{
+				"scan",
+				testServer.URL,
+				"--dictionary",
+				"testdata/dict.txt",
+				"--threads",
+				threads,
+				"--no-wildcard-detection",
+				"--delay",
+				"2",
+			},
+			args...,
+		)...,
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	return maxInFlight
+}
+
+func TestScanWithRateShouldThrottleRequests(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	start := time.Now()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--threads",
+		"3",
+		"--rate",
+		"10",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// the first request is immediate, the following 2 have to wait 100ms each
+	assert.True(t, time.Since(start) >= 180*time.Millisecond)
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestScanWithDelayShouldWaitBeforeEachRequest(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	start := time.Now()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--threads",
+		"1",
+		"--delay",
+		"100",
+		"--jitter",
+		"10",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.True(t, time.Since(start) >= 270*time.Millisecond)
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestScanWithInvalidJitterShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--delay",
+		"100",
+		"--jitter",
+		"150",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for jitter: it must be between 0 and 100")
+}
+
+func TestScanWithNegativeRateShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--rate",
+		"-1",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for rate: it cannot be negative")
+}
+
+func TestScanWithExtensions(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"-x",
+		"php",
+		"--extension",
+		".bak",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	requestedPaths := make([]string, 0, serverAssertion.Len())
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+	})
+
+	expectedPaths := []string{
+		"/home",
+		"/home.php",
+		"/home.bak",
+		"/home/index.php",
+		"/home/index.php.bak",
+		"/blabla",
+		"/blabla.php",
+		"/blabla.bak",
+	}
+	assert.ElementsMatch(t, expectedPaths, requestedPaths)
+}
+
+func TestScanWithDictionaryFromStdin(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dictionaryContent, err := ioutil.ReadFile("testdata/dict.txt")
+	assert.NoError(t, err)
+
+	c.SetIn(strings.NewReader(string(dictionaryContent)))
+
+	newServer := func() (*httptest.Server, *test.ServerAssertion) {
+		return test.NewServerWithAssertion(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}),
+		)
+	}
+
+	firstServer, firstServerAssertion := newServer()
+	defer firstServer.Close()
+
+	secondServer, secondServerAssertion := newServer()
+	defer secondServer.Close()
+
+	err = executeCommand(
+		c,
+		"scan",
+		firstServer.URL,
+		secondServer.URL,
+		"--dictionary",
+		"-",
+		"-x",
+		"php",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	expectedPaths := []string{
+		"/home",
+		"/home.php",
+		"/home/index.php",
+		"/blabla",
+		"/blabla.php",
+	}
+
+	// the standard input is read only once, both the targets are scanned with the same dictionary
+	for _, serverAssertion := range []*test.ServerAssertion{firstServerAssertion, secondServerAssertion} {
+		requestedPaths := make([]string, 0, serverAssertion.Len())
+
+		serverAssertion.Range(func(_ int, r http.Request) {
+			requestedPaths = append(requestedPaths, r.URL.Path)
+		})
+
+		assert.ElementsMatch(t, expectedPaths, requestedPaths)
+	}
+}
+
+func TestScanWithDictionaryFromStdinShouldStartBeforeTheDictionaryIsRead(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	stdinReader, stdinWriter := io.Pipe()
+	c.SetIn(stdinReader)
+
+	done := make(chan error)
+
+	go func() {
+		done <- executeCommand(
+			c,
+			"scan",
+			testServer.URL,
+			"--dictionary",
+			"-",
+			"--scan-depth",
+			"0",
+			"--no-wildcard-detection",
+		)
+	}()
+
+	_, err := stdinWriter.Write([]byte("home\n"))
+	assert.NoError(t, err)
+
+	// the entry is requested while the standard input is still open
+	deadline := time.Now().Add(5 * time.Second)
+	for serverAssertion.Len() < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, 1, serverAssertion.Len())
+
+	_, err = stdinWriter.Write([]byte("blabla\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, stdinWriter.Close())
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the scan did not end when the standard input was closed")
+	}
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	serverAssertion.At(1, func(r http.Request) {
+		assert.Equal(t, "/blabla", r.URL.Path)
+	})
+}
+
+func TestScanInDryRunModeShouldPrintTheRequestsWithoutPerformingThem(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	out, err := executeCommandWithOutput(
+		c,
+		"scan",
+		testServer.URL+"/app",
+		"--dictionary",
+		"testdata/dict.txt",
+		"-x",
+		"php",
+		"--http-methods",
+		"GET,POST",
+		"--exclude-path",
+		"/blabla",
+		"--dry-run",
+	)
+	assert.NoError(t, err)
+
+	expectedRequests := []string{
+		"GET " + testServer.URL + "/app/home",
+		"POST " + testServer.URL + "/app/home",
+		"GET " + testServer.URL + "/app/home.php",
+		"POST " + testServer.URL + "/app/home.php",
+		"GET " + testServer.URL + "/app/home/index.php",
+		"POST " + testServer.URL + "/app/home/index.php",
+		"GET " + testServer.URL + "/app/blabla.php",
+		"POST " + testServer.URL + "/app/blabla.php",
+	}
+	assert.ElementsMatch(t, expectedRequests, strings.Split(strings.TrimSpace(out), "\n"))
+
+	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "Dry run completed, no request has been performed")
+	assert.Contains(t, loggerBuffer.String(), "requests=8")
+}
+
+func TestScanWithInvalidExtensionShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"-x",
+		".",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for extension")
+}
+
+func TestScanWithAppendQueryShouldRequestEachPathAlsoWithTheQueryStrings(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/blabla" && r.URL.Query().Get("debug") == "1" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--append-query",
+		"?debug=1",
+		"--append-query",
+		"q=a b&page",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// 3 entries in the dictionary, each one requested without query string and with the 2 specified
+	assert.Equal(t, 9, serverAssertion.Len())
+
+	requests := make(map[string]int)
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requests[r.URL.RawQuery]++
+	})
+	assert.Equal(t, map[string]int{"": 3, "debug=1": 3, "q=a+b&page": 3}, requests)
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), testServer.URL+"/blabla?debug=1 [200] [GET]")
+}
+
+func TestScanWithInvalidAppendQueryShouldErr(t *testing.T) {
+	for _, query := range []string{"?", "&", "debug=%zz"} {
+		logger, _ := test.NewLogger()
+
+		c := createCommand(logger)
+		assert.NotNil(t, c)
+
+		err := executeCommand(
+			c,
+			"scan",
+			"http://localhost/",
+			"--dictionary",
+			"testdata/dict.txt",
+			"--append-query",
+			query,
+		)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid value for append-query: unsupported query string: "+query)
+	}
+}
+
+func TestScanWithIncludeStatusShouldReportOnlyTheIncludedStatuses(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/test/" {
+				w.WriteHeader(http.StatusMovedPermanently)
+				return
+			}
+
+			if r.URL.Path == "/test/home" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			if r.URL.Path == "/blabla" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	outputFilename := "testdata/out/" + test.RandStringRunes(10) + ".txt"
+	defer removeTestFile(outputFilename)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict2.txt",
+		"--scan-depth",
+		"1",
+		"--include-status",
+		"200,203-205",
+		"--out",
+		outputFilename,
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// the results not included are not reported, but they are still scanned
+	assert.Equal(t, 12, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/test/home [200] [GET]")
+	assert.NotContains(t, loggerBuffer.String(), "/blabla [500] [GET]")
+
+	b, err := ioutil.ReadFile(outputFilename)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), "\n"))
+	assert.Contains(t, string(b), `"Path":"/test/home"`)
+}
+
+func TestScanWithExcludeStatusShouldNotReportTheExcludedStatuses(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			if r.URL.Path == "/blabla" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--exclude-status",
+		"500-599",
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home [200] [GET]")
+	assert.NotContains(t, loggerBuffer.String(), "/blabla [500] [GET]")
+}
+
+func TestScanWithInvalidStatusesToIncludeOrExcludeShouldErr(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--include-status", "abc"},
+			expectedError: "invalid value for include-status: invalid http status: abc",
+		},
+		{
+			args:          []string{"--include-status", "200,500-200"},
+			expectedError: "invalid value for include-status: invalid http status range: 500-200",
+		},
+		{
+			args:          []string{"--exclude-status", "200-"},
+			expectedError: "invalid value for exclude-status: invalid http status: ",
+		},
+		{
+			args:          []string{"--exclude-status", "1000"},
+			expectedError: "invalid value for exclude-status: invalid http status: 1000",
+		},
+		{
+			args:          []string{"--include-status", "200", "--exclude-status", "404"},
+			expectedError: "include-status and exclude-status cannot be used at the same time",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			args := append(
+				[]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithFilterSizeShouldIgnoreResultsWithTheGivenSizes(t *testing.T) {
+	testCases := []struct {
+		args []string
+	}{
+		{args: []string{"--filter-size", "9,100"}},
+		{args: []string{"--filter-size-range", "1-2,5-10"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/home" {
+						_, _ = w.Write([]byte("something interesting")) //nolint:errcheck
+						return
+					}
+
+					_, _ = w.Write([]byte("not found")) //nolint:errcheck
+				}),
+			)
+			defer testServer.Close()
+
+			args := append(
+				[]string{"scan", testServer.URL, "--dictionary", "testdata/dict.txt", "--scan-depth", "0"},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.NoError(t, err)
+
+			assert.Contains(t, loggerBuffer.String(), "1 results found")
+			assert.Contains(t, loggerBuffer.String(), "/home [200] [GET]")
+		})
+	}
+}
+
+func TestScanWithMatchRegexShouldReportOnlyTheResponsesWithAMatchingBody(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				_, _ = w.Write([]byte("<title>Index of /home</title>")) //nolint:errcheck
+				return
+			}
+
+			_, _ = w.Write([]byte("<title>Welcome</title>")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--match-regex",
+		"(?i)index of",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home [200] [GET]")
+}
+
+func TestScanWithMatchRegexShouldMatchTheBodyDecodedFromItsCharset(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+
+			if r.URL.Path == "/home" {
+				_, _ = w.Write([]byte("<title>R\xe9pertoire /home</title>")) //nolint:errcheck
+				return
+			}
+
+			_, _ = w.Write([]byte("<title>Bienvenue</title>")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--match-regex",
+		"Répertoire",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home [200] [GET]")
+}
+
+func TestScanWithMaxBodySizeShouldReportTheLongerBodiesAsTruncated(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				_, _ = w.Write([]byte(strings.Repeat("a", 2048))) //nolint:errcheck
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--max-body-size",
+		"1024",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "/home [200] [GET] [body truncated]")
+	assert.Contains(t, loggerBuffer.String(), "body-truncated=true")
+}
+
+func TestScanWithMatchHeaderShouldReportOnlyTheResponsesWithTheHeader(t *testing.T) {
+	testCases := []struct {
+		args []string
+	}{
+		{args: []string{"--match-header", "WWW-Authenticate"}},
+		{args: []string{"--match-header", "Server: ^nginx"}},
+		{
+			args: []string{"--match-header", "Server: nginx", "--match-header", "www-authenticate", "--http-methods", "HEAD"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/home" {
+						w.Header().Set("Server", "nginx")
+						w.Header().Set("WWW-Authenticate", "Basic")
+						w.WriteHeader(http.StatusUnauthorized)
+
+						return
+					}
+
+					w.Header().Set("Server", "apache")
+				}),
+			)
+			defer testServer.Close()
+
+			args := append(
+				[]string{"scan", testServer.URL, "--dictionary", "testdata/dict.txt", "--scan-depth", "0"},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.NoError(t, err)
+
+			assert.Contains(t, loggerBuffer.String(), "1 results found")
+			assert.Contains(t, loggerBuffer.String(), "/home [401]")
+		})
+	}
+}
+
+func TestScanWithContentTypeFiltersShouldReportOnlyTheMatchingResponses(t *testing.T) {
+	testCases := []struct {
+		args []string
+	}{
+		{args: []string{"--match-content-type", "TEXT/HTML"}},
+		{args: []string{"--match-content-type", "regex:^text/html;"}},
+		{args: []string{"--exclude-content-type", "image/", "--exclude-content-type", "regex:json$"}},
+		{args: []string{"--match-content-type", "text/", "--exclude-content-type", "text/css", "--http-methods", "HEAD"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/home":
+						w.Header().Set("Content-Type", "text/html; charset=utf-8")
+					case "/home/index.php":
+						w.Header().Set("Content-Type", "application/json")
+					default:
+						w.Header().Set("Content-Type", "image/png")
+					}
+
+					// the css is excluded only by the last test case, the others don't match it
+					if r.URL.Path == "/blabla" && r.Method == http.MethodHead {
+						w.Header().Set("Content-Type", "text/css")
+					}
+				}),
+			)
+			defer testServer.Close()
+
+			args := append(
+				[]string{
+					"scan",
+					testServer.URL,
+					"--dictionary",
+					"testdata/dict.txt",
+					"--scan-depth",
+					"0",
+					"--no-wildcard-detection",
+				},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.NoError(t, err)
+
+			assert.Contains(t, loggerBuffer.String(), "1 results found")
+			assert.Contains(t, loggerBuffer.String(), "/home [200]")
+		})
+	}
+}
+
+func TestScanWithFilterTimeAboveShouldReportOnlyTheSlowResponses(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home/index.php" {
+				time.Sleep(300 * time.Millisecond)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--filter-time-above",
+		"200ms",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home/index.php [200]")
+}
+
+func TestScanWithDedupResultsShouldReportTheSameResourceOnce(t *testing.T) {
+	testCases := []struct {
+		flags                []string
+		expectedResultsCount string
+	}{
+		{flags: nil, expectedResultsCount: "2 results found"},
+		{flags: []string{"--dedup-results"}, expectedResultsCount: "1 results found"},
+	}
+
+	for _, tc := range testCases {
+		logger, loggerBuffer := test.NewLogger()
+
+		c := createCommand(logger)
+		assert.NotNil(t, c)
+
+		testServer, serverAssertion := test.NewServerWithAssertion(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/home" && r.URL.Path != "/home/" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				_, _ = w.Write([]byte("the home page"))
+			}),
+		)
+
+		args := []string{
+			"scan",
+			testServer.URL,
+			"--dictionary",
+			"testdata/dict.txt",
+			"--trailing-slash",
+			"both",
+			"--scan-depth",
+			"0",
+			"--no-wildcard-detection",
+		}
+
+		err := executeCommand(c, append(args, tc.flags...)...)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 5, serverAssertion.Len())
+		assert.Contains(t, loggerBuffer.String(), tc.expectedResultsCount)
+
+		testServer.Close()
+	}
+}
+
+func TestScanWithNegativeFilterTimeAboveShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--filter-time-above",
+		"-1s",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for filter-time-above: it cannot be negative")
+}
+
+func TestScanWithInvalidContentTypeRegexShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--exclude-content-type",
+		"regex:(",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for exclude-content-type")
+}
+
+func TestScanWithProbeBackupsShouldRequestTheBackupsOfTheFilesFound(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/home", "/home/index.php", "/home/index.php.bak":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--probe-backups",
+		"--backup-suffixes",
+		".bak,~",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// 3 entries in the dictionary, the backups only of the file found
+	assert.Equal(t, 5, serverAssertion.Len())
+
+	requestedPaths := make([]string, 0, 5)
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+	})
+	assert.Contains(t, requestedPaths, "/home/index.php~")
+
+	assert.Contains(t, loggerBuffer.String(), "3 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home/index.php.bak [200] [GET]")
+}
+
+func TestScanWithRecursionStrategyShouldScanTheFoldersFoundInTheGivenOrder(t *testing.T) {
+	testCases := []struct {
+		strategy      string
+		expectedPaths []string
+	}{
+		{
+			strategy: "bfs",
+			expectedPaths: []string{
+				"/home", "/home/index.php", "/blabla",
+				"/home/home", "/home/home/index.php", "/home/blabla",
+				"/home/home/home", "/home/home/home/index.php", "/home/home/blabla",
+			},
+		},
+		{
+			strategy: "dfs",
+			expectedPaths: []string{
+				"/home",
+				"/home/home",
+				"/home/home/home", "/home/home/home/index.php", "/home/home/blabla",
+				"/home/home/index.php", "/home/blabla",
+				"/home/index.php", "/blabla",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.strategy, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if strings.HasSuffix(r.URL.Path, "/home") {
+						return
+					}
+
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			err := executeCommand(
+				c,
+				"scan",
+				testServer.URL,
+				"--dictionary",
+				"testdata/dict.txt",
+				"--recursion-strategy",
+				tc.strategy,
+				"--scan-depth",
+				"2",
+				"--threads",
+				"1",
+				"--no-wildcard-detection",
+			)
+			assert.NoError(t, err)
+
+			paths := make([]string, 0, len(tc.expectedPaths))
+			serverAssertion.Range(func(_ int, r http.Request) {
+				paths = append(paths, r.URL.Path)
+			})
+
+			assert.Equal(t, tc.expectedPaths, paths)
+		})
+	}
+}
+
+func TestScanWithInvalidRecursionStrategyShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--recursion-strategy",
+		"random",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for recursion-strategy: random, the available ones are: bfs, dfs")
+}
+
+func TestScanWithTrailingSlashBothShouldRequestThePathsWithAndWithoutIt(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home/" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--trailing-slash",
+		"both",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	paths := make([]string, 0, 5)
+	serverAssertion.Range(func(_ int, r http.Request) {
+		paths = append(paths, r.URL.Path)
+	})
+
+	sort.Strings(paths)
+
+	// the files are not requested with the trailing slash
+	assert.Equal(t, []string{"/blabla", "/blabla/", "/home", "/home/", "/home/index.php"}, paths)
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), testServer.URL+"/home/ [200] [GET]")
+}
+
+func TestScanWithInvalidTrailingSlashShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--trailing-slash",
+		"always",
+	)
+	assert.Error(t, err)
+	assert.Contains(
+		t,
+		err.Error(),
+		"invalid value for trailing-slash: always, the available ones are: keep, both, with, without",
+	)
+}
+
+func TestScanWithInvalidBackupSuffixShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--probe-backups",
+		"--backup-suffixes",
+		".bak,/old",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for backup-suffixes: `/old` is not a valid suffix")
+}
+
+func TestScanShouldIgnoreWildcardResponses(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				_, _ = w.Write([]byte("home page")) //nolint:errcheck
+				return
+			}
+
+			_, _ = w.Write([]byte("this page does not exist")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+	)
+	assert.NoError(t, err)
+
+	// 3 random paths are requested to detect the wildcard responses
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "Wildcard response detected")
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home [200] [GET]")
+}
+
+func TestScanWithNoWildcardDetectionShouldNotIgnoreWildcardResponses(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("this page does not exist")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.NotContains(t, loggerBuffer.String(), "Wildcard response detected")
+	assert.Contains(t, loggerBuffer.String(), "3 results found")
+}
+
+func TestScanWithBaselineRequestShouldIgnoreTheResultsSimilarToTheBaselineOfTheirDirectory(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				_, _ = w.Write([]byte("welcome to the home page of the website, enjoy")) //nolint:errcheck
+				return
+			}
+
+			// the length of the page varies with the path requested
+			_, _ = w.Write([]byte("not found: " + r.URL.Path)) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--baseline-request",
+		"--diff-threshold",
+		"15",
+		"--scan-depth",
+		"1",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// 3 entries in the dictionary for / and /home/, 3 random paths for the baseline of each directory having
+	// results: /, /home/ (of /home/index.php) and /home/home/ (of /home/home/index.php)
+	assert.Equal(t, 15, serverAssertion.Len())
+
+	baselineDirectories := make(map[string]int)
+	serverAssertion.Range(func(_ int, r http.Request) {
+		p := strings.TrimSuffix(r.URL.Path, "/")
+		if i := strings.LastIndex(p, "/"); len(p)-i-1 == 16 {
+			baselineDirectories[p[:i+1]]++
+		}
+	})
+	assert.Equal(t, map[string]int{"/": 3, "/home/": 3, "/home/home/": 3}, baselineDirectories)
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/home [200] [GET]")
+}
+
+func TestScanWithNegativeDiffThresholdShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--baseline-request",
+		"--diff-threshold",
+		"-1",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for diff-threshold: it cannot be negative")
+}
+
+func TestScanShouldAbortWhenAWAFBlockPageIsDetected(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var requests int32
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the not found page first, then the block page
+			if atomic.AddInt32(&requests, 1) <= 3 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte("not found")) //nolint:errcheck
+
+				return
+			}
+
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("too many requests, you have been blocked")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	secondServer, secondServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer secondServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		secondServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"-x",
+		"php",
+		"--scan-depth",
+		"0",
+		"--threads",
+		"1",
+		"--on-waf",
+		"abort",
+		"--waf-window",
+		"3",
+		"--waf-threshold",
+		"60",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// the block page is detected once it is 2 of the last 3 responses, out of the 6 requests of the scan
+	assert.Equal(t, 5, serverAssertion.Len())
+	assert.Equal(t, 0, secondServerAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "A WAF seems to be blocking the scan")
+	assert.Contains(t, loggerBuffer.String(), "status=403")
+	assert.Contains(t, loggerBuffer.String(), "The scan has been aborted as a WAF seems to be blocking it")
+}
+
+func TestScanShouldPauseWhenAWAFBlockPageIsDetected(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusForbidden)
+		}),
+	)
+	defer testServer.Close()
+
+	start := time.Now()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--threads",
+		"1",
+		"--on-waf",
+		"pause",
+		"--waf-window",
+		"1",
+		"--waf-pause",
+		"200ms",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// the scan is resumed after the pause
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+
+	assert.Contains(t, loggerBuffer.String(), "A WAF seems to be blocking the scan")
+	assert.Contains(t, loggerBuffer.String(), "Pausing the scan")
+	assert.Contains(t, loggerBuffer.String(), "pause=200ms")
+}
+
+func TestScanWithInvalidWAFDetectionShouldErr(t *testing.T) {
+	testCases := []struct {
+		flag          string
+		value         string
+		expectedError string
+	}{
+		{
+			flag:          "--on-waf",
+			value:         "ignore",
+			expectedError: "invalid value for on-waf: ignore, the available ones are: warn, pause, abort",
+		},
+		{flag: "--waf-window", value: "-1", expectedError: "invalid value for waf-window: it cannot be negative"},
+		{
+			flag:          "--waf-threshold",
+			value:         "0",
+			expectedError: "invalid value for waf-threshold: it must be between 1 and 100",
+		},
+		{
+			flag:          "--waf-threshold",
+			value:         "101",
+			expectedError: "invalid value for waf-threshold: it must be between 1 and 100",
+		},
+		{flag: "--waf-pause", value: "-1s", expectedError: "invalid value for waf-pause: it cannot be negative"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.flag+" "+tc.value, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, "scan", "http://localhost/", "--dictionary", "testdata/dict.txt", tc.flag, tc.value)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithBaselineRequestAndVHostDictionaryShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--vhost-dictionary",
+		"testdata/dict.txt",
+		"--baseline-request",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "vhost-dictionary and baseline-request cannot be used at the same time")
+}
+
+func TestScanShouldReportWhereTheRedirectsPointTo(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				http.Redirect(w, r, "/login", http.StatusFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "/home [302] [GET] -> /login")
+}
+
+func TestScanWithFollowRedirectsShouldReportTheFinalResponse(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				http.Redirect(w, r, "/login", http.StatusFound)
+				return
+			}
+
+			if r.URL.Path == "/blabla" {
+				http.Redirect(w, r, "/login", http.StatusMovedPermanently)
+				return
+			}
+
+			if r.URL.Path == "/login" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--follow-redirects",
+	)
+	assert.NoError(t, err)
+
+	// both /home and /blabla redirect to /login, it is requested for each of them
+	assert.Equal(t, 5, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "/login [200] [GET]\n")
+	assert.NotContains(t, loggerBuffer.String(), "[302]")
+	assert.NotContains(t, loggerBuffer.String(), "[301]")
+}
+
+func TestScanWithFollowRedirectsShouldStopAfterTheMaxAmountOfRedirects(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				http.Redirect(w, r, "/first", http.StatusFound)
+				return
+			}
+
+			if r.URL.Path == "/first" {
+				http.Redirect(w, r, "/second", http.StatusFound)
+				return
+			}
+
+			if r.URL.Path == "/second" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--follow-redirects",
+		"--max-redirects",
+		"1",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "/first [302] [GET] -> /second")
+}
+
+func TestScanWithFollowRedirectsShouldNotFollowRedirectsOutOfScope(t *testing.T) {
+	testCases := []struct {
+		args                    []string
+		expectedRequests        int
+		shouldFollowTheRedirect bool
+	}{
+		{args: []string{}, expectedRequests: 3, shouldFollowTheRedirect: false},
+		{args: []string{"--scope-domain", "localhost"}, expectedRequests: 4, shouldFollowTheRedirect: true},
+		{args: []string{"--scope", "any"}, expectedRequests: 4, shouldFollowTheRedirect: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			var testServer *httptest.Server
+
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/home" {
+						// same server, but a different host
+						location := strings.Replace(testServer.URL, "127.0.0.1", "localhost", 1) + "/login"
+						http.Redirect(w, r, location, http.StatusFound)
+
+						return
+					}
+
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			args := append(
+				[]string{
+					"scan",
+					testServer.URL,
+					"--dictionary",
+					"testdata/dict.txt",
+					"--scan-depth",
+					"0",
+					"--no-wildcard-detection",
+					"--follow-redirects",
+				},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedRequests, serverAssertion.Len())
+
+			if tc.shouldFollowTheRedirect {
+				assert.NotContains(t, loggerBuffer.String(), "Out of scope redirect")
+			} else {
+				assert.Contains(t, loggerBuffer.String(), "Out of scope redirect, it will not be followed")
+				assert.Contains(t, loggerBuffer.String(), "/home [302] [GET]")
+			}
+		})
+	}
+}
+
+func TestScanWithInvalidScopeShouldErr(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--scope", "domain"},
+			expectedError: "invalid value for scope: domain, the available ones are: host, subdomains, any",
+		},
+		{
+			args:          []string{"--scope-domain", "http://example.com"},
+			expectedError: "invalid value for scope-domain: http://example.com must be a host name",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(
+				c,
+				append([]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"}, tc.args...)...,
+			)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithExcludePathShouldSkipTheDictionaryEntriesMatchingIt(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"1",
+		"--no-wildcard-detection",
+		"--exclude-path",
+		"/blabla",
+		"--exclude-path",
+		"regex:index",
+	)
+	assert.NoError(t, err)
+
+	requestedPaths := make([]string, 0, serverAssertion.Len())
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+	})
+
+	// the entries are skipped also when scanning /home recursively
+	assert.ElementsMatch(t, []string{"/home", "/home/home"}, requestedPaths)
+}
+
+func TestScanWithExcludePathShouldNotScanRecursivelyThePathsFoundMatchingIt(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" || r.URL.Path == "/home/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"2",
+		"--no-wildcard-detection",
+		"--exclude-path",
+		"*/home",
+	)
+	assert.NoError(t, err)
+
+	requestedPaths := make([]string, 0, serverAssertion.Len())
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requestedPaths = append(requestedPaths, r.URL.Path)
+	})
+
+	expectedPaths := []string{
+		"/home",
+		"/home/index.php",
+		"/blabla",
+		"/home/home",
+		"/home/home/index.php",
+		"/home/blabla",
+	}
+	assert.ElementsMatch(t, expectedPaths, requestedPaths)
+
+	// /home/home is reported even if it is not scanned recursively
+	assert.Contains(t, loggerBuffer.String(), "/home/home [200] [GET]")
+}
+
+func TestScanWithInvalidExcludePathShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--exclude-path",
+		"regex:/static/(",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for exclude-path: invalid regular expression `regex:/static/(`")
+}
+
+func TestScanWithNegativeMaxRedirectsShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--follow-redirects",
+		"--max-redirects",
+		"-1",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for max-redirects: it cannot be negative")
+}
+
+func TestScanWithInvalidFilterSizeShouldErr(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--filter-size", "-1"},
+			expectedError: "invalid value for filter-size: it cannot be negative",
+		},
+		{
+			args:          []string{"--filter-size-range", "100"},
+			expectedError: "invalid value for filter-size-range: 100 is not a range",
+		},
+		{
+			args:          []string{"--filter-size-range", "200-100"},
+			expectedError: "invalid value for filter-size-range: 200-100 is not a valid range",
+		},
+		{
+			args:          []string{"--filter-size-range", "abc-100"},
+			expectedError: "invalid value for filter-size-range: abc-100 is not a valid range",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			args := append(
+				[]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithInvalidMatchersShouldErr(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--match-regex", "index of ("},
+			expectedError: "invalid value for match-regex: error parsing regexp",
+		},
+		{
+			args:          []string{"--match-regex", "index of", "--max-body-size", "0"},
+			expectedError: "invalid value for max-body-size: it must be at least 1",
+		},
+		{
+			args:          []string{"--match-header", ": nginx"},
+			expectedError: "invalid value for match-header: : nginx has no header name",
+		},
+		{
+			args:          []string{"--match-header", "Server: nginx("},
+			expectedError: "invalid value for match-header: error parsing regexp",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			args := append(
+				[]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithNegativeScanDepthShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"-1",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for scan-depth: it cannot be negative")
+}
+
+func TestScanWithZeroScanDepthShouldNotRecurse(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict2.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, serverAssertion.Len())
+}
+
+func TestScanShouldAcceptRecursionDepthAsAliasOfScanDepth(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict2.txt",
+		"--recursion-depth",
+		"1",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 16, serverAssertion.Len())
+
+	// the results are printed as a tree, with the folders found containing their own results
+	assert.Contains(t, loggerBuffer.String(), "scan-depth=1")
+	assert.Contains(t, loggerBuffer.String(), "/test/home")
+}
+
+func TestScanWithInvalidThreadsShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--threads",
+		"0",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for threads: at least 1 thread is required")
+}
+
+func TestScanWithNegativePerHostThreadsShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--per-host-threads",
+		"-1",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for per-host-threads: it cannot be negative")
+}
+
+func TestScanWithHighAmountOfThreadsShouldWarn(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--threads",
+		"201",
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "Using a high amount of threads")
+}
+
+func TestScanWithTimeoutShouldGiveUpOnTheSlowRequestsAndKeepScanning(t *testing.T) {
+	testCases := []struct {
+		name            string
+		args            []string
+		expectedTimeout string
+	}{
+		{
+			name:            "timeout",
+			args:            []string{"--timeout", "100ms"},
+			expectedTimeout: "timeout=100",
+		},
+		{
+			name:            "deprecated http-timeout",
+			args:            []string{"--http-timeout", "150"},
+			expectedTimeout: "timeout=150",
+		},
+		{
+			name:            "timeout taking precedence over http-timeout",
+			args:            []string{"--timeout", "100ms", "--http-timeout", "10000"},
+			expectedTimeout: "timeout=100",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			var requestsCount int32
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&requestsCount, 1)
+
+					if r.URL.Path == "/home" {
+						time.Sleep(300 * time.Millisecond)
+					}
+
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			err := executeCommand(
+				c,
+				append(
+					[]string{
+						"scan",
+						testServer.URL,
+						"--dictionary",
+						"testdata/dict.txt",
+						"--threads",
+						"1",
+						"--scan-depth",
+						"0",
+						"--no-wildcard-detection",
+					},
+					tc.args...,
+				)...,
+			)
+			assert.NoError(t, err)
+
+			// the slow request times out, the following ones are still performed
+			assert.Equal(t, int32(3), atomic.LoadInt32(&requestsCount))
+			assert.Contains(t, loggerBuffer.String(), "request timed out")
+			assert.Contains(t, loggerBuffer.String(), tc.expectedTimeout)
+		})
+	}
+}
+
+func TestScanWithInvalidTimeoutShouldErr(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "too short",
+			args:          []string{"--timeout", "0s"},
+			expectedError: "invalid value for timeout: it must be at least 1ms",
+		},
+		{
+			name:          "not a duration",
+			args:          []string{"--timeout", "5000"},
+			expectedError: "invalid argument \"5000\" for \"--timeout\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(
+				c,
+				append([]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"}, tc.args...)...,
+			)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithNoTargetShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "--dictionary", "testdata/dict2.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no URL provided")
+}
+
+func TestScanWithInvalidTargetShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "--dictionary", "testdata/dict2.txt", "localhost%%2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid URI")
+}
+
+func TestScanWithMultipleTargets(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	newServer := func() (*httptest.Server, *test.ServerAssertion) {
+		return test.NewServerWithAssertion(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/home" {
+					return
+				}
+
+				w.WriteHeader(http.StatusNotFound)
+			}),
+		)
+	}
+
+	firstServer, firstServerAssertion := newServer()
+	defer firstServer.Close()
+
+	secondServer, secondServerAssertion := newServer()
+	defer secondServer.Close()
+
+	thirdServer, thirdServerAssertion := newServer()
+	defer thirdServer.Close()
+
+	targetsFile := test.MustWriteTempFile(
+		t,
+		[]byte("# targets\n"+secondServer.URL+"\n\n"+thirdServer.URL+"\n"),
+	)
+	defer removeTempFile(targetsFile)
+
+	err := executeCommand(
+		c,
+		"scan",
+		firstServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--targets-file",
+		targetsFile,
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, firstServerAssertion.Len())
+	assert.Equal(t, 3, secondServerAssertion.Len())
+	assert.Equal(t, 3, thirdServerAssertion.Len())
+
+	for _, u := range []string{firstServer.URL, secondServer.URL, thirdServer.URL} {
+		assert.Contains(t, loggerBuffer.String(), "Results for "+u)
+		assert.Contains(t, loggerBuffer.String(), u+"/home [200] [GET]")
+	}
+}
+
+func TestScanWithStdinTargetsShouldScanTheUrlsReadFromTheStandardInput(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	newServer := func() (*httptest.Server, *test.ServerAssertion) {
+		return test.NewServerWithAssertion(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}),
+		)
+	}
+
+	firstServer, firstServerAssertion := newServer()
+	defer firstServer.Close()
+
+	secondServer, secondServerAssertion := newServer()
+	defer secondServer.Close()
+
+	c.SetIn(strings.NewReader(firstServer.URL + "\n\n# a comment\nnot a url\n  " + secondServer.URL + "  \n"))
+
+	err := executeCommand(
+		c,
+		"scan",
+		"--stdin-targets",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, firstServerAssertion.Len())
+	assert.Equal(t, 3, secondServerAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "Invalid url read from the standard input, skipping it")
+	assert.Contains(t, loggerBuffer.String(), "url=\"not a url\"")
+}
+
+func TestScanWithStdinTargetsShouldScanEachUrlAsSoonAsItIsRead(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	newServer := func() (*httptest.Server, *test.ServerAssertion) {
+		return test.NewServerWithAssertion(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}),
+		)
+	}
+
+	argumentServer, argumentServerAssertion := newServer()
+	defer argumentServer.Close()
+
+	stdinServer, stdinServerAssertion := newServer()
+	defer stdinServer.Close()
+
+	stdinReader, stdinWriter := io.Pipe()
+	c.SetIn(stdinReader)
+
+	done := make(chan error)
+
+	go func() {
+		done <- executeCommand(
+			c,
+			"scan",
+			argumentServer.URL,
+			"--stdin-targets",
+			"--dictionary",
+			"testdata/dict.txt",
+			"--scan-depth",
+			"0",
+			"--no-wildcard-detection",
+		)
+	}()
+
+	_, err := stdinWriter.Write([]byte(stdinServer.URL + "\n"))
+	assert.NoError(t, err)
+
+	// the url is scanned while the standard input is still open
+	deadline := time.Now().Add(5 * time.Second)
+	for stdinServerAssertion.Len() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, 3, stdinServerAssertion.Len())
+
+	assert.Equal(t, 3, argumentServerAssertion.Len())
+
+	assert.NoError(t, stdinWriter.Close())
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the scan did not end when the standard input was closed")
+	}
+}
+
+func TestScanWithInvalidStdinTargetsShouldErr(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--dictionary", "-"},
+			expectedError: "stdin-targets cannot be used with a dictionary read from the standard input",
+		},
+		{
+			args:          []string{"--dictionary", "testdata/dict.txt", "--dry-run"},
+			expectedError: "stdin-targets and dry-run cannot be used at the same time",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.expectedError, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, append([]string{"scan", "--stdin-targets"}, tc.args...)...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithInvalidTargetsFileShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "--dictionary", "testdata/dict.txt", "--targets-file", "/i-do-not-exist")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read targets-file")
+
+	targetsFile := test.MustWriteTempFile(t, []byte("http://localhost\nlocalhost%%2\n"))
+	defer removeTempFile(targetsFile)
+
+	err = executeCommand(c, "scan", "--dictionary", "testdata/dict.txt", "--targets-file", targetsFile)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "localhost%%2 is not a valid url")
+}
+
+func TestScanShouldResumeFromTheSavedState(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	stateFile := "testdata/out/" + test.RandStringRunes(10) + ".json"
+	defer removeTempFile(stateFile)
+
+	args := []string{
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--resume-from",
+		stateFile,
+		"--no-wildcard-detection",
+	}
+
+	err := executeCommand(c, args...)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.FileExists(t, stateFile)
+
+	// the dictionary entries have all been completed already
+	err = executeCommand(c, args...)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "Resuming scan")
+
+	args[3] = "testdata/dict2.txt"
+
+	err = executeCommand(c, args...)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the dictionary is different")
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestScanWithShuffleShouldRequestTheDictionaryInTheOrderOfTheSeed(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	dict := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		dict = append(dict, "entry"+strconv.Itoa(i))
+	}
+
+	dictionaryFile := test.MustWriteTempFile(t, []byte(strings.Join(dict, "\n")))
+	defer removeTempFile(dictionaryFile)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		dictionaryFile,
+		"--shuffle",
+		"--seed",
+		"42",
+		"--threads",
+		"1",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	requestedPaths := make([]string, 0, len(dict))
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requestedPaths = append(requestedPaths, strings.TrimPrefix(r.URL.Path, "/"))
+	})
+
+	assert.Equal(t, dictionary.Shuffle(dict, 42), requestedPaths)
+	assert.Contains(t, loggerBuffer.String(), "The dictionary has been shuffled")
+	assert.Contains(t, loggerBuffer.String(), "seed=42")
+}
+
+func TestScanWithShuffleShouldResumeInTheOrderItWasStartedWith(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	stateFile := "testdata/out/" + test.RandStringRunes(10) + ".json"
+	defer removeTempFile(stateFile)
+
+	args := []string{
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--shuffle",
+		"--resume-from",
+		stateFile,
+		"--no-wildcard-detection",
+	}
+
+	err := executeCommand(c, args...)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	content, err := ioutil.ReadFile(stateFile)
+	assert.NoError(t, err)
+
+	var savedState map[string]struct {
+		ShuffleSeed int64 `json:"shuffle_seed"`
+	}
+	assert.NoError(t, json.Unmarshal(content, &savedState))
+
+	seed := savedState[testServer.URL].ShuffleSeed
+	assert.NotEqual(t, int64(0), seed)
+	assert.Equal(t, 1, strings.Count(loggerBuffer.String(), "seed="+strconv.FormatInt(seed, 10)))
+
+	// the seed of the state is used, not a new random one
+	err = executeCommand(c, args...)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Equal(t, 2, strings.Count(loggerBuffer.String(), "seed="+strconv.FormatInt(seed, 10)))
+}
+
+func TestScanWithSeedWithoutShuffleShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "http://localhost/", "--dictionary", "testdata/dict.txt", "--seed", "42")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "seed requires shuffle")
+}
+
+func TestScanShouldNotShowTheProgressWhenTheOutputIsNotATerminal(t *testing.T) {
+	for _, extraArgs := range [][]string{{}, {"--no-progress"}} {
+		logger, loggerBuffer := test.NewLogger()
+
+		c := createCommand(logger)
+		assert.NotNil(t, c)
+
+		testServer, serverAssertion := test.NewServerWithAssertion(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}),
+		)
+
+		args := append(
+			[]string{"scan", testServer.URL, "--dictionary", "testdata/dict.txt", "--no-wildcard-detection"},
+			extraArgs...,
+		)
+
+		err := executeCommand(c, args...)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, serverAssertion.Len())
+		assert.NotContains(t, loggerBuffer.String(), "completed |")
+		assert.NotContains(t, loggerBuffer.String(), "Scan progress")
+
+		testServer.Close()
+	}
+}
+
+func TestScanWithProgressIntervalShouldLogTheProgressWhenTheOutputIsNotATerminal(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--threads",
+		"1",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--progress-interval",
+		"20ms",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "Scan progress")
+	assert.Contains(t, loggerBuffer.String(), "total=3")
+	assert.Contains(t, loggerBuffer.String(), "req/s")
+	assert.NotContains(t, loggerBuffer.String(), "completed |")
+}
+
+func TestScanWithInvalidProgressIntervalShouldErr(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "negative",
+			args:          []string{"--progress-interval", "-1s"},
+			expectedError: "invalid value for progress-interval: it cannot be negative",
+		},
+		{
+			name:          "with no progress",
+			args:          []string{"--progress-interval", "10s", "--no-progress"},
+			expectedError: "progress-interval and no-progress cannot be used at the same time",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(
+				c,
+				append([]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"}, tc.args...)...,
+			)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanInQuietModeShouldPrintOnlyTheURLsFound(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" || r.URL.Path == "/home/index.php" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	out, err := executeCommandWithOutput(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-methods",
+		"GET,POST",
+		"--scan-depth",
+		"1",
+		"--threads",
+		"1",
+		"-q",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, serverAssertion.Len())
+
+	assert.Equal(t, testServer.URL+"/home\n"+testServer.URL+"/home/index.php\n", out)
+	assert.Empty(t, loggerBuffer.String())
+}
+
+func TestScanWithJSONLinesShouldPrintAnObjectPerResult(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" || r.URL.Path == "/home/index.php" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	out, err := executeCommandWithOutput(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--jsonl",
+		"-q",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.Len(t, lines, 2)
+
+	urls := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		var r output.JSONResult
+		assert.NoError(t, json.Unmarshal([]byte(line), &r))
+		assert.Equal(t, http.StatusOK, r.StatusCode)
+
+		urls = append(urls, r.URL)
+	}
+
+	assert.ElementsMatch(t, []string{testServer.URL + "/home", testServer.URL + "/home/index.php"}, urls)
+	assert.Empty(t, loggerBuffer.String())
+}
+
+func TestScanWithWebhookShouldNotifyTheResults(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	payloads := make(chan webhook.Payload, 10)
+
+	webhookServer, webhookServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload webhook.Payload
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+			payloads <- payload
+		}),
+	)
+	defer webhookServer.Close()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/home":
+				_, _ = w.Write([]byte("home")) //nolint:errcheck
+			case "/blabla":
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--webhook-url",
+		webhookServer.URL,
+		"--webhook-status",
+		"200-299",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// the scan waits for the notifications to be sent before returning
+	assert.Equal(t, 1, webhookServerAssertion.Len())
+
+	expectedPayload := webhook.Payload{
+		Target: testServer.URL,
+		URL:    testServer.URL + "/home",
+		Path:   "/home",
+		Method: http.MethodGet,
+		Status: http.StatusOK,
+		Length: 4,
+	}
+	assert.Equal(t, expectedPayload, <-payloads)
+}
+
+func TestScanWithFailingWebhookShouldNotStopTheScan(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	webhookServer, webhookServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)
+	defer webhookServer.Close()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--webhook-url",
+		webhookServer.URL,
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Equal(t, 3, webhookServerAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "the webhook rejected the notification")
+	assert.Contains(t, loggerBuffer.String(), "3 results found")
+}
+
+func TestScanWithSlackWebhookShouldPostTheResultsInOneMessage(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	messages := make(chan webhook.SlackMessage, 10)
+
+	slackServer, slackServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var message webhook.SlackMessage
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+
+			messages <- message
+		}),
+	)
+	defer slackServer.Close()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/blabla" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--slack-webhook",
+		slackServer.URL+"/services/secret",
+		"--webhook-status",
+		"200",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	// the results found within the batch interval are posted together when the scan ends
+	assert.Equal(t, 1, slackServerAssertion.Len())
+
+	message := <-messages
+	assert.Equal(t, "dirstalk found 2 result(s)", message.Text)
+	assert.Len(t, message.Blocks, 2)
+	assert.Contains(t, message.Blocks[1].Text.Text, "Target <"+testServer.URL+"|"+testServer.URL+">")
+	assert.Contains(t, message.Blocks[1].Text.Text, "<"+testServer.URL+"/home|/home> [200] [GET]")
+	assert.Contains(t, message.Blocks[1].Text.Text, "<"+testServer.URL+"/home/index.php|/home/index.php> [200] [GET]")
+
+	assert.NotContains(t, loggerBuffer.String(), "secret")
+}
+
+func TestScanWithSaveResponsesShouldSaveTheResponsesOfTheResultsShown(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dir, err := ioutil.TempDir("", "dirstalk-responses")
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir) //nolint:errcheck
+	}()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/home":
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte("home")) //nolint:errcheck
+			case "/blabla":
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	err = executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--save-responses",
+		dir,
+		"--exclude-status",
+		"403",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+
+	// the response excluded via --exclude-status is not saved
+	assert.Len(t, files, 1)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(content), "url: "+testServer.URL+"/home\n")
+	assert.Contains(t, string(content), "status: 200\n")
+	assert.Contains(t, string(content), "  Content-Type: text/plain\n")
+	assert.True(t, strings.HasSuffix(string(content), "\n\nhome"))
+}
+
+func TestScanWithMaxSaveBytesShouldLogWhenTheLimitIsReached(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dir, err := ioutil.TempDir("", "dirstalk-responses")
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(dir) //nolint:errcheck
+	}()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err = executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--save-responses",
+		dir,
+		"--max-save-bytes",
+		"10",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+
+	assert.Equal(t, 1, strings.Count(loggerBuffer.String(), "The limit of bytes of the saved responses has been reached"))
+	assert.Contains(t, loggerBuffer.String(), "3 results found")
+}
+
+func TestScanWithNegativeMaxSaveBytesShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--save-responses",
+		"responses",
+		"--max-save-bytes",
+		"-1",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for max-save-bytes: it cannot be negative")
+}
+
+func TestScanWithInvalidWebhookShouldErr(t *testing.T) {
+	testCases := []struct {
+		flags         []string
+		expectedError string
+	}{
+		{
+			flags:         []string{"--webhook-url", "localhost:8080/notify"},
+			expectedError: "invalid value for webhook-url",
+		},
+		{
+			flags:         []string{"--webhook-url", "http://localhost:8080/notify", "--webhook-status", "abc"},
+			expectedError: "invalid value for webhook-status",
+		},
+		{
+			flags:         []string{"--slack-webhook", "ftp://hooks.slack.com/services/abc"},
+			expectedError: "invalid value for slack-webhook",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.expectedError, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			args := append([]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"}, tc.flags...)
+
+			err := executeCommand(c, args...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanShouldPrintTheSummaryOfTheScan(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "Summary:\n")
+	assert.Contains(t, loggerBuffer.String(), "Total requests:       3\n")
+	assert.Contains(t, loggerBuffer.String(), "Responses:            2xx: 1, 4xx: 2\n")
+	assert.Contains(t, loggerBuffer.String(), "Status codes:         404: 2, 200: 1\n")
+	assert.Contains(t, loggerBuffer.String(), "Discovered paths:     1\n")
+	assert.Contains(t, loggerBuffer.String(), "Errors:               0\n")
+	assert.Contains(t, loggerBuffer.String(), "Requests per second:")
+}
+
+func TestScanWithNoBannerShouldNotLogTheScanStartButPrintTheSummary(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--no-banner",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.NotContains(t, loggerBuffer.String(), "Starting scan")
+	assert.NotContains(t, loggerBuffer.String(), "Finished scan")
+
+	assert.Contains(t, loggerBuffer.String(), "Results for "+testServer.URL)
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), testServer.URL+"/home [200] [GET]")
+}
+
+func TestScanShouldNotColorTheStatusCodesWhenNotPrintingToATerminal(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "default"},
+		{name: "no-color", args: []string{"--no-color"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/home" {
+						return
+					}
+
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			args := []string{
+				"scan",
+				testServer.URL,
+				"--dictionary",
+				"testdata/dict.txt",
+				"--scan-depth",
+				"0",
+				"--no-wildcard-detection",
+			}
+
+			err := executeCommand(c, append(args, tc.args...)...)
+			assert.NoError(t, err)
+
+			assert.Contains(t, loggerBuffer.String(), testServer.URL+"/home [200] [GET]")
+			assert.NotContains(t, loggerBuffer.String(), "\x1b[")
+		})
+	}
+}
+
+func TestScanWithExitOnMatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		foundPath     string
+		expectedError error
+	}{
+		{name: "matches found", foundPath: "/home", expectedError: cmd.ErrMatchesFound},
+		{name: "no match", foundPath: "/admin", expectedError: nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == tc.foundPath {
+						return
+					}
+
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			out, err := executeCommandWithOutput(
+				c,
+				"scan",
+				testServer.URL,
+				"--dictionary",
+				"testdata/dict.txt",
+				"--scan-depth",
+				"0",
+				"--no-wildcard-detection",
+				"--exit-on-match",
+			)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, 3, serverAssertion.Len())
+
+			// finding something is not a failure of the command
+			assert.NotContains(t, out, "Error:")
+			assert.NotContains(t, out, "Usage:")
+
+			assert.Contains(t, loggerBuffer.String(), "Summary:")
+		})
+	}
+}
+
+func TestNoBannerShouldBeAvailableToAllTheCommands(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "--no-banner", "dictionary.generate", "./termination")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "handler.go")
+}
+
+func TestScanInQuietModeShouldErrWhenVerbose(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "http://localhost", "--dictionary", "testdata/dict.txt", "-q", "-v")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "quiet and verbose cannot be used at the same time")
+}
+
+func TestScanWhenVerboseShouldLogEachResponse(t *testing.T) {
+	testCases := []struct {
+		verbosityFlag    string
+		shouldLogHeaders bool
+		expectedLogLevel logrus.Level
+	}{
+		{verbosityFlag: "-v", shouldLogHeaders: false, expectedLogLevel: logrus.DebugLevel},
+		{verbosityFlag: "-vv", shouldLogHeaders: true, expectedLogLevel: logrus.TraceLevel},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.verbosityFlag, func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+			logger.SetLevel(logrus.InfoLevel)
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-Test", "test-header")
+
+					if r.URL.Path == "/home" {
+						return
+					}
+
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			err := executeCommand(
+				c,
+				"scan",
+				testServer.URL,
+				"--dictionary",
+				"testdata/dict.txt",
+				"--scan-depth",
+				"0",
+				"--no-wildcard-detection",
+				tc.verbosityFlag,
+			)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedLogLevel, logger.GetLevel())
+
+			assert.Contains(t, loggerBuffer.String(), "Response accepted by the filters")
+			assert.Contains(t, loggerBuffer.String(), "Response ignored by the filters")
+			assert.Contains(t, loggerBuffer.String(), `url="`+testServer.URL+`/blabla"`)
+			assert.Contains(t, loggerBuffer.String(), "status-code=404")
+			assert.Contains(t, loggerBuffer.String(), "duration=")
+			assert.Equal(t, tc.shouldLogHeaders, strings.Contains(loggerBuffer.String(), "test-header"))
+		})
+	}
+}
+
+func TestScanCommandCanBeInterrupted(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(time.Millisecond * 650)
+
+			if r.URL.Path == "/test/" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	go func() {
+		time.Sleep(time.Millisecond * 200)
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT) //nolint:errcheck
+	}()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict2.txt",
+		"-v",
+		"--http-timeout",
+		"900",
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "Received sigint")
+}
+
+func TestScanWithRemoteDictionary(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dictionaryServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dict := `home
+home/index.php
+blabla
+`
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(dict)) //nolint:errcheck
+		}),
+	)
+	defer dictionaryServer.Close()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		dictionaryServer.URL,
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestScanWithResolverShouldResolveTheTargetAndTheRemoteDictionaryViaIt(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dictionaryServer, dictionaryServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("home\nblabla\n")) //nolint:errcheck
+		}),
+	)
+	defer dictionaryServer.Close()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	dnsServer := test.MustStartDNSServer(
+		t,
+		map[string][]net.IP{
+			"dictionary.dirstalk.test": {net.ParseIP("127.0.0.1")},
+			"target.dirstalk.test":     {net.ParseIP("127.0.0.1")},
+		},
+	)
+	defer dnsServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://target.dirstalk.test:"+test.MustParseURL(t, testServer.URL).Port()+"/",
+		"--dictionary",
+		"http://dictionary.dirstalk.test:"+test.MustParseURL(t, dictionaryServer.URL).Port()+"/dict.txt",
+		"--resolver",
+		dnsServer.Addr(),
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, dictionaryServerAssertion.Len())
+	assert.Equal(t, 2, serverAssertion.Len())
+
+	assert.Contains(t, dnsServer.Queries(), "dictionary.dirstalk.test")
+	assert.Contains(t, dnsServer.Queries(), "target.dirstalk.test")
+}
+
+func TestScanWithInvalidResolverShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--resolver",
+		"10.0.0.1",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for resolver")
+}
+
+func TestScanWithUserAgentFlag(t *testing.T) {
+	const testUserAgent = "my_test_user_agent"
+
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--user-agent",
+		testUserAgent,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, testUserAgent, r.Header.Get("User-Agent"))
+	})
+
+	// to ensure we print the user agent to the cli
+	assert.Contains(t, loggerBuffer.String(), testUserAgent)
+}
+
+func TestScanWithRandomUserAgentShouldPickTheUserAgentsFromThePool(t *testing.T) {
+	userAgentFile := test.MustWriteTempFile(t, []byte("# custom pool\nfirst_user_agent\n\nsecond_user_agent\n"))
+	defer removeTempFile(userAgentFile)
+
+	testCases := []struct {
+		args       []string
+		userAgents []string
+	}{
+		{
+			args:       []string{"--random-user-agent"},
+			userAgents: client.BrowserUserAgents,
+		},
+		{
+			args:       []string{"--user-agent-file", userAgentFile},
+			userAgents: []string{"first_user_agent", "second_user_agent"},
+		},
+		{
+			args:       []string{"--random-user-agent", "--user-agent-file", userAgentFile},
+			userAgents: []string{"first_user_agent", "second_user_agent"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			args := append(
+				[]string{"scan", testServer.URL, "--dictionary", "testdata/dict.txt", "--no-wildcard-detection"},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.NoError(t, err)
+
+			assert.Equal(t, 3, serverAssertion.Len())
+			serverAssertion.Range(func(_ int, r http.Request) {
+				assert.Contains(t, tc.userAgents, r.Header.Get("User-Agent"))
+			})
+		})
+	}
+}
+
+func TestScanWithInvalidUserAgentsShouldErr(t *testing.T) {
+	emptyUserAgentFile := test.MustWriteTempFile(t, []byte("# nothing here\n"))
+	defer removeTempFile(emptyUserAgentFile)
+
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--user-agent", "my_user_agent", "--random-user-agent"},
+			expectedError: "user-agent cannot be used together with random-user-agent or user-agent-file",
+		},
+		{
+			args:          []string{"--user-agent-file", emptyUserAgentFile},
+			expectedError: "invalid value for user-agent-file: no user agent found",
+		},
+		{
+			args:          []string{"--user-agent-file", "/root/123/user-agents.txt"},
+			expectedError: "failed to read user-agent-file",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			args := append(
+				[]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanShouldUseTheValuesOfTheConfigFileUnlessSpecifiedViaCommandLine(t *testing.T) {
+	configFile := test.MustWriteTempFile(
+		t,
+		[]byte(`# scan defaults
+dictionary: testdata/dict.txt
+threads: 1
+user-agent: "file_user_agent"
+no-wildcard-detection: true
+scan-depth: 0
+header:
+  - "X-First: 1"
+  - 'X-Second: 2'
+extension:
+  - php
+`),
+	)
+	defer removeTempFile(configFile)
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "scan", testServer.URL, "--config", configFile, "--user-agent", "cli_user_agent")
+	assert.NoError(t, err)
+
+	// 3 entries in the dictionary, the ones without an extension also with the php one
+	assert.Equal(t, 5, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "cli_user_agent", r.Header.Get("User-Agent"))
+		assert.Equal(t, "1", r.Header.Get("X-First"))
+		assert.Equal(t, "2", r.Header.Get("X-Second"))
+	})
+}
+
+func TestScanShouldUseTheConfigFileInTheWorkingDirectory(t *testing.T) {
+	err := ioutil.WriteFile("dirstalk.yaml", []byte("dictionary: testdata/dict.txt\nno-wildcard-detection: true\n"), 0600)
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = os.Remove("dirstalk.yaml") //nolint:errcheck
+	}()
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err = executeCommand(c, "scan", testServer.URL)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestScanShouldUseTheEnvironmentVariablesUnlessSpecifiedViaCommandLine(t *testing.T) {
+	configFile := test.MustWriteTempFile(t, []byte("user-agent: file_user_agent\nthreads: 5\n"))
+	defer removeTempFile(configFile)
+
+	environment := map[string]string{
+		"DIRSTALK_DICTIONARY":            "testdata/dict.txt",
+		"DIRSTALK_THREADS":               "1",
+		"DIRSTALK_TIMEOUT":               "1s",
+		"DIRSTALK_USER_AGENT":            "env_user_agent",
+		"DIRSTALK_HEADER":                "Authorization: Bearer token",
+		"DIRSTALK_NO_WILDCARD_DETECTION": "true",
+		"DIRSTALK_CONFIG":                configFile,
+	}
+
+	for variable, value := range environment {
+		assert.NoError(t, os.Setenv(variable, value))
+	}
+
+	defer func() {
+		for variable := range environment {
+			_ = os.Unsetenv(variable) //nolint:errcheck
+		}
+	}()
+
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "scan", testServer.URL, "--threads", "2")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "env_user_agent", r.Header.Get("User-Agent"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+	})
+
+	assert.Contains(t, loggerBuffer.String(), "threads=2")
+	assert.Contains(t, loggerBuffer.String(), "timeout=1000")
+}
+
+func TestScanShouldNotOverrideTheTimeoutSpecifiedViaCommandLineWithTheEnvironment(t *testing.T) {
+	testCases := []struct {
+		name            string
+		variable        string
+		value           string
+		args            []string
+		expectedTimeout string
+	}{
+		{
+			name:            "timeout in the environment",
+			variable:        "DIRSTALK_TIMEOUT",
+			value:           "10s",
+			args:            []string{"--http-timeout", "150"},
+			expectedTimeout: "timeout=150",
+		},
+		{
+			name:            "http-timeout in the environment",
+			variable:        "DIRSTALK_HTTP_TIMEOUT",
+			value:           "10000",
+			args:            []string{"--timeout", "150ms"},
+			expectedTimeout: "timeout=150",
+		},
+		{
+			name:            "no timeout specified via command line",
+			variable:        "DIRSTALK_TIMEOUT",
+			value:           "150ms",
+			expectedTimeout: "timeout=150",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, os.Setenv(tc.variable, tc.value))
+
+			defer func() {
+				_ = os.Unsetenv(tc.variable) //nolint:errcheck
+			}()
+
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			args := append([]string{"scan", testServer.URL, "--dictionary", "testdata/dict.txt"}, tc.args...)
+
+			err := executeCommand(c, args...)
+			assert.NoError(t, err)
+
+			assert.Contains(t, loggerBuffer.String(), tc.expectedTimeout)
+		})
+	}
+}
+
+func TestScanWithInvalidEnvironmentVariableShouldErr(t *testing.T) {
+	assert.NoError(t, os.Setenv("DIRSTALK_TIMEOUT", "soon"))
+
+	defer func() {
+		_ = os.Unsetenv("DIRSTALK_TIMEOUT") //nolint:errcheck
+	}()
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "http://localhost/", "--dictionary", "testdata/dict.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for DIRSTALK_TIMEOUT")
+}
+
+func TestScanShouldNotOverrideTheTimeoutSpecifiedViaCommandLineWithTheConfigFile(t *testing.T) {
+	testCases := []struct {
+		name            string
+		config          string
+		args            []string
+		expectedTimeout string
+	}{
+		{
+			name:            "timeout in the config file",
+			config:          "timeout: 10s\n",
+			args:            []string{"--http-timeout", "150"},
+			expectedTimeout: "timeout=150",
+		},
+		{
+			name:            "http-timeout in the config file",
+			config:          "http-timeout: 10000\n",
+			args:            []string{"--timeout", "150ms"},
+			expectedTimeout: "timeout=150",
+		},
+		{
+			name:            "no timeout specified via command line",
+			config:          "http-timeout: 150\n",
+			expectedTimeout: "timeout=150",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			configFile := test.MustWriteTempFile(t, []byte(tc.config))
+			defer removeTempFile(configFile)
+
+			logger, loggerBuffer := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			args := append(
+				[]string{"scan", testServer.URL, "--dictionary", "testdata/dict.txt", "--config", configFile},
+				tc.args...,
+			)
+
+			err := executeCommand(c, args...)
+			assert.NoError(t, err)
+
+			assert.Contains(t, loggerBuffer.String(), tc.expectedTimeout)
+		})
+	}
+}
+
+func TestScanWithInvalidConfigFileShouldErr(t *testing.T) {
+	testCases := []struct {
+		content       string
+		expectedError string
+	}{
+		{
+			content:       "dictionary: testdata/dict.txt\nunknown-flag: 1\n",
+			expectedError: "unknown flag unknown-flag at line 2",
+		},
+		{
+			content:       "dictionary: testdata/dict.txt\nthreads: many\n",
+			expectedError: "invalid value for threads at line 2",
+		},
+		{
+			content:       "dictionary: testdata/dict.txt\nheader:\n",
+			expectedError: "no value for header at line 2",
+		},
+		{
+			content:       "- value\n",
+			expectedError: "list item without a flag at line 1",
+		},
+		{
+			content:       "dictionary testdata/dict.txt\n",
+			expectedError: "expected `flag: value` at line 1",
+		},
+		{
+			content:       "threads: 1\nthreads: 2\n",
+			expectedError: "threads specified more than once at line 2",
+		},
+		{
+			content:       "config: another.yaml\n",
+			expectedError: "unknown flag config at line 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.expectedError, func(t *testing.T) {
+			configFile := test.MustWriteTempFile(t, []byte(tc.content))
+			defer removeTempFile(configFile)
+
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, "scan", "http://localhost/", "--config", configFile)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithMissingConfigFileShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "http://localhost/", "--config", "/root/123/dirstalk.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config file /root/123/dirstalk.yaml")
+}
+
+func TestScanShouldUseTheConfigFileRetrievedFromAURL(t *testing.T) {
+	configServer, configServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("dictionary: testdata/dict.txt\nno-wildcard-detection: true\nscan-depth: 0\n" +
+				"header:\n  - \"X-Config: 1\"\n"))
+		}),
+	)
+	defer configServer.Close()
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "scan", testServer.URL, "--config", configServer.URL+"/dirstalk.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, configServerAssertion.Len())
+	configServerAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "/dirstalk.yaml", r.URL.Path)
+	})
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "1", r.Header.Get("X-Config"))
+	})
+}
+
+func TestScanWithConfigFileFromAURLShouldErrWhenItCannotBeRetrieved(t *testing.T) {
+	configServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer configServer.Close()
+
+	for _, flag := range []string{"--config", "--scan-spec"} {
+		logger, _ := test.NewLogger()
+
+		c := createCommand(logger)
+		assert.NotNil(t, c)
+
+		args := []string{"scan", flag, configServer.URL + "/dirstalk.yaml"}
+		if flag == "--config" {
+			args = append(args, "http://localhost/")
+		}
+
+		err := executeCommand(c, args...)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), configServer.URL+"/dirstalk.yaml")
+		assert.Contains(t, err.Error(), "status code 404")
+	}
+}
+
+func TestScanWithScanSpecRetrievedFromAURLShouldScanTheTargetsOfTheSpec(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	specServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("targets:\n  - " + testServer.URL + "\ndictionary: testdata/dict.txt\n" +
+				"no-wildcard-detection: true\nscan-depth: 0\n"))
+		}),
+	)
+	defer specServer.Close()
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "scan", "--scan-spec", specServer.URL+"/spec.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestScanWithScanSpecShouldScanTheTargetsOfTheSpec(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	firstServer, firstServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer firstServer.Close()
+
+	secondServer, secondServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer secondServer.Close()
+
+	specFile := test.MustWriteTempFile(
+		t,
+		[]byte(`targets:
+  - `+firstServer.URL+`
+  - `+secondServer.URL+`
+dictionary: testdata/dict.txt
+extension:
+  - php
+header:
+  - "X-Spec: 1"
+threads: 1
+user-agent: spec_user_agent
+no-wildcard-detection: true
+scan-depth: 0
+`),
+	)
+	defer removeTempFile(specFile)
+
+	// the scan spec is self-contained, the environment is not used
+	assert.NoError(t, os.Setenv("DIRSTALK_USER_AGENT", "env_user_agent"))
+
+	defer func() {
+		_ = os.Unsetenv("DIRSTALK_USER_AGENT") //nolint:errcheck
+	}()
+
+	err := executeCommand(c, "scan", "--scan-spec", specFile, "--threads", "2")
+	assert.NoError(t, err)
+
+	// 3 entries in the dictionary, the ones without an extension also with the php one
+	for _, serverAssertion := range []*test.ServerAssertion{firstServerAssertion, secondServerAssertion} {
+		assert.Equal(t, 5, serverAssertion.Len())
+		serverAssertion.Range(func(_ int, r http.Request) {
+			assert.Equal(t, "spec_user_agent", r.Header.Get("User-Agent"))
+			assert.Equal(t, "1", r.Header.Get("X-Spec"))
+		})
+	}
+}
+
+func TestScanWithInvalidScanSpecShouldErr(t *testing.T) {
+	testCases := []struct {
+		content       string
+		args          []string
+		expectedError string
+	}{
+		{
+			content:       "dictionary: testdata/dict.txt\n",
+			expectedError: "targets is required",
+		},
+		{
+			content:       "targets: http://localhost/\n",
+			expectedError: "either dictionary or vhost-dictionary is required",
+		},
+		{
+			content:       "targets:\n  - http://localhost/\n  - localhost\ndictionary: testdata/dict.txt\n",
+			expectedError: "invalid value for targets at line 1: localhost is not a valid url",
+		},
+		{
+			content:       "targets: http://localhost/\ndictionary: testdata/dict.txt\nunknown-field: 1\n",
+			expectedError: "unknown field unknown-field at line 3",
+		},
+		{
+			content:       "targets: http://localhost/\ndictionary: testdata/dict.txt\ntargets-file: targets.txt\n",
+			expectedError: "unknown field targets-file at line 3",
+		},
+		{
+			content:       "targets: http://localhost/\ndictionary: testdata/dict.txt\nthreads: many\n",
+			expectedError: "invalid value for threads at line 3",
+		},
+		{
+			content:       "targets: http://localhost/\nheader:\ndictionary: testdata/dict.txt\n",
+			expectedError: "no value for header at line 2",
+		},
+		{
+			content:       "targets: http://localhost/\ndictionary: testdata/dict.txt\n",
+			args:          []string{"http://localhost/"},
+			expectedError: "the urls to scan cannot be specified as arguments when using scan-spec",
+		},
+		{
+			content:       "targets: http://localhost/\ndictionary: testdata/dict.txt\n",
+			args:          []string{"--config", "dirstalk.yaml"},
+			expectedError: "scan-spec and config cannot be used at the same time",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.expectedError, func(t *testing.T) {
+			specFile := test.MustWriteTempFile(t, []byte(tc.content))
+			defer removeTempFile(specFile)
+
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, append([]string{"scan", "--scan-spec", specFile}, tc.args...)...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithCookies(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--cookie",
+		"name1=val1",
+		"--cookie",
+		"name2=val2",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+	)
+	assert.NoError(t, err)
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, 2, len(r.Cookies()))
+
+		assert.Equal(t, r.Cookies()[0].Name, "name1")
+		assert.Equal(t, r.Cookies()[0].Value, "val1")
+
+		assert.Equal(t, r.Cookies()[1].Name, "name2")
+		assert.Equal(t, r.Cookies()[1].Value, "val2")
+	})
+
+	// to ensure we print the cookies to the cli
+	assert.Contains(t, loggerBuffer.String(), "name1=val1")
+	assert.Contains(t, loggerBuffer.String(), "name2=val2")
+}
+
+func TestScanWithCookieValueContainingEqualSign(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--cookie",
+		"session=dGVzdA==",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, 1, len(r.Cookies()))
+
+		assert.Equal(t, "session", r.Cookies()[0].Name)
+		assert.Equal(t, "dGVzdA==", r.Cookies()[0].Value)
+	})
+}
+
+func TestWhenProvidingCookiesInWrongFormatShouldErr(t *testing.T) {
+	const malformedCookie = "gibberish"
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--cookie",
+		malformedCookie,
+		"--dictionary",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cookie format is invalid")
+	assert.Contains(t, err.Error(), malformedCookie)
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestScanWithCookieFileShouldSendTheCookiesMatchingTheTargets(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	cookieFile := test.MustWriteTempFile(
+		t,
+		[]byte(strings.Join(
+			[]string{
+				"# Netscape HTTP Cookie File",
+				"127.0.0.1\tFALSE\t/\tFALSE\t0\tsession\tabc",
+				"#HttpOnly_127.0.0.1\tFALSE\t/home\tFALSE\t4102444800\thome_cookie\txyz",
+				"127.0.0.1\tFALSE\t/\tFALSE\t946684800\texpired\tvalue",
+				".example.com\tTRUE\t/\tFALSE\t0\tanother_domain\tvalue",
+			},
+			"\n",
+		)),
+	)
+	defer os.Remove(cookieFile) //nolint:errcheck
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--cookie",
+		"name1=val1",
+		"--cookie-file",
+		cookieFile,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		cookies := make(map[string]string)
+		for _, c := range r.Cookies() {
+			cookies[c.Name] = c.Value
+		}
+
+		expectedCookies := map[string]string{"name1": "val1", "session": "abc"}
+		if strings.HasPrefix(r.URL.Path, "/home") {
+			expectedCookies["home_cookie"] = "xyz"
+		}
+
+		assert.Equal(t, expectedCookies, cookies, r.URL.Path)
+	})
+
+	assert.Contains(t, loggerBuffer.String(), "file-cookies=3")
+}
+
+func TestScanWithCookieFileShouldFailForAnInvalidFile(t *testing.T) {
+	testCases := []struct {
+		content       string
+		expectedError string
+	}{
+		{
+			content:       "127.0.0.1\tFALSE\t/\tFALSE\tnever\tsession\tabc",
+			expectedError: "invalid cookie at line 1",
+		},
+		{
+			content:       "# Netscape HTTP Cookie File\n127.0.0.1\tFALSE\t/\tFALSE\t946684800\texpired\tvalue",
+			expectedError: "no valid cookie found",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.expectedError, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			)
+			defer testServer.Close()
+
+			cookieFile := test.MustWriteTempFile(t, []byte(tc.content))
+			defer os.Remove(cookieFile) //nolint:errcheck
+
+			err := executeCommand(
+				c,
+				"scan",
+				testServer.URL,
+				"--cookie-file",
+				cookieFile,
+				"--dictionary",
+				"testdata/dict.txt",
+			)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+
+			assert.Equal(t, 0, serverAssertion.Len())
+		})
+	}
+}
+
+func TestScanWithNonExistingCookieFileShouldFail(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--cookie-file",
+		"testdata/gibberish_nonexisting_file",
+		"--dictionary",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read cookie-file")
+}
+
+func TestScanWithCookieJar(t *testing.T) {
+	const (
+		serverCookieName  = "server_cookie_name"
+		serverCookieValue = "server_cookie_value"
+	)
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	once := sync.Once{}
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			once.Do(func() {
+				http.SetCookie(
+					w,
+					&http.Cookie{
+						Name:    serverCookieName,
+						Value:   serverCookieValue,
+						Expires: time.Now().AddDate(0, 1, 0),
+					},
+				)
+			})
+		}),
+	)
+
+	defer testServer.Close() //nolint:errcheck
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--use-cookie-jar",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"-t",
+		"1",
+	)
+	assert.NoError(t, err)
+
+	serverAssertion.Range(func(index int, r http.Request) {
+		if index == 0 { // first request should have no cookies
+			assert.Equal(t, 0, len(r.Cookies()))
+			return
+		}
+
+		assert.Equal(t, 1, len(r.Cookies()))
+		assert.Equal(t, r.Cookies()[0].Name, serverCookieName)
+		assert.Equal(t, r.Cookies()[0].Value, serverCookieValue)
+	})
+}
+
+func TestScanWithUnknownFlagShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--gibberishflag",
+		"--dictionary",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown flag")
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestScanWithHeaders(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--header",
+		"Accept-Language: en-US,en;q=0.5",
+		"--header",
+		`"Authorization: Bearer 123"`,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+	)
+	assert.NoError(t, err)
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, 2, len(r.Header))
+
+		assert.Equal(t, "en-US,en;q=0.5", r.Header.Get("Accept-Language"))
+		assert.Equal(t, "Bearer 123", r.Header.Get("Authorization"))
+	})
+
+	// to ensure we print the headers to the cli
+	assert.Contains(t, loggerBuffer.String(), "Accept-Language")
+	assert.Contains(t, loggerBuffer.String(), "Authorization")
+	assert.Contains(t, loggerBuffer.String(), "Bearer 123")
+}
+
+func TestScanWithHostHeader(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--host-header",
+		"vhost.example.com",
+		"--header",
+		"Host: header.example.com",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "vhost.example.com", r.Host)
+	})
+
+	assert.Contains(t, loggerBuffer.String(), "host-header=vhost.example.com")
+}
+
+func TestScanWithVHostDictionaryShouldReportTheHostsDifferingFromTheBaseline(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Host {
+			case "admin.example.com":
+				_, _ = w.Write([]byte("admin panel")) //nolint:errcheck
+			case "dev.example.com":
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte("default site")) //nolint:errcheck
+			default:
+				_, _ = w.Write([]byte("default site")) //nolint:errcheck
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	vhostDictionaryPath := test.MustWriteTempFile(t, []byte("admin.example.com\ndev.example.com\nwww.example.com\n"))
+	defer removeTempFile(vhostDictionaryPath)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--vhost-dictionary",
+		vhostDictionaryPath,
+	)
+	assert.NoError(t, err)
+
+	// the baseline request and one request per host, the paths are not scanned
+	assert.Equal(t, 4, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "/", r.URL.Path)
+	})
+
+	assert.Contains(t, loggerBuffer.String(), "Baseline response detected")
+	assert.Contains(t, loggerBuffer.String(), "2 results found")
+	assert.Contains(t, loggerBuffer.String(), "[200] [GET] [Host: admin.example.com]")
+	assert.Contains(t, loggerBuffer.String(), "[403] [GET] [Host: dev.example.com]")
+	assert.NotContains(t, loggerBuffer.String(), "[Host: www.example.com]")
+}
+
+func TestScanWithVHostDictionaryInDryRunShouldPrintTheHosts(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	vhostDictionaryPath := test.MustWriteTempFile(t, []byte("admin.example.com\ndev.example.com\n"))
+	defer removeTempFile(vhostDictionaryPath)
+
+	out, err := executeCommandWithOutput(
+		c,
+		"scan",
+		"http://10.0.0.1/",
+		"--vhost-dictionary",
+		vhostDictionaryPath,
+		"--dry-run",
+	)
+	assert.NoError(t, err)
+
+	expectedRequests := []string{
+		"GET http://10.0.0.1/ Host: admin.example.com",
+		"GET http://10.0.0.1/ Host: dev.example.com",
+	}
+	assert.Equal(t, expectedRequests, strings.Split(strings.TrimSpace(out), "\n"))
+}
+
+func TestScanWithInvalidVHostDictionaryCombinationsShouldErr(t *testing.T) {
+	testCases := []struct {
+		name          string
+		flags         []string
+		expectedError string
+	}{
+		{
+			name:          "no dictionary",
+			flags:         []string{},
+			expectedError: "either dictionary or vhost-dictionary is required",
+		},
+		{
+			name:          "dictionary",
+			flags:         []string{"--vhost-dictionary", "testdata/dict.txt", "--dictionary", "testdata/dict.txt"},
+			expectedError: "dictionary and vhost-dictionary cannot be used at the same time",
+		},
+		{
+			name:          "host header",
+			flags:         []string{"--vhost-dictionary", "testdata/dict.txt", "--host-header", "vhost.example.com"},
+			expectedError: "vhost-dictionary and host-header cannot be used at the same time",
+		},
+		{
+			name:          "Host via header",
+			flags:         []string{"--vhost-dictionary", "testdata/dict.txt", "--header", "host: vhost.example.com"},
+			expectedError: "vhost-dictionary cannot be used together with a Host header",
+		},
+		{
+			name:          "resume from",
+			flags:         []string{"--vhost-dictionary", "testdata/dict.txt", "--resume-from", "state.json"},
+			expectedError: "vhost-dictionary and resume-from cannot be used at the same time",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, append([]string{"scan", "http://localhost/"}, tc.flags...)...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithRequestTemplateShouldReplaceThePlaceholdersWithTheWords(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var (
+		receivedBodies   []string
+		receivedBodiesMx sync.Mutex
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			receivedBodiesMx.Lock()
+			receivedBodies = append(receivedBodies, string(b))
+			receivedBodiesMx.Unlock()
+
+			if r.Header.Get("X-Role") != "home" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	templatePath := test.MustWriteTempFile(
+		t,
+		[]byte("PUT /api/users?FUZZ=1 HTTP/1.1\nX-Role: FUZZ\nX-Literal: \\FUZZ\n\nrole=FUZZ"),
+	)
+	defer removeTempFile(templatePath)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--request-template",
+		templatePath,
+		"--http-timeout",
+		"300",
+	)
+	assert.NoError(t, err)
+
+	// no wildcard detection, a request per word and nothing scanned recursively
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/users", r.URL.Path)
+		assert.Equal(t, r.Header.Get("X-Role")+"=1", r.URL.RawQuery)
+		assert.Equal(t, "FUZZ", r.Header.Get("X-Literal"))
+	})
+
+	sort.Strings(receivedBodies)
+	assert.Equal(t, []string{"role=blabla", "role=home", "role=home/index.php"}, receivedBodies)
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/api/users?home=1 [200] [PUT] [Word: home]")
+}
+
+func TestScanWithRequestTemplateInDryRunShouldPrintTheRequests(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	templatePath := test.MustWriteTempFile(t, []byte("GET /index.php?page=FUZZ HTTP/1.1\n"))
+	defer removeTempFile(templatePath)
+
+	out, err := executeCommandWithOutput(
+		c,
+		"scan",
+		"http://10.0.0.1/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--request-template",
+		templatePath,
+		"--dry-run",
+	)
+	assert.NoError(t, err)
+
+	expectedRequests := []string{
+		"GET http://10.0.0.1/index.php?page=home Word: home",
+		"GET http://10.0.0.1/index.php?page=home/index.php Word: home/index.php",
+		"GET http://10.0.0.1/index.php?page=blabla Word: blabla",
+	}
+	assert.Equal(t, expectedRequests, strings.Split(strings.TrimSpace(out), "\n"))
+}
+
+func TestScanWithRequestTemplateAndSecondDictionaryShouldRequestEveryCombination(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var (
+		receivedBodies   []string
+		receivedBodiesMx sync.Mutex
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			receivedBodiesMx.Lock()
+			receivedBodies = append(receivedBodies, string(b))
+			receivedBodiesMx.Unlock()
+
+			if string(b) != "user=home&password=secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	templatePath := test.MustWriteTempFile(t, []byte("POST /login HTTP/1.1\n\nuser=FUZZ1&password=FUZZ2"))
+	defer removeTempFile(templatePath)
+
+	secondDictionaryPath := test.MustWriteTempFile(t, []byte("1234\nsecret\n"))
+	defer removeTempFile(secondDictionaryPath)
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--request-template",
+		templatePath,
+		"--dictionary-2",
+		secondDictionaryPath,
+		"--http-statuses-to-ignore",
+		"401",
+		"--http-timeout",
+		"300",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	sort.Strings(receivedBodies)
+	assert.Equal(
+		t,
+		[]string{
+			"user=blabla&password=1234",
+			"user=blabla&password=secret",
+			"user=home&password=1234",
+			"user=home&password=secret",
+			"user=home/index.php&password=1234",
+			"user=home/index.php&password=secret",
+		},
+		receivedBodies,
+	)
+
+	assert.Contains(t, loggerBuffer.String(), "1 results found")
+	assert.Contains(t, loggerBuffer.String(), "/login [200] [POST] [Word: home, secret]")
+}
+
+func TestScanWithRequestTemplateInPitchforkModeShouldCombineTheWordsInParallel(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	templatePath := test.MustWriteTempFile(t, []byte("GET /FUZZ1?token=FUZZ2 HTTP/1.1\n"))
+	defer removeTempFile(templatePath)
+
+	secondDictionaryPath := test.MustWriteTempFile(t, []byte("a\nb\n"))
+	defer removeTempFile(secondDictionaryPath)
+
+	out, err := executeCommandWithOutput(
+		c,
+		"scan",
+		"http://10.0.0.1/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--request-template",
+		templatePath,
+		"--dictionary-2",
+		secondDictionaryPath,
+		"--mode",
+		"pitchfork",
+		"--dry-run",
+	)
+	assert.NoError(t, err)
+
+	expectedRequests := []string{
+		"GET http://10.0.0.1/home?token=a Word: home, a",
+		"GET http://10.0.0.1/home/index.php?token=b Word: home/index.php, b",
+	}
+	assert.Equal(t, expectedRequests, strings.Split(strings.TrimSpace(out), "\n"))
+}
+
+func TestScanWithTooManyCombinationsShouldRequireConfirmation(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	templatePath := test.MustWriteTempFile(t, []byte("GET /FUZZ1/FUZZ2 HTTP/1.1\n"))
+	defer removeTempFile(templatePath)
+
+	words := make([]string, 0, 101)
+	for i := 0; i < 101; i++ {
+		words = append(words, strconv.Itoa(i))
+	}
+
+	dictionaryPath := test.MustWriteTempFile(t, []byte(strings.Join(words, "\n")))
+	defer removeTempFile(dictionaryPath)
+
+	secondDictionaryPath := test.MustWriteTempFile(t, []byte(strings.Join(words[:100], "\n")))
+	defer removeTempFile(secondDictionaryPath)
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	c.SetIn(strings.NewReader(""))
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		dictionaryPath,
+		"--request-template",
+		templatePath,
+		"--dictionary-2",
+		secondDictionaryPath,
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "would perform 10100 requests, use --yes to confirm it")
+	assert.Equal(t, 0, serverAssertion.Len())
+
+	// the requests are capped by the limit of the scan
+	logger, _ = test.NewLogger()
+
+	c = createCommand(logger)
+	assert.NotNil(t, c)
+
+	c.SetIn(strings.NewReader(""))
+
+	err = executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		dictionaryPath,
+		"--request-template",
+		templatePath,
+		"--dictionary-2",
+		secondDictionaryPath,
+		"--max-requests",
+		"10",
+		"--threads",
+		"1",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, serverAssertion.Len())
+}
+
+func TestScanWithInvalidRequestTemplateCombinationsShouldErr(t *testing.T) {
+	templatePath := test.MustWriteTempFile(t, []byte("GET /FUZZ HTTP/1.1\n"))
+	defer removeTempFile(templatePath)
+
+	noPlaceholderPath := test.MustWriteTempFile(t, []byte("GET /home HTTP/1.1\n"))
+	defer removeTempFile(noPlaceholderPath)
+
+	secondPlaceholderPath := test.MustWriteTempFile(t, []byte("GET /FUZZ1/FUZZ2 HTTP/1.1\n"))
+	defer removeTempFile(secondPlaceholderPath)
+
+	testCases := []struct {
+		name          string
+		flags         []string
+		expectedError string
+	}{
+		{
+			name:          "unreadable template",
+			flags:         []string{"--dictionary", "testdata/dict.txt", "--request-template", "/root/gibberish.txt"},
+			expectedError: "failed to read /root/gibberish.txt",
+		},
+		{
+			name:          "no placeholder",
+			flags:         []string{"--dictionary", "testdata/dict.txt", "--request-template", noPlaceholderPath},
+			expectedError: "invalid value for request-template: the request template doesn't contain the FUZZ placeholder",
+		},
+		{
+			name:          "vhost dictionary",
+			flags:         []string{"--vhost-dictionary", "testdata/dict.txt", "--request-template", templatePath},
+			expectedError: "request-template and vhost-dictionary cannot be used at the same time",
+		},
+		{
+			name: "http methods",
+			flags: []string{
+				"--dictionary", "testdata/dict.txt", "--request-template", templatePath, "--http-methods", "POST",
+			},
+			expectedError: "request-template and http-methods cannot be used at the same time",
+		},
+		{
+			name: "resume from",
+			flags: []string{
+				"--dictionary", "testdata/dict.txt", "--request-template", templatePath, "--resume-from", "state.json",
+			},
+			expectedError: "request-template and resume-from cannot be used at the same time",
+		},
+		{
+			name: "baseline request",
+			flags: []string{
+				"--dictionary", "testdata/dict.txt", "--request-template", templatePath, "--baseline-request",
+			},
+			expectedError: "request-template and baseline-request cannot be used at the same time",
+		},
+		{
+			name:          "second dictionary without template",
+			flags:         []string{"--dictionary", "testdata/dict.txt", "--dictionary-2", "testdata/dict.txt"},
+			expectedError: "dictionary-2 requires request-template",
+		},
+		{
+			name: "second dictionary without second placeholder",
+			flags: []string{
+				"--dictionary", "testdata/dict.txt", "--request-template", templatePath, "--dictionary-2", "testdata/dict.txt",
+			},
+			expectedError: "the request template doesn't contain the FUZZ2 placeholder, required by dictionary-2",
+		},
+		{
+			name:          "second placeholder without second dictionary",
+			flags:         []string{"--dictionary", "testdata/dict.txt", "--request-template", secondPlaceholderPath},
+			expectedError: "the request template contains the FUZZ2 placeholder, it requires dictionary-2",
+		},
+		{
+			name: "both dictionaries from stdin",
+			flags: []string{
+				"--dictionary", "-", "--request-template", secondPlaceholderPath, "--dictionary-2", "-",
+			},
+			expectedError: "dictionary and dictionary-2 cannot be both read from the standard input",
+		},
+		{
+			name: "invalid mode",
+			flags: []string{
+				"--dictionary", "testdata/dict.txt", "--request-template", secondPlaceholderPath,
+				"--dictionary-2", "testdata/dict.txt", "--mode", "sniper",
+			},
+			expectedError: "invalid value for mode: sniper, the available ones are: clusterbomb, pitchfork",
+		},
+		{
+			name: "mode without second dictionary",
+			flags: []string{
+				"--dictionary", "testdata/dict.txt", "--request-template", templatePath, "--mode", "pitchfork",
+			},
+			expectedError: "mode requires dictionary-2",
+		},
+		{
+			name: "shuffle in pitchfork mode",
+			flags: []string{
+				"--dictionary", "testdata/dict.txt", "--request-template", secondPlaceholderPath,
+				"--dictionary-2", "testdata/dict.txt", "--mode", "pitchfork", "--shuffle",
+			},
+			expectedError: "shuffle cannot be used with mode pitchfork",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(c, append([]string{"scan", "http://localhost/"}, tc.flags...)...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithHeadersContainingColonsInTheValue(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--header",
+		"Referer: https://example.com:8080/path",
+		"--header",
+		"X-Api-Key:abc",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "https://example.com:8080/path", r.Header.Get("Referer"))
+		assert.Equal(t, "abc", r.Header.Get("X-Api-Key"))
+	})
+}
+
+func TestScanWithUserAgentFlagShouldOverrideUserAgentHeader(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--header",
+		"User-Agent: header_user_agent",
+		"--user-agent",
+		"flag_user_agent",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "flag_user_agent", r.Header.Get("User-Agent"))
+	})
+}
+
+func TestScanWithBasicAuth(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--basic-auth",
+		"myuser:my:password",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "myuser", username)
+		assert.Equal(t, "my:password", password)
+	})
+
+	assert.NotContains(t, loggerBuffer.String(), "my:password")
+}
+
+func TestScanWithBasicAuthAndAuthorizationHeaderShouldPreferTheHeader(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--basic-auth",
+		"myuser:mypassword",
+		"--header",
+		"Authorization: Bearer 123",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "Bearer 123", r.Header.Get("Authorization"))
+	})
+}
+
+func TestScanWithMalformedBasicAuthShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--basic-auth",
+		"gibberish",
+		"--dictionary",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "basic-auth is in invalid format, expected user:password")
+}
+
+func TestScanWithNTLMShouldStartTheHandshakeWhenTheServerRequiresIt(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.Header().Set("WWW-Authenticate", "NTLM")
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			// the server doesn't send a challenge, the handshake stops at the negotiate message
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--ntlm-user",
+		"myuser",
+		"--ntlm-password",
+		"mypassword",
+		"--ntlm-domain",
+		"mydomain",
+		"--no-check-certificate",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+		"--scan-depth",
+		"0",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	negotiateMessages := 0
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		if strings.HasPrefix(r.Header.Get("Authorization"), "NTLM TlRMTVNTUAABAAAA") {
+			negotiateMessages++
+		}
+	})
+	assert.Equal(t, 3, negotiateMessages)
+
+	assert.NotContains(t, loggerBuffer.String(), "mypassword")
+}
+
+func TestScanWithNTLMPasswordWithoutUserShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--ntlm-password",
+		"mypassword",
+		"--dictionary",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ntlm-password and ntlm-domain require ntlm-user")
+}
+
+func TestScanWithNTLMAndBasicAuthShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--ntlm-user",
+		"myuser",
+		"--basic-auth",
+		"myuser:mypassword",
+		"--dictionary",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "basic-auth and ntlm-user cannot be used at the same time")
+}
+
+func TestScanWithBearerTokenShouldSendItWithEveryRequest(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--bearer-token",
+		"mytoken",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+	})
+
+	assert.NotContains(t, loggerBuffer.String(), "mytoken")
+}
+
+func TestScanWithBearerTokenFileShouldSendTheTokenWithEveryRequest(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	tokenFile := test.MustWriteTempFile(t, []byte("mytoken\n"))
+	defer removeTempFile(tokenFile)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--bearer-token-file",
+		tokenFile,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+	})
+}
+
+func TestScanWithBearerTokenAndAuthorizationHeaderShouldPreferTheHeader(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--bearer-token",
+		"mytoken",
+		"--header",
+		"Authorization: Bearer 123",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "Bearer 123", r.Header.Get("Authorization"))
+	})
+}
+
+func TestScanWithInvalidBearerTokenFlagsShouldErr(t *testing.T) {
+	emptyFile := test.MustWriteTempFile(t, []byte("\n"))
+	defer removeTempFile(emptyFile)
+
+	testCases := []struct {
+		name          string
+		flags         []string
+		expectedError string
+	}{
+		{
+			name:          "token and token file",
+			flags:         []string{"--bearer-token", "mytoken", "--bearer-token-file", "testdata/dict.txt"},
+			expectedError: "bearer-token and bearer-token-file cannot be used at the same time",
+		},
+		{
+			name:          "unreadable token file",
+			flags:         []string{"--bearer-token-file", "/root/gibberish/token.txt"},
+			expectedError: "failed to read /root/gibberish/token.txt",
+		},
+		{
+			name:          "empty token file",
+			flags:         []string{"--bearer-token-file", emptyFile},
+			expectedError: "invalid value for bearer-token-file",
+		},
+		{
+			name:          "token and basic auth",
+			flags:         []string{"--bearer-token", "mytoken", "--basic-auth", "myuser:mypassword"},
+			expectedError: "basic-auth and bearer-token cannot be used at the same time",
+		},
+		{
+			name:          "token and ntlm",
+			flags:         []string{"--bearer-token", "mytoken", "--ntlm-user", "myuser"},
+			expectedError: "ntlm-user and bearer-token cannot be used at the same time",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			args := append([]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"}, tc.flags...)
+
+			err := executeCommand(c, args...)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestScanWithBodyShouldSendItWithEveryRequest(t *testing.T) {
+	const body = "param1=value1&param2=value2"
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var (
+		receivedBodies   = make(map[string]int)
+		receivedBodiesMx sync.Mutex
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			receivedBodiesMx.Lock()
+			receivedBodies[r.Method+" "+string(b)]++
+			receivedBodiesMx.Unlock()
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-methods",
+		"GET,POST",
+		"--body",
+		body,
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 6, serverAssertion.Len())
+	assert.Equal(t, map[string]int{"GET ": 3, "POST " + body: 3}, receivedBodies)
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		if r.Method == http.MethodPost {
+			assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		}
+	})
+}
+
+func TestScanWithBodyFileShouldAllowOverridingTheContentType(t *testing.T) {
+	const body = `{"key": "value"}`
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	bodyFile := test.MustWriteTempFile(t, []byte(body))
+	defer removeTempFile(bodyFile)
+
+	var (
+		receivedBodies   []string
+		receivedBodiesMx sync.Mutex
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			receivedBodiesMx.Lock()
+			receivedBodies = append(receivedBodies, string(b))
+			receivedBodiesMx.Unlock()
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-methods",
+		"PUT",
+		"--body-file",
+		bodyFile,
+		"--header",
+		"Content-Type: application/json",
+		"--http-timeout",
+		"300",
+		"--no-wildcard-detection",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Equal(t, []string{body, body, body}, receivedBodies)
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	})
+}
+
+func TestScanWithBodyAndBodyFileShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--body",
+		"a=b",
+		"--body-file",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "body and body-file cannot be used at the same time")
+}
+
+func TestScanWithMalformedHeaderShouldErr(t *testing.T) {
+	const malformedHeader = "gibberish"
+
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--header",
+		"Accept-Language: en-US,en;q=0.5",
+		"--header",
+		malformedHeader,
+		"--dictionary",
+		"testdata/dict.txt",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), malformedHeader)
+	assert.Contains(t, err.Error(), "header is in invalid format")
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestStartScanWithSocks5ShouldFindResultsWhenAServerIsAvailable(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path == "/home" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-
 			w.WriteHeader(http.StatusNotFound)
 		}),
 	)
 	defer testServer.Close()
 
+	socks5Server := startSocks5TestServer(t)
+	defer socks5Server.Close() //nolint:errcheck
+
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
 		"--dictionary",
-		"testdata/dict2.txt",
-		"--out",
-		"/root/blabla/123/gibberish/123",
+		"testdata/dict.txt",
+		"-v",
+		"--http-timeout",
+		"300",
+		"--socks5",
+		socks5TestServerHost,
+		"--no-wildcard-detection",
 	)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to create output saver")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, serverAssertion.Len())
 }
 
-func TestScanWithInvalidStatusesToIgnoreShouldErr(t *testing.T) {
-	logger, _ := test.NewLogger()
+func TestShouldFailToScanWithAnUnreachableSocks5Server(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
 	testServer, serverAssertion := test.NewServerWithAssertion(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
 	)
 	defer testServer.Close()
 
+	socks5Server := startSocks5TestServer(t)
+	defer socks5Server.Close() //nolint:errcheck
+
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
 		"--dictionary",
-		"testdata/dict2.txt",
+		"testdata/dict.txt",
 		"-v",
-		"--http-statuses-to-ignore",
-		"300,gibberish,404",
 		"--http-timeout",
 		"300",
+		"--socks5",
+		"127.0.0.1:9555", // invalid
 	)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "strconv.Atoi: parsing")
-	assert.Contains(t, err.Error(), "gibberish")
+	assert.NoError(t, err)
 
 	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "failed to perform request")
+	assert.Contains(t, loggerBuffer.String(), "socks connect tcp")
+	assert.Contains(t, loggerBuffer.String(), "connect: connection refused")
 }
 
-func TestScanWithNoTargetShouldErr(t *testing.T) {
+func TestShouldFailToStartWithAnInvalidSocks5Address(t *testing.T) {
 	logger, _ := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	err := executeCommand(c, "scan", "--dictionary", "testdata/dict2.txt")
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"-v",
+		"--http-timeout",
+		"300",
+		"--socks5",
+		"localhost%%2", // invalid
+	)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "no URL provided")
+	assert.Contains(t, err.Error(), "invalid URL escape")
+
+	assert.Equal(t, 0, serverAssertion.Len())
 }
 
-func TestScanWithInvalidTargetShouldErr(t *testing.T) {
+func TestShouldFailToStartWithASocks5AddressWithoutPort(t *testing.T) {
 	logger, _ := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	err := executeCommand(c, "scan", "--dictionary", "testdata/dict2.txt", "localhost%%2")
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--socks5",
+		"127.0.0.1", // missing port
+	)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid URI")
+	assert.Contains(t, err.Error(), "invalid value for socks5")
+	assert.Contains(t, err.Error(), "missing port in address")
+
+	assert.Equal(t, 0, serverAssertion.Len())
 }
 
-func TestScanCommandCanBeInterrupted(t *testing.T) {
+func TestScanWithHTTPProxyShouldRouteRequestsThroughTheProxy(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, _ := test.NewServerWithAssertion(
+	testServer, serverAssertion := test.NewServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			time.Sleep(time.Millisecond * 650)
-
-			if r.URL.Path == "/test/" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-
 			w.WriteHeader(http.StatusNotFound)
 		}),
 	)
 	defer testServer.Close()
 
-	go func() {
-		time.Sleep(time.Millisecond * 200)
-		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT) //nolint:errcheck
-	}()
+	proxyServer, proxyServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer proxyServer.Close()
+
+	proxyURL := test.MustParseURL(t, proxyServer.URL)
+	proxyURL.User = url.UserPassword("myuser", "mypassword")
 
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
 		"--dictionary",
-		"testdata/dict2.txt",
-		"-v",
+		"testdata/dict.txt",
 		"--http-timeout",
-		"900",
+		"300",
+		"--http-proxy",
+		proxyURL.String(),
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
-	assert.Contains(t, loggerBuffer.String(), "Received sigint")
+	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Equal(t, 3, proxyServerAssertion.Len())
+
+	proxyServerAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, testServer.Listener.Addr().String(), r.URL.Host)
+		assert.Equal(t, "Basic bXl1c2VyOm15cGFzc3dvcmQ=", r.Header.Get("Proxy-Authorization"))
+	})
+
+	// to ensure the credentials are not printed to the cli
+	assert.NotContains(t, loggerBuffer.String(), "mypassword")
 }
 
-func TestScanWithRemoteDictionary(t *testing.T) {
+func TestScanWithHTTPProxyAndSocks5ShouldErr(t *testing.T) {
 	logger, _ := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	dictionaryServer := httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			dict := `home
-home/index.php
-blabla
-`
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(dict)) //nolint:errcheck
-		}),
-	)
-	defer dictionaryServer.Close()
-
 	testServer, serverAssertion := test.NewServerWithAssertion(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusNotFound)
-		}),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
 	)
 	defer testServer.Close()
 
@@ -311,18 +6572,38 @@ blabla
 		"scan",
 		testServer.URL,
 		"--dictionary",
-		dictionaryServer.URL,
-		"--http-timeout",
-		"300",
+		"testdata/dict.txt",
+		"--socks5",
+		socks5TestServerHost,
+		"--http-proxy",
+		"http://127.0.0.1:8080",
 	)
-	assert.NoError(t, err)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "socks5 and http-proxy cannot be used at the same time")
 
-	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Equal(t, 0, serverAssertion.Len())
 }
 
-func TestScanWithUserAgentFlag(t *testing.T) {
-	const testUserAgent = "my_test_user_agent"
+func TestScanWithInvalidHTTPProxyShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--http-proxy",
+		"ftp://127.0.0.1:8080",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for http-proxy")
+}
 
+func TestScanWithReplayProxyShouldReplayTheResultsThroughTheProxy(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
@@ -330,34 +6611,52 @@ func TestScanWithUserAgentFlag(t *testing.T) {
 
 	testServer, serverAssertion := test.NewServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
 			w.WriteHeader(http.StatusNotFound)
 		}),
 	)
 	defer testServer.Close()
 
+	proxyServer, proxyServerAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer proxyServer.Close()
+
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
-		"--user-agent",
-		testUserAgent,
 		"--dictionary",
 		"testdata/dict.txt",
 		"--http-timeout",
 		"300",
+		"--header",
+		"Authorization:Bearer mytoken",
+		"--replay-proxy",
+		proxyServer.URL,
+		"--no-wildcard-detection",
+		"--scan-depth",
+		"0",
 	)
 	assert.NoError(t, err)
 
+	// the scan doesn't go through the replay proxy
 	assert.Equal(t, 3, serverAssertion.Len())
-	serverAssertion.Range(func(_ int, r http.Request) {
-		assert.Equal(t, testUserAgent, r.Header.Get("User-Agent"))
+
+	assert.Equal(t, 1, proxyServerAssertion.Len())
+	proxyServerAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, testServer.URL+"/home", r.URL.String())
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
 	})
 
-	// to ensure we print the user agent to the cli
-	assert.Contains(t, loggerBuffer.String(), testUserAgent)
+	assert.Contains(t, loggerBuffer.String(), "Request replayed through the proxy")
 }
 
-func TestScanWithCookies(t *testing.T) {
+func TestScanWithFailingReplayProxyShouldNotAbortTheScan(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
@@ -368,47 +6667,60 @@ func TestScanWithCookies(t *testing.T) {
 	)
 	defer testServer.Close()
 
+	proxyServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	proxyServer.Close()
+
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
-		"--cookie",
-		"name1=val1",
-		"--cookie",
-		"name2=val2",
 		"--dictionary",
 		"testdata/dict.txt",
 		"--http-timeout",
 		"300",
+		"--replay-proxy",
+		proxyServer.URL,
+		"--no-wildcard-detection",
+		"--scan-depth",
+		"0",
 	)
 	assert.NoError(t, err)
 
-	serverAssertion.Range(func(_ int, r http.Request) {
-		assert.Equal(t, 2, len(r.Cookies()))
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "failed to replay the request through the proxy")
+	assert.Contains(t, loggerBuffer.String(), "Finished scan")
+}
 
-		assert.Equal(t, r.Cookies()[0].Name, "name1")
-		assert.Equal(t, r.Cookies()[0].Value, "val1")
+func TestScanWithInvalidReplayProxyShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
 
-		assert.Equal(t, r.Cookies()[1].Name, "name2")
-		assert.Equal(t, r.Cookies()[1].Value, "val2")
-	})
+	c := createCommand(logger)
+	assert.NotNil(t, c)
 
-	// to ensure we print the cookies to the cli
-	assert.Contains(t, loggerBuffer.String(), "name1=val1")
-	assert.Contains(t, loggerBuffer.String(), "name2=val2")
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--replay-proxy",
+		"127.0.0.1:8080",
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value for replay-proxy")
 }
 
-func TestWhenProvidingCookiesInWrongFormatShouldErr(t *testing.T) {
-	const malformedCookie = "gibberish"
-
-	logger, _ := test.NewLogger()
+func TestScanShouldFailToCommunicateWithServerHavingInvalidSSLCertificates(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, serverAssertion := test.NewServerWithAssertion(
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusNotFound)
+			w.WriteHeader(http.StatusOK)
 		}),
 	)
 	defer testServer.Close()
@@ -417,81 +6729,61 @@ func TestWhenProvidingCookiesInWrongFormatShouldErr(t *testing.T) {
 		c,
 		"scan",
 		testServer.URL,
-		"--cookie",
-		malformedCookie,
 		"--dictionary",
-		"testdata/dict.txt",
+		"testdata/dict2.txt",
+		"--scan-depth",
+		"1",
 	)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "cookie format is invalid")
-	assert.Contains(t, err.Error(), malformedCookie)
+	assert.NoError(t, err)
 
 	assert.Equal(t, 0, serverAssertion.Len())
-}
 
-func TestScanWithCookieJar(t *testing.T) {
-	const (
-		serverCookieName  = "server_cookie_name"
-		serverCookieValue = "server_cookie_value"
-	)
+	assert.Contains(t, loggerBuffer.String(), "certificate signed by unknown authority")
+	assert.NotContains(t, loggerBuffer.String(), "SSL certificates validation is disabled")
+}
 
-	logger, _ := test.NewLogger()
+func TestScanShouldBeAbleToSkipSSLCertificatesCheck(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	once := sync.Once{}
-	testServer, serverAssertion := test.NewServerWithAssertion(
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			once.Do(func() {
-				http.SetCookie(
-					w,
-					&http.Cookie{
-						Name:    serverCookieName,
-						Value:   serverCookieValue,
-						Expires: time.Now().AddDate(0, 1, 0),
-					},
-				)
-			})
+			w.WriteHeader(http.StatusOK)
 		}),
 	)
-
-	defer testServer.Close() //nolint:errcheck
+	defer testServer.Close()
 
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
-		"--use-cookie-jar",
 		"--dictionary",
-		"testdata/dict.txt",
-		"--http-timeout",
-		"300",
-		"-t",
+		"testdata/dict2.txt",
+		"--scan-depth",
 		"1",
+		"--no-check-certificate",
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
-	serverAssertion.Range(func(index int, r http.Request) {
-		if index == 0 { // first request should have no cookies
-			assert.Equal(t, 0, len(r.Cookies()))
-			return
-		}
+	assert.Equal(t, 16, serverAssertion.Len())
 
-		assert.Equal(t, 1, len(r.Cookies()))
-		assert.Equal(t, r.Cookies()[0].Name, serverCookieName)
-		assert.Equal(t, r.Cookies()[0].Value, serverCookieValue)
-	})
+	assert.NotContains(t, loggerBuffer.String(), "certificate signed by unknown authority")
+	assert.Equal(t, 1, strings.Count(loggerBuffer.String(), "SSL certificates validation is disabled"))
 }
 
-func TestScanWithUnknownFlagShouldErr(t *testing.T) {
-	logger, _ := test.NewLogger()
+func TestScanShouldBeAbleToSkipSSLCertificatesCheckUsingShorthand(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, serverAssertion := test.NewServerWithAssertion(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
 	)
 	defer testServer.Close()
 
@@ -499,65 +6791,77 @@ func TestScanWithUnknownFlagShouldErr(t *testing.T) {
 		c,
 		"scan",
 		testServer.URL,
-		"--gibberishflag",
 		"--dictionary",
 		"testdata/dict.txt",
+		"-k",
+		"--no-wildcard-detection",
 	)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unknown flag")
+	assert.NoError(t, err)
 
-	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	assert.Contains(t, loggerBuffer.String(), "SSL certificates validation is disabled")
 }
 
-func TestScanWithHeaders(t *testing.T) {
+func TestScanWithClientCertificateShouldCommunicateWithServerRequiringIt(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, serverAssertion := test.NewServerWithAssertion(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	clientCertificatePath, clientKeyPath := test.MustCreateSelfSignedCertificateFiles(t)
+	defer removeTempFile(clientCertificatePath)
+	defer removeTempFile(clientKeyPath)
+
+	testServer, serverAssertion := test.NewMTLSServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+		mustLoadCertPool(t, clientCertificatePath),
 	)
 	defer testServer.Close()
 
+	caCertificatePath := test.MustWriteTempFile(
+		t,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testServer.Certificate().Raw}),
+	)
+	defer removeTempFile(caCertificatePath)
+
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
-		"--header",
-		"Accept-Language: en-US,en;q=0.5",
-		"--header",
-		`"Authorization: Bearer 123"`,
 		"--dictionary",
 		"testdata/dict.txt",
-		"--http-timeout",
-		"300",
+		"--client-cert",
+		clientCertificatePath,
+		"--client-key",
+		clientKeyPath,
+		"--ca-cert",
+		caCertificatePath,
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
-	serverAssertion.Range(func(_ int, r http.Request) {
-		assert.Equal(t, 2, len(r.Header))
-
-		assert.Equal(t, "en-US,en;q=0.5", r.Header.Get("Accept-Language"))
-		assert.Equal(t, "Bearer 123", r.Header.Get("Authorization"))
-	})
-
-	// to ensure we print the headers to the cli
-	assert.Contains(t, loggerBuffer.String(), "Accept-Language")
-	assert.Contains(t, loggerBuffer.String(), "Authorization")
-	assert.Contains(t, loggerBuffer.String(), "Bearer 123")
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.NotContains(t, loggerBuffer.String(), "failed to perform request")
 }
 
-func TestScanWithMalformedHeaderShouldErr(t *testing.T) {
-	const malformedHeader = "gibberish"
-
-	logger, _ := test.NewLogger()
+func TestScanWithoutClientCertificateShouldFailToCommunicateWithServerRequiringIt(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, serverAssertion := test.NewServerWithAssertion(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	clientCertificatePath, clientKeyPath := test.MustCreateSelfSignedCertificateFiles(t)
+	defer removeTempFile(clientCertificatePath)
+	defer removeTempFile(clientKeyPath)
+
+	testServer, serverAssertion := test.NewMTLSServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+		mustLoadCertPool(t, clientCertificatePath),
 	)
 	defer testServer.Close()
 
@@ -565,121 +6869,108 @@ func TestScanWithMalformedHeaderShouldErr(t *testing.T) {
 		c,
 		"scan",
 		testServer.URL,
-		"--header",
-		"Accept-Language: en-US,en;q=0.5",
-		"--header",
-		malformedHeader,
 		"--dictionary",
 		"testdata/dict.txt",
+		"-k",
 	)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), malformedHeader)
-	assert.Contains(t, err.Error(), "header is in invalid format")
+	assert.NoError(t, err)
 
 	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "failed to perform request")
 }
 
-func TestStartScanWithSocks5ShouldFindResultsWhenAServerIsAvailable(t *testing.T) {
+func TestScanWithClientCertificateWithoutKeyShouldErr(t *testing.T) {
 	logger, _ := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, serverAssertion := test.NewServerWithAssertion(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusNotFound)
-		}),
+	err := executeCommand(
+		c,
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--client-cert",
+		"testdata/client.crt",
 	)
-	defer testServer.Close()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "client-cert and client-key must be specified together")
+}
 
-	socks5Server := startSocks5TestServer(t)
-	defer socks5Server.Close() //nolint:errcheck
+func TestScanWithInvalidCACertificateShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
 
 	err := executeCommand(
 		c,
 		"scan",
-		testServer.URL,
+		"http://localhost/",
 		"--dictionary",
 		"testdata/dict.txt",
-		"-v",
-		"--http-timeout",
-		"300",
-		"--socks5",
-		socks5TestServerHost,
+		"--ca-cert",
+		"testdata/dict.txt",
 	)
-	assert.NoError(t, err)
-
-	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no valid PEM encoded certificate found in testdata/dict.txt")
 }
 
-func TestShouldFailToScanWithAnUnreachableSocks5Server(t *testing.T) {
+func TestScanWithHTTP2ShouldUseHTTP2(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, serverAssertion := test.NewServerWithAssertion(
+	testServer, serverAssertion := test.NewHTTP2TLSServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 		}),
 	)
 	defer testServer.Close()
 
-	socks5Server := startSocks5TestServer(t)
-	defer socks5Server.Close() //nolint:errcheck
-
 	err := executeCommand(
 		c,
 		"scan",
 		testServer.URL,
 		"--dictionary",
 		"testdata/dict.txt",
+		"--http2",
+		"-k",
 		"-v",
-		"--http-timeout",
-		"300",
-		"--socks5",
-		"127.0.0.1:9555", // invalid
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
-	assert.Equal(t, 0, serverAssertion.Len())
-	assert.Contains(t, loggerBuffer.String(), "failed to perform request")
-	assert.Contains(t, loggerBuffer.String(), "socks connect tcp")
-	assert.Contains(t, loggerBuffer.String(), "connect: connection refused")
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, 2, r.ProtoMajor)
+	})
+
+	assert.Contains(t, loggerBuffer.String(), "protocol=HTTP/2.0")
 }
 
-func TestShouldFailToStartWithAnInvalidSocks5Address(t *testing.T) {
+func TestScanWithHTTP2AndNoHTTP2ShouldErr(t *testing.T) {
 	logger, _ := test.NewLogger()
 
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	testServer, serverAssertion := test.NewServerWithAssertion(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusNotFound)
-		}),
-	)
-	defer testServer.Close()
-
 	err := executeCommand(
 		c,
 		"scan",
-		testServer.URL,
+		"http://localhost/",
 		"--dictionary",
 		"testdata/dict.txt",
-		"-v",
-		"--http-timeout",
-		"300",
-		"--socks5",
-		"localhost%%2", // invalid
+		"--http2",
+		"--no-http2",
 	)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid URL escape")
-
-	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Contains(t, err.Error(), "http2 and no-http2 cannot be used at the same time")
 }
 
-func TestScanShouldFailToCommunicateWithServerHavingInvalidSSLCertificates(t *testing.T) {
+func TestScanWithNoTLSVerifyHostnameShouldAcceptCertificatesIssuedForOtherHosts(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
@@ -687,28 +6978,55 @@ func TestScanShouldFailToCommunicateWithServerHavingInvalidSSLCertificates(t *te
 
 	testServer, serverAssertion := test.NewTSLServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
+			w.WriteHeader(http.StatusNotFound)
 		}),
 	)
 	defer testServer.Close()
 
+	caCertificatePath := test.MustWriteTempFile(
+		t,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testServer.Certificate().Raw}),
+	)
+	defer removeTempFile(caCertificatePath)
+
+	// the certificate of the test server is not issued for localhost
 	err := executeCommand(
 		c,
 		"scan",
-		testServer.URL,
+		"https://localhost:"+test.MustParseURL(t, testServer.URL).Port()+"/",
 		"--dictionary",
-		"testdata/dict2.txt",
-		"--scan-depth",
-		"1",
+		"testdata/dict.txt",
+		"--ca-cert",
+		caCertificatePath,
+		"--no-tls-verify-hostname",
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
-	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.NotContains(t, loggerBuffer.String(), "failed to perform request")
+	assert.Contains(t, loggerBuffer.String(), "SSL certificates hostname verification is disabled")
+}
 
-	assert.Contains(t, loggerBuffer.String(), "certificate signed by unknown authority")
+func mustLoadCertPool(t *testing.T, certificatePath string) *x509.CertPool {
+	rawCertificate, err := ioutil.ReadFile(certificatePath) // #nosec
+	if err != nil {
+		t.Fatalf("failed to read certificate: %s", err.Error())
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(rawCertificate) {
+		t.Fatalf("failed to append certificate to pool")
+	}
+
+	return pool
 }
 
-func TestScanShouldBeAbleToSkipSSLCertificatesCheck(t *testing.T) {
+func removeTempFile(path string) {
+	_ = os.Remove(path) //nolint:errcheck
+}
+
+func TestScanShouldBeAbleToSkipSSLCertificatesCheckUsingTheInsecureAlias(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
 	c := createCommand(logger)
@@ -716,7 +7034,7 @@ func TestScanShouldBeAbleToSkipSSLCertificatesCheck(t *testing.T) {
 
 	testServer, serverAssertion := test.NewTSLServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
+			w.WriteHeader(http.StatusNotFound)
 		}),
 	)
 	defer testServer.Close()
@@ -726,16 +7044,16 @@ func TestScanShouldBeAbleToSkipSSLCertificatesCheck(t *testing.T) {
 		"scan",
 		testServer.URL,
 		"--dictionary",
-		"testdata/dict2.txt",
-		"--scan-depth",
-		"1",
-		"--no-check-certificate",
+		"testdata/dict.txt",
+		"--insecure",
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
-	assert.Equal(t, 16, serverAssertion.Len())
+	assert.Equal(t, 3, serverAssertion.Len())
 
 	assert.NotContains(t, loggerBuffer.String(), "certificate signed by unknown authority")
+	assert.Equal(t, 1, strings.Count(loggerBuffer.String(), "SSL certificates validation is disabled"))
 }
 
 func startSocks5TestServer(t *testing.T) net.Listener {
@@ -826,6 +7144,7 @@ func TestScanShouldBeAbleToFetchRemoteDictionary(t *testing.T) {
 		dictionaryTestServer.URL,
 		"--dictionary-get-timeout",
 		"500",
+		"--no-wildcard-detection",
 	)
 	assert.NoError(t, err)
 
@@ -836,3 +7155,171 @@ func TestScanShouldBeAbleToFetchRemoteDictionary(t *testing.T) {
 		assert.Equal(t, "/dictionary/entry", r.URL.Path)
 	})
 }
+
+func TestScanWithWatchIntervalShouldScanAgainUntilSigint(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	go func() {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			if serverAssertion.Len() >= 6 {
+				break
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT) //nolint:errcheck
+	}()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--watch-interval",
+		"20ms",
+	)
+	assert.NoError(t, err)
+
+	assert.True(t, serverAssertion.Len() >= 6, "the targets should have been scanned at least twice")
+	assert.Contains(t, loggerBuffer.String(), "Waiting to scan the targets again")
+	assert.Contains(t, loggerBuffer.String(), "Received sigint")
+}
+
+func TestScanWithWatchDiffShouldReportOnlyTheChangesSinceThePreviousScan(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var requestsCount int32
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the first scan performs 3 requests, the status codes change afterwards
+			firstScan := atomic.AddInt32(&requestsCount, 1) <= 3
+
+			switch {
+			case r.URL.Path == "/home" && firstScan:
+				w.WriteHeader(http.StatusOK)
+			case r.URL.Path == "/home":
+				w.WriteHeader(http.StatusForbidden)
+			case r.URL.Path == "/blabla" && !firstScan:
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	go func() {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			if strings.Contains(loggerBuffer.String(), "No differences found") {
+				break
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT) //nolint:errcheck
+	}()
+
+	err := executeCommand(
+		c,
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict.txt",
+		"--threads",
+		"1",
+		"--scan-depth",
+		"0",
+		"--no-wildcard-detection",
+		"--watch-interval",
+		"20ms",
+		"--watch-diff",
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "Changes since the previous scan")
+	assert.Contains(t, loggerBuffer.String(), "+ [200] GET "+testServer.URL+"/blabla")
+	assert.Contains(t, loggerBuffer.String(), "~ [200 -> 403] GET "+testServer.URL+"/home")
+	assert.Contains(t, loggerBuffer.String(), "1 appeared, 0 disappeared, 1 changed status code")
+	assert.Contains(t, loggerBuffer.String(), "No differences found")
+}
+
+func TestScanWithInvalidWatchIntervalShouldErr(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "negative",
+			args:          []string{"--watch-interval", "-1s"},
+			expectedError: "invalid value for watch-interval: it cannot be negative",
+		},
+		{
+			name:          "diff without interval",
+			args:          []string{"--watch-diff"},
+			expectedError: "watch-diff requires watch-interval",
+		},
+		{
+			name:          "with dry run",
+			args:          []string{"--watch-interval", "1h", "--dry-run"},
+			expectedError: "watch-interval and dry-run cannot be used at the same time",
+		},
+		{
+			name:          "with resume",
+			args:          []string{"--watch-interval", "1h", "--resume-from", "state.json"},
+			expectedError: "watch-interval and resume-from cannot be used at the same time",
+		},
+		{
+			name:          "with exit on match",
+			args:          []string{"--watch-interval", "1h", "--exit-on-match"},
+			expectedError: "watch-interval and exit-on-match cannot be used at the same time",
+		},
+		{
+			name:          "with stdin targets",
+			args:          []string{"--watch-interval", "1h", "--stdin-targets"},
+			expectedError: "watch-interval and stdin-targets cannot be used at the same time",
+		},
+		{
+			name:          "with a dictionary from stdin",
+			args:          []string{"--watch-interval", "1h", "--dictionary", "-"},
+			expectedError: "watch-interval cannot be used with a dictionary read from the standard input",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			c := createCommand(logger)
+			assert.NotNil(t, c)
+
+			err := executeCommand(
+				c,
+				append([]string{"scan", "http://localhost/", "--dictionary", "testdata/dict.txt"}, tc.args...)...,
+			)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
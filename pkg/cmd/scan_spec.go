@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// scanSpecTargets is the field of the scan spec listing the urls to scan, the other fields are named after the flags
+const scanSpecTargets = "targets"
+
+// scanSpecUnsupportedFlags are the flags that can't be set in a scan spec, as they would make it depend on other files
+var scanSpecUnsupportedFlags = map[string]struct{}{
+	flagScanSpec:        {},
+	flagScanConfig:      {},
+	flagScanTargetsFile: {},
+}
+
+// scanSpec is the scan described by a scan spec file
+type scanSpec struct {
+	targets []string
+}
+
+// loadScanSpec sets the flags not specified via the command line to the values found in the scan spec and keeps
+// the targets it lists in spec; being self-contained, the environment and the config file are not used with it
+func loadScanSpec(logger *logrus.Logger, cmd *cobra.Command, args []string, spec *scanSpec) error {
+	path := cmd.Flag(flagScanSpec).Value.String()
+
+	for _, flag := range []string{flagScanConfig, flagScanTargetsFile} {
+		if cmd.Flags().Changed(flag) {
+			return errors.Errorf("%s and %s cannot be used at the same time", flagScanSpec, flag)
+		}
+	}
+
+	if len(args) > 0 {
+		return errors.Errorf("the urls to scan cannot be specified as arguments when using %s", flagScanSpec)
+	}
+
+	logger.WithField("path", path).Debug("Loading scan spec")
+
+	content, err := readConfigFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read scan spec %s", path)
+	}
+
+	entries, err := parseConfigFile(string(content))
+	if err != nil {
+		return errors.Wrapf(err, "invalid scan spec %s", path)
+	}
+
+	if err := validateScanSpec(cmd, path, entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.flag == scanSpecTargets {
+			spec.targets = entry.values
+			continue
+		}
+
+		f := cmd.Flags().Lookup(entry.flag)
+
+		// the flags specified via the command line take precedence over the scan spec
+		if f.Changed {
+			continue
+		}
+
+		for _, value := range entry.values {
+			if err := cmd.Flags().Set(f.Name, value); err != nil {
+				return errors.Wrapf(err, "invalid scan spec %s: invalid value for %s at line %d", path, f.Name, entry.line)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateScanSpec checks the fields of the scan spec before any flag is set
+func validateScanSpec(cmd *cobra.Command, path string, entries []configFileEntry) error {
+	found := make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		found[entry.flag] = struct{}{}
+
+		if entry.flag == scanSpecTargets {
+			if err := validateScanSpecTargets(entry); err != nil {
+				return errors.Wrapf(err, "invalid scan spec %s", path)
+			}
+
+			continue
+		}
+
+		f := cmd.Flags().Lookup(entry.flag)
+		_, unsupported := scanSpecUnsupportedFlags[entry.flag]
+
+		if f == nil || unsupported || cmd.InheritedFlags().Lookup(f.Name) != nil {
+			return errors.Errorf("invalid scan spec %s: unknown field %s at line %d", path, entry.flag, entry.line)
+		}
+	}
+
+	if _, ok := found[scanSpecTargets]; !ok {
+		return errors.Errorf("invalid scan spec %s: %s is required", path, scanSpecTargets)
+	}
+
+	_, hasDictionary := found[flagScanDictionary]
+	_, hasVHostDictionary := found[flagScanVHostDictionary]
+
+	if !hasDictionary && !hasVHostDictionary {
+		return errors.Errorf(
+			"invalid scan spec %s: either %s or %s is required",
+			path,
+			flagScanDictionary,
+			flagScanVHostDictionary,
+		)
+	}
+
+	return nil
+}
+
+func validateScanSpecTargets(entry configFileEntry) error {
+	for _, target := range entry.values {
+		if _, err := url.ParseRequestURI(target); err != nil {
+			return errors.Errorf(
+				"invalid value for %s at line %d: %s is not a valid url",
+				scanSpecTargets,
+				entry.line,
+				target,
+			)
+		}
+	}
+
+	return nil
+}
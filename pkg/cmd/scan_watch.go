@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/result"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+)
+
+// watchIteration is one of the scans performed by watchScan
+type watchIteration struct {
+	// results are the results found by the scan, in the JSON output format the diff works with
+	results []output.JSONResult
+	// onlyChanges is true when the changes since the previous scan are reported instead of the results
+	onlyChanges bool
+}
+
+func (i *watchIteration) add(r scan.Result) {
+	i.results = append(i.results, output.NewJSONResult(r))
+}
+
+// watchScan scans the urls again and again, waiting cnf.WatchInterval after the end of each scan, until a sigint
+// is received or ctx is canceled. A sigint received during a scan stops it as usual and no other scan is started.
+// With cnf.WatchDiff, from the second scan on, only the changes since the previous one are reported.
+func watchScan(
+	ctx context.Context,
+	logger *logrus.Logger,
+	cnf *scan.Config,
+	urls []*url.URL,
+	in io.Reader,
+	out io.Writer,
+) error {
+	sigint := make(chan os.Signal, 1)
+	signal.Notify(sigint, os.Interrupt)
+
+	defer signal.Stop(sigint)
+
+	var previous *watchIteration
+
+	for count := 1; ; count++ {
+		iteration := &watchIteration{
+			results:     make([]output.JSONResult, 0),
+			onlyChanges: cnf.WatchDiff && previous != nil,
+		}
+
+		if err := startScan(ctx, logger, cnf, urls, in, out, iteration); err != nil {
+			return err
+		}
+
+		// the amount of requests doesn't change between the scans, it is confirmed only once
+		cnf.SkipConfirmation = true
+
+		if iteration.onlyChanges {
+			if err := reportWatchChanges(logger, cnf, out, result.NewDiff(previous.results, iteration.results)); err != nil {
+				return errors.Wrap(err, "failed to print the changes since the previous scan")
+			}
+		}
+
+		previous = iteration
+
+		select {
+		case <-sigint:
+			logger.Info("Received sigint, the targets will not be scanned again")
+			return nil
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		logger.WithFields(logrus.Fields{
+			"scans":    count,
+			"interval": cnf.WatchInterval.String(),
+		}).Info("Waiting to scan the targets again")
+
+		timer := time.NewTimer(cnf.WatchInterval)
+
+		select {
+		case <-sigint:
+			timer.Stop()
+			logger.Info("Received sigint, the targets will not be scanned again")
+
+			return nil
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// reportWatchChanges prints the changes where the results would be printed: in quiet mode the output of the
+// command, otherwise the logger one
+func reportWatchChanges(logger *logrus.Logger, cnf *scan.Config, out io.Writer, diff result.Diff) error {
+	if cnf.Quiet {
+		return printScanDiff(out, diff)
+	}
+
+	logger.Info("Changes since the previous scan")
+
+	return printScanDiff(logger.Out, diff)
+}
@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// IsRemote returns true when the file of the path is retrieved via http
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, "http")
+}
+
+// Open retrieves the file at the given url via the doer, the responses with a status code other than 2xx
+// are reported as errors
+func Open(path string, doer Doer) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build request for `%s`", path)
+	}
+
+	res, err := doer.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get `%s`", path)
+	}
+
+	statusCode := res.StatusCode
+	if statusCode > 299 || statusCode < 200 {
+		_ = res.Body.Close()
+
+		return nil, errors.Errorf(
+			"failed to retrieve from `%s`, status code %d",
+			path,
+			statusCode,
+		)
+	}
+
+	return res.Body, nil
+}
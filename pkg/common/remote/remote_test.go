@@ -0,0 +1,54 @@
+package remote_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/remote"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRemote(t *testing.T) {
+	assert.True(t, remote.IsRemote("http://mysite/dict.txt"))
+	assert.True(t, remote.IsRemote("https://mysite/dict.txt"))
+	assert.False(t, remote.IsRemote("testdata/dict.txt"))
+	assert.False(t, remote.IsRemote("-"))
+}
+
+func TestOpenShouldRetrieveTheFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("my content"))
+	}))
+	defer srv.Close()
+
+	reader, err := remote.Open(srv.URL, &http.Client{})
+	assert.NoError(t, err)
+
+	defer reader.Close() //nolint:errcheck
+
+	content, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "my content", string(content))
+}
+
+func TestOpenShouldErrForAStatusCodeOtherThan2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := remote.Open(srv.URL, &http.Client{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve from `"+srv.URL+"`, status code 403")
+}
+
+func TestOpenShouldErrWhenTheServerCannotBeReached(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+
+	_, err := remote.Open(srv.URL, &http.Client{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get `"+srv.URL+"`")
+}
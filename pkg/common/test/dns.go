@@ -0,0 +1,137 @@
+package test
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNSServer is a DNS server replying over UDP to the A and AAAA queries for the configured domains
+type DNSServer struct {
+	conn    net.PacketConn
+	records map[string][]net.IP
+
+	queries   []string
+	queriesMx sync.RWMutex
+}
+
+// MustStartDNSServer starts a DNS server resolving each domain to the given IPs,
+// it needs to be closed by the caller
+func MustStartDNSServer(t TestingT, records map[string][]net.IP) *DNSServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start dns server: %s", err.Error())
+	}
+
+	s := &DNSServer{conn: conn, records: make(map[string][]net.IP, len(records))}
+
+	for domain, ips := range records {
+		s.records[strings.TrimSuffix(strings.ToLower(domain), ".")] = ips
+	}
+
+	go s.serve()
+
+	return s
+}
+
+// Addr returns the host:port the server is listening on
+func (s *DNSServer) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// Queries returns the domains queried so far, one per query
+func (s *DNSServer) Queries() []string {
+	s.queriesMx.RLock()
+	defer s.queriesMx.RUnlock()
+
+	return append([]string{}, s.queries...)
+}
+
+func (s *DNSServer) Close() {
+	_ = s.conn.Close() //nolint:errcheck
+}
+
+func (s *DNSServer) serve() {
+	buf := make([]byte, 512)
+
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		response, err := s.reply(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		_, _ = s.conn.WriteTo(response, addr) //nolint:errcheck
+	}
+}
+
+func (s *DNSServer) reply(query []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+
+	header, err := p.Start(query)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := p.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	domain := strings.TrimSuffix(strings.ToLower(question.Name.String()), ".")
+
+	s.queriesMx.Lock()
+	s.queries = append(s.queries, domain)
+	s.queriesMx.Unlock()
+
+	ips, found := s.records[domain]
+
+	responseHeader := dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true}
+	if !found {
+		responseHeader.RCode = dnsmessage.RCodeNameError
+	}
+
+	b := dnsmessage.NewBuilder(nil, responseHeader)
+	b.EnableCompression()
+
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+
+	if err := b.Question(question); err != nil {
+		return nil, err
+	}
+
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		resourceHeader := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60}
+
+		switch {
+		case question.Type == dnsmessage.TypeA && ip.To4() != nil:
+			var a dnsmessage.AResource
+			copy(a.A[:], ip.To4())
+
+			err = b.AResource(resourceHeader, a)
+		case question.Type == dnsmessage.TypeAAAA && ip.To4() == nil:
+			var aaaa dnsmessage.AAAAResource
+			copy(aaaa.AAAA[:], ip.To16())
+
+			err = b.AAAAResource(resourceHeader, aaaa)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Finish()
+}
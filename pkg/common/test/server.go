@@ -1,6 +1,8 @@
 package test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"sync"
 
 	"net/http"
@@ -24,6 +26,35 @@ func NewTSLServerWithAssertion(handler http.HandlerFunc) (*httptest.Server, *Ser
 	return server, serverAssertion
 }
 
+// NewHTTP2TLSServerWithAssertion creates a TLS server that supports HTTP/2, for the clients negotiating it
+func NewHTTP2TLSServerWithAssertion(handler http.HandlerFunc) (*httptest.Server, *ServerAssertion) {
+	serverAssertion := &ServerAssertion{}
+
+	server := httptest.NewUnstartedServer(serverAssertion.wrap(handler))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+
+	return server, serverAssertion
+}
+
+// NewMTLSServerWithAssertion creates a TLS server that requires the clients to present
+// a certificate signed by one of the given CAs
+func NewMTLSServerWithAssertion(
+	handler http.HandlerFunc,
+	clientCAs *x509.CertPool,
+) (*httptest.Server, *ServerAssertion) {
+	serverAssertion := &ServerAssertion{}
+
+	server := httptest.NewUnstartedServer(serverAssertion.wrap(handler))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+
+	return server, serverAssertion
+}
+
 type ServerAssertion struct {
 	requests   []http.Request
 	requestsMx sync.RWMutex
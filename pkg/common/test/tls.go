@@ -0,0 +1,64 @@
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"time"
+)
+
+// MustCreateSelfSignedCertificateFiles creates a self signed certificate and its private key
+// and stores them PEM encoded in temporary files, that needs to be removed by the caller
+func MustCreateSelfSignedCertificateFiles(t TestingT) (certificatePath string, keyPath string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err.Error())
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "dirstalk-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	rawCertificate, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err.Error())
+	}
+
+	certificatePath = MustWriteTempFile(t, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rawCertificate}))
+	keyPath = MustWriteTempFile(
+		t,
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	)
+
+	return certificatePath, keyPath
+}
+
+// MustWriteTempFile writes the given content to a temporary file and returns its path,
+// the file needs to be removed by the caller
+func MustWriteTempFile(t TestingT, content []byte) string {
+	file, err := ioutil.TempFile("", "dirstalk-test")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %s", err.Error())
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	if _, err := file.Write(content); err != nil {
+		_ = os.Remove(file.Name())
+		t.Fatalf("failed to write temp file: %s", err.Error())
+	}
+
+	return file.Name()
+}
@@ -0,0 +1,88 @@
+package urlpath
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RegexPatternPrefix is the prefix identifying the patterns that are regular expressions,
+// the patterns without it are globs
+const RegexPatternPrefix = "regex:"
+
+// NewPattern parses the given pattern, either a glob (EG `/static/*`) or, when prefixed with
+// RegexPatternPrefix, a regular expression (EG `regex:^/assets(/|$)`)
+func NewPattern(pattern string) (Pattern, error) {
+	if strings.HasPrefix(pattern, RegexPatternPrefix) {
+		regex, err := regexp.Compile(strings.TrimPrefix(pattern, RegexPatternPrefix))
+		if err != nil {
+			return Pattern{}, errors.Wrapf(err, "invalid regular expression `%s`", pattern)
+		}
+
+		return Pattern{regex: regex}, nil
+	}
+
+	glob := normalize(pattern)
+
+	if _, err := path.Match(glob, ""); err != nil {
+		return Pattern{}, errors.Wrapf(err, "invalid glob `%s`", pattern)
+	}
+
+	return Pattern{glob: glob}, nil
+}
+
+// Pattern matches the url paths against a glob or a regular expression
+type Pattern struct {
+	glob  string
+	regex *regexp.Regexp
+}
+
+// Match reports whether the path matches the pattern, the path is compared with a leading slash and
+// without the trailing one (EG `static`, `/static` and `/static/` are all compared as `/static`).
+// Globs have to match the whole path and their `*` doesn't match `/`, regular expressions can match any part of it.
+func (p Pattern) Match(urlPath string) bool {
+	urlPath = normalize(urlPath)
+
+	if p.regex != nil {
+		return p.regex.MatchString(urlPath)
+	}
+
+	matched, _ := path.Match(p.glob, urlPath) //nolint:errcheck // the glob has been validated when created
+
+	return matched
+}
+
+// Patterns is a set of patterns, a path matches it when it matches any of them
+type Patterns []Pattern
+
+// NewPatterns parses all the given patterns, see NewPattern
+func NewPatterns(patterns []string) (Patterns, error) {
+	parsed := make(Patterns, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		p, err := NewPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, p)
+	}
+
+	return parsed, nil
+}
+
+func (ps Patterns) Match(urlPath string) bool {
+	for _, p := range ps {
+		if p.Match(urlPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalize(p string) string {
+	return "/" + strings.Trim(path.Clean("/"+p), "/")
+}
@@ -0,0 +1,67 @@
+package urlpath_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternMatch(t *testing.T) {
+	testCases := []struct {
+		pattern        string
+		path           string
+		expectedResult bool
+	}{
+		{pattern: "/static/", path: "/static", expectedResult: true},
+		{pattern: "/static", path: "static/", expectedResult: true},
+		{pattern: "static", path: "/static/", expectedResult: true},
+		{pattern: "/static", path: "/static/css", expectedResult: false},
+		{pattern: "/static/*", path: "/static/css", expectedResult: true},
+		{pattern: "/static/*", path: "/static/css/main.css", expectedResult: false},
+		{pattern: "*/static", path: "/admin/static", expectedResult: true},
+		{pattern: "/*.css", path: "/main.css", expectedResult: true},
+		{pattern: "/img[0-9]", path: "/img1", expectedResult: true},
+		{pattern: "regex:^/static(/|$)", path: "/static/css/main.css", expectedResult: true},
+		{pattern: "regex:^/static(/|$)", path: "/staticfiles", expectedResult: false},
+		{pattern: "regex:\\.(css|js)$", path: "/assets/app.js", expectedResult: true},
+		{pattern: "regex:/$", path: "/admin/", expectedResult: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := urlpath.NewPattern(tc.pattern)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, p.Match(tc.path))
+		})
+	}
+}
+
+func TestNewPatternShouldErrForInvalidPatterns(t *testing.T) {
+	_, err := urlpath.NewPattern("/static/[")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid glob `/static/[`")
+
+	_, err = urlpath.NewPattern("regex:/static/(")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid regular expression `regex:/static/(`")
+}
+
+func TestPatternsMatch(t *testing.T) {
+	ps, err := urlpath.NewPatterns([]string{"/static", "regex:^/assets"})
+	assert.NoError(t, err)
+
+	assert.True(t, ps.Match("/static/"))
+	assert.True(t, ps.Match("/assets/app.js"))
+	assert.False(t, ps.Match("/admin"))
+
+	_, err = urlpath.NewPatterns([]string{"/static", "/["})
+	assert.Error(t, err)
+
+	assert.False(t, urlpath.Patterns{}.Match("/admin"))
+}
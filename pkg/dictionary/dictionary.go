@@ -3,32 +3,51 @@ package dictionary
 import (
 	"bufio"
 	"io"
-	"net/http"
+	"io/ioutil"
 	"os"
-	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/common/remote"
 )
 
 const commentPrefix = "#"
 
+// StdinPath is the path to use to read a dictionary from the standard input
+const StdinPath = "-"
+
 func NewDictionaryFrom(path string, doer Doer) ([]string, error) {
-	if strings.HasPrefix(path, "http") {
-		return newDictionaryFromRemoteFile(path, doer)
+	reader, err := open(path, doer)
+	if err != nil {
+		return nil, err
 	}
 
-	return newDictionaryFromLocalFile(path)
+	defer reader.Close() //nolint:errcheck
+
+	return dictionaryFromReader(reader), nil
+}
+
+// NewDictionaryFromReader reads the dictionary from the given reader until EOF
+func NewDictionaryFromReader(reader io.Reader) []string {
+	return dictionaryFromReader(reader)
 }
 
-func newDictionaryFromLocalFile(path string) ([]string, error) {
+// open returns the content of the given path, when it is a url the content is retrieved via the doer
+// and when it is StdinPath the content is read from the standard input
+func open(path string, doer Doer) (io.ReadCloser, error) {
+	if path == StdinPath {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+
+	if remote.IsRemote(path) {
+		return openRemoteFile(path, doer)
+	}
+
 	file, err := os.Open(path) // #nosec
 	if err != nil {
 		return nil, errors.Wrapf(err, "dictionary: unable to open: %s", path)
 	}
 
-	defer file.Close() //nolint:errcheck
-
-	return dictionaryFromReader(file), nil
+	return file, nil
 }
 
 func dictionaryFromReader(reader io.Reader) []string {
@@ -36,44 +55,26 @@ func dictionaryFromReader(reader io.Reader) []string {
 	scanner := bufio.NewScanner(reader)
 
 	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) == 0 {
-			continue
+		if line := scanner.Text(); isEntry(line) {
+			entries = append(entries, line)
 		}
-
-		if isAComment(line) {
-			continue
-		}
-
-		entries = append(entries, line)
 	}
 
 	return entries
 }
 
-func newDictionaryFromRemoteFile(path string, doer Doer) ([]string, error) {
-	req, err := http.NewRequest(http.MethodGet, path, nil)
+func openRemoteFile(path string, doer Doer) (io.ReadCloser, error) {
+	reader, err := remote.Open(path, doer)
 	if err != nil {
-		return nil, errors.Wrapf(err, "dictionary: failed to build request for `%s`", path)
+		return nil, errors.Wrap(err, "dictionary")
 	}
 
-	res, err := doer.Do(req)
-	if err != nil {
-		return nil, errors.Wrapf(err, "dictionary: failed to get `%s`", path)
-	}
-
-	defer res.Body.Close() //nolint:errcheck
-
-	statusCode := res.StatusCode
-	if statusCode > 299 || statusCode < 200 {
-		return nil, errors.Errorf(
-			"dictionary: failed to retrieve from `%s`, status code %d",
-			path,
-			statusCode,
-		)
-	}
+	return reader, nil
+}
 
-	return dictionaryFromReader(res.Body), nil
+// isEntry returns false for the empty lines and the comments of the dictionary
+func isEntry(line string) bool {
+	return len(line) > 0 && !isAComment(line)
 }
 
 func isAComment(line string) bool {
@@ -26,6 +26,12 @@ func TestDictionaryFromFile(t *testing.T) {
 	assert.Equal(t, expectedValue, entries)
 }
 
+func TestDictionaryFromReader(t *testing.T) {
+	entries := dictionary.NewDictionaryFromReader(strings.NewReader("# a comment\nhome\n\nhome/index.php\n"))
+
+	assert.Equal(t, []string{"home", "home/index.php"}, entries)
+}
+
 func TestShouldFailToCreateDictionaryFromInvalidPath(t *testing.T) {
 	_, err := dictionary.NewDictionaryFrom("http:///home/\n", &http.Client{})
 	assert.Error(t, err)
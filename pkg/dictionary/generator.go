@@ -1,77 +1,296 @@
 package dictionary
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
+// The formats available to write the generated dictionaries
+const (
+	// OutputFormatText writes each entry on its own line
+	OutputFormatText = "text"
+	// OutputFormatJSON writes each entry on its own line as a JSON object (see jsonEntry)
+	OutputFormatJSON = "json"
+)
+
+// OutputFormats contains all the formats available
+var OutputFormats = []string{OutputFormatText, OutputFormatJSON}
+
+// IsValidOutputFormat returns true when the format is one of the available ones
+func IsValidOutputFormat(format string) bool {
+	for _, f := range OutputFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
 func NewGenerator(out io.Writer) *Generator {
-	return &Generator{out: out}
+	return &Generator{out: out, format: OutputFormatText, ignoreRules: newIgnoreRules(), logger: newDiscardLogger()}
 }
 
 type Generator struct {
-	out io.Writer
+	out                  io.Writer
+	format               string
+	mangler              *Mangler
+	caseInsensitiveDedup bool
+	ignoreRules          ignoreRules
+	followSymlinks       bool
+	logger               logrus.FieldLogger
+}
+
+// jsonEntry is an entry of a dictionary written in the json format, the source is the path where the word was
+// found (for the variants produced by the mangling, the one of the word they were produced from)
+type jsonEntry struct {
+	Word   string `json:"word"`
+	Source string `json:"source,omitempty"`
+}
+
+// WithOutputFormat makes the generator write the entries in the given format (one of OutputFormats)
+func (g *Generator) WithOutputFormat(format string) *Generator {
+	g.format = format
+
+	return g
+}
+
+// WithIgnoredDirectories makes the generator skip the folders with the given names (EG `node_modules`) when
+// generating a dictionary from a folder, their content included
+func (g *Generator) WithIgnoredDirectories(directories []string) *Generator {
+	g.ignoreRules.addDirectories(directories)
+
+	return g
+}
+
+// WithIgnoredExtensions makes the generator skip the files with the given extensions (EG `png`) when generating
+// a dictionary from a folder
+func (g *Generator) WithIgnoredExtensions(extensions []string) *Generator {
+	g.ignoreRules.addExtensions(extensions)
+
+	return g
+}
+
+// WithGitignore makes the generator skip the files and folders ignored by the .gitignore files found while
+// generating a dictionary from a folder
+func (g *Generator) WithGitignore() *Generator {
+	g.ignoreRules.respectGitignore = true
+
+	return g
+}
+
+// WithMaxDepth makes the generator descend at most depth folders below the one a dictionary is generated from,
+// 0 meaning that only its entries are listed; a negative depth means no limit
+func (g *Generator) WithMaxDepth(depth int) *Generator {
+	g.ignoreRules.maxDepth = depth
+
+	return g
+}
+
+// WithFollowSymlinks makes the generator follow the symlinks when generating a dictionary from a folder, apart from
+// the ones pointing to a folder containing them (as following them would make the walk loop)
+func (g *Generator) WithFollowSymlinks() *Generator {
+	g.followSymlinks = true
+
+	return g
+}
+
+// WithLogger makes the generator log at debug level the symlinks skipped or not followed
+func (g *Generator) WithLogger(logger logrus.FieldLogger) *Generator {
+	g.logger = logger
+
+	return g
+}
+
+// WithMangler makes the generator write the variants produced by the mangler together with each entry
+func (g *Generator) WithMangler(mangler *Mangler) *Generator {
+	g.mangler = mangler
+
+	return g
+}
+
+// WithCaseInsensitiveDedup makes the generator remove the entries differing from a previous one only by case,
+// as it is applied after the mangling the variants produced by the upper, lower and capitalize rules are removed too
+func (g *Generator) WithCaseInsensitiveDedup() *Generator {
+	g.caseInsensitiveDedup = true
+
+	return g
 }
 
 func (g *Generator) GenerateDictionaryFrom(path string, absoluteOnly bool) error {
 	var (
 		dictionary []string
+		sources    map[string]string
 		err        error
 	)
 
 	if absoluteOnly {
-		dictionary, err = findAbsolutePaths(path)
+		dictionary, sources, err = findAbsolutePaths(path, g.walk)
 	} else {
-		dictionary, err = findFileNames(path)
+		dictionary, sources, err = findFileNames(path, g.walk)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to generate dictionary")
+	}
+
+	return g.write(dictionary, sources)
+}
+
+// GenerateDictionaryFromRobots generates a dictionary containing the paths listed in the Allow and Disallow
+// directives of the given robots.txt (either a local file or a url)
+func (g *Generator) GenerateDictionaryFromRobots(robotsPath string, doer Doer) error {
+	dictionary, err := findRobotsPaths(robotsPath, doer)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate dictionary")
+	}
+
+	return g.write(dictionary, nil)
+}
+
+// GenerateDictionaryFromSitemap generates a dictionary containing the paths of the locations listed in the
+// given sitemap (either a local file or a url), following the nested sitemap indexes
+func (g *Generator) GenerateDictionaryFromSitemap(sitemapPath string, doer Doer) error {
+	dictionary, err := findSitemapPaths(sitemapPath, doer)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate dictionary")
 	}
 
+	return g.write(dictionary, nil)
+}
+
+// GenerateDictionaryFromJavascript generates a dictionary containing the paths found in the quoted strings
+// of the given javascript files (either local files or urls)
+func (g *Generator) GenerateDictionaryFromJavascript(javascriptPaths []string, doer Doer) error {
+	dictionary, err := findJavascriptPaths(javascriptPaths, doer)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate dictionary")
 	}
 
+	return g.write(dictionary, nil)
+}
+
+// MergeDictionaries generates a dictionary containing the entries of the given dictionaries (either local files
+// or urls), the entries are normalized before removing the duplicates unless preserveFormat is true
+func (g *Generator) MergeDictionaries(dictionaryPaths []string, preserveFormat bool, doer Doer) error {
+	dictionary, err := mergeDictionaries(dictionaryPaths, preserveFormat, doer)
+	if err != nil {
+		return errors.Wrap(err, "failed to merge dictionaries")
+	}
+
+	// the duplicates are removed before sorting, so that the first occurrence of an entry is the one kept
+	dictionary = g.deduplicate(dictionary)
+	sort.Strings(dictionary)
+
+	return g.write(dictionary, nil)
+}
+
+// write writes the dictionary, sources contains the path where each entry was found (if known)
+func (g *Generator) write(dictionary []string, sources map[string]string) error {
+	if g.mangler != nil {
+		dictionary, sources = g.mangle(dictionary, sources)
+	}
+
+	dictionary = g.deduplicate(dictionary)
+
 	for _, entry := range dictionary {
-		_, err = fmt.Fprintln(g.out, entry)
-		if err != nil {
-			return errors.Wrap(err, "failed to write to buffer")
+		if err := g.writeEntry(entry, sources[entry]); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func findAbsolutePaths(root string) ([]string, error) {
+func (g *Generator) writeEntry(entry, source string) error {
+	if g.format != OutputFormatJSON {
+		_, err := fmt.Fprintln(g.out, entry)
+
+		return errors.Wrap(err, "failed to write to buffer")
+	}
+
+	rawEntry, err := json.Marshal(jsonEntry{Word: entry, Source: source})
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert entry %s", entry)
+	}
+
+	_, err = g.out.Write(append(rawEntry, '\n'))
+
+	return errors.Wrap(err, "failed to write to buffer")
+}
+
+// mangle mangles the dictionary, the variants are attributed to the source of the entry they were produced from
+func (g *Generator) mangle(dictionary []string, sources map[string]string) ([]string, map[string]string) {
+	mangled, origins := g.mangler.mangle(dictionary)
+	if sources == nil {
+		return mangled, nil
+	}
+
+	mangledSources := make(map[string]string, len(mangled))
+	for i, entry := range mangled {
+		mangledSources[entry] = sources[dictionary[origins[i]]]
+	}
+
+	return mangled, mangledSources
+}
+
+// walk walks the tree under root without visiting the ignored files and folders
+func (g *Generator) walk(root string, walkFn filepath.WalkFunc) error {
+	w := treeWalker{followSymlinks: g.followSymlinks, logger: g.logger}
+
+	return w.walk(root, g.ignoreRules.filter(root, walkFn))
+}
+
+func (g *Generator) deduplicate(dictionary []string) []string {
+	if !g.caseInsensitiveDedup {
+		return dictionary
+	}
+
+	return removeCaseInsensitiveDuplicates(dictionary)
+}
+
+// findAbsolutePaths returns the paths of the files under root, each of them is its own source
+func findAbsolutePaths(root string, walk walkFunc) ([]string, map[string]string, error) {
 	var files []string
 
-	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+	sources := make(map[string]string)
+
+	err := walk(root, func(p string, info os.FileInfo, err error) error {
 		if err != nil {
 			return errors.Wrap(err, "findAbsolutePaths: failed to walk")
 		}
 
 		if !info.IsDir() {
 			files = append(files, p)
+			sources[p] = p
 		}
 		return nil
 	})
 
-	return files, err
+	return files, sources, err
 }
 
-func findFileNames(root string) ([]string, error) {
+// findFileNames returns the names of the files and folders under root together with the path where each of them
+// was found first
+func findFileNames(root string, walk walkFunc) ([]string, map[string]string, error) {
 	var files []string
 
-	filesByKey := make(map[string]bool)
+	sources := make(map[string]string)
 
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	err := walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return errors.Wrap(err, "findFileNames: failed to walk")
 		}
 
-		if _, ok := filesByKey[info.Name()]; !ok {
-			filesByKey[info.Name()] = true
+		if _, ok := sources[info.Name()]; !ok {
+			sources[info.Name()] = path
 			files = append(files, info.Name())
 			return nil
 		}
@@ -79,5 +298,5 @@ func findFileNames(root string) ([]string, error) {
 		return nil
 	})
 
-	return files, err
+	return files, sources, err
 }
@@ -2,8 +2,14 @@ package dictionary_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
 	"github.com/stefanoj3/dirstalk/pkg/dictionary"
 	"github.com/stretchr/testify/assert"
 )
@@ -56,6 +62,396 @@ myfile2.php
 	assert.Equal(t, expectedOutput, b.String())
 }
 
+func TestFilenamePathsGeneratorWithMangler(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	mangler, err := dictionary.NewMangler([]string{dictionary.MangleRuleCapitalize}, 2019)
+	assert.NoError(t, err)
+
+	dictionaryGenerator := dictionary.NewGenerator(b).WithMangler(mangler)
+
+	err = dictionaryGenerator.GenerateDictionaryFrom(
+		"testdata/directory_to_generate_dictionary/subfolder",
+		false,
+	)
+	assert.NoError(t, err)
+
+	expectedOutput := `subfolder
+Subfolder
+image.jpg
+Image.jpg
+image2.gif
+Image2.gif
+subsubfolder
+Subsubfolder
+myfile.php
+Myfile.php
+myfile2.php
+Myfile2.php
+`
+
+	assert.Equal(t, expectedOutput, b.String())
+}
+
+func TestFilenamePathsGeneratorWithCaseInsensitiveDedupShouldPreserveTheOrder(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	mangler, err := dictionary.NewMangler(
+		[]string{dictionary.MangleRuleUpper, dictionary.MangleRuleAppendDigits},
+		2019,
+	)
+	assert.NoError(t, err)
+
+	dictionaryGenerator := dictionary.NewGenerator(b).WithMangler(mangler).WithCaseInsensitiveDedup()
+
+	err = dictionaryGenerator.GenerateDictionaryFrom(
+		"testdata/directory_to_generate_dictionary/subfolder/subsubfolder",
+		false,
+	)
+	assert.NoError(t, err)
+
+	// the variants produced by the upper rule differ only by case, so they are removed
+	expectedOutput := `subsubfolder
+subsubfolder1
+subsubfolder123
+myfile.php
+myfile.php1
+myfile.php123
+myfile2.php
+myfile2.php1
+myfile2.php123
+`
+
+	assert.Equal(t, expectedOutput, b.String())
+}
+
+func TestFilenamePathsGeneratorWithJSONOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	mangler, err := dictionary.NewMangler([]string{dictionary.MangleRuleAppendDigits}, 2019)
+	assert.NoError(t, err)
+
+	dictionaryGenerator := dictionary.NewGenerator(b).
+		WithOutputFormat(dictionary.OutputFormatJSON).
+		WithMangler(mangler)
+
+	err = dictionaryGenerator.GenerateDictionaryFrom(
+		"testdata/directory_to_generate_dictionary/subfolder/subsubfolder",
+		false,
+	)
+	assert.NoError(t, err)
+
+	// the variants have the source of the entry they were produced from
+	expectedOutput := `{"word":"subsubfolder","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder"}
+{"word":"subsubfolder1","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder"}
+{"word":"subsubfolder123","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder"}
+{"word":"myfile.php","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile.php"}
+{"word":"myfile.php1","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile.php"}
+{"word":"myfile.php123","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile.php"}
+{"word":"myfile2.php","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile2.php"}
+{"word":"myfile2.php1","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile2.php"}
+{"word":"myfile2.php123","source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile2.php"}
+`
+
+	assert.Equal(t, expectedOutput, b.String())
+}
+
+func TestAbsolutePathsGeneratorWithJSONOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	dictionaryGenerator := dictionary.NewGenerator(b).WithOutputFormat(dictionary.OutputFormatJSON)
+
+	err := dictionaryGenerator.GenerateDictionaryFrom(
+		"testdata/directory_to_generate_dictionary/subfolder",
+		true,
+	)
+	assert.NoError(t, err)
+
+	expectedOutput := `{"word":"testdata/directory_to_generate_dictionary/subfolder/image.jpg",` +
+		`"source":"testdata/directory_to_generate_dictionary/subfolder/image.jpg"}
+{"word":"testdata/directory_to_generate_dictionary/subfolder/image2.gif",` +
+		`"source":"testdata/directory_to_generate_dictionary/subfolder/image2.gif"}
+{"word":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile.php",` +
+		`"source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile.php"}
+{"word":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile2.php",` +
+		`"source":"testdata/directory_to_generate_dictionary/subfolder/subsubfolder/myfile2.php"}
+`
+
+	assert.Equal(t, expectedOutput, b.String())
+}
+
+func TestFilenamePathsGeneratorWithIgnores(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	dictionaryGenerator := dictionary.NewGenerator(b).
+		WithIgnoredDirectories(dictionary.DefaultIgnoredDirectories).
+		WithIgnoredDirectories([]string{"cache/"}).
+		WithIgnoredExtensions([]string{".png", "WOFF"})
+
+	err := dictionaryGenerator.GenerateDictionaryFrom(
+		"testdata/directory_with_junk_to_generate_dictionary",
+		false,
+	)
+	assert.NoError(t, err)
+
+	// neither the ignored folders nor their content are listed
+	expectedOutput := `directory_with_junk_to_generate_dictionary
+assets
+app.js
+index.php
+`
+
+	assert.Equal(t, expectedOutput, b.String())
+}
+
+func TestAbsolutePathsGeneratorWithIgnores(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	dictionaryGenerator := dictionary.NewGenerator(b).
+		WithIgnoredDirectories([]string{"node_modules", "vendor"}).
+		WithIgnoredExtensions([]string{"png"})
+
+	err := dictionaryGenerator.GenerateDictionaryFrom(
+		"testdata/directory_with_junk_to_generate_dictionary",
+		true,
+	)
+	assert.NoError(t, err)
+
+	expectedOutput := `testdata/directory_with_junk_to_generate_dictionary/assets/app.js
+testdata/directory_with_junk_to_generate_dictionary/assets/font.woff
+testdata/directory_with_junk_to_generate_dictionary/cache/data.bin
+testdata/directory_with_junk_to_generate_dictionary/index.php
+`
+
+	assert.Equal(t, expectedOutput, b.String())
+}
+
+func TestAbsolutePathsGeneratorWithGitignore(t *testing.T) {
+	t.Parallel()
+
+	dir := mustCreateTree(t, map[string]string{
+		".gitignore":           "# build artifacts\n/build/\n*.log\n!important.log\ntmp*\ndocs/**/*.html\n",
+		"build/app.bin":        "",
+		"debug.log":            "",
+		"important.log":        "",
+		"tmpcache/data.txt":    "",
+		"docs/index.html":      "",
+		"docs/api/v1.html":     "",
+		"docs/readme.md":       "",
+		"src/.gitignore":       "generated/\n!tmp.txt\n",
+		"src/build/main.go":    "",
+		"src/generated/gen.go": "",
+		"src/index.php":        "",
+		"src/tmp.txt":          "",
+	})
+	defer removeDir(dir)
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).WithGitignore().GenerateDictionaryFrom(dir, true)
+	assert.NoError(t, err)
+
+	// the patterns of the .gitignore of src apply only to its content and take precedence over the ones of the root
+	expectedOutput := `.gitignore
+docs/readme.md
+important.log
+src/.gitignore
+src/build/main.go
+src/index.php
+src/tmp.txt
+`
+
+	assert.Equal(t, expectedOutput, strings.ReplaceAll(filepath.ToSlash(b.String()), filepath.ToSlash(dir)+"/", ""))
+}
+
+func TestFilenamePathsGeneratorShouldIncludeTheIgnoredFilesWithoutGitignore(t *testing.T) {
+	t.Parallel()
+
+	dir := mustCreateTree(t, map[string]string{
+		".gitignore": "*.log\n",
+		"debug.log":  "",
+	})
+	defer removeDir(dir)
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFrom(dir, false)
+	assert.NoError(t, err)
+
+	assert.Contains(t, b.String(), "debug.log\n")
+}
+
+func TestFilenamePathsGeneratorWithMaxDepth(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		maxDepth       int
+		expectedOutput string
+	}{
+		{
+			maxDepth:       0,
+			expectedOutput: "directory_to_generate_dictionary\nmyfile.php\nsubfolder\n",
+		},
+		{
+			maxDepth:       1,
+			expectedOutput: "directory_to_generate_dictionary\nmyfile.php\nsubfolder\nimage.jpg\nimage2.gif\nsubsubfolder\n",
+		},
+		{
+			maxDepth: -1,
+			expectedOutput: "directory_to_generate_dictionary\nmyfile.php\nsubfolder\nimage.jpg\nimage2.gif\n" +
+				"subsubfolder\nmyfile2.php\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(strconv.Itoa(tc.maxDepth), func(t *testing.T) {
+			t.Parallel()
+
+			b := &bytes.Buffer{}
+
+			err := dictionary.NewGenerator(b).
+				WithMaxDepth(tc.maxDepth).
+				GenerateDictionaryFrom("testdata/directory_to_generate_dictionary", false)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedOutput, b.String())
+		})
+	}
+}
+
+func TestAbsolutePathsGeneratorShouldNotFollowTheSymlinks(t *testing.T) {
+	t.Parallel()
+
+	dir := mustCreateTree(t, map[string]string{
+		"admin/config/settings.php": "",
+		"index.php":                 "",
+	})
+	defer removeDir(dir)
+
+	// a link to the root, following it would make the walk loop
+	assert.NoError(t, os.Symlink("..", filepath.Join(dir, "admin", "loop")))
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).WithMaxDepth(1).GenerateDictionaryFrom(dir, true)
+	assert.NoError(t, err)
+
+	expectedOutput := `admin/loop
+index.php
+`
+
+	assert.Equal(t, expectedOutput, strings.ReplaceAll(filepath.ToSlash(b.String()), filepath.ToSlash(dir)+"/", ""))
+}
+
+func TestAbsolutePathsGeneratorShouldFollowTheSymlinksWithoutLooping(t *testing.T) {
+	t.Parallel()
+
+	dir := mustCreateTree(t, map[string]string{
+		"admin/config/settings.php": "",
+		"index.php":                 "",
+	})
+	defer removeDir(dir)
+
+	assert.NoError(t, os.Symlink("..", filepath.Join(dir, "admin", "loop")))
+	assert.NoError(t, os.Symlink(filepath.Join("admin", "config"), filepath.Join(dir, "shared")))
+	assert.NoError(t, os.Symlink("missing", filepath.Join(dir, "broken")))
+
+	logger, loggerBuffer := test.NewLogger()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).
+		WithFollowSymlinks().
+		WithLogger(logger).
+		GenerateDictionaryFrom(dir, true)
+	assert.NoError(t, err)
+
+	// the folders pointed by the symlinks not making the walk loop are visited
+	expectedOutput := `admin/config/settings.php
+index.php
+shared/settings.php
+`
+
+	assert.Equal(t, expectedOutput, strings.ReplaceAll(filepath.ToSlash(b.String()), filepath.ToSlash(dir)+"/", ""))
+
+	assert.Contains(t, loggerBuffer.String(), "Skipping symlink, following it would make the walk loop")
+	assert.Contains(t, loggerBuffer.String(), filepath.Join(dir, "admin", "loop"))
+	assert.Contains(t, loggerBuffer.String(), "Skipping symlink, it cannot be resolved")
+	assert.Contains(t, loggerBuffer.String(), filepath.Join(dir, "broken"))
+}
+
+func TestFilenamePathsGeneratorShouldNotLoopWhenFollowingTheSymlinksPointingToEachOther(t *testing.T) {
+	t.Parallel()
+
+	dir := mustCreateTree(t, map[string]string{
+		"a/first.php":  "",
+		"b/second.php": "",
+	})
+	defer removeDir(dir)
+
+	assert.NoError(t, os.Symlink(filepath.Join("..", "b"), filepath.Join(dir, "a", "to-b")))
+	assert.NoError(t, os.Symlink(filepath.Join("..", "a"), filepath.Join(dir, "b", "to-a")))
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).WithFollowSymlinks().GenerateDictionaryFrom(dir, false)
+	assert.NoError(t, err)
+
+	// a/to-b/to-a and b/to-a/to-b point to a folder containing them, so they are skipped
+	expectedOutput := `a
+first.php
+to-b
+second.php
+b
+to-a
+`
+
+	assert.Equal(t, expectedOutput, strings.SplitN(b.String(), "\n", 2)[1])
+}
+
+func TestGeneratorShouldLogTheSymlinksNotFollowed(t *testing.T) {
+	t.Parallel()
+
+	dir := mustCreateTree(t, map[string]string{"admin/index.php": ""})
+	defer removeDir(dir)
+
+	assert.NoError(t, os.Symlink("admin", filepath.Join(dir, "link")))
+
+	logger, loggerBuffer := test.NewLogger()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).WithLogger(logger).GenerateDictionaryFrom(dir, false)
+	assert.NoError(t, err)
+
+	assert.Contains(t, b.String(), "\nlink\n")
+	assert.Contains(t, loggerBuffer.String(), "Not following symlink")
+	assert.Contains(t, loggerBuffer.String(), filepath.Join(dir, "link"))
+}
+
+func TestIsValidOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range dictionary.OutputFormats {
+		assert.True(t, dictionary.IsValidOutputFormat(format))
+	}
+
+	assert.False(t, dictionary.IsValidOutputFormat("csv"))
+}
+
 func BenchmarkGenerateDictionaryFrom(b *testing.B) {
 	buf := &bytes.Buffer{}
 
@@ -69,3 +465,29 @@ func BenchmarkGenerateDictionaryFrom(b *testing.B) {
 		)
 	}
 }
+
+// mustCreateTree creates a temporary folder containing the given files (by slash separated path)
+func mustCreateTree(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "dirstalk")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err.Error())
+	}
+
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("failed to create dir: %s", err.Error())
+		}
+
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to create file: %s", err.Error())
+		}
+	}
+
+	return dir
+}
+
+func removeDir(dir string) {
+	_ = os.RemoveAll(dir) //nolint:errcheck
+}
@@ -0,0 +1,122 @@
+package dictionary
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const gitignoreFileName = ".gitignore"
+
+// gitignore contains the patterns of a .gitignore file (see https://git-scm.com/docs/gitignore), the common subset
+// of the syntax is supported: comments, negations (`!`), folders only patterns (trailing `/`), patterns relative to
+// the folder of the file (containing a `/`), the wildcards `*`, `?` and `[...]` and the `**` folders wildcard
+type gitignore struct {
+	patterns []gitignorePattern
+}
+
+type gitignorePattern struct {
+	segments []string
+	negated  bool
+	dirOnly  bool
+}
+
+func parseGitignore(r io.Reader) (gitignore, error) {
+	g := gitignore{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if pattern, ok := parseGitignorePattern(scanner.Text()); ok {
+			g.patterns = append(g.patterns, pattern)
+		}
+	}
+
+	return g, errors.Wrap(scanner.Err(), "failed to parse gitignore")
+}
+
+func parseGitignorePattern(line string) (gitignorePattern, bool) {
+	pattern := gitignorePattern{}
+
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		pattern.negated = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if line == "" {
+		return pattern, false
+	}
+
+	// a pattern without slashes matches at any level, the others are relative to the folder of the .gitignore
+	if !strings.Contains(line, "/") {
+		line = "**/" + line
+	}
+
+	pattern.segments = strings.Split(strings.TrimPrefix(line, "/"), "/")
+
+	return pattern, true
+}
+
+// match returns true as first value when the path (slash separated and relative to the folder of the .gitignore)
+// is ignored, the second value is false when no pattern matches the path; the last pattern matching it wins
+func (g gitignore) match(relativePath string, isDir bool) (ignored bool, matched bool) {
+	segments := strings.Split(relativePath, "/")
+
+	for i := len(g.patterns) - 1; i >= 0; i-- {
+		pattern := g.patterns[i]
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+
+		if matchSegments(pattern.segments, segments) {
+			return !pattern.negated, true
+		}
+	}
+
+	return false, false
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		// a trailing `**` matches everything inside the folder, but not the folder itself
+		if len(pattern) == 1 {
+			return len(segments) > 0
+		}
+
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+		return false
+	}
+
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,59 @@
+package dictionary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitignoreMatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		patterns        string
+		path            string
+		isDir           bool
+		expectedIgnored bool
+		expectedMatched bool
+	}{
+		{patterns: "", path: "main.go"},
+		{patterns: "# main.go", path: "main.go"},
+		{patterns: `\#main.go`, path: "#main.go", expectedIgnored: true, expectedMatched: true},
+		{patterns: "main.go", path: "main.go", expectedIgnored: true, expectedMatched: true},
+		{patterns: "main.go  ", path: "src/cmd/main.go", expectedIgnored: true, expectedMatched: true},
+		{patterns: "*.log", path: "logs/debug.log", expectedIgnored: true, expectedMatched: true},
+		{patterns: "debug?.log", path: "debug1.log", expectedIgnored: true, expectedMatched: true},
+		{patterns: "debug[0-9].log", path: "debuga.log"},
+		{patterns: "*.log\n!important.log", path: "important.log", expectedMatched: true},
+		{patterns: "!important.log\n*.log", path: "important.log", expectedIgnored: true, expectedMatched: true},
+		{patterns: "build/", path: "build"},
+		{patterns: "build/", path: "build", isDir: true, expectedIgnored: true, expectedMatched: true},
+		{patterns: "build/", path: "src/build", isDir: true, expectedIgnored: true, expectedMatched: true},
+		{patterns: "/build", path: "build", isDir: true, expectedIgnored: true, expectedMatched: true},
+		{patterns: "/build", path: "src/build", isDir: true},
+		{patterns: "src/build", path: "src/build", expectedIgnored: true, expectedMatched: true},
+		{patterns: "src/build", path: "app/src/build"},
+		{patterns: "**/build", path: "app/src/build", expectedIgnored: true, expectedMatched: true},
+		{patterns: "docs/**/*.html", path: "docs/index.html", expectedIgnored: true, expectedMatched: true},
+		{patterns: "docs/**/*.html", path: "docs/api/v1/index.html", expectedIgnored: true, expectedMatched: true},
+		{patterns: "docs/**/*.html", path: "src/docs/index.html"},
+		{patterns: "docs/**", path: "docs", isDir: true},
+		{patterns: "docs/**", path: "docs/index.html", expectedIgnored: true, expectedMatched: true},
+		{patterns: "[", path: "["},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.patterns+" "+tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := parseGitignore(strings.NewReader(tc.patterns))
+			assert.NoError(t, err)
+
+			ignored, matched := g.match(tc.path, tc.isDir)
+			assert.Equal(t, tc.expectedIgnored, ignored)
+			assert.Equal(t, tc.expectedMatched, matched)
+		})
+	}
+}
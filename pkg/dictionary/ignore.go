@@ -0,0 +1,157 @@
+package dictionary
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultIgnoredDirectories contains the folders usually containing dependencies, caches or version control data
+// rather than the files of the application
+var DefaultIgnoredDirectories = []string{
+	".git",
+	".hg",
+	".svn",
+	"node_modules",
+	"bower_components",
+	"vendor",
+	"__pycache__",
+}
+
+func newIgnoreRules() ignoreRules {
+	return ignoreRules{
+		directories: make(map[string]struct{}),
+		extensions:  make(map[string]struct{}),
+		maxDepth:    -1,
+	}
+}
+
+// ignoreRules decides which files and folders are left out when walking a tree: the folders are ignored by name,
+// the files by extension (case insensitive and with or without the leading dot, EG `.PNG` and `png` are the same)
+// and, when respectGitignore is true, both are ignored according to the .gitignore files found in the tree;
+// the content of the folders at maxDepth (0 being the content of root) is not visited, unless maxDepth is negative
+type ignoreRules struct {
+	directories      map[string]struct{}
+	extensions       map[string]struct{}
+	respectGitignore bool
+	maxDepth         int
+}
+
+func (r ignoreRules) addDirectories(directories []string) {
+	for _, directory := range directories {
+		if directory = strings.Trim(directory, "/"); directory != "" {
+			r.directories[directory] = struct{}{}
+		}
+	}
+}
+
+func (r ignoreRules) addExtensions(extensions []string) {
+	for _, extension := range extensions {
+		if extension = normalizeExtension(extension); extension != "" {
+			r.extensions[extension] = struct{}{}
+		}
+	}
+}
+
+// filter returns a filepath.WalkFunc calling walkFn for the entries of the tree under root which are not ignored,
+// the ignored folders are skipped together with their content; root itself is never ignored
+func (r ignoreRules) filter(root string, walkFn filepath.WalkFunc) filepath.WalkFunc {
+	// the .gitignore files found so far, by folder (the paths visited are clean, apart from root)
+	gitignores := make(map[string]gitignore)
+
+	return func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return walkFn(path, info, err)
+		}
+
+		if path != root && r.ignores(filepath.Clean(root), path, info, gitignores) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if info.IsDir() && r.respectGitignore {
+			if err := loadGitignore(filepath.Clean(path), gitignores); err != nil {
+				return err
+			}
+		}
+
+		if err := walkFn(path, info, err); err != nil {
+			return err
+		}
+
+		if info.IsDir() && path != root && r.maxDepth >= 0 && depth(root, path) >= r.maxDepth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	}
+}
+
+// depth returns the depth of path relative to root, the entries of root having depth 0
+func depth(root, path string) int {
+	relativePath, err := filepath.Rel(root, path)
+	if err != nil {
+		return 0
+	}
+
+	return strings.Count(filepath.ToSlash(relativePath), "/")
+}
+
+func (r ignoreRules) ignores(root, path string, info os.FileInfo, gitignores map[string]gitignore) bool {
+	if info.IsDir() {
+		if _, ok := r.directories[info.Name()]; ok {
+			return true
+		}
+	} else if _, ok := r.extensions[normalizeExtension(filepath.Ext(info.Name()))]; ok {
+		return true
+	}
+
+	// the .gitignore of the nearest folder having a pattern matching the path decides
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if g, ok := gitignores[dir]; ok {
+			relativePath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return false
+			}
+
+			if ignored, matched := g.match(filepath.ToSlash(relativePath), info.IsDir()); matched {
+				return ignored
+			}
+		}
+
+		if dir == root || dir == filepath.Dir(dir) {
+			return false
+		}
+	}
+}
+
+func loadGitignore(dir string, gitignores map[string]gitignore) error {
+	file, err := os.Open(filepath.Join(dir, gitignoreFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to open the %s of %s", gitignoreFileName, dir)
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	g, err := parseGitignore(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the %s of %s", gitignoreFileName, dir)
+	}
+
+	gitignores[dir] = g
+
+	return nil
+}
+
+func normalizeExtension(extension string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(extension), "."))
+}
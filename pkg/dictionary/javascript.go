@@ -0,0 +1,75 @@
+package dictionary
+
+import (
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// javascriptPathRegex matches the quoted strings that look like a route: the ones starting with `/`, `./` or `../`
+// and the relative paths ending with a file extension (EG `api/users.json`)
+var javascriptPathRegex = regexp.MustCompile(
+	"[\"'`]" +
+		"((?:/|\\.\\.?/)[^\"'`\\s<>]*|[a-zA-Z0-9_\\-]+/[a-zA-Z0-9_\\-/.]*\\.[a-zA-Z0-9]{2,5}(?:[?#][^\"'`\\s<>]*)?)" +
+		"[\"'`]",
+)
+
+// findJavascriptPaths returns the paths found in the given javascript files (either local files or urls),
+// sorted and without duplicates; the query string and the fragment are removed from them
+func findJavascriptPaths(javascriptPaths []string, doer Doer) ([]string, error) {
+	alreadyFound := make(map[string]struct{})
+
+	for _, javascriptPath := range javascriptPaths {
+		reader, err := open(javascriptPath, doer)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := ioutil.ReadAll(reader)
+		_ = reader.Close()
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "dictionary: failed to read `%s`", javascriptPath)
+		}
+
+		for _, match := range javascriptPathRegex.FindAllStringSubmatch(string(content), -1) {
+			p, ok := javascriptCandidatePath(match[1])
+			if !ok {
+				continue
+			}
+
+			alreadyFound[p] = struct{}{}
+		}
+	}
+
+	paths := make([]string, 0, len(alreadyFound))
+	for p := range alreadyFound {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// javascriptCandidatePath cleans up the matched string, false when what is left is not a path that can be requested
+func javascriptCandidatePath(p string) (string, bool) {
+	// the interpolations of the template literals are not known, the path is kept up to the first one
+	if i := strings.Index(p, "${"); i >= 0 {
+		p = p[:i]
+	}
+
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+
+	// `//` is the beginning of a protocol relative url (or of a comment), it points to another host
+	if strings.HasPrefix(p, "//") {
+		return "", false
+	}
+
+	return p, len(p) > 0 && p != "/" && p != "./" && p != "../"
+}
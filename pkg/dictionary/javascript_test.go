@@ -0,0 +1,92 @@
+package dictionary_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedJavascriptDictionary = `./templates/main.html
+/api/v1
+/api/v1/settings
+/api/v1/users
+/api/v1/users/
+/login
+/logout
+legacy/export.php
+`
+
+func TestJavascriptGeneratorFromLocalFile(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFromJavascript([]string{"testdata/app.js"}, &http.Client{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, expectedJavascriptDictionary, b.String())
+}
+
+func TestJavascriptGeneratorFromMultipleFiles(t *testing.T) {
+	t.Parallel()
+
+	app, err := ioutil.ReadFile("testdata/app.js")
+	assert.NoError(t, err)
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/app.js":
+				_, _ = w.Write(app) //nolint:errcheck
+			case "/vendor.js":
+				_, _ = w.Write([]byte(`fetch("/api/v2/items"); fetch("/login");`)) //nolint:errcheck
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	b := &bytes.Buffer{}
+
+	err = dictionary.NewGenerator(b).GenerateDictionaryFromJavascript(
+		[]string{srv.URL + "/app.js", srv.URL + "/vendor.js"},
+		&http.Client{},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		`./templates/main.html
+/api/v1
+/api/v1/settings
+/api/v1/users
+/api/v1/users/
+/api/v2/items
+/login
+/logout
+legacy/export.php
+`,
+		b.String(),
+	)
+}
+
+func TestJavascriptGeneratorShouldFailWhenAFileCannotBeRetrieved(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFromJavascript(
+		[]string{"testdata/app.js", "testdata/missing.js"},
+		&http.Client{},
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.js")
+
+	assert.Empty(t, b.String())
+}
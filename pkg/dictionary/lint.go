@@ -0,0 +1,87 @@
+package dictionary
+
+import (
+	"bufio"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// maxLintLineSize is the size of the longest line that can be read while linting a dictionary,
+// it is way above any reasonable length for an entry so that very long lines can be reported instead of failing
+const maxLintLineSize = 1024 * 1024
+
+// LintReport contains the issues found in a dictionary and the entries left once the issues are removed
+type LintReport struct {
+	Entries               int
+	BlankLines            int
+	Duplicates            int
+	WithSpaces            int
+	WithControlCharacters int
+	TooLong               int
+	Cleaned               []string
+}
+
+// IssuesCount returns the amount of lines with an issue
+func (r LintReport) IssuesCount() int {
+	return r.BlankLines + r.Duplicates + r.WithSpaces + r.WithControlCharacters + r.TooLong
+}
+
+// Lint checks the given dictionary (either a local file or a url) for blank lines, duplicates, entries containing
+// spaces or control characters and entries longer than maxLength; the comments are ignored.
+// The cleaned entries are trimmed and do not contain any of the entries with an issue.
+func Lint(path string, doer Doer, maxLength int) (LintReport, error) {
+	reader, err := open(path, doer)
+	if err != nil {
+		return LintReport{}, err
+	}
+
+	defer reader.Close() //nolint:errcheck
+
+	report := LintReport{Cleaned: make([]string, 0)}
+	alreadyFound := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLintLineSize)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		entry := strings.TrimSpace(line)
+		if len(entry) == 0 {
+			report.BlankLines++
+			continue
+		}
+
+		if isAComment(line) {
+			continue
+		}
+
+		report.Entries++
+
+		switch {
+		case strings.IndexFunc(line, unicode.IsControl) >= 0:
+			report.WithControlCharacters++
+		case strings.ContainsRune(line, ' '):
+			report.WithSpaces++
+		case utf8.RuneCountInString(entry) > maxLength:
+			report.TooLong++
+		default:
+			if _, found := alreadyFound[entry]; found {
+				report.Duplicates++
+				continue
+			}
+
+			alreadyFound[entry] = struct{}{}
+			report.Cleaned = append(report.Cleaned, entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return LintReport{}, errors.Wrapf(err, "dictionary: failed to read `%s`", path)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,77 @@
+package dictionary_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintShouldReportTheIssuesFound(t *testing.T) {
+	t.Parallel()
+
+	// the carriage returns of the CRLF line endings are not reported
+	report, err := dictionary.Lint("testdata/lint.txt", &http.Client{}, 10)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		dictionary.LintReport{
+			Entries:               10,
+			BlankLines:            2,
+			Duplicates:            2,
+			WithSpaces:            1,
+			WithControlCharacters: 2,
+			TooLong:               1,
+			Cleaned:               []string{"admin", "login", "backup", "/static/"},
+		},
+		report,
+	)
+	assert.Equal(t, 8, report.IssuesCount())
+}
+
+func TestLintShouldNotReportIssuesForAValidDictionary(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("# comment\nadmin\nlogin\n")) //nolint:errcheck
+		}),
+	)
+	defer srv.Close()
+
+	report, err := dictionary.Lint(srv.URL, &http.Client{}, 256)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, report.Entries)
+	assert.Equal(t, 0, report.IssuesCount())
+	assert.Equal(t, []string{"admin", "login"}, report.Cleaned)
+}
+
+func TestLintShouldReportLinesLongerThanTheDefaultScannerBuffer(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("admin\n" + strings.Repeat("a", 100000) + "\n")) //nolint:errcheck
+		}),
+	)
+	defer srv.Close()
+
+	report, err := dictionary.Lint(srv.URL, &http.Client{}, 256)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, report.TooLong)
+	assert.Equal(t, []string{"admin"}, report.Cleaned)
+}
+
+func TestLintShouldFailWhenTheDictionaryCannotBeRead(t *testing.T) {
+	t.Parallel()
+
+	_, err := dictionary.Lint("testdata/missing.txt", &http.Client{}, 256)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.txt")
+}
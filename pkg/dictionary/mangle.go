@@ -0,0 +1,117 @@
+package dictionary
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The rules available to mangle the words of a dictionary
+const (
+	MangleRuleUpper        = "upper"
+	MangleRuleLower        = "lower"
+	MangleRuleCapitalize   = "capitalize"
+	MangleRuleAppendDigits = "append-digits"
+	MangleRuleAppendYear   = "append-year"
+)
+
+// MangleRules contains all the rules available, in the order they are applied
+var MangleRules = []string{
+	MangleRuleUpper,
+	MangleRuleLower,
+	MangleRuleCapitalize,
+	MangleRuleAppendDigits,
+	MangleRuleAppendYear,
+}
+
+var appendedDigits = []string{"1", "123"}
+
+type mangleRule func(word string) []string
+
+// NewMangler returns a mangler applying the given rules, the year is the one appended by the append-year rule
+func NewMangler(rules []string, year int) (*Mangler, error) {
+	m := &Mangler{}
+
+	for _, rule := range rules {
+		switch rule {
+		case MangleRuleUpper:
+			m.rules = append(m.rules, func(word string) []string { return []string{strings.ToUpper(word)} })
+		case MangleRuleLower:
+			m.rules = append(m.rules, func(word string) []string { return []string{strings.ToLower(word)} })
+		case MangleRuleCapitalize:
+			m.rules = append(m.rules, func(word string) []string { return []string{capitalize(word)} })
+		case MangleRuleAppendDigits:
+			m.rules = append(m.rules, func(word string) []string {
+				variants := make([]string, 0, len(appendedDigits))
+				for _, digits := range appendedDigits {
+					variants = append(variants, word+digits)
+				}
+
+				return variants
+			})
+		case MangleRuleAppendYear:
+			y := strconv.Itoa(year)
+			m.rules = append(m.rules, func(word string) []string { return []string{word + y} })
+		default:
+			return nil, errors.Errorf(
+				"unknown mangle rule `%s`, the available ones are: %s",
+				rule,
+				strings.Join(MangleRules, ","),
+			)
+		}
+	}
+
+	return m, nil
+}
+
+// Mangler produces the variants of the words of a dictionary
+type Mangler struct {
+	rules []mangleRule
+}
+
+// Mangle returns each word followed by its variants, in the order the rules were provided and without duplicates
+func (m *Mangler) Mangle(words []string) []string {
+	mangled, _ := m.mangle(words)
+
+	return mangled
+}
+
+// mangle works like Mangle, it also returns the index of the word each entry of the result was produced from
+func (m *Mangler) mangle(words []string) ([]string, []int) {
+	mangled := make([]string, 0, len(words)*(len(m.rules)+1))
+	origins := make([]int, 0, cap(mangled))
+	alreadyFound := make(map[string]struct{})
+
+	add := func(word string, origin int) {
+		if _, found := alreadyFound[word]; found {
+			return
+		}
+
+		alreadyFound[word] = struct{}{}
+		mangled = append(mangled, word)
+		origins = append(origins, origin)
+	}
+
+	for i, word := range words {
+		add(word, i)
+
+		for _, rule := range m.rules {
+			for _, variant := range rule(word) {
+				add(variant, i)
+			}
+		}
+	}
+
+	return mangled, origins
+}
+
+func capitalize(word string) string {
+	if len(word) == 0 {
+		return word
+	}
+
+	lower := strings.ToLower(word)
+
+	return strings.ToUpper(lower[:1]) + lower[1:]
+}
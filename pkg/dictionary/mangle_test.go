@@ -0,0 +1,60 @@
+package dictionary_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManglerShouldApplyTheRulesInTheOrderProvided(t *testing.T) {
+	t.Parallel()
+
+	sut, err := dictionary.NewMangler(
+		[]string{dictionary.MangleRuleAppendYear, dictionary.MangleRuleUpper, dictionary.MangleRuleAppendDigits},
+		2019,
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]string{"admin", "admin2019", "ADMIN", "admin1", "admin123", "Login", "Login2019", "LOGIN", "Login1", "Login123"},
+		sut.Mangle([]string{"admin", "Login"}),
+	)
+}
+
+func TestManglerShouldNotProduceDuplicates(t *testing.T) {
+	t.Parallel()
+
+	sut, err := dictionary.NewMangler(dictionary.MangleRules, 2019)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]string{
+			"Admin", "ADMIN", "admin", "Admin1", "Admin123", "Admin2019",
+			"admin1", "admin123", "admin2019",
+			"ADMIN1", "ADMIN123", "ADMIN2019",
+			"123", "1231", "123123", "1232019",
+		},
+		sut.Mangle([]string{"Admin", "admin", "ADMIN", "Admin", "123"}),
+	)
+}
+
+func TestManglerWithoutRulesShouldRemoveTheDuplicates(t *testing.T) {
+	t.Parallel()
+
+	sut, err := dictionary.NewMangler(nil, 2019)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"admin", "login"}, sut.Mangle([]string{"admin", "login", "admin"}))
+}
+
+func TestManglerShouldErrForUnknownRules(t *testing.T) {
+	t.Parallel()
+
+	_, err := dictionary.NewMangler([]string{dictionary.MangleRuleUpper, "reverse"}, 2019)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown mangle rule `reverse`")
+	assert.Contains(t, err.Error(), "upper,lower,capitalize,append-digits,append-year")
+}
@@ -0,0 +1,65 @@
+package dictionary
+
+import (
+	"strings"
+	"unicode"
+)
+
+// mergeDictionaries returns the entries of all the given dictionaries (either local files or urls) in the order
+// they are found, without duplicates; unless preserveFormat is true the entries are normalized before removing
+// the duplicates
+func mergeDictionaries(dictionaryPaths []string, preserveFormat bool, doer Doer) ([]string, error) {
+	merged := make([]string, 0)
+	alreadyFound := make(map[string]struct{})
+
+	for _, dictionaryPath := range dictionaryPaths {
+		entries, err := NewDictionaryFrom(dictionaryPath, doer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !preserveFormat {
+				entry = normalizeEntry(entry)
+			}
+
+			if len(entry) == 0 {
+				continue
+			}
+
+			if _, found := alreadyFound[entry]; found {
+				continue
+			}
+
+			alreadyFound[entry] = struct{}{}
+			merged = append(merged, entry)
+		}
+	}
+
+	return merged, nil
+}
+
+// removeCaseInsensitiveDuplicates removes the entries differing from a previous one only by case,
+// the first occurrence is kept and the order of the entries is preserved
+func removeCaseInsensitiveDuplicates(entries []string) []string {
+	deduplicated := make([]string, 0, len(entries))
+	alreadyFound := make(map[string]struct{})
+
+	for _, entry := range entries {
+		key := strings.ToLower(entry)
+		if _, found := alreadyFound[key]; found {
+			continue
+		}
+
+		alreadyFound[key] = struct{}{}
+		deduplicated = append(deduplicated, entry)
+	}
+
+	return deduplicated
+}
+
+// normalizeEntry removes the leading slashes and the trailing whitespaces, so that EG `admin` and `/admin `
+// are considered the same entry
+func normalizeEntry(entry string) string {
+	return strings.TrimLeft(strings.TrimRightFunc(entry, unicode.IsSpace), "/")
+}
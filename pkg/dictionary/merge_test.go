@@ -0,0 +1,113 @@
+package dictionary_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeDictionariesShouldNormalizeTheEntries(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).MergeDictionaries(
+		[]string{"testdata/merge/first.txt", "testdata/merge/second.txt"},
+		false,
+		&http.Client{},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Upload/\nadmin\nbackup.zip\nlogin\n", b.String())
+}
+
+func TestMergeDictionariesShouldPreserveTheFormatWhenRequested(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).MergeDictionaries(
+		[]string{"testdata/merge/first.txt", "testdata/merge/second.txt"},
+		true,
+		&http.Client{},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/\n/Upload/  \n/admin\n/login\nadmin\nbackup.zip\t\nlogin\n", b.String())
+}
+
+func TestMergeDictionariesWithCaseInsensitiveDedupShouldKeepTheFirstOccurrence(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/first.txt":
+				_, _ = w.Write([]byte("Admin\nlogin\n")) //nolint:errcheck
+			case "/second.txt":
+				_, _ = w.Write([]byte("ADMIN\nbackup\n/admin\nLogin\n")) //nolint:errcheck
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer srv.Close()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).WithCaseInsensitiveDedup().MergeDictionaries(
+		[]string{srv.URL + "/first.txt", srv.URL + "/second.txt"},
+		false,
+		&http.Client{},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Admin\nbackup\nlogin\n", b.String())
+}
+
+func TestMergeDictionariesFromRemoteFiles(t *testing.T) {
+	t.Parallel()
+
+	second, err := ioutil.ReadFile("testdata/merge/second.txt")
+	assert.NoError(t, err)
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(second) //nolint:errcheck
+		}),
+	)
+	defer srv.Close()
+
+	b := &bytes.Buffer{}
+
+	err = dictionary.NewGenerator(b).MergeDictionaries(
+		[]string{"testdata/merge/first.txt", srv.URL + "/second.txt"},
+		false,
+		&http.Client{},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Upload/\nadmin\nbackup.zip\nlogin\n", b.String())
+}
+
+func TestMergeDictionariesShouldFailWhenADictionaryCannotBeRead(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).MergeDictionaries(
+		[]string{"testdata/merge/first.txt", "testdata/merge/missing.txt"},
+		false,
+		&http.Client{},
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to merge dictionaries")
+	assert.Contains(t, err.Error(), "missing.txt")
+
+	assert.Empty(t, b.String())
+}
@@ -0,0 +1,58 @@
+package dictionary
+
+import (
+	"strings"
+)
+
+// findRobotsPaths returns the paths of the Allow and Disallow directives of the robots.txt, without duplicates;
+// the query string and the wildcards are removed from them
+func findRobotsPaths(robotsPath string, doer Doer) ([]string, error) {
+	lines, err := NewDictionaryFrom(robotsPath, doer)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0)
+	alreadyFound := make(map[string]struct{})
+
+	for _, line := range lines {
+		p, ok := robotsDirectivePath(line)
+		if !ok {
+			continue
+		}
+
+		if _, found := alreadyFound[p]; found {
+			continue
+		}
+
+		alreadyFound[p] = struct{}{}
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
+
+// robotsDirectivePath returns the path of the directive, false when the line is not an Allow or Disallow directive
+// or when the path is empty (EG `Disallow:` or `Allow: /`)
+func robotsDirectivePath(line string) (string, bool) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+
+	directive := strings.ToLower(strings.TrimSpace(parts[0]))
+	if directive != "allow" && directive != "disallow" {
+		return "", false
+	}
+
+	p := parts[1]
+
+	// everything after a wildcard, the query string or an inline comment is not part of a path that can be requested
+	if i := strings.IndexAny(p, "*$?#"); i >= 0 {
+		p = p[:i]
+	}
+
+	p = strings.TrimSpace(p)
+
+	return p, len(p) > 0 && p != "/"
+}
@@ -0,0 +1,73 @@
+package dictionary_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedRobotsDictionary = `/admin/
+/search
+/private/
+/tmp
+`
+
+func TestRobotsGeneratorFromLocalFile(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFromRobots("testdata/robots.txt", &http.Client{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, expectedRobotsDictionary, b.String())
+}
+
+func TestRobotsGeneratorFromRemoteFile(t *testing.T) {
+	t.Parallel()
+
+	robots, err := ioutil.ReadFile("testdata/robots.txt")
+	assert.NoError(t, err)
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/robots.txt" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write(robots) //nolint:errcheck
+		}),
+	)
+	defer srv.Close()
+
+	b := &bytes.Buffer{}
+
+	err = dictionary.NewGenerator(b).GenerateDictionaryFromRobots(srv.URL+"/robots.txt", &http.Client{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, expectedRobotsDictionary, b.String())
+}
+
+func TestRobotsGeneratorShouldFailWhenTheRobotsCannotBeRetrieved(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer srv.Close()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFromRobots(srv.URL+"/robots.txt", &http.Client{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status code 404")
+	assert.Empty(t, b.String())
+}
@@ -0,0 +1,16 @@
+package dictionary
+
+import "math/rand"
+
+// Shuffle returns a copy of the dictionary with its entries in random order, the same seed always produces
+// the same order of a given dictionary
+func Shuffle(dictionary []string, seed int64) []string {
+	shuffled := make([]string, len(dictionary))
+	copy(shuffled, dictionary)
+
+	rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled
+}
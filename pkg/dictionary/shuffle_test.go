@@ -0,0 +1,29 @@
+package dictionary_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShuffleShouldProduceTheSameOrderForTheSameSeed(t *testing.T) {
+	t.Parallel()
+
+	dict := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		dict = append(dict, "entry"+strconv.Itoa(i))
+	}
+
+	original := append([]string{}, dict...)
+
+	shuffled := dictionary.Shuffle(dict, 42)
+
+	assert.Equal(t, original, dict, "the dictionary should not be modified")
+	assert.NotEqual(t, dict, shuffled)
+	assert.ElementsMatch(t, dict, shuffled)
+
+	assert.Equal(t, shuffled, dictionary.Shuffle(dict, 42))
+	assert.NotEqual(t, shuffled, dictionary.Shuffle(dict, 43))
+}
@@ -0,0 +1,108 @@
+package dictionary
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxSitemapIndexDepth is how many levels of nested sitemap indexes are followed, the deeper ones are ignored
+const maxSitemapIndexDepth = 3
+
+// sitemapDocument represents both a sitemap (urlset) and a sitemap index (sitemapindex), as they
+// have the same structure
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLocation `xml:"url"`
+	Sitemaps []sitemapLocation `xml:"sitemap"`
+}
+
+type sitemapLocation struct {
+	Loc string `xml:"loc"`
+}
+
+// findSitemapPaths returns the paths of the locations of the sitemap, without duplicates; the sitemaps
+// referenced by a sitemap index are retrieved and parsed as well
+func findSitemapPaths(sitemapPath string, doer Doer) ([]string, error) {
+	f := &sitemapPathFinder{
+		doer:         doer,
+		visited:      make(map[string]struct{}),
+		alreadyFound: make(map[string]struct{}),
+	}
+
+	if err := f.find(sitemapPath, 0); err != nil {
+		return nil, err
+	}
+
+	return f.paths, nil
+}
+
+type sitemapPathFinder struct {
+	doer         Doer
+	visited      map[string]struct{}
+	alreadyFound map[string]struct{}
+	paths        []string
+}
+
+func (f *sitemapPathFinder) find(sitemapPath string, depth int) error {
+	// a sitemap index may reference itself or an index referencing it
+	if _, ok := f.visited[sitemapPath]; ok {
+		return nil
+	}
+
+	f.visited[sitemapPath] = struct{}{}
+
+	doc, err := f.parse(sitemapPath)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range doc.URLs {
+		f.add(u.Loc)
+	}
+
+	if depth >= maxSitemapIndexDepth {
+		return nil
+	}
+
+	for _, s := range doc.Sitemaps {
+		if err := f.find(strings.TrimSpace(s.Loc), depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *sitemapPathFinder) parse(sitemapPath string) (sitemapDocument, error) {
+	reader, err := open(sitemapPath, f.doer)
+	if err != nil {
+		return sitemapDocument{}, err
+	}
+
+	defer reader.Close() //nolint:errcheck
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return sitemapDocument{}, errors.Wrapf(err, "sitemap: failed to parse `%s`", sitemapPath)
+	}
+
+	return doc, nil
+}
+
+// add records the path of the location, the scheme, the host and the query are not part of it
+func (f *sitemapPathFinder) add(loc string) {
+	u, err := url.Parse(strings.TrimSpace(loc))
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return
+	}
+
+	if _, ok := f.alreadyFound[u.Path]; ok {
+		return
+	}
+
+	f.alreadyFound[u.Path] = struct{}{}
+	f.paths = append(f.paths, u.Path)
+}
@@ -0,0 +1,103 @@
+package dictionary_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSitemapGeneratorFromLocalFile(t *testing.T) {
+	t.Parallel()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFromSitemap("testdata/sitemap/sitemap.xml", &http.Client{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/about\n/blog/first-post\n", b.String())
+}
+
+func TestSitemapGeneratorShouldFollowTheSitemapIndexes(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+
+	srv = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			index := func(locations ...string) string {
+				sitemaps := ""
+				for _, l := range locations {
+					sitemaps += fmt.Sprintf("<sitemap><loc>%s%s</loc></sitemap>", srv.URL, l)
+				}
+
+				return `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + sitemaps + `</sitemapindex>`
+			}
+
+			urlset := func(locations ...string) string {
+				urls := ""
+				for _, l := range locations {
+					urls += fmt.Sprintf("<url><loc>%s%s</loc></url>", srv.URL, l)
+				}
+
+				return `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + urls + `</urlset>`
+			}
+
+			var body string
+
+			switch r.URL.Path {
+			case "/sitemap_index.xml":
+				// referencing itself, it should not be parsed again
+				body = index("/sitemap_index.xml", "/sitemap_products.xml", "/nested/level1.xml")
+			case "/sitemap_products.xml":
+				body = urlset("/products/1", "/products/2")
+			case "/nested/level1.xml":
+				body = index("/nested/level2.xml")
+			case "/nested/level2.xml":
+				body = index("/nested/level3.xml", "/nested/deep.xml")
+			case "/nested/deep.xml":
+				body = urlset("/deep")
+			case "/nested/level3.xml":
+				// too deep, the sitemaps referenced are not followed
+				body = index("/nested/level4.xml")
+			case "/nested/level4.xml":
+				body = urlset("/too-deep")
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write([]byte(body)) //nolint:errcheck
+		}),
+	)
+	defer srv.Close()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFromSitemap(srv.URL+"/sitemap_index.xml", &http.Client{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/products/1\n/products/2\n/deep\n", b.String())
+}
+
+func TestSitemapGeneratorShouldFailForAnInvalidSitemap(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("<urlset><url>")) //nolint:errcheck
+		}),
+	)
+	defer srv.Close()
+
+	b := &bytes.Buffer{}
+
+	err := dictionary.NewGenerator(b).GenerateDictionaryFromSitemap(srv.URL+"/sitemap.xml", &http.Client{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse")
+	assert.Empty(t, b.String())
+}
@@ -0,0 +1,45 @@
+package dictionary
+
+import "context"
+
+// Source is a dictionary that can be iterated more than once (EG once for each folder scanned recursively)
+type Source interface {
+	// Entries returns the entries of the dictionary in order, the channel is closed after the last one
+	// or when ctx is done
+	Entries(ctx context.Context) <-chan string
+}
+
+// Words is a dictionary held in memory
+type Words []string
+
+func (w Words) Entries(ctx context.Context) <-chan string {
+	entries := make(chan string, 10)
+
+	go func() {
+		defer close(entries)
+
+		for _, word := range w {
+			select {
+			case <-ctx.Done():
+				return
+			case entries <- word:
+			}
+		}
+	}()
+
+	return entries
+}
+
+// Load returns the entries of the source, loading all of them in memory (EG to shuffle them)
+func Load(ctx context.Context, source Source) []string {
+	if words, ok := source.(Words); ok {
+		return words
+	}
+
+	entries := make([]string, 0)
+	for entry := range source.Entries(ctx) {
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
@@ -0,0 +1,151 @@
+package dictionary
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const spoolBufferSize = 64 * 1024
+
+func newSpool(reader io.ReadCloser) (*spool, error) {
+	file, err := ioutil.TempFile("", "dirstalk-dictionary-")
+	if err != nil {
+		_ = reader.Close()
+
+		return nil, errors.Wrap(err, "dictionary: failed to create the temporary file")
+	}
+
+	s := &spool{file: file, changed: make(chan struct{})}
+
+	go s.fill(reader)
+
+	return s, nil
+}
+
+// spool copies the entries of a reader that can be read only once to a temporary file, so that they can be
+// iterated more than once without holding them in memory; the iterations read the entries as soon as they are
+// written, waiting for the following ones until the reader is read completely
+type spool struct {
+	file *os.File
+
+	mx sync.Mutex
+	// size is the amount of bytes of the entries available in the file
+	size int64
+	// done is true once the reader has been read completely, err is the error that interrupted it (if any)
+	done bool
+	err  error
+	// changed is closed (and replaced) every time size or done change
+	changed chan struct{}
+}
+
+func (s *spool) fill(reader io.ReadCloser) {
+	defer reader.Close() //nolint:errcheck
+
+	in := bufio.NewReaderSize(reader, spoolBufferSize)
+	out := bufio.NewWriterSize(s.file, spoolBufferSize)
+
+	var written, available int64
+
+	for {
+		line, readErr := in.ReadString('\n')
+
+		if line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"); isEntry(line) {
+			n, err := out.WriteString(line + "\n")
+			if err != nil {
+				s.update(available, true, errors.Wrap(err, "dictionary: failed to write the temporary file"))
+				return
+			}
+
+			written += int64(n)
+		}
+
+		// the entries are made available as soon as reading the following ones requires waiting for them
+		if readErr == nil && in.Buffered() > 0 {
+			continue
+		}
+
+		if err := out.Flush(); err != nil {
+			s.update(available, true, errors.Wrap(err, "dictionary: failed to write the temporary file"))
+			return
+		}
+
+		available = written
+
+		switch {
+		case readErr == io.EOF:
+			s.update(available, true, nil)
+			return
+		case readErr != nil:
+			s.update(available, true, errors.Wrap(readErr, "dictionary: failed to read"))
+			return
+		default:
+			s.update(available, false, nil)
+		}
+	}
+}
+
+func (s *spool) update(size int64, done bool, err error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.size, s.done, s.err = size, done, err
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// read sends the entries of the file to the channel, until the reader has been read completely or ctx is done
+func (s *spool) read(ctx context.Context, entries chan<- string) error {
+	var offset int64
+
+	for {
+		size, err := s.wait(ctx, offset)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if size == offset {
+			return err
+		}
+
+		if err := sendEntries(ctx, io.NewSectionReader(s.file, offset, size-offset), entries); err != nil {
+			return err
+		}
+
+		offset = size
+	}
+}
+
+// wait returns the size of the entries available once it exceeds offset, the reader has been read completely or
+// ctx is done; the error is the one that interrupted the reader, if any
+func (s *spool) wait(ctx context.Context, offset int64) (int64, error) {
+	for {
+		s.mx.Lock()
+		size, done, err, changed := s.size, s.done, s.err, s.changed
+		s.mx.Unlock()
+
+		if size > offset || done {
+			return size, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return size, nil
+		case <-changed:
+		}
+	}
+}
+
+func (s *spool) close() error {
+	// the reader stops as soon as it fails to write the entries
+	_ = s.file.Close()
+
+	return os.Remove(s.file.Name())
+}
@@ -0,0 +1,144 @@
+package dictionary
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// NewStream returns the dictionary of the path (like NewDictionaryFrom) without loading it in memory: a local
+// file is read again at every iteration, while the standard input and the remote files are copied to a temporary
+// file as they are read, so that the iterations can start before they are read completely.
+// The stream must be closed once it is not iterated anymore, to remove the temporary file.
+func NewStream(path string, doer Doer) (*Stream, error) {
+	reader, err := open(path, doer)
+	if err != nil {
+		return nil, err
+	}
+
+	// unlike the standard input, the remote files and the named pipes, a local file can be read more than once
+	if isRegularFile(reader) {
+		_ = reader.Close()
+
+		return &Stream{path: path}, nil
+	}
+
+	return newSpooledStream(reader)
+}
+
+// NewStreamFromReader returns the dictionary read from the reader until EOF, copying it to a temporary file
+// as it is read (see NewStream)
+func NewStreamFromReader(reader io.Reader) (*Stream, error) {
+	return newSpooledStream(ioutil.NopCloser(reader))
+}
+
+func newSpooledStream(reader io.ReadCloser) (*Stream, error) {
+	s, err := newSpool(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stream{spool: s}, nil
+}
+
+// Stream is a dictionary read while it is iterated, only the entries not yet consumed by the iterations are held
+// in memory; it is safe for concurrent use
+type Stream struct {
+	// path is the local file read at every iteration, empty when the dictionary is read from the spool
+	path  string
+	spool *spool
+
+	mx  sync.Mutex
+	err error
+}
+
+func (s *Stream) Entries(ctx context.Context) <-chan string {
+	entries := make(chan string, 10)
+
+	go func() {
+		defer close(entries)
+
+		var err error
+		if s.spool != nil {
+			err = s.spool.read(ctx, entries)
+		} else {
+			err = readFile(ctx, s.path, entries)
+		}
+
+		if err != nil {
+			s.mx.Lock()
+			defer s.mx.Unlock()
+
+			if s.err == nil {
+				s.err = err
+			}
+		}
+	}()
+
+	return entries
+}
+
+// Err returns the first error occurred reading the dictionary, the iteration during which it occurred ended
+// before the last entry
+func (s *Stream) Err() error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.err
+}
+
+// Close removes the temporary file of the dictionary, if any
+func (s *Stream) Close() error {
+	if s.spool == nil {
+		return nil
+	}
+
+	return s.spool.close()
+}
+
+func readFile(ctx context.Context, path string, entries chan<- string) error {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return errors.Wrapf(err, "dictionary: unable to open: %s", path)
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	return sendEntries(ctx, file, entries)
+}
+
+// sendEntries sends the entries read from the reader until EOF or until ctx is done
+func sendEntries(ctx context.Context, reader io.Reader, entries chan<- string) error {
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !isEntry(line) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case entries <- line:
+		}
+	}
+
+	return errors.Wrap(scanner.Err(), "dictionary: failed to read")
+}
+
+func isRegularFile(reader io.Reader) bool {
+	file, ok := reader.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+
+	return err == nil && info.Mode().IsRegular()
+}
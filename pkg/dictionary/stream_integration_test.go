@@ -0,0 +1,171 @@
+package dictionary_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamFromFileShouldReadTheEntriesAtEveryIteration(t *testing.T) {
+	sut, err := dictionary.NewStream("testdata/dict.txt", &http.Client{})
+	assert.NoError(t, err)
+
+	defer sut.Close() //nolint:errcheck
+
+	expectedValue := []string{
+		"home",
+		"home/index.php",
+		"blabla",
+	}
+	assert.Equal(t, expectedValue, dictionary.Load(context.Background(), sut))
+	assert.Equal(t, expectedValue, dictionary.Load(context.Background(), sut))
+	assert.NoError(t, sut.Err())
+}
+
+func TestStreamShouldFailForANonExistingFile(t *testing.T) {
+	sut, err := dictionary.NewStream("testdata/gibberish_nonexisting_file", &http.Client{})
+	assert.Error(t, err)
+	assert.Nil(t, sut)
+}
+
+func TestStreamFromRemoteFileShouldRetrieveItOnce(t *testing.T) {
+	srv, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("home\n# a comment\n\nhome/index.php"))
+		}),
+	)
+	defer srv.Close()
+
+	sut, err := dictionary.NewStream(srv.URL, &http.Client{})
+	assert.NoError(t, err)
+
+	defer sut.Close() //nolint:errcheck
+
+	assert.Equal(t, []string{"home", "home/index.php"}, dictionary.Load(context.Background(), sut))
+	assert.Equal(t, []string{"home", "home/index.php"}, dictionary.Load(context.Background(), sut))
+	assert.NoError(t, sut.Err())
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestStreamFromReaderShouldSendTheEntriesBeforeTheReaderIsClosed(t *testing.T) {
+	reader, writer := io.Pipe()
+
+	sut, err := dictionary.NewStreamFromReader(reader)
+	assert.NoError(t, err)
+
+	defer sut.Close() //nolint:errcheck
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries := sut.Entries(ctx)
+
+	_, err = writer.Write([]byte("home\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "home", <-entries)
+
+	_, err = writer.Write([]byte("# a comment\n\nhome/index.php"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	remaining := make([]string, 0)
+	for entry := range entries {
+		remaining = append(remaining, entry)
+	}
+
+	assert.Equal(t, []string{"home/index.php"}, remaining)
+	assert.Equal(t, []string{"home", "home/index.php"}, dictionary.Load(ctx, sut))
+	assert.NoError(t, sut.Err())
+	assert.NoError(t, ctx.Err())
+}
+
+func TestStreamFromReaderShouldReturnTheErrorOfTheReader(t *testing.T) {
+	reader, writer := io.Pipe()
+
+	sut, err := dictionary.NewStreamFromReader(reader)
+	assert.NoError(t, err)
+
+	defer sut.Close() //nolint:errcheck
+
+	_, err = writer.Write([]byte("home\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.CloseWithError(fmt.Errorf("my error")))
+
+	assert.Equal(t, []string{"home"}, dictionary.Load(context.Background(), sut))
+	assert.Error(t, sut.Err())
+	assert.Contains(t, sut.Err().Error(), "my error")
+}
+
+func TestStreamShouldUseLessMemoryThanLoadingTheDictionary(t *testing.T) {
+	const entriesCount = 500000
+
+	var content strings.Builder
+	for i := 0; i < entriesCount; i++ {
+		_, _ = fmt.Fprintf(&content, "admin/backup-%d.tar.gz\n", i)
+	}
+
+	path := test.MustWriteTempFile(t, []byte(content.String()))
+	defer os.Remove(path) //nolint:errcheck
+
+	content.Reset()
+
+	before := liveHeap()
+
+	entries, err := dictionary.NewDictionaryFrom(path, &http.Client{})
+	assert.NoError(t, err)
+
+	loaded := liveHeap() - before
+
+	assert.Len(t, entries, entriesCount)
+	entries = nil //nolint:ineffassign
+
+	before = liveHeap()
+
+	sut, err := dictionary.NewStream(path, &http.Client{})
+	assert.NoError(t, err)
+
+	defer sut.Close() //nolint:errcheck
+
+	var streamed int64
+
+	count := 0
+
+	for range sut.Entries(context.Background()) {
+		// the heap is sampled without collecting its garbage, it is an upper bound of the memory used
+		if count++; count%10000 == 0 {
+			if used := heap() - before; used > streamed {
+				streamed = used
+			}
+		}
+	}
+
+	assert.Equal(t, entriesCount, count)
+	assert.NoError(t, sut.Err())
+
+	t.Logf("loading the dictionary: %d bytes, streaming it: %d bytes at most", loaded, streamed)
+	assert.True(t, streamed < loaded/4, "streaming: %d bytes, loading: %d bytes", streamed, loaded)
+}
+
+// liveHeap returns the bytes of the heap used after collecting its garbage
+func liveHeap() int64 {
+	runtime.GC()
+
+	return heap()
+}
+
+func heap() int64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return int64(stats.HeapAlloc)
+}
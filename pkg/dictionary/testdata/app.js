@@ -0,0 +1,26 @@
+// a comment that should not be extracted: //not/a/path
+var API_BASE = "/api/v1";
+
+function loadUsers() {
+    return fetch("/api/v1/users?page=1").then(function (res) { return res.json(); });
+}
+
+function loadUser(id) {
+    return fetch(`/api/v1/users/${id}/profile`);
+}
+
+function saveSettings(settings) {
+    return axios.post('/api/v1/settings#top', settings);
+}
+
+var config = {
+    template: "./templates/main.html",
+    legacy: "legacy/export.php?format=csv",
+    contentType: "application/json",
+    cdn: "//cdn.example.com/lib.js",
+    message: "this is not a path",
+    root: "/",
+    home: "/"
+};
+
+var routes = ['/login', '/logout', "/api/v1/users?page=2"];
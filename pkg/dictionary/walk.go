@@ -0,0 +1,131 @@
+package dictionary
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+type walkFunc func(root string, walkFn filepath.WalkFunc) error
+
+// treeWalker walks a tree like filepath.Walk (in lexical order and with the same semantic for walkFn), but it can
+// follow the symlinks: a symlink pointing to a folder containing it is skipped, as following it would make the walk
+// loop, the symlinks which cannot be resolved are skipped too
+type treeWalker struct {
+	followSymlinks bool
+	logger         logrus.FieldLogger
+}
+
+func (w treeWalker) walk(root string, walkFn filepath.WalkFunc) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		err = walkFn(root, nil, err)
+	} else {
+		err = w.visit(root, info, nil, walkFn)
+	}
+
+	if err == filepath.SkipDir {
+		return nil
+	}
+
+	return err
+}
+
+// visit visits path and, if it is a folder, its content; ancestors are the folders containing path
+func (w treeWalker) visit(path string, info os.FileInfo, ancestors []os.FileInfo, walkFn filepath.WalkFunc) error {
+	if info.Mode()&os.ModeSymlink != 0 {
+		resolved, ok := w.resolveSymlink(path, ancestors)
+		if !ok {
+			return walkFn(path, info, nil)
+		}
+
+		if resolved == nil {
+			return nil
+		}
+
+		info = resolved
+	}
+
+	if err := walkFn(path, info, nil); err != nil || !info.IsDir() {
+		return err
+	}
+
+	names, err := readDirNames(path)
+	if err != nil {
+		return walkFn(path, info, err)
+	}
+
+	ancestors = append(ancestors[:len(ancestors):len(ancestors)], info)
+
+	for _, name := range names {
+		filename := filepath.Join(path, name)
+
+		fileInfo, err := os.Lstat(filename)
+		if err != nil {
+			err = walkFn(filename, fileInfo, err)
+		} else {
+			err = w.visit(filename, fileInfo, ancestors, walkFn)
+		}
+
+		if err != nil && err != filepath.SkipDir {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveSymlink returns false when the symlink is not followed, otherwise the info of the file it points to or nil
+// when it has to be skipped
+func (w treeWalker) resolveSymlink(path string, ancestors []os.FileInfo) (os.FileInfo, bool) {
+	if !w.followSymlinks {
+		w.logger.WithField("path", path).Debug("Not following symlink")
+
+		return nil, false
+	}
+
+	resolved, err := os.Stat(path)
+	if err != nil {
+		w.logger.WithFields(logrus.Fields{"path": path, "err": err}).Debug("Skipping symlink, it cannot be resolved")
+
+		return nil, true
+	}
+
+	for _, ancestor := range ancestors {
+		if os.SameFile(ancestor, resolved) {
+			w.logger.WithField("path", path).Debug("Skipping symlink, following it would make the walk loop")
+
+			return nil, true
+		}
+	}
+
+	return resolved, true
+}
+
+func readDirNames(dir string) ([]string, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := f.Readdirnames(-1)
+	_ = f.Close() //nolint:errcheck
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func newDiscardLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	return logger
+}
@@ -0,0 +1,393 @@
+package dirstalk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stefanoj3/dirstalk/pkg/scan/scope"
+	"github.com/stefanoj3/dirstalk/pkg/scan/vhost"
+	"github.com/stefanoj3/dirstalk/pkg/scan/wildcard"
+)
+
+// NewScanner creates a scanner performing the scans described by cnf, opts are applied to the scanner of each
+// target (EG scan.WithFilter to add custom filters)
+func NewScanner(cnf *scan.Config, logger *logrus.Logger, opts ...scan.Option) *Scanner {
+	s := &Scanner{cnf: cnf, logger: logger, opts: opts}
+
+	// the requests in flight to each host are capped across all the targets
+	if cnf.PerHostThreads > 0 {
+		s.hostSlots = client.NewHostSlots(cnf.PerHostThreads)
+	}
+
+	return s
+}
+
+// Scanner performs the same scans as the scan command, as described by its config, allowing to embed them in other
+// tools; the config must not be modified while scanning.
+//
+// The filters built from the config are invoked first, in the order: the statuses and the lengths to ignore,
+// the wildcard responses (or the baseline of the virtual hosts), the body regex, the headers, the content types
+// and the per-directory baseline; the filters of the options follow, in the order they are given. Once a filter
+// ignores a result the following ones are not invoked for it (see scan.WithResultFilter).
+type Scanner struct {
+	cnf    *scan.Config
+	logger *logrus.Logger
+	opts   []scan.Option
+
+	// hostSlots is nil when the requests to each host are not capped
+	hostSlots *client.HostSlots
+}
+
+// Scan scans target using the paths (or the hosts, for the scans of the virtual hosts) of the entries and returns
+// the results found, ignoring the ones excluded by the statuses of the config; the channel is closed when the
+// scan is complete or ctx is cancelled
+func (s *Scanner) Scan(ctx context.Context, target *url.URL, entries []string) (<-chan scan.Result, error) {
+	ts, err := s.NewTargetScanner(ctx, target, dictionary.Words(entries), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reportFilter := NewResultReportFilter(s.cnf)
+
+	results := make(chan scan.Result, s.cnf.Threads)
+
+	go func() {
+		defer close(results)
+
+		for r := range ts.Scan(ctx, target, s.cnf.Threads) {
+			if !reportFilter.ShouldIgnore(r) {
+				results <- r
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+// NewTargetScanner builds the scanner of target, it allows to observe the scan (EG its stats) while Scan returns
+// only its results; when isCompleted is not nil the targets of the dictionary for which it returns true are skipped
+// (EG the ones completed by a previous scan). The wildcard (or virtual hosts baseline) detection is performed by it.
+// The source is iterated once, plus once for each folder scanned recursively. opts are applied after the ones
+// of NewScanner, EG to observe the scan of this target only (see scan.WithTargetCompletedHandler).
+func (s *Scanner) NewTargetScanner(
+	ctx context.Context,
+	target *url.URL,
+	source dictionary.Source,
+	isCompleted func(scan.Target) bool,
+	opts ...scan.Option,
+) (*scan.Scanner, error) {
+	cnf := s.cnf
+
+	targetProducer := NewTargetProducer(cnf, source)
+
+	var reproducer scan.ReProducer = producer.NewReProducer(targetProducer)
+
+	if cnf.ShouldProbeBackups && len(cnf.BackupSuffixes) > 0 {
+		reproducer = producer.NewBackupReProducer(reproducer, cnf.BackupSuffixes)
+	}
+
+	// the backups of the excluded paths are not requested either
+	if len(cnf.ExcludePaths) > 0 {
+		reproducer = producer.NewSkipReProducer(reproducer, func(r scan.Result) bool {
+			return cnf.ExcludePaths.Match(r.Target.Path)
+		})
+	}
+
+	if isCompleted != nil {
+		// only the targets coming from the dictionary are skipped, the folders found are still scanned
+		// recursively using the whole dictionary
+		targetProducer = producer.NewSkipProducer(targetProducer, isCompleted)
+	}
+
+	sc := scope.NewScope(cnf.Scope, target, cnf.ScopeDomains)
+
+	scannerClient, err := newScannerClient(cnf, target, sc, s.hostSlots, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	resultFilter, err := s.newResultFilter(ctx, target, scannerClient)
+	if err != nil {
+		return nil, err
+	}
+
+	scannerOpts := []scan.Option{
+		scan.WithScope(sc.Contains),
+		scan.WithRecursionStrategy(cnf.RecursionStrategy),
+		scan.WithBodyReadLimit(cnf.MaxBodySize),
+	}
+
+	if cnf.RequestTemplate != nil {
+		scannerOpts = append(scannerOpts, scan.WithRequestTemplate(cnf.RequestTemplate))
+	}
+
+	if cnf.MatchRegex != nil || cnf.SaveResponsesDir != "" {
+		scannerOpts = append(scannerOpts, scan.WithBodyKept(cnf.MaxBodySize))
+	}
+
+	scannerOpts = append(scannerOpts, s.opts...)
+	scannerOpts = append(scannerOpts, opts...)
+
+	return scan.NewScanner(
+		scannerClient,
+		targetProducer,
+		reproducer,
+		resultFilter,
+		s.logger,
+		scannerOpts...,
+	), nil
+}
+
+// newResultFilter builds the filter of the results of the scanner of target, detecting the wildcard responses
+// (or the baseline of the virtual hosts) first
+func (s *Scanner) newResultFilter(
+	ctx context.Context,
+	target *url.URL,
+	scannerClient *http.Client,
+) (scan.ResultFilter, error) {
+	cnf := s.cnf
+
+	var resultFilter scan.ResultFilter = filter.NewAggregateResultFilter(
+		filter.NewHTTPStatusResultFilter(cnf.HTTPStatusesToIgnore),
+		filter.NewContentLengthResultFilter(cnf.ContentLengthsToIgnore, cnf.ContentLengthRangesToIgnore),
+	)
+
+	switch {
+	case cnf.VHostScan:
+		baselineResults, err := vhost.NewBaselineDetector(scannerClient, cnf.HTTPMethods, s.logger).
+			Detect(ctx, target, cnf.Threads)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to establish the baseline")
+		}
+
+		for _, r := range baselineResults {
+			s.logger.WithFields(logrus.Fields{
+				"method":         r.Target.Method,
+				"host":           r.Target.Host,
+				"status":         r.StatusCode,
+				"content-length": r.ContentLength,
+			}).Info("Baseline response detected, the hosts replying with the same status and length will be ignored")
+		}
+
+		resultFilter = filter.NewAggregateResultFilter(resultFilter, filter.NewVHostResultFilter(baselineResults))
+	case cnf.RequestTemplate != nil:
+		// the wildcard responses are detected requesting random paths, the requests of a template may not
+		// differ by their path at all
+	case !cnf.ShouldSkipWildcardDetection:
+		wildcardResults, err := wildcard.NewDetector(scannerClient, cnf.HTTPMethods, resultFilter, s.logger).
+			Detect(ctx, target, cnf.Threads)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to detect wildcard responses")
+		}
+
+		for _, r := range wildcardResults {
+			s.logger.WithFields(logrus.Fields{
+				"method":         r.Target.Method,
+				"status":         r.StatusCode,
+				"content-length": r.ContentLength,
+			}).Warn("Wildcard response detected, the results matching it will be ignored")
+		}
+
+		resultFilter = filter.NewAggregateResultFilter(resultFilter, filter.NewWildcardResultFilter(wildcardResults))
+	}
+
+	// the responses of the wildcard detection don't include the body, so the regex is matched only by the scanner
+	if cnf.MatchRegex != nil {
+		resultFilter = filter.NewAggregateResultFilter(resultFilter, filter.NewBodyRegexResultFilter(cnf.MatchRegex))
+	}
+
+	if len(cnf.HeaderMatchers) > 0 {
+		resultFilter = filter.NewAggregateResultFilter(resultFilter, filter.NewHeaderResultFilter(cnf.HeaderMatchers))
+	}
+
+	if len(cnf.ContentTypesToMatch) > 0 || len(cnf.ContentTypesToExclude) > 0 {
+		resultFilter = filter.NewAggregateResultFilter(
+			resultFilter,
+			filter.NewContentTypeResultFilter(cnf.ContentTypesToMatch, cnf.ContentTypesToExclude),
+		)
+	}
+
+	// last, so that the baseline is established only for the directories having results not ignored otherwise
+	if cnf.BaselineRequest {
+		resultFilter = filter.NewAggregateResultFilter(
+			resultFilter,
+			wildcard.NewBaselineResultFilter(ctx, scannerClient, cnf.DiffThreshold, s.logger),
+		)
+	}
+
+	return resultFilter, nil
+}
+
+// NewResultReportFilter builds the filter deciding which of the results produced by the scanner are reported.
+// Unlike the filter used by the scanner it does not prevent the results from being processed further (EG when
+// following redirects or going deeper in the scan)
+func NewResultReportFilter(cnf *scan.Config) scan.ResultFilter {
+	var reportFilter scan.ResultFilter = filter.NewHTTPStatusResultFilter(cnf.HTTPStatusesToExclude)
+	if len(cnf.HTTPStatusesToInclude) > 0 {
+		reportFilter = filter.NewHTTPStatusToIncludeResultFilter(cnf.HTTPStatusesToInclude)
+	}
+
+	// the slow responses are the ones worth reporting, the fast ones (EG of the folders) are still scanned
+	if cnf.FilterTimeAbove > 0 {
+		reportFilter = filter.NewAggregateResultFilter(
+			reportFilter,
+			filter.NewResponseTimeResultFilter(cnf.FilterTimeAbove),
+		)
+	}
+
+	// last, so that only the results reported are remembered
+	if cnf.DedupResults {
+		reportFilter = filter.NewAggregateResultFilter(reportFilter, filter.NewDuplicateResultFilter())
+	}
+
+	return reportFilter
+}
+
+// NewTargetProducer builds the producer of the targets generated from the entries of the source
+func NewTargetProducer(cnf *scan.Config, source dictionary.Source) scan.Producer {
+	if cnf.VHostScan {
+		return producer.NewVHostSourceProducer(cnf.HTTPMethods, source)
+	}
+
+	if cnf.RequestTemplate != nil {
+		return producer.NewTemplateSourceProducer(
+			cnf.RequestTemplate.Method(),
+			source,
+			cnf.SecondDictionary,
+			cnf.TemplateMode,
+		)
+	}
+
+	var targetProducer scan.Producer = producer.NewExtensionProducer(
+		producer.NewDictionarySourceProducer(cnf.HTTPMethods, source, cnf.ScanDepth),
+		cnf.Extensions,
+	)
+
+	if cnf.TrailingSlash != "" && cnf.TrailingSlash != producer.TrailingSlashKeep {
+		targetProducer = producer.NewTrailingSlashProducer(targetProducer, cnf.TrailingSlash)
+	}
+
+	if len(cnf.AppendQueries) > 0 {
+		targetProducer = producer.NewQueryProducer(targetProducer, cnf.AppendQueries)
+	}
+
+	if len(cnf.ExcludePaths) > 0 {
+		targetProducer = producer.NewSkipProducer(targetProducer, func(t scan.Target) bool {
+			return cnf.ExcludePaths.Match(t.Path)
+		})
+	}
+
+	return targetProducer
+}
+
+func newScannerClient(
+	cnf *scan.Config,
+	u *url.URL,
+	sc *scope.Scope,
+	hostSlots *client.HostSlots,
+	logger *logrus.Logger,
+) (*http.Client, error) {
+	clientConfig := ClientConfig(cnf, cnf.TimeoutInMilliseconds)
+	clientConfig.Body = cnf.Body
+	clientConfig.RequestsPerSecond = cnf.RequestsPerSecond
+	clientConfig.PerHostThreads = cnf.PerHostThreads
+	clientConfig.HostSlots = hostSlots
+	clientConfig.MaxConnsPerHost = cnf.MaxConnsPerHost
+
+	// each thread keeps reusing its connection, instead of dialing a new one for most of the requests
+	clientConfig.MaxIdleConns = cnf.MaxIdleConns
+	if clientConfig.MaxIdleConns == 0 {
+		clientConfig.MaxIdleConns = cnf.Threads
+	}
+
+	clientConfig.DelayInMilliseconds = cnf.DelayInMilliseconds
+	clientConfig.JitterPercentage = cnf.JitterPercentage
+	clientConfig.Retries = cnf.Retries
+	clientConfig.RetryWaitInMilliseconds = cnf.RetryWaitInMilliseconds
+	clientConfig.AdaptiveThrottle = !cnf.ShouldSkipAdaptiveThrottle
+	clientConfig.FollowRedirects = cnf.FollowRedirects
+	clientConfig.MaxRedirects = cnf.MaxRedirects
+	clientConfig.Host = cnf.HostHeader
+	clientConfig.IsInScope = sc.Contains
+
+	if cnf.HeadFirst {
+		statusFilter := filter.NewHTTPStatusResultFilter(cnf.HTTPStatusesToIgnore)
+
+		clientConfig.HeadFirst = true
+		clientConfig.IsStatusIgnored = func(statusCode int) bool {
+			return statusFilter.ShouldIgnore(scan.Result{StatusCode: statusCode})
+		}
+	}
+	clientConfig.Logger = logger
+
+	// the requests of a template may differ only by their headers or body, they would be deemed redundant
+	if cnf.RequestTemplate != nil {
+		clientConfig.CacheRequests = false
+	}
+
+	c, err := client.NewClientFromConfig(clientConfig, u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build scanner client")
+	}
+
+	return c, nil
+}
+
+// NewReplayClient returns the client replaying the requests of the results through the replay proxy, it shares the
+// configuration of the scanner client but not its state (cookie jar, cache, rate limit) and it never follows the
+// redirects, so that the proxy records the responses the results are made of
+func NewReplayClient(cnf *scan.Config) (*http.Client, error) {
+	clientConfig := ClientConfig(cnf, cnf.TimeoutInMilliseconds)
+	clientConfig.Socks5Url = nil
+	clientConfig.HTTPProxyUrl = cnf.ReplayProxyUrl
+	clientConfig.UseCookieJar = false
+	clientConfig.CacheRequests = false
+	clientConfig.Body = cnf.Body
+	clientConfig.Host = cnf.HostHeader
+
+	c, err := client.NewClientFromConfig(clientConfig, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build replay client")
+	}
+
+	return c, nil
+}
+
+// ClientConfig returns the config of the clients shared by the scanner and the retrieval of the remote dictionaries
+func ClientConfig(cnf *scan.Config, timeoutInMilliseconds int) client.Config {
+	return client.Config{
+		TimeoutInMilliseconds:               timeoutInMilliseconds,
+		Socks5Url:                           cnf.Socks5Url,
+		HTTPProxyUrl:                        cnf.HTTPProxyUrl,
+		UserAgent:                           cnf.UserAgent,
+		UserAgents:                          cnf.UserAgents,
+		UseCookieJar:                        cnf.UseCookieJar,
+		Cookies:                             cnf.Cookies,
+		FileCookies:                         cnf.FileCookies,
+		Headers:                             cnf.Headers,
+		BasicAuthUsername:                   cnf.BasicAuthUsername,
+		BasicAuthPassword:                   cnf.BasicAuthPassword,
+		NTLMDomain:                          cnf.NTLMDomain,
+		NTLMUsername:                        cnf.NTLMUsername,
+		NTLMPassword:                        cnf.NTLMPassword,
+		BearerToken:                         cnf.BearerToken,
+		CacheRequests:                       cnf.CacheRequests,
+		ShouldSkipSSLCertificatesValidation: cnf.ShouldSkipSSLCertificatesValidation,
+		ShouldSkipTLSHostnameVerification:   cnf.ShouldSkipTLSHostnameVerification,
+		ClientCertificatePath:               cnf.ClientCertificatePath,
+		ClientKeyPath:                       cnf.ClientKeyPath,
+		CACertificatePath:                   cnf.CACertificatePath,
+		ForceHTTP2:                          cnf.ForceHTTP2,
+		DisableHTTP2:                        cnf.DisableHTTP2,
+		Resolver:                            cnf.Resolver,
+		PreferIPv6:                          cnf.PreferIPv6,
+	}
+}
@@ -0,0 +1,160 @@
+package dirstalk_test
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stefanoj3/dirstalk/pkg/dirstalk"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScannerShouldReturnTheResultsFound(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/home", "/home/index.php":
+				w.WriteHeader(http.StatusOK)
+			case "/admin":
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	sut := dirstalk.NewScanner(
+		&scan.Config{
+			Threads:                     3,
+			TimeoutInMilliseconds:       1000,
+			HTTPMethods:                 []string{http.MethodGet},
+			HTTPStatusesToIgnore:        []int{http.StatusNotFound},
+			HTTPStatusesToExclude:       []int{http.StatusForbidden},
+			ScanDepth:                   1,
+			ShouldSkipWildcardDetection: true,
+		},
+		logger,
+	)
+
+	results, err := sut.Scan(
+		context.Background(),
+		test.MustParseURL(t, testServer.URL),
+		[]string{"home", "admin", "index.php"},
+	)
+	assert.NoError(t, err)
+
+	paths := make([]string, 0, 2)
+	for r := range results {
+		paths = append(paths, r.URL.Path)
+	}
+
+	sort.Strings(paths)
+
+	// the excluded results are not returned but they are still scanned recursively
+	assert.Equal(t, []string{"/home", "/home/index.php"}, paths)
+	assert.Equal(t, 9, serverAssertion.Len())
+}
+
+func TestScannerShouldApplyTheFiltersOfTheOptions(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	sut := dirstalk.NewScanner(
+		&scan.Config{
+			Threads:                     1,
+			TimeoutInMilliseconds:       1000,
+			HTTPMethods:                 []string{http.MethodGet},
+			ScanDepth:                   1,
+			ShouldSkipWildcardDetection: true,
+		},
+		logger,
+		scan.WithFilter(func(r scan.Result) bool {
+			return r.URL.Path == "/home"
+		}),
+	)
+
+	results, err := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), []string{"home", "about"})
+	assert.NoError(t, err)
+
+	paths := make([]string, 0, 3)
+	for r := range results {
+		paths = append(paths, r.URL.Path)
+	}
+
+	sort.Strings(paths)
+
+	// the results ignored by the filter are not scanned recursively
+	assert.Equal(t, []string{"/about", "/about/about", "/about/home"}, paths)
+	assert.Equal(t, 4, serverAssertion.Len())
+}
+
+func TestScannerShouldSkipTheCompletedTargets(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	sut := dirstalk.NewScanner(
+		&scan.Config{
+			Threads:                     1,
+			TimeoutInMilliseconds:       1000,
+			HTTPMethods:                 []string{http.MethodGet},
+			HTTPStatusesToIgnore:        []int{http.StatusNotFound},
+			ShouldSkipWildcardDetection: true,
+		},
+		logger,
+	)
+
+	s, err := sut.NewTargetScanner(
+		context.Background(),
+		test.MustParseURL(t, testServer.URL),
+		dictionary.Words{"home", "about"},
+		func(target scan.Target) bool { return target.Path == "home" },
+	)
+	assert.NoError(t, err)
+
+	for range s.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		assert.Fail(t, "no result expected")
+	}
+
+	assert.Equal(t, int64(1), s.RequestsCount())
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, "/about", r.URL.Path)
+	})
+}
+
+func TestScannerShouldErrWhenTheClientCannotBeBuilt(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	sut := dirstalk.NewScanner(
+		&scan.Config{
+			Threads:                     1,
+			HTTPMethods:                 []string{http.MethodGet},
+			ShouldSkipWildcardDetection: true,
+			CACertificatePath:           "/root/123/abc",
+		},
+		logger,
+	)
+
+	_, err := sut.Scan(context.Background(), test.MustParseURL(t, "http://localhost/"), []string{"home"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to build scanner client")
+}
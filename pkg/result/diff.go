@@ -0,0 +1,136 @@
+package result
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+)
+
+// LoadJSONResultsFromFile reads the results saved via the JSON output of the scan command (a JSON array)
+func LoadJSONResultsFromFile(resultFilePath string) ([]output.JSONResult, error) {
+	file, err := os.Open(resultFilePath) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", resultFilePath)
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	results := make([]output.JSONResult, 0)
+	if err := json.NewDecoder(file).Decode(&results); err != nil {
+		return nil, errors.Wrapf(err, "failed to read the results of %s", resultFilePath)
+	}
+
+	return results, nil
+}
+
+// Diff contains the differences between the results of two scans
+type Diff struct {
+	// Appeared are the results found only by the second scan
+	Appeared []output.JSONResult `json:"appeared"`
+	// Disappeared are the results found only by the first scan
+	Disappeared []output.JSONResult `json:"disappeared"`
+	// StatusChanged are the results found by both the scans, with a different status code
+	StatusChanged []StatusChange `json:"status_changed"`
+}
+
+// StatusChange is a result whose status code changed between the two scans
+type StatusChange struct {
+	Old output.JSONResult `json:"old"`
+	New output.JSONResult `json:"new"`
+}
+
+// IsEmpty returns true when the results of the two scans don't differ
+func (d Diff) IsEmpty() bool {
+	return len(d.Appeared) == 0 && len(d.Disappeared) == 0 && len(d.StatusChanged) == 0
+}
+
+// NewDiff compares the results of the first scan with the ones of the second: two results are the same when their
+// request is (the url, the method, the host and the words), the changes of their length are not reported.
+// The results of the diff are sorted by url.
+func NewDiff(first, second []output.JSONResult) Diff {
+	firstByRequest := resultsByRequest(first)
+	secondByRequest := resultsByRequest(second)
+
+	diff := Diff{
+		Appeared:      make([]output.JSONResult, 0),
+		Disappeared:   make([]output.JSONResult, 0),
+		StatusChanged: make([]StatusChange, 0),
+	}
+
+	for key, r := range secondByRequest {
+		old, ok := firstByRequest[key]
+
+		switch {
+		case !ok:
+			diff.Appeared = append(diff.Appeared, r)
+		case old.StatusCode != r.StatusCode:
+			diff.StatusChanged = append(diff.StatusChanged, StatusChange{Old: old, New: r})
+		}
+	}
+
+	for key, r := range firstByRequest {
+		if _, ok := secondByRequest[key]; !ok {
+			diff.Disappeared = append(diff.Disappeared, r)
+		}
+	}
+
+	sortResults(diff.Appeared)
+	sortResults(diff.Disappeared)
+
+	sort.Slice(diff.StatusChanged, func(i, j int) bool {
+		return requestOf(diff.StatusChanged[i].New).less(requestOf(diff.StatusChanged[j].New))
+	})
+
+	return diff
+}
+
+// request identifies the request of a result
+type request struct {
+	url        string
+	method     string
+	host       string
+	word       string
+	secondWord string
+}
+
+func requestOf(r output.JSONResult) request {
+	return request{url: r.URL, method: r.Method, host: r.Host, word: r.Word, secondWord: r.SecondWord}
+}
+
+func (r request) less(other request) bool {
+	switch {
+	case r.url != other.url:
+		return r.url < other.url
+	case r.method != other.method:
+		return r.method < other.method
+	case r.host != other.host:
+		return r.host < other.host
+	case r.word != other.word:
+		return r.word < other.word
+	default:
+		return r.secondWord < other.secondWord
+	}
+}
+
+// resultsByRequest indexes the results by their request, when a request is found more than once the first
+// result is kept
+func resultsByRequest(results []output.JSONResult) map[request]output.JSONResult {
+	byRequest := make(map[request]output.JSONResult, len(results))
+
+	for _, r := range results {
+		if _, ok := byRequest[requestOf(r)]; !ok {
+			byRequest[requestOf(r)] = r
+		}
+	}
+
+	return byRequest
+}
+
+func sortResults(results []output.JSONResult) {
+	sort.Slice(results, func(i, j int) bool {
+		return requestOf(results[i]).less(requestOf(results[j]))
+	})
+}
@@ -0,0 +1,66 @@
+package result_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/result"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffShouldReportTheResultsAppearedDisappearedAndChangingStatusCode(t *testing.T) {
+	t.Parallel()
+
+	first := []output.JSONResult{
+		{URL: "http://mysite/b", Method: "GET", StatusCode: 200},
+		{URL: "http://mysite/a", Method: "GET", StatusCode: 200},
+		{URL: "http://mysite/", Method: "GET", StatusCode: 200, Host: "admin.mysite"},
+		{URL: "http://mysite/same", Method: "GET", StatusCode: 200, ContentLength: 10},
+	}
+
+	second := []output.JSONResult{
+		{URL: "http://mysite/same", Method: "GET", StatusCode: 200, ContentLength: 20},
+		{URL: "http://mysite/a", Method: "GET", StatusCode: 403},
+		{URL: "http://mysite/a", Method: "POST", StatusCode: 200},
+		{URL: "http://mysite/", Method: "GET", StatusCode: 200, Host: "dev.mysite"},
+	}
+
+	diff := result.NewDiff(first, second)
+	assert.False(t, diff.IsEmpty())
+
+	assert.Equal(
+		t,
+		[]output.JSONResult{
+			{URL: "http://mysite/", Method: "GET", StatusCode: 200, Host: "dev.mysite"},
+			{URL: "http://mysite/a", Method: "POST", StatusCode: 200},
+		},
+		diff.Appeared,
+	)
+	assert.Equal(
+		t,
+		[]output.JSONResult{
+			{URL: "http://mysite/", Method: "GET", StatusCode: 200, Host: "admin.mysite"},
+			{URL: "http://mysite/b", Method: "GET", StatusCode: 200},
+		},
+		diff.Disappeared,
+	)
+	assert.Equal(
+		t,
+		[]result.StatusChange{
+			{
+				Old: output.JSONResult{URL: "http://mysite/a", Method: "GET", StatusCode: 200},
+				New: output.JSONResult{URL: "http://mysite/a", Method: "GET", StatusCode: 403},
+			},
+		},
+		diff.StatusChanged,
+	)
+}
+
+func TestDiffOfTheSameResultsShouldBeEmpty(t *testing.T) {
+	t.Parallel()
+
+	results := []output.JSONResult{{URL: "http://mysite/a", Method: "GET", StatusCode: 200}}
+
+	assert.True(t, result.NewDiff(results, results).IsEmpty())
+	assert.True(t, result.NewDiff(nil, nil).IsEmpty())
+}
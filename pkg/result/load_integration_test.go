@@ -6,6 +6,7 @@ import (
 
 	"github.com/stefanoj3/dirstalk/pkg/result"
 	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,3 +73,33 @@ func TestLoadResultsFromFileShouldErrForInvalidFileFormat(t *testing.T) {
 
 	assert.Contains(t, err.Error(), "unable to read line")
 }
+
+func TestLoadJSONResultsFromFile(t *testing.T) {
+	results, err := result.LoadJSONResultsFromFile("testdata/out.json")
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]output.JSONResult{
+			{
+				URL:            "http://someaddress.url/home",
+				Method:         "GET",
+				StatusCode:     301,
+				Location:       "/home/",
+				ResponseTimeMs: 12,
+			},
+			{URL: "http://someaddress.url/", Method: "GET", StatusCode: 200, ContentLength: 42, Host: "admin.local"},
+		},
+		results,
+	)
+}
+
+func TestLoadJSONResultsFromFileShouldErrForInvalidFiles(t *testing.T) {
+	_, err := result.LoadJSONResultsFromFile("testdata/invalidout.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read the results of testdata/invalidout.txt")
+
+	_, err = result.LoadJSONResultsFromFile("testdata/gibberish_nonexisting_file")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open testdata/gibberish_nonexisting_file")
+}
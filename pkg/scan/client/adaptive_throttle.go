@@ -0,0 +1,249 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// throttleDefaultPause is how long the requests are paused after a 429 without a valid Retry-After header
+	throttleDefaultPause = time.Second
+	// throttleMaxPause caps the pause requested by the server via the Retry-After header
+	throttleMaxPause = time.Minute
+	// throttleRampUpInterval is how long a rate has to be kept without 429 responses before it is increased
+	throttleRampUpInterval = 10 * time.Second
+	// throttleRampUpFactor is how much the rate is increased at each step
+	throttleRampUpFactor = 1.5
+	// throttleMinRate is the lowest rate the requests are reduced to, in requests per second
+	throttleMinRate = 1
+	// throttleMaxRetries is how many times a request getting a 429 is retried, the last 429 is returned
+	throttleMaxRetries = 3
+)
+
+func decorateTransportWithAdaptiveThrottleDecorator(
+	decorated http.RoundTripper,
+	requestsPerSecond int,
+	logger *logrus.Logger,
+) (*adaptiveThrottleTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if requestsPerSecond < 0 {
+		return nil, errors.New("requests per second cannot be negative")
+	}
+
+	if logger == nil {
+		return nil, errors.New("logger is nil")
+	}
+
+	maxRate := rate.Inf
+	if requestsPerSecond > 0 {
+		maxRate = rate.Limit(requestsPerSecond)
+	}
+
+	return &adaptiveThrottleTransportDecorator{
+		decorated:      decorated,
+		logger:         logger,
+		limiter:        rate.NewLimiter(maxRate, 1),
+		maxRate:        maxRate,
+		defaultPause:   throttleDefaultPause,
+		maxPause:       throttleMaxPause,
+		rampUpInterval: throttleRampUpInterval,
+		minRate:        throttleMinRate,
+		since:          time.Now(),
+	}, nil
+}
+
+// adaptiveThrottleTransportDecorator slows down all the requests going through it when the server replies 429:
+// the requests are paused (for the time specified via Retry-After, if any), the rate is halved and the request
+// is retried; the rate is then increased again, step by step, as long as no other 429 is received.
+// When not limited (maxRate is rate.Inf) the rate is reduced starting from the one the requests were performed at.
+// Each retry has its own timeout, which doesn't include the pause (see timeoutTransportDecorator).
+type adaptiveThrottleTransportDecorator struct {
+	decorated http.RoundTripper
+	logger    *logrus.Logger
+	limiter   *rate.Limiter
+	maxRate   rate.Limit
+
+	defaultPause   time.Duration
+	maxPause       time.Duration
+	rampUpInterval time.Duration
+	minRate        rate.Limit
+
+	mx          sync.Mutex
+	pausedUntil time.Time
+
+	// lastAdjustment is when the rate has been changed the last time, noReductionUntil prevents the 429 responses
+	// to the requests performed before a reduction from reducing the rate further
+	lastAdjustment   time.Time
+	noReductionUntil time.Time
+
+	// rampUpTo is the rate above which the throttling ends, the maximum one is restored when reaching it
+	rampUpTo rate.Limit
+
+	// requests is the amount of requests performed since the rate has been changed (or since the beginning),
+	// used to estimate the rate of the requests when it is not limited
+	requests int64
+	since    time.Time
+}
+
+func (d *adaptiveThrottleTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := d.wait(r); err != nil {
+			return nil, err
+		}
+
+		res, err := d.decorated.RoundTrip(r)
+		if err != nil || res.StatusCode != http.StatusTooManyRequests {
+			return res, err
+		}
+
+		d.throttle(r, retryAfter(res.Header.Get("Retry-After"), d.defaultPause, d.maxPause))
+
+		if attempt >= throttleMaxRetries {
+			return res, nil
+		}
+
+		_ = res.Body.Close() //nolint:errcheck
+
+		if err := rewindBody(r); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// wait waits for the pause to be over and for the rate to allow the request
+func (d *adaptiveThrottleTransportDecorator) wait(r *http.Request) error {
+	d.mx.Lock()
+	d.rampUp()
+	d.requests++
+	pause := time.Until(d.pausedUntil)
+	d.mx.Unlock()
+
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+
+		select {
+		case <-r.Context().Done():
+			return r.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	return d.limiter.Wait(r.Context())
+}
+
+// throttle pauses the requests and halves the rate
+func (d *adaptiveThrottleTransportDecorator) throttle(r *http.Request, pause time.Duration) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	now := time.Now()
+
+	if until := now.Add(pause); until.After(d.pausedUntil) {
+		d.pausedUntil = until
+	}
+
+	if now.Before(d.noReductionUntil) {
+		return
+	}
+
+	current := d.limiter.Limit()
+	if current == rate.Inf {
+		current = d.observedRate(now)
+		d.rampUpTo = current
+	} else if d.rampUpTo == 0 {
+		d.rampUpTo = d.maxRate
+	}
+
+	reduced := current / 2
+	if reduced < d.minRate {
+		reduced = d.minRate
+	}
+
+	d.adjust(now, reduced)
+	d.noReductionUntil = d.pausedUntil.Add(time.Second)
+
+	d.logger.WithFields(logrus.Fields{
+		"url":   r.URL.String(),
+		"pause": pause.String(),
+		"rate":  formatRate(reduced),
+	}).Info("Too many requests, throttling the scan")
+}
+
+// rampUp increases the rate if it has been kept long enough without receiving a 429, it must be invoked
+// holding the lock
+func (d *adaptiveThrottleTransportDecorator) rampUp() {
+	current := d.limiter.Limit()
+	if current == d.maxRate {
+		return
+	}
+
+	now := time.Now()
+	if now.Before(d.pausedUntil) || now.Sub(d.lastAdjustment) < d.rampUpInterval {
+		return
+	}
+
+	increased := current * throttleRampUpFactor
+	if increased >= d.rampUpTo {
+		increased = d.maxRate
+	}
+
+	d.adjust(now, increased)
+
+	d.logger.WithField("rate", formatRate(increased)).Info("No more too many requests, increasing the rate")
+}
+
+func (d *adaptiveThrottleTransportDecorator) adjust(now time.Time, limit rate.Limit) {
+	d.limiter.SetLimitAt(now, limit)
+	d.lastAdjustment = now
+	d.requests = 0
+	d.since = now
+}
+
+// observedRate returns the rate the requests have been performed at since the last adjustment
+func (d *adaptiveThrottleTransportDecorator) observedRate(now time.Time) rate.Limit {
+	elapsed := now.Sub(d.since)
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+
+	return rate.Limit(float64(d.requests) / elapsed.Seconds())
+}
+
+// retryAfter returns how long to wait according to the value of the Retry-After header (either an amount of seconds
+// or a date), defaultPause when it is missing or invalid and maxPause at most
+func retryAfter(value string, defaultPause, maxPause time.Duration) time.Duration {
+	pause := defaultPause
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		pause = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		pause = time.Until(date)
+		if pause < 0 {
+			pause = 0
+		}
+	}
+
+	if pause > maxPause {
+		return maxPause
+	}
+
+	return pause
+}
+
+func formatRate(limit rate.Limit) string {
+	if limit == rate.Inf {
+		return "unlimited"
+	}
+
+	return strconv.FormatFloat(float64(limit), 'f', 1, 64) + "/s"
+}
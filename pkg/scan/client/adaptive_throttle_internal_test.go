@@ -0,0 +1,213 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestDecorateTransportAdaptiveThrottleShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithAdaptiveThrottleDecorator(nil, 0, logrus.New())
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportAdaptiveThrottleShouldFailWithNegativeRate(t *testing.T) {
+	transport, err := decorateTransportWithAdaptiveThrottleDecorator(http.DefaultTransport, -1, logrus.New())
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportAdaptiveThrottleShouldFailWithNilLogger(t *testing.T) {
+	transport, err := decorateTransportWithAdaptiveThrottleDecorator(http.DefaultTransport, 0, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestAdaptiveThrottleShouldPauseReduceTheRateAndRetryOnTooManyRequests(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	decorated := &statusSequenceRoundTripper{
+		statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+		retryAfter: "0",
+	}
+
+	sut, err := decorateTransportWithAdaptiveThrottleDecorator(decorated, 100, logger)
+	assert.NoError(t, err)
+
+	sut.minRate = 10
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/home", strings.NewReader("my_body"))
+	assert.NoError(t, err)
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	assert.Equal(t, 2, decorated.calls())
+	assert.Equal(t, []string{"my_body", "my_body"}, decorated.bodies)
+
+	assert.Equal(t, rate.Limit(50), sut.limiter.Limit())
+	assert.Contains(t, loggerBuffer.String(), "Too many requests, throttling the scan")
+	assert.Contains(t, loggerBuffer.String(), "rate=50.0/s")
+}
+
+func TestAdaptiveThrottleShouldReturnTheTooManyRequestsResponseAfterRetrying(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	decorated := &statusSequenceRoundTripper{statuses: []int{http.StatusTooManyRequests}, retryAfter: "0"}
+
+	sut, err := decorateTransportWithAdaptiveThrottleDecorator(decorated, 0, logger)
+	assert.NoError(t, err)
+
+	sut.minRate = 1000
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/home", nil)
+	assert.NoError(t, err)
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+
+	assert.Equal(t, throttleMaxRetries+1, decorated.calls())
+
+	// the 429 responses received right after the reduction don't reduce the rate further
+	assert.Equal(t, rate.Limit(1000), sut.limiter.Limit())
+}
+
+func TestAdaptiveThrottleShouldHonorTheRetryAfterHeader(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	decorated := &statusSequenceRoundTripper{
+		statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+		retryAfter: "1",
+	}
+
+	sut, err := decorateTransportWithAdaptiveThrottleDecorator(decorated, 0, logger)
+	assert.NoError(t, err)
+
+	sut.minRate = 1000
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/home", nil)
+	assert.NoError(t, err)
+
+	start := time.Now()
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	assert.True(t, time.Since(start) >= time.Second)
+	assert.Contains(t, loggerBuffer.String(), "pause=1s")
+}
+
+func TestAdaptiveThrottleShouldIncreaseTheRateUntilTheMaximumOneWithoutTooManyRequests(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	decorated := &statusSequenceRoundTripper{
+		statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+		retryAfter: "0",
+	}
+
+	sut, err := decorateTransportWithAdaptiveThrottleDecorator(decorated, 0, logger)
+	assert.NoError(t, err)
+
+	sut.minRate = 1000
+	sut.rampUpInterval = time.Millisecond
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/home", nil)
+		assert.NoError(t, err)
+
+		_, err = sut.RoundTrip(req)
+		assert.NoError(t, err)
+
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// not limited before the 429, the rate is restored once it reaches the one the requests were performed at
+	assert.Equal(t, rate.Inf, sut.limiter.Limit())
+	assert.Contains(t, loggerBuffer.String(), "No more too many requests, increasing the rate")
+	assert.Contains(t, loggerBuffer.String(), "rate=unlimited")
+}
+
+func TestRetryAfter(t *testing.T) {
+	testCases := []struct {
+		value         string
+		expectedPause time.Duration
+	}{
+		{value: "", expectedPause: 2 * time.Second},
+		{value: "invalid", expectedPause: 2 * time.Second},
+		{value: "-1", expectedPause: 2 * time.Second},
+		{value: "0", expectedPause: 0},
+		{value: "5", expectedPause: 5 * time.Second},
+		{value: "3600", expectedPause: time.Minute},
+		{value: "Mon, 02 Jan 2006 15:04:05 GMT", expectedPause: 0},
+		{value: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), expectedPause: time.Minute},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expectedPause, retryAfter(tc.value, 2*time.Second, time.Minute))
+		})
+	}
+}
+
+// statusSequenceRoundTripper replies with the statuses in order, the last one is repeated once reached
+type statusSequenceRoundTripper struct {
+	statuses   []int
+	retryAfter string
+
+	mx     sync.Mutex
+	count  int
+	bodies []string
+}
+
+func (s *statusSequenceRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if r.Body != nil {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		s.bodies = append(s.bodies, string(b))
+	}
+
+	status := s.statuses[len(s.statuses)-1]
+	if s.count < len(s.statuses) {
+		status = s.statuses[s.count]
+	}
+
+	s.count++
+
+	header := http.Header{}
+	if status == http.StatusTooManyRequests {
+		header.Set("Retry-After", s.retryAfter)
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    r,
+	}, nil
+}
+
+func (s *statusSequenceRoundTripper) calls() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.count
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+func decorateTransportWithBasicAuthDecorator(
+	decorated http.RoundTripper,
+	username string,
+	password string,
+) (*basicAuthTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	return &basicAuthTransportDecorator{decorated: decorated, username: username, password: password}, nil
+}
+
+type basicAuthTransportDecorator struct {
+	decorated http.RoundTripper
+	username  string
+	password  string
+}
+
+func (b *basicAuthTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	// an Authorization header explicitly provided takes precedence over the basic auth credentials
+	if r.Header.Get("Authorization") == "" {
+		r.SetBasicAuth(b.username, b.password)
+	}
+
+	return b.decorated.RoundTrip(r)
+}
@@ -0,0 +1,13 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportBasicAuthShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithBasicAuthDecorator(nil, "user", "password")
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
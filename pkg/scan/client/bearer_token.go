@@ -0,0 +1,31 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+func decorateTransportWithBearerTokenDecorator(
+	decorated http.RoundTripper,
+	token string,
+) (*bearerTokenTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	return &bearerTokenTransportDecorator{decorated: decorated, token: token}, nil
+}
+
+type bearerTokenTransportDecorator struct {
+	decorated http.RoundTripper
+	token     string
+}
+
+func (b *bearerTokenTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	// an Authorization header explicitly provided takes precedence over the bearer token
+	if r.Header.Get("Authorization") == "" {
+		r.Header.Set("Authorization", "Bearer "+b.token)
+	}
+
+	return b.decorated.RoundTrip(r)
+}
@@ -0,0 +1,13 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportBearerTokenShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithBearerTokenDecorator(nil, "mytoken")
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const defaultBodyContentType = "application/x-www-form-urlencoded"
+
+func decorateTransportWithBodyDecorator(decorated http.RoundTripper, body []byte) (*bodyTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if body == nil {
+		return nil, errors.New("body is nil")
+	}
+
+	return &bodyTransportDecorator{decorated: decorated, body: body}, nil
+}
+
+// bodyTransportDecorator attaches the given body to every request that does not have one,
+// except for GET and HEAD requests
+type bodyTransportDecorator struct {
+	decorated http.RoundTripper
+	body      []byte
+}
+
+func (b *bodyTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return b.decorated.RoundTrip(r)
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		return b.decorated.RoundTrip(r)
+	}
+
+	// a new reader is created for every request, so concurrent requests never share the same stream
+	r.Body = ioutil.NopCloser(bytes.NewReader(b.body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b.body)), nil
+	}
+	r.ContentLength = int64(len(b.body))
+
+	if r.Header.Get("Content-Type") == "" {
+		r.Header.Set("Content-Type", defaultBodyContentType)
+	}
+
+	return b.decorated.RoundTrip(r)
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportBodyShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithBodyDecorator(nil, []byte{})
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportBodyShouldFailWithNilBody(t *testing.T) {
+	transport, err := decorateTransportWithBodyDecorator(http.DefaultTransport, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
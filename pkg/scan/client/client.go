@@ -3,6 +3,9 @@ package client
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
@@ -14,48 +17,104 @@ import (
 	"golang.org/x/net/proxy"
 )
 
-func NewClientFromConfig(
-	timeoutInMilliseconds int,
-	socks5Url *url.URL,
-	userAgent string,
-	useCookieJar bool,
-	cookies []*http.Cookie,
-	headers map[string]string,
-	shouldCacheRequests bool,
-	shouldSkipSSLCertificatesValidation bool,
-	u *url.URL,
-) (*http.Client, error) {
-	transport := buildTransport(shouldSkipSSLCertificatesValidation)
+func NewClientFromConfig(cnf Config, u *url.URL) (*http.Client, error) {
+	d := newDialer(cnf.Resolver, cnf.PreferIPv6)
+
+	transport, err := buildTransport(cnf, d)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig: failed to build transport")
+	}
 
 	c := &http.Client{
-		Timeout:   time.Millisecond * time.Duration(timeoutInMilliseconds),
-		Transport: transport,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		Transport:     transport,
+		CheckRedirect: buildCheckRedirect(cnf),
+	}
+
+	if c.Jar, err = buildCookieJar(cnf, u); err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig: failed to create cookie jar")
+	}
+
+	if err := configureProxy(cnf, transport, d); err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig: failed to create socks5 proxy")
+	}
+
+	// each NTLM handshake is performed on a connection of its own, the other decorators are applied to its steps
+	var connections *pinnedTransport
+	if cnf.NTLMUsername != "" {
+		connections = newPinnedTransport(transport)
+		c.Transport = connections
+	}
+
+	decorators := []func(Config, http.RoundTripper, *pinnedTransport) (http.RoundTripper, error){
+		decorateTransportWithPacingDecorators,
+		decorateTransportWithRequestDecorators,
+		decorateTransportWithAuthDecorators,
+		decorateTransportWithBehaviourDecorators,
+	}
+
+	for _, decorate := range decorators {
+		if c.Transport, err = decorate(cnf, c.Transport, connections); err != nil {
+			return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+		}
+	}
+
+	return c, nil
+}
+
+func buildCheckRedirect(cnf Config) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !cnf.FollowRedirects || len(via) > cnf.MaxRedirects {
+			return http.ErrUseLastResponse
+		}
+
+		if cnf.IsInScope != nil && !cnf.IsInScope(req.URL) {
+			if cnf.Logger != nil {
+				cnf.Logger.WithField("location", req.URL.String()).
+					Info("Out of scope redirect, it will not be followed")
+			}
+
 			return http.ErrUseLastResponse
-		},
+		}
+
+		return nil
 	}
+}
 
-	if useCookieJar {
+// buildCookieJar returns the jar sending the configured cookies, it is nil when there are none to send or store
+func buildCookieJar(cnf Config, u *url.URL) (http.CookieJar, error) {
+	if cnf.UseCookieJar {
 		jar, err := cookiejar.New(nil)
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to create cookie jar")
+			return nil, err
 		}
 
-		c.Jar = jar
+		jar.SetCookies(u, cnf.Cookies)
+		cookie.AddFileCookies(jar, cnf.FileCookies)
+
+		return jar, nil
 	}
 
-	if c.Jar != nil {
-		c.Jar.SetCookies(u, cookies)
+	if len(cnf.FileCookies) > 0 {
+		fileJar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return cookie.NewStatelessJarWithFileCookies(cnf.Cookies, cnf.FileCookies, fileJar), nil
 	}
 
-	if len(cookies) > 0 && c.Jar == nil {
-		c.Jar = cookie.NewStatelessJar(cookies)
+	if len(cnf.Cookies) > 0 {
+		return cookie.NewStatelessJar(cnf.Cookies), nil
 	}
 
-	if socks5Url != nil {
-		tbDialer, err := proxy.FromURL(socks5Url, proxy.Direct)
+	return nil, nil
+}
+
+func configureProxy(cnf Config, transport *http.Transport, d *dialer) error {
+	if cnf.Socks5Url != nil {
+		tbDialer, err := proxy.FromURL(cnf.Socks5Url, d)
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to create socks5 proxy")
+			return err
 		}
 
 		transport.DialContext = func(ctx context.Context, network, addr string) (conn net.Conn, e error) {
@@ -63,42 +122,274 @@ func NewClientFromConfig(
 		}
 	}
 
+	// when no proxy is explicitly configured we honor HTTP_PROXY/HTTPS_PROXY, like most go CLIs do
+	switch {
+	case cnf.HTTPProxyUrl != nil:
+		transport.Proxy = http.ProxyURL(cnf.HTTPProxyUrl)
+	case cnf.Socks5Url == nil:
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	return nil
+}
+
+// decorateTransportWithPacingDecorators applies the decorators limiting how long the requests take and how often
+// they are performed, they are the innermost ones
+func decorateTransportWithPacingDecorators(
+	cnf Config,
+	transport http.RoundTripper,
+	_ *pinnedTransport,
+) (http.RoundTripper, error) {
 	var err error
 
-	c.Transport, err = decorateTransportWithUserAgentDecorator(c.Transport, userAgent)
+	// innermost, so that the time waited by the other decorators doesn't count against the timeout
+	if cnf.TimeoutInMilliseconds > 0 {
+		transport, err = decorateTransportWithTimeoutDecorator(
+			transport,
+			time.Millisecond*time.Duration(cnf.TimeoutInMilliseconds),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// right above the timeout, so that the requests hold a slot of their host only while they are on the wire
+	if cnf.PerHostThreads > 0 {
+		slots := cnf.HostSlots
+		if slots == nil {
+			slots = NewHostSlots(cnf.PerHostThreads)
+		}
+
+		transport, err = decorateTransportWithHostConcurrencyDecorator(transport, slots)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the adaptive throttle limits the rate itself, the configured one is the highest it uses
+	switch {
+	case cnf.AdaptiveThrottle:
+		transport, err = decorateTransportWithAdaptiveThrottleDecorator(transport, cnf.RequestsPerSecond, cnf.Logger)
+		if err != nil {
+			return nil, err
+		}
+	case cnf.RequestsPerSecond > 0:
+		transport, err = decorateTransportWithRateLimitDecorator(transport, cnf.RequestsPerSecond)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cnf.DelayInMilliseconds > 0 {
+		return decorateTransportWithDelayDecorator(
+			transport,
+			time.Millisecond*time.Duration(cnf.DelayInMilliseconds),
+			cnf.JitterPercentage,
+			rand.NewSource(time.Now().UnixNano()),
+		)
+	}
+
+	return transport, nil
+}
+
+// decorateTransportWithRequestDecorators applies the decorators setting the user agent and the body of the requests
+func decorateTransportWithRequestDecorators(
+	cnf Config,
+	transport http.RoundTripper,
+	_ *pinnedTransport,
+) (http.RoundTripper, error) {
+	var err error
+
+	if len(cnf.UserAgents) > 0 {
+		transport, err = decorateTransportWithRandomUserAgentDecorator(
+			transport,
+			cnf.UserAgents,
+			rand.NewSource(time.Now().UnixNano()),
+		)
+	} else {
+		transport, err = decorateTransportWithUserAgentDecorator(transport, cnf.UserAgent)
+	}
+
 	if err != nil {
-		return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+		return nil, err
 	}
 
-	if len(headers) > 0 {
-		c.Transport, err = decorateTransportWithHeadersDecorator(c.Transport, headers)
+	if cnf.Body != nil {
+		return decorateTransportWithBodyDecorator(transport, cnf.Body)
+	}
+
+	return transport, nil
+}
+
+// decorateTransportWithAuthDecorators applies the decorators authenticating the requests, then the ones setting
+// their host and headers
+func decorateTransportWithAuthDecorators(
+	cnf Config,
+	transport http.RoundTripper,
+	connections *pinnedTransport,
+) (http.RoundTripper, error) {
+	var err error
+
+	if cnf.BasicAuthUsername != "" {
+		transport, err = decorateTransportWithBasicAuthDecorator(
+			transport,
+			cnf.BasicAuthUsername,
+			cnf.BasicAuthPassword,
+		)
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+			return nil, err
 		}
 	}
 
-	if shouldCacheRequests {
-		c.Transport, err = decorateTransportWithRequestCacheDecorator(c.Transport)
+	if cnf.NTLMUsername != "" {
+		transport, err = decorateTransportWithNTLMDecorator(
+			transport,
+			connections,
+			cnf.NTLMDomain,
+			cnf.NTLMUsername,
+			cnf.NTLMPassword,
+		)
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+			return nil, err
 		}
 	}
 
-	return c, nil
+	if cnf.BearerToken != "" {
+		transport, err = decorateTransportWithBearerTokenDecorator(transport, cnf.BearerToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// decorated before the headers, to take precedence over a Host header specified among them
+	if cnf.Host != "" {
+		transport, err = decorateTransportWithHostDecorator(transport, cnf.Host)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(cnf.Headers) > 0 {
+		return decorateTransportWithHeadersDecorator(transport, cnf.Headers)
+	}
+
+	return transport, nil
 }
 
-func buildTransport(shouldSkipSSLCertificatesValidation bool) *http.Transport {
+// decorateTransportWithBehaviourDecorators applies the decorators retrying, probing with HEAD or caching the
+// requests, they are the outermost ones
+func decorateTransportWithBehaviourDecorators(
+	cnf Config,
+	transport http.RoundTripper,
+	_ *pinnedTransport,
+) (http.RoundTripper, error) {
+	var err error
+
+	if cnf.Retries > 0 {
+		transport, err = decorateTransportWithRetryDecorator(
+			transport,
+			cnf.Retries,
+			time.Millisecond*time.Duration(cnf.RetryWaitInMilliseconds),
+			cnf.Logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// decorated after the retries, so that the HEAD and the GET requests are retried separately
+	if cnf.HeadFirst {
+		transport, err = decorateTransportWithHeadFirstDecorator(transport, cnf.IsStatusIgnored)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cnf.CacheRequests {
+		return decorateTransportWithRequestCacheDecorator(transport)
+	}
+
+	return transport, nil
+}
+
+func buildTransport(cnf Config, d *dialer) (*http.Transport, error) {
 	transport := http.Transport{
+		DialContext:           d.DialContext,
 		MaxIdleConns:          100,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{},
+	}
+
+	if cnf.MaxIdleConns > 0 {
+		transport.MaxIdleConnsPerHost = cnf.MaxIdleConns
+
+		if cnf.MaxIdleConns > transport.MaxIdleConns {
+			transport.MaxIdleConns = cnf.MaxIdleConns
+		}
+	}
+
+	transport.MaxConnsPerHost = cnf.MaxConnsPerHost
+
+	if cnf.ShouldSkipSSLCertificatesValidation {
+		//nolint:gosec
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	if cnf.ClientCertificatePath != "" || cnf.ClientKeyPath != "" {
+		certificate, err := tls.LoadX509KeyPair(cnf.ClientCertificatePath, cnf.ClientKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+
+		transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if cnf.CACertificatePath != "" {
+		rootCAs, err := loadCertificatePool(cnf.CACertificatePath)
+		if err != nil {
+			return nil, err
+		}
+
+		transport.TLSClientConfig.RootCAs = rootCAs
 	}
 
-	if shouldSkipSSLCertificatesValidation {
+	if cnf.ShouldSkipTLSHostnameVerification && !cnf.ShouldSkipSSLCertificatesValidation {
+		// the standard verification can't skip just the hostname, it is replaced by one verifying only the chain
 		//nolint:gosec
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+		transport.TLSClientConfig.VerifyPeerCertificate = verifyChainIgnoringHostname(transport.TLSClientConfig.RootCAs)
+	}
+
+	// as the TLS config is customized HTTP/2 is not attempted unless forced
+	switch {
+	case cnf.ForceHTTP2:
+		transport.ForceAttemptHTTP2 = true
+	case cnf.DisableHTTP2:
+		// a non nil empty map disables HTTP/2 also in case the default behaviour of the transport changes
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return &transport, nil
+}
+
+// loadCertificatePool adds the PEM encoded certificates found at the given path
+// to the system certificate pool
+func loadCertificatePool(path string) (*x509.CertPool, error) {
+	rawCertificates, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read CA certificate %s", path)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(rawCertificates) {
+		return nil, errors.Errorf("no valid PEM encoded certificate found in %s", path)
 	}
 
-	return &transport
+	return pool, nil
 }
@@ -1,8 +1,17 @@
 package client_test
 
 import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -20,14 +29,10 @@ func TestWhenRemoteIsTooSlowClientShouldTimeout(t *testing.T) {
 	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		10,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 10,
+			CacheRequests:         true,
+		},
 		nil,
 	)
 	assert.NoError(t, err)
@@ -70,14 +75,11 @@ func TestShouldForwardProvidedCookiesWhenUsingJar(t *testing.T) {
 	}
 
 	c, err := client.NewClientFromConfig(
-		100,
-		nil,
-		"",
-		true,
-		cookies,
-		map[string]string{},
-		false,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			UseCookieJar:          true,
+			Cookies:               cookies,
+		},
 		u,
 	)
 	assert.NoError(t, err)
@@ -135,14 +137,11 @@ func TestShouldForwardCookiesWhenJarIsDisabled(t *testing.T) {
 	}
 
 	c, err := client.NewClientFromConfig(
-		100,
-		nil,
-		"",
-		false,
-		cookies,
-		map[string]string{},
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			Cookies:               cookies,
+			CacheRequests:         true,
+		},
 		u,
 	)
 	assert.NoError(t, err)
@@ -179,14 +178,11 @@ func TestShouldForwardProvidedHeader(t *testing.T) {
 	assert.NoError(t, err)
 
 	c, err := client.NewClientFromConfig(
-		100,
-		nil,
-		"",
-		false,
-		nil,
-		map[string]string{headerName: headerValue},
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			Headers:               map[string]string{headerName: headerValue},
+			CacheRequests:         true,
+		},
 		u,
 	)
 	assert.NoError(t, err)
@@ -204,18 +200,319 @@ func TestShouldForwardProvidedHeader(t *testing.T) {
 	})
 }
 
+func TestShouldPreserveUserAgentProvidedViaHeaders(t *testing.T) {
+	const userAgent = "my_header_user_agent"
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close() //nolint:errcheck
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			Headers:               map[string]string{"User-Agent": userAgent},
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+
+	defer res.Body.Close() //nolint:errcheck
+
+	assert.Equal(t, 1, serverAssertion.Len())
+
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, userAgent, r.Header.Get("User-Agent"))
+	})
+}
+
+func TestShouldRetryRequestsFailingWithServerErrors(t *testing.T) {
+	const body = "my_body"
+
+	logger, loggerBuffer := test.NewLogger()
+
+	var (
+		attempts   int
+		attemptsMx sync.Mutex
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, string(b))
+
+			attemptsMx.Lock()
+			defer attemptsMx.Unlock()
+
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds:   1000,
+			Body:                    []byte(body),
+			Retries:                 2,
+			RetryWaitInMilliseconds: 1,
+			CacheRequests:           true,
+			Logger:                  logger,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), nil)
+	assert.NoError(t, err)
+
+	res, err := c.Do(req)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Equal(t, 2, strings.Count(loggerBuffer.String(), "retrying request"))
+	assert.NotContains(t, loggerBuffer.String(), "giving up on request after retrying")
+}
+
+func TestShouldGiveUpRetryingAfterTheConfiguredAmountOfRetries(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds:   1000,
+			Retries:                 2,
+			RetryWaitInMilliseconds: 1,
+			Logger:                  logger,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "level=warning msg=\"giving up on request after retrying\"")
+}
+
+func TestShouldNotRetryRequestsFailingWithClientErrors(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds:   1000,
+			Retries:                 2,
+			RetryWaitInMilliseconds: 1,
+			Logger:                  logger,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, 1, serverAssertion.Len())
+	assert.NotContains(t, loggerBuffer.String(), "retrying request")
+}
+
+func TestShouldThrottleAndRetryTheRequestsGettingTooManyRequestsWhenAdaptiveThrottleIsEnabled(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	var requests int32
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			RequestsPerSecond:     100,
+			AdaptiveThrottle:      true,
+			Logger:                logger,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "Too many requests, throttling the scan")
+}
+
+func TestShouldNotCountThePauseRequestedViaRetryAfterAgainstTheTimeout(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	var requests int32
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 500,
+			AdaptiveThrottle:      true,
+			Logger:                logger,
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, serverAssertion.Len())
+}
+
+func TestShouldNotRetryTheRequestsGettingTooManyRequestsWhenAdaptiveThrottleIsDisabled(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			Logger:                logger,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, 1, serverAssertion.Len())
+	assert.NotContains(t, loggerBuffer.String(), "throttling")
+}
+
+func TestShouldRetryRequestsFailingWithNetworkErrors(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds:   1000,
+			Retries:                 1,
+			RetryWaitInMilliseconds: 1,
+			Logger:                  logger,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Nil(t, res)
+
+	assert.Equal(t, 1, strings.Count(loggerBuffer.String(), "retrying request"))
+	assert.Contains(t, loggerBuffer.String(), "giving up on request after retrying")
+}
+
 func TestShouldFailToCreateAClientWithInvalidSocks5Url(t *testing.T) {
 	u := url.URL{Scheme: "potatoscheme"}
 
 	c, err := client.NewClientFromConfig(
-		100,
-		&u,
-		"",
-		false,
-		nil,
-		map[string]string{},
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			Socks5Url:             &u,
+			CacheRequests:         true,
+		},
 		nil,
 	)
 	assert.Nil(t, c)
@@ -234,14 +531,10 @@ func TestShouldNotRepeatTheSameRequestTwice(t *testing.T) {
 	assert.NoError(t, err)
 
 	c, err := client.NewClientFromConfig(
-		100,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			CacheRequests:         true,
+		},
 		u,
 	)
 	assert.NoError(t, err)
@@ -263,8 +556,8 @@ func TestShouldNotRepeatTheSameRequestTwice(t *testing.T) {
 	assert.Equal(t, 1, serverAssertion.Len())
 }
 
-func TestShouldFailToCommunicateWithServerHavingInvalidSSLCertificates(t *testing.T) {
-	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+func TestShouldNotConsiderRedundantTheRequestsDifferingByTheQueryString(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
 	)
 	defer testServer.Close()
@@ -273,65 +566,819 @@ func TestShouldFailToCommunicateWithServerHavingInvalidSSLCertificates(t *testin
 	assert.NoError(t, err)
 
 	c, err := client.NewClientFromConfig(
-		1500,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			CacheRequests:         true,
+		},
 		u,
 	)
 	assert.NoError(t, err)
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-	assert.NoError(t, err)
+	for _, query := range []string{"", "?debug=1", "?debug=2"} {
+		res, err := c.Get(testServer.URL + "/home" + query)
+		assert.NoError(t, err)
 
-	res, err := c.Do(req) //nolint:bodyclose
-	assert.Error(t, err)
-	assert.Nil(t, res)
+		res.Body.Close() //nolint:errcheck,gosec
+	}
 
-	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+	_, err = c.Get(testServer.URL + "/home?debug=1") //nolint:bodyclose
+	assert.Contains(t, err.Error(), client.ErrRequestRedundant.Error())
 
-	// the request should NOT hit the handler
-	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Equal(t, 3, serverAssertion.Len())
 }
 
-func TestShouldBeAbleToSkipSSLCertificatesCheck(t *testing.T) {
-	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+func TestShouldNotFollowRedirectsByDefault(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusNoContent)
+			if r.URL.Path == "/" {
+				http.Redirect(w, r, "/final", http.StatusMovedPermanently)
+			}
 		}),
 	)
 	defer testServer.Close()
 
-	u, err := url.Parse(testServer.URL)
-	assert.NoError(t, err)
-
 	c, err := client.NewClientFromConfig(
-		1500,
-		nil,
-		"",
-		false,
-		nil,
+		client.Config{
+			TimeoutInMilliseconds: 100,
+		},
 		nil,
-		true,
-		true,
-		u,
 	)
 	assert.NoError(t, err)
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-	assert.NoError(t, err)
-
-	res, err := c.Do(req)
+	res, err := c.Get(testServer.URL) //nolint
 	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
 
-	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusMovedPermanently, res.StatusCode)
+	assert.Equal(t, "/final", res.Header.Get("Location"))
+	assert.Equal(t, 1, serverAssertion.Len())
+}
 
-	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+func TestShouldFollowRedirectsUpToTheConfiguredAmount(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				http.Redirect(w, r, "/first", http.StatusMovedPermanently)
+			case "/first":
+				http.Redirect(w, r, "/second", http.StatusFound)
+			case "/second":
+				http.Redirect(w, r, "/final", http.StatusTemporaryRedirect)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	testCases := []struct {
+		maxRedirects       int
+		expectedStatusCode int
+		expectedPath       string
+	}{
+		{maxRedirects: 5, expectedStatusCode: http.StatusOK, expectedPath: "/final"},
+		{maxRedirects: 3, expectedStatusCode: http.StatusOK, expectedPath: "/final"},
+		{maxRedirects: 2, expectedStatusCode: http.StatusTemporaryRedirect, expectedPath: "/second"},
+		{maxRedirects: 0, expectedStatusCode: http.StatusMovedPermanently, expectedPath: "/"},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			client.Config{
+				TimeoutInMilliseconds: 100,
+				FollowRedirects:       true,
+				MaxRedirects:          tc.maxRedirects,
+				CacheRequests:         true,
+			},
+			nil,
+		)
+		assert.NoError(t, err)
+
+		res, err := c.Get(testServer.URL + "/") //nolint
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+
+		assert.Equal(t, tc.expectedStatusCode, res.StatusCode)
+		assert.Equal(t, tc.expectedPath, res.Request.URL.Path)
+	}
+
+	assert.Equal(t, 4+4+3+1, serverAssertion.Len())
+}
+
+func TestShouldNotFollowRedirectsOutOfScope(t *testing.T) {
+	var testServer *httptest.Server
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				// same server, but a different host
+				http.Redirect(w, r, strings.Replace(testServer.URL, "127.0.0.1", "localhost", 1)+"/final", http.StatusFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	testCases := []struct {
+		isInScope          func(u *url.URL) bool
+		expectedStatusCode int
+		expectedRequests   int
+	}{
+		{
+			isInScope:          func(u *url.URL) bool { return u.Hostname() == "127.0.0.1" },
+			expectedStatusCode: http.StatusFound,
+			expectedRequests:   1,
+		},
+		{
+			isInScope:          func(u *url.URL) bool { return true },
+			expectedStatusCode: http.StatusOK,
+			expectedRequests:   2,
+		},
+	}
+
+	for _, tc := range testCases {
+		logger, loggerBuffer := test.NewLogger()
+
+		c, err := client.NewClientFromConfig(
+			client.Config{
+				TimeoutInMilliseconds: 100,
+				FollowRedirects:       true,
+				MaxRedirects:          5,
+				IsInScope:             tc.isInScope,
+				Logger:                logger,
+			},
+			nil,
+		)
+		assert.NoError(t, err)
+
+		requestsBefore := serverAssertion.Len()
+
+		res, err := c.Get(testServer.URL + "/") //nolint
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+
+		assert.Equal(t, tc.expectedStatusCode, res.StatusCode)
+		assert.Equal(t, tc.expectedRequests, serverAssertion.Len()-requestsBefore)
+
+		if tc.expectedStatusCode == http.StatusFound {
+			assert.Contains(t, loggerBuffer.String(), "Out of scope redirect, it will not be followed")
+		}
+	}
+}
+
+func TestShouldAllowDifferentRequestsToRedirectToTheSameLocationWhenCachingRequests(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/final" {
+				http.Redirect(w, r, "/final", http.StatusMovedPermanently)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 100,
+			FollowRedirects:       true,
+			MaxRedirects:          5,
+			CacheRequests:         true,
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	for _, p := range []string{"/home", "/about"} {
+		res, err := c.Get(testServer.URL + p) //nolint
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	assert.Equal(t, 4, serverAssertion.Len())
+}
+
+func TestShouldFailToCommunicateWithServerHavingInvalidSSLCertificates(t *testing.T) {
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1500,
+			CacheRequests:         true,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	assert.NoError(t, err)
+
+	res, err := c.Do(req) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Nil(t, res)
+
+	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+
+	// the request should NOT hit the handler
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestShouldBeAbleToSkipSSLCertificatesCheck(t *testing.T) {
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds:               1500,
+			CacheRequests:                       true,
+			ShouldSkipSSLCertificatesValidation: true,
+		},
+		u,
+	)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	assert.NoError(t, err)
+
+	res, err := c.Do(req)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
 
 	// the request should hit the handler
 	assert.Equal(t, 1, serverAssertion.Len())
 }
+
+func TestShouldUseTheConfiguredHTTPVersion(t *testing.T) {
+	testCases := []struct {
+		cnf                   client.Config
+		expectedProtocolMajor int
+	}{
+		{cnf: client.Config{}, expectedProtocolMajor: 1},
+		{cnf: client.Config{ForceHTTP2: true}, expectedProtocolMajor: 2},
+		{cnf: client.Config{DisableHTTP2: true}, expectedProtocolMajor: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(fmt.Sprintf("force: %t, disable: %t", tc.cnf.ForceHTTP2, tc.cnf.DisableHTTP2), func(t *testing.T) {
+			testServer, serverAssertion := test.NewHTTP2TLSServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			)
+			defer testServer.Close()
+
+			u, err := url.Parse(testServer.URL)
+			assert.NoError(t, err)
+
+			tc.cnf.TimeoutInMilliseconds = 1500
+			tc.cnf.ShouldSkipSSLCertificatesValidation = true
+
+			c, err := client.NewClientFromConfig(tc.cnf, u)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+			assert.NoError(t, err)
+
+			res, err := c.Do(req)
+			assert.NoError(t, err)
+
+			res.Body.Close() //nolint:errcheck,gosec
+
+			assert.Equal(t, tc.expectedProtocolMajor, res.ProtoMajor)
+
+			assert.Equal(t, 1, serverAssertion.Len())
+			serverAssertion.At(0, func(r http.Request) {
+				assert.Equal(t, tc.expectedProtocolMajor, r.ProtoMajor)
+			})
+		})
+	}
+}
+
+func TestShouldResolveTheHostsViaTheConfiguredResolver(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	dnsServer := test.MustStartDNSServer(t, map[string][]net.IP{"dirstalk.test": {net.ParseIP("127.0.0.1")}})
+	defer dnsServer.Close()
+
+	u := test.MustParseURL(t, "http://dirstalk.test:"+test.MustParseURL(t, testServer.URL).Port()+"/")
+
+	c, err := client.NewClientFromConfig(client.Config{TimeoutInMilliseconds: 1500, Resolver: dnsServer.Addr()}, u)
+	assert.NoError(t, err)
+
+	res, err := c.Get(u.String())
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	assert.Contains(t, dnsServer.Queries(), "dirstalk.test")
+}
+
+func TestShouldFailWhenTheConfiguredResolverCannotResolveTheHost(t *testing.T) {
+	dnsServer := test.MustStartDNSServer(t, map[string][]net.IP{})
+	defer dnsServer.Close()
+
+	u := test.MustParseURL(t, "http://dirstalk.test/")
+
+	c, err := client.NewClientFromConfig(client.Config{TimeoutInMilliseconds: 1500, Resolver: dnsServer.Addr()}, u)
+	assert.NoError(t, err)
+
+	_, err = c.Get(u.String())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "dirstalk.test")
+}
+
+func TestShouldPreferIPv6WhenConfigured(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 is not available: %s", err.Error())
+	}
+
+	serverAssertion := &remoteAddrAssertion{}
+
+	testServer := httptest.NewUnstartedServer(serverAssertion)
+	testServer.Listener = listener
+	testServer.Start()
+
+	defer testServer.Close()
+
+	dnsServer := test.MustStartDNSServer(
+		t,
+		map[string][]net.IP{"dirstalk.test": {net.ParseIP("127.0.0.1"), net.ParseIP("::1")}},
+	)
+	defer dnsServer.Close()
+
+	u := test.MustParseURL(t, "http://dirstalk.test:"+test.MustParseURL(t, testServer.URL).Port()+"/")
+
+	c, err := client.NewClientFromConfig(
+		client.Config{TimeoutInMilliseconds: 1500, Resolver: dnsServer.Addr(), PreferIPv6: true},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(u.String())
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, []string{"::1"}, serverAssertion.hosts())
+}
+
+func TestShouldFallbackToIPv4WhenPreferringIPv6AndTheHostHasNoIPv6Address(t *testing.T) {
+	serverAssertion := &remoteAddrAssertion{}
+
+	testServer := httptest.NewServer(serverAssertion)
+	defer testServer.Close()
+
+	dnsServer := test.MustStartDNSServer(t, map[string][]net.IP{"dirstalk.test": {net.ParseIP("127.0.0.1")}})
+	defer dnsServer.Close()
+
+	u := test.MustParseURL(t, "http://dirstalk.test:"+test.MustParseURL(t, testServer.URL).Port()+"/")
+
+	c, err := client.NewClientFromConfig(
+		client.Config{TimeoutInMilliseconds: 1500, Resolver: dnsServer.Addr(), PreferIPv6: true},
+		u,
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(u.String())
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, []string{"127.0.0.1"}, serverAssertion.hosts())
+}
+
+// remoteAddrAssertion records the host of the remote address of the requests it serves
+type remoteAddrAssertion struct {
+	remoteHosts []string
+	mx          sync.Mutex
+}
+
+func (a *remoteAddrAssertion) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
+	host, _, _ := net.SplitHostPort(r.RemoteAddr) //nolint:errcheck
+
+	a.mx.Lock()
+	a.remoteHosts = append(a.remoteHosts, host)
+	a.mx.Unlock()
+}
+
+func (a *remoteAddrAssertion) hosts() []string {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	return append([]string{}, a.remoteHosts...)
+}
+
+func TestShouldSendTheConfiguredHostHeader(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cnf          client.Config
+		expectedHost func(u *url.URL) string
+	}{
+		{
+			name:         "default",
+			cnf:          client.Config{},
+			expectedHost: func(u *url.URL) string { return u.Host },
+		},
+		{
+			name:         "host",
+			cnf:          client.Config{Host: "vhost.example.com"},
+			expectedHost: func(u *url.URL) string { return "vhost.example.com" },
+		},
+		{
+			name:         "host header",
+			cnf:          client.Config{Headers: map[string]string{"host": "header.example.com"}},
+			expectedHost: func(u *url.URL) string { return "header.example.com" },
+		},
+		{
+			name: "host and host header",
+			cnf: client.Config{
+				Host:    "vhost.example.com",
+				Headers: map[string]string{"Host": "header.example.com"},
+			},
+			expectedHost: func(u *url.URL) string { return "vhost.example.com" },
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			)
+			defer testServer.Close()
+
+			u := test.MustParseURL(t, testServer.URL)
+
+			tc.cnf.TimeoutInMilliseconds = 1500
+
+			c, err := client.NewClientFromConfig(tc.cnf, u)
+			assert.NoError(t, err)
+
+			res, err := c.Get(u.String())
+			assert.NoError(t, err)
+
+			res.Body.Close() //nolint:errcheck,gosec
+
+			assert.Equal(t, 1, serverAssertion.Len())
+			serverAssertion.At(0, func(r http.Request) {
+				assert.Equal(t, tc.expectedHost(u), r.Host)
+				assert.Empty(t, r.Header.Get("Host"))
+			})
+		})
+	}
+}
+
+func TestShouldBeAbleToSkipOnlyTheTLSHostnameVerification(t *testing.T) {
+	testServer, _ := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	caCertificatePath := test.MustWriteTempFile(
+		t,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testServer.Certificate().Raw}),
+	)
+	defer os.Remove(caCertificatePath) //nolint:errcheck
+
+	// the certificate of the test server is issued for example.com and the loopback IPs, not for localhost
+	u := test.MustParseURL(t, "https://localhost:"+test.MustParseURL(t, testServer.URL).Port()+"/")
+
+	testCases := []struct {
+		name          string
+		cnf           client.Config
+		expectedError string
+	}{
+		{
+			name:          "hostname verified",
+			cnf:           client.Config{CACertificatePath: caCertificatePath},
+			expectedError: "certificate is valid for",
+		},
+		{
+			name: "hostname not verified",
+			cnf:  client.Config{CACertificatePath: caCertificatePath, ShouldSkipTLSHostnameVerification: true},
+		},
+		{
+			name:          "hostname not verified, unknown authority",
+			cnf:           client.Config{ShouldSkipTLSHostnameVerification: true},
+			expectedError: "certificate signed by unknown authority",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cnf.TimeoutInMilliseconds = 1500
+
+			c, err := client.NewClientFromConfig(tc.cnf, u)
+			assert.NoError(t, err)
+
+			res, err := c.Get(u.String())
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			res.Body.Close() //nolint:errcheck,gosec
+
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		})
+	}
+}
+
+func TestShouldReuseTheIdleConnections(t *testing.T) {
+	const workers = 8
+
+	// the transport keeps 2 idle connections per host by default and closes the others when no request is waiting
+	// for them, as the workers pause between the requests most of them need a new connection; the amount of
+	// connections dialed depends on the scheduling of the workers, hence it is compared with the default one
+	defaultNewConns := newConnsOfConcurrentRequests(t, client.Config{TimeoutInMilliseconds: 1000}, workers)
+
+	idleNewConns := newConnsOfConcurrentRequests(
+		t,
+		client.Config{TimeoutInMilliseconds: 1000, MaxIdleConns: workers},
+		workers,
+	)
+	assert.True(t, idleNewConns >= 1, "%d connections dialed", idleNewConns)
+	assert.True(
+		t,
+		idleNewConns < defaultNewConns,
+		"%d connections dialed with an idle connection per worker, %d by default",
+		idleNewConns,
+		defaultNewConns,
+	)
+
+	limitedNewConns := newConnsOfConcurrentRequests(
+		t,
+		client.Config{TimeoutInMilliseconds: 1000, MaxIdleConns: workers, MaxConnsPerHost: 2},
+		workers,
+	)
+	assert.True(t, limitedNewConns >= 1, "%d connections dialed", limitedNewConns)
+	assert.True(
+		t,
+		limitedNewConns < defaultNewConns,
+		"%d connections dialed with limited connections per host, %d by default",
+		limitedNewConns,
+		defaultNewConns,
+	)
+}
+
+// newConnsOfConcurrentRequests returns the amount of connections dialed by a client built from config while workers
+// goroutines perform 10 requests each, pausing after each request so that its connection becomes idle
+func newConnsOfConcurrentRequests(t *testing.T, config client.Config, workers int) int64 {
+	const requestsPerWorker = 10
+
+	var newConns int64
+
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	testServer.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&newConns, 1)
+		}
+	}
+	testServer.Start()
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(config, nil)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < requestsPerWorker; j++ {
+				res, err := c.Get(testServer.URL) //nolint
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				_, _ = ioutil.ReadAll(res.Body)
+				assert.NoError(t, res.Body.Close())
+
+				time.Sleep(2 * time.Millisecond)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return atomic.LoadInt64(&newConns)
+}
+
+func TestShouldNotCountTheTimeWaitedForTheRateLimitAgainstTheTimeout(t *testing.T) {
+	const requests = 4
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	// the last request is performed 1.5s after the first one
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			RequestsPerSecond:     2,
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	for _, err := range getConcurrently(c, testServer.URL, requests) {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, requests, serverAssertion.Len())
+}
+
+func TestShouldNotCountTheDelayAgainstTheTimeout(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 200,
+			DelayInMilliseconds:   300,
+			JitterPercentage:      10,
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	for _, err := range getConcurrently(c, testServer.URL, 2) {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, serverAssertion.Len())
+}
+
+func TestShouldGiveEachRetryTheWholeTimeout(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	var attempts int32
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				time.Sleep(300 * time.Millisecond)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds:   200,
+			Retries:                 1,
+			RetryWaitInMilliseconds: 150,
+			Logger:                  logger,
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	for _, err := range getConcurrently(c, testServer.URL, 1) {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	assert.Equal(t, 1, strings.Count(loggerBuffer.String(), "retrying request"))
+}
+
+func TestShouldNotCountTheTimeWaitedForASlotOfTheHostAgainstTheTimeout(t *testing.T) {
+	const requests = 4
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 500,
+			PerHostThreads:        1,
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	for _, err := range getConcurrently(c, testServer.URL, requests) {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, requests, serverAssertion.Len())
+}
+
+func TestShouldShareTheSlotsOfTheHostsAmongTheClients(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				previous := atomic.LoadInt32(&maxInFlight)
+				if current <= previous || atomic.CompareAndSwapInt32(&maxInFlight, previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(50 * time.Millisecond)
+		}),
+	)
+	defer testServer.Close()
+
+	config := client.Config{
+		TimeoutInMilliseconds: 1000,
+		PerHostThreads:        2,
+		HostSlots:             client.NewHostSlots(2),
+	}
+
+	first, err := client.NewClientFromConfig(config, nil)
+	assert.NoError(t, err)
+
+	second, err := client.NewClientFromConfig(config, nil)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for _, c := range []*http.Client{first, second} {
+		wg.Add(1)
+
+		go func(c *http.Client) {
+			defer wg.Done()
+
+			for _, err := range getConcurrently(c, testServer.URL, 3) {
+				assert.NoError(t, err)
+			}
+		}(c)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 6, serverAssertion.Len())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+}
+
+// getConcurrently performs the given amount of GET requests to the url at the same time, it returns their errors
+func getConcurrently(c *http.Client, u string, requests int) []error {
+	errs := make([]error, requests)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			res, err := c.Get(u) //nolint
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			_, errs[i] = ioutil.ReadAll(res.Body)
+			_ = res.Body.Close() //nolint:errcheck
+		}(i)
+	}
+
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Config represents the configuration needed to build the http client used to perform a scan
+type Config struct {
+	TimeoutInMilliseconds               int
+	Socks5Url                           *url.URL
+	HTTPProxyUrl                        *url.URL
+	UserAgent                           string
+	UseCookieJar                        bool
+	Cookies                             []*http.Cookie
+	FileCookies                         []*http.Cookie
+	Headers                             map[string]string
+	BasicAuthUsername                   string
+	BasicAuthPassword                   string
+	NTLMDomain                          string
+	NTLMUsername                        string
+	NTLMPassword                        string
+	BearerToken                         string
+	Body                                []byte
+	RequestsPerSecond                   int
+	PerHostThreads                      int
+	HostSlots                           *HostSlots
+	DelayInMilliseconds                 int
+	JitterPercentage                    int
+	Retries                             int
+	RetryWaitInMilliseconds             int
+	FollowRedirects                     bool
+	MaxRedirects                        int
+	CacheRequests                       bool
+	ShouldSkipSSLCertificatesValidation bool
+	ShouldSkipTLSHostnameVerification   bool
+	ClientCertificatePath               string
+	ClientKeyPath                       string
+	CACertificatePath                   string
+	ForceHTTP2                          bool
+	DisableHTTP2                        bool
+	PreferIPv6                          bool
+
+	// MaxIdleConns is the amount of idle (keep-alive) connections kept open to each host, to be reused by the
+	// following requests; when 0 the default of the transport is used. MaxConnsPerHost caps the connections to
+	// each host, the requests wait for one of them to be available (0 means no limit).
+	MaxIdleConns    int
+	MaxConnsPerHost int
+
+	// Resolver is the host:port of the DNS server used to resolve the hosts, when empty the system resolver is used
+	Resolver string
+
+	// Host is the Host header sent with each request, the connections are still opened to the host of the
+	// request urls. When empty the host of the urls is sent, unless a Host header is among Headers
+	Host string
+
+	// UserAgents is the pool of user agents picked randomly for each request, when not empty UserAgent is ignored
+	UserAgents []string
+
+	// IsInScope decides to which urls the redirects can be followed, when nil any redirect is followed
+	IsInScope func(u *url.URL) bool
+
+	// HeadFirst sends a HEAD request before each GET one, the GET request is sent only when IsStatusIgnored
+	// returns false for the status of the HEAD response, that is returned otherwise
+	HeadFirst       bool
+	IsStatusIgnored func(statusCode int) bool
+
+	// AdaptiveThrottle slows down the requests when the server replies 429 (Too Many Requests), retrying them
+	AdaptiveThrottle bool
+
+	// Logger is required only when Retries is greater than 0 or AdaptiveThrottle is enabled
+	Logger *logrus.Logger
+}
@@ -9,13 +9,26 @@ func NewStatelessJar(cookies []*http.Cookie) StatelessJar {
 	return StatelessJar{cookies: cookies}
 }
 
+// NewStatelessJarWithFileCookies returns a StatelessJar also sending the cookies read via ParseNetscapeFile,
+// each one only to the urls it matches
+func NewStatelessJarWithFileCookies(cookies, fileCookies []*http.Cookie, fileJar http.CookieJar) StatelessJar {
+	AddFileCookies(fileJar, fileCookies)
+
+	return StatelessJar{cookies: cookies, fileJar: fileJar}
+}
+
 type StatelessJar struct {
 	cookies []*http.Cookie
+	fileJar http.CookieJar
 }
 
 func (s StatelessJar) SetCookies(_ *url.URL, _ []*http.Cookie) {
 }
 
-func (s StatelessJar) Cookies(_ *url.URL) []*http.Cookie {
-	return s.cookies
+func (s StatelessJar) Cookies(u *url.URL) []*http.Cookie {
+	if s.fileJar == nil || u == nil {
+		return s.cookies
+	}
+
+	return append(append([]*http.Cookie{}, s.cookies...), s.fileJar.Cookies(u)...)
 }
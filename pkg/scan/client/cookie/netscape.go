@@ -0,0 +1,150 @@
+package cookie
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// netscapeHTTPOnlyPrefix marks the domain of the http only cookies in the files exported by the browsers,
+// such lines would otherwise be comments
+const netscapeHTTPOnlyPrefix = "#HttpOnly_"
+
+// netscapeFieldsCount are the fields of each line: domain, include subdomains, path, secure, expiry, name and value
+const netscapeFieldsCount = 7
+
+// ParseNetscapeFile reads the cookies of a file in the Netscape/Mozilla format (cookies.txt), as exported by the
+// browsers: the cookies expired before now are skipped, as well as the empty lines and the comments.
+// The domain of the cookies including the subdomains starts with a dot, the one of the others doesn't.
+func ParseNetscapeFile(r io.Reader, now time.Time) ([]*http.Cookie, error) {
+	cookies := make([]*http.Cookie, 0)
+
+	scanner := bufio.NewScanner(r)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := strings.HasPrefix(line, netscapeHTTPOnlyPrefix)
+		if httpOnly {
+			line = strings.TrimPrefix(line, netscapeHTTPOnlyPrefix)
+		}
+
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		c, err := parseNetscapeLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cookie at line %d", lineNumber)
+		}
+
+		if !c.Expires.IsZero() && !c.Expires.After(now) {
+			continue
+		}
+
+		c.HttpOnly = httpOnly
+
+		cookies = append(cookies, c)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the cookies")
+	}
+
+	return cookies, nil
+}
+
+func parseNetscapeLine(line string) (*http.Cookie, error) {
+	fields := strings.SplitN(line, "\t", netscapeFieldsCount)
+	if len(fields) != netscapeFieldsCount {
+		return nil, errors.Errorf("expected %d fields separated by tabs, found %d", netscapeFieldsCount, len(fields))
+	}
+
+	domain := strings.TrimPrefix(strings.ToLower(fields[0]), ".")
+	if domain == "" {
+		return nil, errors.New("the domain cannot be empty")
+	}
+
+	includeSubdomains, err := parseNetscapeBool(fields[1])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid include subdomains field")
+	}
+
+	if includeSubdomains {
+		domain = "." + domain
+	}
+
+	path := fields[2]
+	if !strings.HasPrefix(path, "/") {
+		path = "/"
+	}
+
+	secure, err := parseNetscapeBool(fields[3])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid secure field")
+	}
+
+	expiry, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid expiry field")
+	}
+
+	if fields[5] == "" {
+		return nil, errors.New("the name cannot be empty")
+	}
+
+	c := &http.Cookie{
+		Name:   fields[5],
+		Value:  fields[6],
+		Domain: domain,
+		Path:   path,
+		Secure: secure,
+	}
+
+	// an expiry of 0 is a session cookie
+	if expiry > 0 {
+		c.Expires = time.Unix(expiry, 0)
+	}
+
+	return c, nil
+}
+
+func parseNetscapeBool(field string) (bool, error) {
+	switch strings.ToUpper(field) {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	default:
+		return false, errors.Errorf("expected TRUE or FALSE, found `%s`", field)
+	}
+}
+
+// AddFileCookies adds to the jar the cookies read via ParseNetscapeFile, so that the jar sends each one
+// only to the urls matching its domain and its path
+func AddFileCookies(jar http.CookieJar, cookies []*http.Cookie) {
+	for _, c := range cookies {
+		host := strings.TrimPrefix(c.Domain, ".")
+
+		scheme := "http"
+		if c.Secure {
+			scheme = "https"
+		}
+
+		bound := *c
+
+		// the jar binds the cookies without a domain to the host they are set for, the IPs have no subdomains
+		if !strings.HasPrefix(c.Domain, ".") || net.ParseIP(host) != nil {
+			bound.Domain = ""
+		}
+
+		jar.SetCookies(&url.URL{Scheme: scheme, Host: host, Path: c.Path}, []*http.Cookie{&bound})
+	}
+}
@@ -0,0 +1,150 @@
+package cookie_test
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/client/cookie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNetscapeFile(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	content := strings.Join(
+		[]string{
+			"# Netscape HTTP Cookie File",
+			"",
+			".example.com\tTRUE\t/\tFALSE\t1800000000\tsession\tabc=",
+			"#HttpOnly_www.example.com\tFALSE\t/admin\tTRUE\t0\ttoken\txyz",
+			"example.com\tFALSE\t/\tFALSE\t1600000000\texpired\tvalue",
+			"other.com\tFALSE\t/\tFALSE\t0\tempty\t\r",
+		},
+		"\n",
+	)
+
+	cookies, err := cookie.ParseNetscapeFile(strings.NewReader(content), now)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]*http.Cookie{
+			{
+				Name:    "session",
+				Value:   "abc=",
+				Domain:  ".example.com",
+				Path:    "/",
+				Expires: time.Unix(1800000000, 0),
+			},
+			{
+				Name:     "token",
+				Value:    "xyz",
+				Domain:   "www.example.com",
+				Path:     "/admin",
+				Secure:   true,
+				HttpOnly: true,
+			},
+			{
+				Name:   "empty",
+				Domain: "other.com",
+				Path:   "/",
+			},
+		},
+		cookies,
+	)
+}
+
+func TestParseNetscapeFileShouldFailForInvalidLines(t *testing.T) {
+	testCases := []struct {
+		line          string
+		expectedError string
+	}{
+		{
+			line:          "example.com\tFALSE\t/\tFALSE\t0\tname",
+			expectedError: "expected 7 fields separated by tabs, found 6",
+		},
+		{
+			line:          "example.com\tYES\t/\tFALSE\t0\tname\tvalue",
+			expectedError: "invalid include subdomains field",
+		},
+		{
+			line:          "example.com\tFALSE\t/\tNO\t0\tname\tvalue",
+			expectedError: "invalid secure field",
+		},
+		{
+			line:          "example.com\tFALSE\t/\tFALSE\tnever\tname\tvalue",
+			expectedError: "invalid expiry field",
+		},
+		{
+			line:          "\tFALSE\t/\tFALSE\t0\tname\tvalue",
+			expectedError: "the domain cannot be empty",
+		},
+		{
+			line:          "example.com\tFALSE\t/\tFALSE\t0\t\tvalue",
+			expectedError: "the name cannot be empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.line, func(t *testing.T) {
+			_, err := cookie.ParseNetscapeFile(strings.NewReader("# a comment\n"+tc.line), time.Now())
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid cookie at line 2")
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestStatelessJarWithFileCookiesShouldSendThemOnlyToTheMatchingUrls(t *testing.T) {
+	fileJar, err := cookiejar.New(nil)
+	assert.NoError(t, err)
+
+	cookies := []*http.Cookie{{Name: "a_cookie_name", Value: "a_cookie_value"}}
+
+	jar := cookie.NewStatelessJarWithFileCookies(
+		cookies,
+		[]*http.Cookie{
+			{Name: "subdomains", Value: "1", Domain: ".example.com", Path: "/"},
+			{Name: "host", Value: "2", Domain: "example.com", Path: "/admin"},
+			{Name: "secure", Value: "3", Domain: "example.com", Path: "/", Secure: true},
+			{Name: "ip", Value: "4", Domain: ".127.0.0.1", Path: "/"},
+		},
+		fileJar,
+	)
+
+	testCases := []struct {
+		url             string
+		expectedCookies []string
+	}{
+		{url: "http://example.com/", expectedCookies: []string{"a_cookie_name", "subdomains"}},
+		{url: "http://example.com/admin/login", expectedCookies: []string{"a_cookie_name", "host", "subdomains"}},
+		{url: "https://example.com/", expectedCookies: []string{"a_cookie_name", "subdomains", "secure"}},
+		{url: "http://www.example.com/admin", expectedCookies: []string{"a_cookie_name", "subdomains"}},
+		{url: "http://127.0.0.1:8080/", expectedCookies: []string{"a_cookie_name", "ip"}},
+		{url: "http://another.com/", expectedCookies: []string{"a_cookie_name"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.url, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			assert.NoError(t, err)
+
+			jar.SetCookies(u, []*http.Cookie{{Name: "server_cookie", Value: "value"}})
+
+			names := make([]string, 0)
+			for _, c := range jar.Cookies(u) {
+				names = append(names, c.Name)
+			}
+
+			assert.Equal(t, tc.expectedCookies, names)
+		})
+	}
+
+	assert.Equal(t, cookies, jar.Cookies(nil))
+}
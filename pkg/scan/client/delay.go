@@ -0,0 +1,77 @@
+package client
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+func decorateTransportWithDelayDecorator(
+	decorated http.RoundTripper,
+	delay time.Duration,
+	jitterPercentage int,
+	source rand.Source,
+) (*delayTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if delay < 0 {
+		return nil, errors.New("delay cannot be negative")
+	}
+
+	if jitterPercentage < 0 || jitterPercentage > 100 {
+		return nil, errors.New("jitter percentage must be between 0 and 100")
+	}
+
+	if source == nil {
+		return nil, errors.New("source is nil")
+	}
+
+	return &delayTransportDecorator{
+		decorated:        decorated,
+		delay:            delay,
+		jitterPercentage: jitterPercentage,
+		rand:             rand.New(source), //nolint:gosec
+	}, nil
+}
+
+// delayTransportDecorator waits before performing every request: since each thread of the scanner
+// performs its requests sequentially the delay is effectively applied per thread. The delay doesn't count against
+// the timeout of the request, which starts once it is performed (see timeoutTransportDecorator).
+type delayTransportDecorator struct {
+	decorated        http.RoundTripper
+	delay            time.Duration
+	jitterPercentage int
+
+	rand   *rand.Rand
+	randMx sync.Mutex
+}
+
+func (d *delayTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	timer := time.NewTimer(d.nextDelay())
+	defer timer.Stop()
+
+	select {
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	case <-timer.C:
+	}
+
+	return d.decorated.RoundTrip(r)
+}
+
+// nextDelay returns the configured delay randomized by up to the configured jitter percentage
+func (d *delayTransportDecorator) nextDelay() time.Duration {
+	maxJitter := int64(d.delay) * int64(d.jitterPercentage) / 100
+	if maxJitter <= 0 {
+		return d.delay
+	}
+
+	d.randMx.Lock()
+	defer d.randMx.Unlock()
+
+	return d.delay + time.Duration(d.rand.Int63n(2*maxJitter+1)-maxJitter)
+}
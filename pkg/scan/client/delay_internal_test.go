@@ -0,0 +1,53 @@
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportDelayShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithDelayDecorator(nil, time.Second, 0, rand.NewSource(1))
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportDelayShouldFailWithInvalidJitter(t *testing.T) {
+	transport, err := decorateTransportWithDelayDecorator(http.DefaultTransport, time.Second, 101, rand.NewSource(1))
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDelayWithoutJitterShouldBeConstant(t *testing.T) {
+	transport, err := decorateTransportWithDelayDecorator(http.DefaultTransport, time.Second, 0, rand.NewSource(1))
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Second, transport.nextDelay())
+	}
+}
+
+func TestDelayWithJitterShouldBeDeterministicAndWithinBounds(t *testing.T) {
+	first, err := decorateTransportWithDelayDecorator(http.DefaultTransport, time.Second, 20, rand.NewSource(42))
+	assert.NoError(t, err)
+
+	second, err := decorateTransportWithDelayDecorator(http.DefaultTransport, time.Second, 20, rand.NewSource(42))
+	assert.NoError(t, err)
+
+	delays := make(map[time.Duration]struct{})
+
+	for i := 0; i < 100; i++ {
+		delay := first.nextDelay()
+
+		assert.Equal(t, delay, second.nextDelay())
+		assert.True(t, delay >= 800*time.Millisecond, delay)
+		assert.True(t, delay <= 1200*time.Millisecond, delay)
+
+		delays[delay] = struct{}{}
+	}
+
+	assert.True(t, len(delays) > 1, "the delay is expected to vary")
+}
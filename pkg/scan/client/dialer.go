@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net"
+)
+
+// dialer opens the connections of the client, both the ones to the servers and the ones to the proxies
+type dialer struct {
+	dialer     *net.Dialer
+	preferIPv6 bool
+}
+
+func newDialer(resolverAddress string, preferIPv6 bool) *dialer {
+	d := &dialer{dialer: &net.Dialer{}, preferIPv6: preferIPv6}
+
+	if resolverAddress != "" {
+		d.dialer.Resolver = &net.Resolver{
+			// the go resolver is the only one allowing to choose the DNS server
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, resolverAddress)
+			},
+		}
+	}
+
+	return d
+}
+
+func (d *dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if d.preferIPv6 && network == "tcp" {
+		// when the host has no IPv6 address (or it is not reachable) the connection is attempted as usual
+		if conn, err := d.dialer.DialContext(ctx, "tcp6", address); err == nil {
+			return conn, nil
+		}
+	}
+
+	return d.dialer.DialContext(ctx, network, address)
+}
+
+// Dial allows to use the dialer as the forward dialer of the socks5 proxy
+func (d *dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+func decorateTransportWithHeadFirstDecorator(
+	decorated http.RoundTripper,
+	isStatusIgnored func(statusCode int) bool,
+) (*headFirstTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if isStatusIgnored == nil {
+		return nil, errors.New("the function deciding the statuses ignored is nil")
+	}
+
+	return &headFirstTransportDecorator{
+		decorated:       decorated,
+		isStatusIgnored: isStatusIgnored,
+		headUnsupported: make(map[string]struct{}),
+	}, nil
+}
+
+// headFirstTransportDecorator sends a HEAD request before each GET one without a body: when the status of the HEAD
+// response is ignored it is returned in place of the GET one, otherwise the GET request is sent. When the server
+// doesn't allow HEAD (405) the GET request is sent, once the server replies it doesn't implement it (501) only
+// the GET requests are sent to its host.
+type headFirstTransportDecorator struct {
+	decorated       http.RoundTripper
+	isStatusIgnored func(statusCode int) bool
+
+	headUnsupported   map[string]struct{}
+	headUnsupportedMx sync.Mutex
+}
+
+func (d *headFirstTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet || (r.Body != nil && r.Body != http.NoBody) || !d.supportsHead(r.URL.Host) {
+		return d.decorated.RoundTrip(r)
+	}
+
+	head := r.Clone(r.Context())
+	head.Method = http.MethodHead
+
+	res, err := d.decorated.RoundTrip(head)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotImplemented:
+		d.headUnsupportedMx.Lock()
+		d.headUnsupported[r.URL.Host] = struct{}{}
+		d.headUnsupportedMx.Unlock()
+	case res.StatusCode != http.StatusMethodNotAllowed && d.isStatusIgnored(res.StatusCode):
+		// the response is handled as the one of the request performed
+		res.Request = r
+		return res, nil
+	}
+
+	drain(res)
+
+	return d.decorated.RoundTrip(r)
+}
+
+func (d *headFirstTransportDecorator) supportsHead(host string) bool {
+	d.headUnsupportedMx.Lock()
+	defer d.headUnsupportedMx.Unlock()
+
+	_, unsupported := d.headUnsupported[host]
+
+	return !unsupported
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportHeadFirstShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithHeadFirstDecorator(nil, isNotFound)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportHeadFirstShouldFailWithNilStatusFunction(t *testing.T) {
+	transport, err := decorateTransportWithHeadFirstDecorator(http.DefaultTransport, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestHeadFirstShouldSendTheGetRequestOnlyWhenTheStatusIsNotIgnored(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/home" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write([]byte("the home page"))
+		}),
+	)
+	defer testServer.Close()
+
+	sut, err := decorateTransportWithHeadFirstDecorator(http.DefaultTransport, isNotFound)
+	assert.NoError(t, err)
+
+	for _, path := range []string{"/blabla", "/home"} {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+		assert.NoError(t, err)
+
+		res, err := sut.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.Equal(t, req, res.Request)
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+
+		if path == "/home" {
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "the home page", string(body))
+		} else {
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		}
+	}
+
+	methods := make([]string, 0, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+	})
+
+	assert.Equal(t, []string{"HEAD /blabla", "HEAD /home", "GET /home"}, methods)
+}
+
+func TestHeadFirstShouldNotSendHeadForOtherRequests(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	sut, err := decorateTransportWithHeadFirstDecorator(http.DefaultTransport, isNotFound)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, testServer.URL+"/home", nil)
+	assert.NoError(t, err)
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+
+	req, err = http.NewRequest(http.MethodGet, testServer.URL+"/home", strings.NewReader("my_body"))
+	assert.NoError(t, err)
+
+	res, err = sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.NotEqual(t, http.MethodHead, r.Method)
+	})
+}
+
+func TestHeadFirstShouldFallBackToGetWhenHeadIsNotSupported(t *testing.T) {
+	testCases := []struct {
+		headStatus      int
+		expectedMethods []string
+	}{
+		{
+			headStatus:      http.StatusMethodNotAllowed,
+			expectedMethods: []string{"HEAD", "GET", "HEAD", "GET"},
+		},
+		{
+			// the host doesn't implement HEAD, it is not sent anymore
+			headStatus:      http.StatusNotImplemented,
+			expectedMethods: []string{"HEAD", "GET", "GET"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(http.StatusText(tc.headStatus), func(t *testing.T) {
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodHead {
+						w.WriteHeader(tc.headStatus)
+						return
+					}
+
+					w.WriteHeader(http.StatusNotFound)
+				}),
+			)
+			defer testServer.Close()
+
+			sut, err := decorateTransportWithHeadFirstDecorator(http.DefaultTransport, isNotFound)
+			assert.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				req, err := http.NewRequest(http.MethodGet, testServer.URL+"/home", nil)
+				assert.NoError(t, err)
+
+				res, err := sut.RoundTrip(req)
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusNotFound, res.StatusCode)
+				assert.NoError(t, res.Body.Close())
+			}
+
+			methods := make([]string, 0, serverAssertion.Len())
+			serverAssertion.Range(func(_ int, r http.Request) {
+				methods = append(methods, r.Method)
+			})
+
+			assert.Equal(t, tc.expectedMethods, methods)
+		})
+	}
+}
+
+func isNotFound(statusCode int) bool {
+	return statusCode == http.StatusNotFound
+}
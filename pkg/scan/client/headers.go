@@ -24,6 +24,12 @@ type headersTransportDecorator struct {
 
 func (h *headersTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
 	for key, value := range h.headers {
+		// the http client ignores the Host header, the one of the request is used instead
+		if http.CanonicalHeaderKey(key) == "Host" {
+			r.Host = value
+			continue
+		}
+
 		r.Header.Set(key, value)
 	}
 
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+func decorateTransportWithHostDecorator(decorated http.RoundTripper, host string) (*hostTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if host == "" {
+		return nil, errors.New("host is empty")
+	}
+
+	return &hostTransportDecorator{decorated: decorated, host: host}, nil
+}
+
+// hostTransportDecorator sends the configured Host header, while the connection is still
+// opened to the host of the request url
+type hostTransportDecorator struct {
+	decorated http.RoundTripper
+	host      string
+}
+
+func (h *hostTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.Host = h.host
+
+	return h.decorated.RoundTrip(r)
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// NewHostSlots returns the slots capping the requests in flight to each host (by hostname); they can be shared
+// by several clients (see Config.HostSlots), so that the cap applies to the requests of all of them
+func NewHostSlots(maxPerHost int) *HostSlots {
+	return &HostSlots{maxPerHost: maxPerHost, semaphores: make(map[string]chan struct{})}
+}
+
+// HostSlots holds a semaphore per host, it is safe for concurrent use
+type HostSlots struct {
+	maxPerHost int
+
+	semaphores   map[string]chan struct{}
+	semaphoresMx sync.Mutex
+}
+
+func (h *HostSlots) semaphore(hostname string) chan struct{} {
+	h.semaphoresMx.Lock()
+	defer h.semaphoresMx.Unlock()
+
+	semaphore, ok := h.semaphores[hostname]
+	if !ok {
+		semaphore = make(chan struct{}, h.maxPerHost)
+		h.semaphores[hostname] = semaphore
+	}
+
+	return semaphore
+}
+
+func decorateTransportWithHostConcurrencyDecorator(
+	decorated http.RoundTripper,
+	slots *HostSlots,
+) (*hostConcurrencyTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if slots == nil {
+		return nil, errors.New("host slots are nil")
+	}
+
+	if slots.maxPerHost <= 0 {
+		return nil, errors.New("the requests per host must be greater than 0")
+	}
+
+	return &hostConcurrencyTransportDecorator{decorated: decorated, slots: slots}, nil
+}
+
+// hostConcurrencyTransportDecorator caps the requests in flight to each host, a request is in flight until the body
+// of its response is closed; the requests over the cap wait for one of the others to complete. The wait doesn't
+// count against the timeout of the request, which starts once it holds a slot (see timeoutTransportDecorator).
+type hostConcurrencyTransportDecorator struct {
+	decorated http.RoundTripper
+	slots     *HostSlots
+}
+
+func (d *hostConcurrencyTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	semaphore := d.slots.semaphore(r.URL.Hostname())
+
+	select {
+	case semaphore <- struct{}{}:
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	}
+
+	release := func() { <-semaphore }
+
+	res, err := d.decorated.RoundTrip(r)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	res.Body = &releasingBody{ReadCloser: res.Body, release: release}
+
+	return res, nil
+}
+
+// releasingBody invokes release the first time it is closed
+type releasingBody struct {
+	io.ReadCloser
+
+	release  func()
+	released sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.released.Do(b.release)
+
+	return err
+}
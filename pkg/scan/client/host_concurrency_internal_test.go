@@ -0,0 +1,91 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportHostConcurrencyShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithHostConcurrencyDecorator(nil, NewHostSlots(2))
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportHostConcurrencyShouldFailWithInvalidCap(t *testing.T) {
+	transport, err := decorateTransportWithHostConcurrencyDecorator(http.DefaultTransport, NewHostSlots(0))
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportHostConcurrencyShouldFailWithNilSlots(t *testing.T) {
+	transport, err := decorateTransportWithHostConcurrencyDecorator(http.DefaultTransport, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestHostConcurrencyShouldCapTheRequestsInFlightToEachHost(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				previous := atomic.LoadInt32(&maxInFlight)
+				if current <= previous || atomic.CompareAndSwapInt32(&maxInFlight, previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(50 * time.Millisecond)
+		}),
+	)
+	defer testServer.Close()
+
+	sut, err := decorateTransportWithHostConcurrencyDecorator(http.DefaultTransport, NewHostSlots(2))
+	assert.NoError(t, err)
+
+	// the same server reached via two hostnames counts as two hosts
+	urls := []string{testServer.URL, strings.Replace(testServer.URL, "127.0.0.1", "localhost", 1)}
+
+	maxInFlightPerRun := make([]int32, 0, len(urls))
+
+	for i := range urls {
+		atomic.StoreInt32(&maxInFlight, 0)
+
+		var wg sync.WaitGroup
+
+		for j := 0; j < 6; j++ {
+			wg.Add(1)
+
+			go func(u string) {
+				defer wg.Done()
+
+				req, err := http.NewRequest(http.MethodGet, u, nil)
+				assert.NoError(t, err)
+
+				res, err := sut.RoundTrip(req)
+				assert.NoError(t, err)
+
+				_, _ = ioutil.ReadAll(res.Body)
+				assert.NoError(t, res.Body.Close())
+			}(urls[j%(i+1)])
+		}
+
+		wg.Wait()
+
+		maxInFlightPerRun = append(maxInFlightPerRun, atomic.LoadInt32(&maxInFlight))
+	}
+
+	assert.Equal(t, 12, serverAssertion.Len())
+	assert.Equal(t, []int32{2, 4}, maxInFlightPerRun)
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportHostShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithHostDecorator(nil, "example.com")
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportHostShouldFailWithEmptyHost(t *testing.T) {
+	transport, err := decorateTransportWithHostDecorator(http.DefaultTransport, "")
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
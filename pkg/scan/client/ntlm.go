@@ -0,0 +1,259 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Azure/go-ntlmssp"
+)
+
+func decorateTransportWithNTLMDecorator(
+	decorated http.RoundTripper,
+	connections *pinnedTransport,
+	domain string,
+	username string,
+	password string,
+) (*ntlmTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if connections == nil {
+		return nil, errors.New("pinned transport is nil")
+	}
+
+	return &ntlmTransportDecorator{
+		decorated:     decorated,
+		connections:   connections,
+		domain:        domain,
+		username:      username,
+		password:      password,
+		authenticated: make(map[string][]http.RoundTripper),
+	}, nil
+}
+
+// ntlmTransportDecorator performs the NTLM (v2) handshake when the server requires it. As NTLM authenticates the
+// connection, each handshake is performed on a connection of its own (see pinnedTransport) and the connection is
+// then reused, one request at a time, by the following requests to the same host. The request is first sent as
+// it is, only on a 401 offering NTLM the negotiate and authenticate messages are sent.
+type ntlmTransportDecorator struct {
+	decorated   http.RoundTripper
+	connections *pinnedTransport
+	domain      string
+	username    string
+	password    string
+
+	// authenticated are the connections not in use authenticated already, by scheme and host
+	authenticated   map[string][]http.RoundTripper
+	authenticatedMx sync.Mutex
+}
+
+func (n *ntlmTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	// an Authorization header explicitly provided takes precedence over the NTLM credentials
+	if r.Header.Get("Authorization") != "" {
+		return n.decorated.RoundTrip(r)
+	}
+
+	body, err := bufferBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	host := r.URL.Scheme + "://" + r.URL.Host
+
+	if connection, ok := n.takeConnection(host); ok {
+		res, err := n.decorated.RoundTrip(withPinnedTransport(withBody(r, body), connection))
+		if err != nil {
+			closeConnection(connection)
+			return nil, err
+		}
+
+		if !requiresNTLM(res) {
+			return n.releasingConnection(res, host, connection), nil
+		}
+
+		// the connection has been closed in the meantime, the new one is authenticated again
+		drain(res)
+
+		return n.handshake(r, body, host, connection)
+	}
+
+	res, err := n.decorated.RoundTrip(withBody(r, body))
+	if err != nil || !requiresNTLM(res) {
+		return res, err
+	}
+
+	drain(res)
+
+	return n.handshake(r, body, host, n.connections.newConnection())
+}
+
+// handshake sends the negotiate and the authenticate messages through connection, the connection is released once
+// the body of the response is closed: it is reused by the following requests only when authenticated
+func (n *ntlmTransportDecorator) handshake(
+	r *http.Request,
+	body []byte,
+	host string,
+	connection http.RoundTripper,
+) (*http.Response, error) {
+	negotiateMessage, err := ntlmssp.NewNegotiateMessage(n.domain, "")
+	if err != nil {
+		closeConnection(connection)
+		return nil, err
+	}
+
+	negotiate := withPinnedTransport(withBody(r, body), connection)
+	negotiate.Header.Set("Authorization", "NTLM "+base64.StdEncoding.EncodeToString(negotiateMessage))
+
+	res, err := n.decorated.RoundTrip(negotiate)
+	if err != nil {
+		closeConnection(connection)
+		return nil, err
+	}
+
+	challenge, ok := ntlmChallengeFromHeader(res.Header)
+	if res.StatusCode != http.StatusUnauthorized || !ok {
+		return closingConnection(res, connection), nil
+	}
+
+	// the domain the credentials belong to is the one the server is part of, as told by the challenge
+	authenticateMessage, err := ntlmssp.ProcessChallenge(challenge, n.username, n.password, n.domain != "")
+	if err != nil {
+		return closingConnection(res, connection), nil
+	}
+
+	drain(res)
+
+	authenticate := withPinnedTransport(withBody(r, body), connection)
+	authenticate.Header.Set("Authorization", "NTLM "+base64.StdEncoding.EncodeToString(authenticateMessage))
+
+	res, err = n.decorated.RoundTrip(authenticate)
+	if err != nil {
+		closeConnection(connection)
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusUnauthorized {
+		return closingConnection(res, connection), nil
+	}
+
+	return n.releasingConnection(res, host, connection), nil
+}
+
+// takeConnection returns a connection to host authenticated already, if any, for the exclusive use of the request
+func (n *ntlmTransportDecorator) takeConnection(host string) (http.RoundTripper, bool) {
+	n.authenticatedMx.Lock()
+	defer n.authenticatedMx.Unlock()
+
+	connections := n.authenticated[host]
+	if len(connections) == 0 {
+		return nil, false
+	}
+
+	connection := connections[len(connections)-1]
+	n.authenticated[host] = connections[:len(connections)-1]
+
+	return connection, true
+}
+
+// releasingConnection makes the authenticated connection available to the following requests once the body
+// of the response is closed
+func (n *ntlmTransportDecorator) releasingConnection(
+	res *http.Response,
+	host string,
+	connection http.RoundTripper,
+) *http.Response {
+	res.Body = &releasingBody{ReadCloser: res.Body, release: func() {
+		n.authenticatedMx.Lock()
+		defer n.authenticatedMx.Unlock()
+
+		n.authenticated[host] = append(n.authenticated[host], connection)
+	}}
+
+	return res
+}
+
+// closingConnection closes the connection once the body of the response is closed
+func closingConnection(res *http.Response, connection http.RoundTripper) *http.Response {
+	res.Body = &releasingBody{ReadCloser: res.Body, release: func() { closeConnection(connection) }}
+
+	return res
+}
+
+func closeConnection(connection http.RoundTripper) {
+	if c, ok := connection.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// requiresNTLM returns true when the response is a 401 offering NTLM among the authentication schemes
+func requiresNTLM(res *http.Response) bool {
+	if res.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	for _, value := range res.Header[http.CanonicalHeaderKey("WWW-Authenticate")] {
+		scheme := strings.Fields(value)
+		if len(scheme) > 0 && strings.EqualFold(scheme[0], "NTLM") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ntlmChallengeFromHeader returns the challenge message sent via the WWW-Authenticate header, if any
+func ntlmChallengeFromHeader(header http.Header) ([]byte, bool) {
+	for _, value := range header[http.CanonicalHeaderKey("WWW-Authenticate")] {
+		if !strings.HasPrefix(value, "NTLM ") {
+			continue
+		}
+
+		challenge, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[len("NTLM "):]))
+		if err != nil {
+			return nil, false
+		}
+
+		return challenge, true
+	}
+
+	return nil, false
+}
+
+// bufferBody reads the body of the request, so that it can be sent once per step of the handshake
+func bufferBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+
+	defer r.Body.Close()
+
+	return ioutil.ReadAll(r.Body)
+}
+
+// withBody returns a copy of the request having the given body
+func withBody(r *http.Request, body []byte) *http.Request {
+	c := r.Clone(r.Context())
+
+	if body != nil {
+		c.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	return c
+}
+
+// drain reads and closes the body of the response, so that its connection is reused by the next step
+// of the handshake
+func drain(res *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+}
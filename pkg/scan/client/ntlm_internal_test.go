@@ -0,0 +1,319 @@
+package client
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/md4" //nolint:staticcheck
+)
+
+func TestDecorateTransportNTLMShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithNTLMDecorator(nil, newTestPinnedTransport(), "domain", "user", "password")
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportNTLMShouldFailWithNilPinnedTransport(t *testing.T) {
+	transport, err := decorateTransportWithNTLMDecorator(http.DefaultTransport, nil, "domain", "user", "password")
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestNTLMShouldPerformTheHandshakeWhenTheServerRequiresIt(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		newNTLMHandler(t, "Domain", "User", "Password", "my_body"),
+	)
+	defer testServer.Close()
+
+	sut := newTestNTLMDecorator(t, "Domain", "User", "Password")
+
+	req, err := http.NewRequest(http.MethodPost, testServer.URL+"/home", strings.NewReader("my_body"))
+	assert.NoError(t, err)
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.NoError(t, res.Body.Close())
+
+	// the anonymous request, the negotiate and the authenticate ones
+	assert.Equal(t, 3, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/home", r.URL.Path)
+	})
+}
+
+func TestNTLMShouldReuseTheAuthenticatedConnection(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(newNTLMHandler(t, "Domain", "User", "Password", ""))
+	defer testServer.Close()
+
+	sut := newTestNTLMDecorator(t, "Domain", "User", "Password")
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/home", nil)
+		assert.NoError(t, err)
+
+		res, err := sut.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		_, _ = ioutil.ReadAll(res.Body)
+		assert.NoError(t, res.Body.Close())
+	}
+
+	// the handshake is performed only by the first request
+	assert.Equal(t, 5, serverAssertion.Len())
+}
+
+func TestNTLMShouldAuthenticateTheConcurrentRequests(t *testing.T) {
+	const requests = 20
+
+	testServer, serverAssertion := test.NewServerWithAssertion(newNTLMHandler(t, "Domain", "User", "Password", ""))
+	defer testServer.Close()
+
+	sut := newTestNTLMDecorator(t, "Domain", "User", "Password")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			req, err := http.NewRequest(http.MethodGet, testServer.URL+"/home", nil)
+			assert.NoError(t, err)
+
+			res, err := sut.RoundTrip(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+
+			_, _ = ioutil.ReadAll(res.Body)
+			assert.NoError(t, res.Body.Close())
+		}()
+	}
+
+	wg.Wait()
+
+	assert.True(t, serverAssertion.Len() >= requests)
+}
+
+func TestNTLMShouldReturnTheUnauthorizedResponseForWrongCredentials(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(newNTLMHandler(t, "Domain", "User", "Password", ""))
+	defer testServer.Close()
+
+	sut := newTestNTLMDecorator(t, "Domain", "User", "WrongPassword")
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/home", nil)
+	assert.NoError(t, err)
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.NoError(t, res.Body.Close())
+
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestNTLMShouldNotPerformTheHandshakeWhenTheServerDoesNotRequireIt(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("WWW-Authenticate", "Basic realm=\"mysite\"")
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer testServer.Close()
+
+	sut := newTestNTLMDecorator(t, "Domain", "User", "Password")
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/home", nil)
+	assert.NoError(t, err)
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.NoError(t, res.Body.Close())
+
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestNTLMShouldNotOverrideTheAuthorizationHeader(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("WWW-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer testServer.Close()
+
+	sut := newTestNTLMDecorator(t, "Domain", "User", "Password")
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/home", nil)
+	assert.NoError(t, err)
+
+	req.Header.Set("Authorization", "Bearer mytoken")
+
+	res, err := sut.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.NoError(t, res.Body.Close())
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+	})
+}
+
+func newTestPinnedTransport() *pinnedTransport {
+	return newPinnedTransport(http.DefaultTransport.(*http.Transport).Clone())
+}
+
+func newTestNTLMDecorator(t *testing.T, domain, username, password string) *ntlmTransportDecorator {
+	connections := newTestPinnedTransport()
+
+	sut, err := decorateTransportWithNTLMDecorator(connections, connections, domain, username, password)
+	assert.NoError(t, err)
+
+	return sut
+}
+
+// newNTLMHandler returns a handler authenticating the connections via NTLMv2, like IIS does: the handshake must be
+// performed on a single connection, then the following requests sent through it are authenticated. The body of
+// every request is expected to be the given one.
+func newNTLMHandler(t *testing.T, domain, username, password, expectedBody string) http.HandlerFunc {
+	const (
+		negotiateMessageType    = 1
+		challengeMessageType    = 2
+		authenticateMessageType = 3
+		// unicode, request target, NTLM, always sign, extended session security, target info, 128 and 56 bits
+		challengeFlags = 0xa0888205
+	)
+
+	serverChallenge := mustDecodeHex(t, "0123456789abcdef")
+	targetName := encodeUTF16(domain)
+	targetInfo := mustDecodeHex(t, "02000c0044006f006d00610069006e0000000000")
+
+	var (
+		// the state of each connection, by remote address: challenged or authenticated
+		connections   = make(map[string]string)
+		connectionsMx sync.Mutex
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedBody, string(body))
+
+		connectionsMx.Lock()
+		defer connectionsMx.Unlock()
+
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			if connections[r.RemoteAddr] == "authenticated" {
+				return
+			}
+
+			w.Header().Add("WWW-Authenticate", "Negotiate")
+			w.Header().Add("WWW-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		message, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authorization, "NTLM "))
+		assert.NoError(t, err)
+
+		switch binary.LittleEndian.Uint32(message[8:]) {
+		case negotiateMessageType:
+			challenge := make([]byte, 48)
+			copy(challenge, "NTLMSSP\x00")
+			binary.LittleEndian.PutUint32(challenge[8:], challengeMessageType)
+			binary.LittleEndian.PutUint16(challenge[12:], uint16(len(targetName)))
+			binary.LittleEndian.PutUint16(challenge[14:], uint16(len(targetName)))
+			binary.LittleEndian.PutUint32(challenge[16:], uint32(len(challenge)))
+			binary.LittleEndian.PutUint32(challenge[20:], challengeFlags)
+			copy(challenge[24:], serverChallenge)
+			binary.LittleEndian.PutUint16(challenge[40:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint16(challenge[42:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint32(challenge[44:], uint32(len(challenge)+len(targetName)))
+			challenge = append(challenge, targetName...)
+			challenge = append(challenge, targetInfo...)
+
+			connections[r.RemoteAddr] = "challenged"
+
+			w.Header().Set("WWW-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(challenge))
+			w.WriteHeader(http.StatusUnauthorized)
+		case authenticateMessageType:
+			ntResponse := ntlmMessageField(message, 20)
+			assert.Equal(t, encodeUTF16(domain), ntlmMessageField(message, 28))
+			assert.Equal(t, encodeUTF16(username), ntlmMessageField(message, 36))
+
+			proof := hmacMD5(ntowfv2(domain, username, password), append(serverChallenge, ntResponse[16:]...))
+			if connections[r.RemoteAddr] != "challenged" || !bytes.Equal(proof, ntResponse[:16]) {
+				delete(connections, r.RemoteAddr)
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			connections[r.RemoteAddr] = "authenticated"
+		default:
+			t.Errorf("unexpected NTLM message %v", message)
+		}
+	})
+}
+
+// ntowfv2 returns the NTLMv2 hash of the credentials
+func ntowfv2(domain, username, password string) []byte {
+	h := md4.New()
+	_, _ = h.Write(encodeUTF16(password))
+
+	return hmacMD5(h.Sum(nil), encodeUTF16(strings.ToUpper(username)+domain))
+}
+
+func hmacMD5(key, data []byte) []byte {
+	h := hmac.New(md5.New, key)
+	_, _ = h.Write(data)
+
+	return h.Sum(nil)
+}
+
+func encodeUTF16(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+
+	b := make([]byte, len(encoded)*2)
+	for i, v := range encoded {
+		binary.LittleEndian.PutUint16(b[i*2:], v)
+	}
+
+	return b
+}
+
+func ntlmMessageField(message []byte, offset int) []byte {
+	length := int(binary.LittleEndian.Uint16(message[offset:]))
+	start := int(binary.LittleEndian.Uint32(message[offset+4:]))
+
+	return message[start : start+length]
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+
+	return b
+}
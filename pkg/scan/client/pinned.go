@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func newPinnedTransport(shared *http.Transport) *pinnedTransport {
+	return &pinnedTransport{shared: shared}
+}
+
+// pinnedTransport performs the requests through the connection pinned to their context (see withPinnedTransport),
+// if any, otherwise through the connections shared by all the requests. It allows a decorator to send several
+// requests through the same connection (EG the steps of the NTLM handshake) while the other decorators are still
+// applied to them.
+type pinnedTransport struct {
+	shared *http.Transport
+}
+
+type pinnedTransportKey struct{}
+
+func (p *pinnedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if connection, ok := r.Context().Value(pinnedTransportKey{}).(http.RoundTripper); ok {
+		return connection.RoundTrip(r)
+	}
+
+	return p.shared.RoundTrip(r)
+}
+
+// newConnection returns a transport keeping at most one connection to each host, configured like the shared one
+func (p *pinnedTransport) newConnection() http.RoundTripper {
+	connection := p.shared.Clone()
+	connection.MaxConnsPerHost = 1
+	connection.MaxIdleConnsPerHost = 1
+
+	return connection
+}
+
+// withPinnedTransport returns a copy of the request that will be performed through connection (see pinnedTransport)
+func withPinnedTransport(r *http.Request, connection http.RoundTripper) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), pinnedTransportKey{}, connection))
+}
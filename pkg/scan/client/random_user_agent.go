@@ -0,0 +1,74 @@
+package client
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+)
+
+// BrowserUserAgents is the pool of user agents of common browsers used when rotating the user agent
+var BrowserUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/129.0.0.0 Safari/537.36 Edg/129.0.0.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) " +
+		"Version/18.0 Safari/605.1.15",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.7; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 18_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) " +
+		"Version/18.0 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/129.0.0.0 Mobile Safari/537.36",
+}
+
+func decorateTransportWithRandomUserAgentDecorator(
+	decorated http.RoundTripper,
+	userAgents []string,
+	source rand.Source,
+) (*randomUserAgentTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if len(userAgents) == 0 {
+		return nil, errors.New("at least one user agent is required")
+	}
+
+	if source == nil {
+		return nil, errors.New("source is nil")
+	}
+
+	return &randomUserAgentTransportDecorator{
+		decorated:  decorated,
+		userAgents: userAgents,
+		rand:       rand.New(source), //nolint:gosec
+	}, nil
+}
+
+// randomUserAgentTransportDecorator sets on every request a user agent picked randomly from the pool
+type randomUserAgentTransportDecorator struct {
+	decorated  http.RoundTripper
+	userAgents []string
+
+	rand   *rand.Rand
+	randMx sync.Mutex
+}
+
+func (u *randomUserAgentTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.Header.Set("User-Agent", u.nextUserAgent())
+
+	return u.decorated.RoundTrip(r)
+}
+
+func (u *randomUserAgentTransportDecorator) nextUserAgent() string {
+	u.randMx.Lock()
+	defer u.randMx.Unlock()
+
+	return u.userAgents[u.rand.Intn(len(u.userAgents))]
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportRandomUserAgentShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithRandomUserAgentDecorator(nil, BrowserUserAgents, rand.NewSource(1))
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportRandomUserAgentShouldFailWithoutUserAgents(t *testing.T) {
+	transport, err := decorateTransportWithRandomUserAgentDecorator(http.DefaultTransport, nil, rand.NewSource(1))
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestRandomUserAgentShouldBePickedFromThePool(t *testing.T) {
+	transport, err := decorateTransportWithRandomUserAgentDecorator(
+		http.DefaultTransport,
+		BrowserUserAgents,
+		rand.NewSource(42),
+	)
+	assert.NoError(t, err)
+
+	userAgents := make(map[string]struct{})
+
+	for i := 0; i < 100; i++ {
+		userAgent := transport.nextUserAgent()
+
+		assert.Contains(t, BrowserUserAgents, userAgent)
+
+		userAgents[userAgent] = struct{}{}
+	}
+
+	assert.True(t, len(userAgents) > 1, "the user agent is expected to vary")
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+func decorateTransportWithRateLimitDecorator(
+	decorated http.RoundTripper,
+	requestsPerSecond int,
+) (*rateLimitTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if requestsPerSecond <= 0 {
+		return nil, errors.New("requests per second must be greater than 0")
+	}
+
+	return &rateLimitTransportDecorator{
+		decorated: decorated,
+		limiter:   rate.NewLimiter(rate.Limit(requestsPerSecond), 1),
+	}, nil
+}
+
+// rateLimitTransportDecorator shares the same limiter across all the requests
+// going through it, so the aggregate rate never exceeds the configured one
+type rateLimitTransportDecorator struct {
+	decorated http.RoundTripper
+	limiter   *rate.Limiter
+}
+
+func (l *rateLimitTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := l.limiter.Wait(r.Context()); err != nil {
+		return nil, err
+	}
+
+	return l.decorated.RoundTrip(r)
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportRateLimitShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithRateLimitDecorator(nil, 10)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportRateLimitShouldFailWithInvalidRate(t *testing.T) {
+	transport, err := decorateTransportWithRateLimitDecorator(http.DefaultTransport, 0)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
@@ -27,6 +27,12 @@ type requestCacheTransportDecorator struct {
 }
 
 func (u *requestCacheTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	// the request is the result of following a redirect: many paths may redirect to the same location
+	// and the responses for all of them should be available
+	if r.Response != nil {
+		return u.decorated.RoundTrip(r)
+	}
+
 	key := u.keyForRequest(r)
 
 	_, found := u.requestMap.Load(key)
@@ -39,6 +45,8 @@ func (u *requestCacheTransportDecorator) RoundTrip(r *http.Request) (*http.Respo
 	return u.decorated.RoundTrip(r)
 }
 
+// keyForRequest identifies the request by its method, host, path and query string (the requests differing only by
+// the query string are not redundant)
 func (u *requestCacheTransportDecorator) keyForRequest(r *http.Request) string {
-	return fmt.Sprintf("%s~%s~%s", r.Method, r.Host, r.URL.Path)
+	return fmt.Sprintf("%s~%s~%s", r.Method, r.Host, r.URL.RequestURI())
 }
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func decorateTransportWithRetryDecorator(
+	decorated http.RoundTripper,
+	retries int,
+	wait time.Duration,
+	logger *logrus.Logger,
+) (*retryTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if retries < 0 {
+		return nil, errors.New("retries cannot be negative")
+	}
+
+	if logger == nil {
+		return nil, errors.New("logger is nil")
+	}
+
+	return &retryTransportDecorator{decorated: decorated, retries: retries, wait: wait, logger: logger}, nil
+}
+
+// retryTransportDecorator retries the requests failing because of a network error or a 5xx response,
+// waiting before each retry twice the time waited for the previous one; each attempt has its own timeout, which
+// doesn't include the waits (see timeoutTransportDecorator)
+type retryTransportDecorator struct {
+	decorated http.RoundTripper
+	retries   int
+	wait      time.Duration
+	logger    *logrus.Logger
+}
+
+func (d *retryTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	l := d.logger.WithFields(logrus.Fields{
+		"method": r.Method,
+		"url":    r.URL.String(),
+	})
+
+	for attempt := 0; ; attempt++ {
+		res, err := d.decorated.RoundTrip(r)
+		if !shouldRetry(res, err) {
+			return res, err
+		}
+
+		if attempt >= d.retries {
+			l.WithError(err).WithField("retries", d.retries).Warn("giving up on request after retrying")
+			return res, err
+		}
+
+		if res != nil {
+			_ = res.Body.Close() //nolint:errcheck
+		}
+
+		wait := d.wait * time.Duration(1<<uint(attempt))
+
+		l.WithError(err).WithFields(logrus.Fields{
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}).Debug("retrying request")
+
+		if err := d.prepareForRetry(r, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (d *retryTransportDecorator) prepareForRetry(r *http.Request, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-r.Context().Done():
+		return r.Context().Err()
+	case <-timer.C:
+	}
+
+	return rewindBody(r)
+}
+
+// rewindBody replaces the body of the request, already read when it has been performed, with a new copy of it
+func rewindBody(r *http.Request) error {
+	if r.GetBody == nil {
+		return nil
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return err
+	}
+
+	r.Body = body
+
+	return nil
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return res.StatusCode >= http.StatusInternalServerError
+}
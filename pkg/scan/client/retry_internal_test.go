@@ -0,0 +1,28 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportRetryShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithRetryDecorator(nil, 1, time.Millisecond, logrus.New())
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportRetryShouldFailWithNilLogger(t *testing.T) {
+	transport, err := decorateTransportWithRetryDecorator(http.DefaultTransport, 1, time.Millisecond, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportRetryShouldFailWithNegativeRetries(t *testing.T) {
+	transport, err := decorateTransportWithRetryDecorator(http.DefaultTransport, -1, time.Millisecond, logrus.New())
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+func decorateTransportWithTimeoutDecorator(
+	decorated http.RoundTripper,
+	timeout time.Duration,
+) (*timeoutTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+
+	return &timeoutTransportDecorator{decorated: decorated, timeout: timeout}, nil
+}
+
+// timeoutTransportDecorator limits the time of each request going through it, until the body of its response
+// is closed. It replaces http.Client.Timeout, which would also count the time the other decorators wait before
+// the request is performed (EG for the rate limit, the delay or a slot of the host) and between its retries,
+// so it must decorate the transport directly.
+type timeoutTransportDecorator struct {
+	decorated http.RoundTripper
+	timeout   time.Duration
+}
+
+func (d *timeoutTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(r.Context(), d.timeout)
+
+	res, err := d.decorated.RoundTrip(r.WithContext(ctx))
+	if err != nil {
+		timedOut := hasTimedOut(r.Context(), ctx)
+
+		cancel()
+
+		if timedOut {
+			return nil, timeoutError{phase: "awaiting headers"}
+		}
+
+		return nil, err
+	}
+
+	res.Body = &timeoutBody{ReadCloser: res.Body, parent: r.Context(), ctx: ctx, cancel: cancel}
+
+	return res, nil
+}
+
+// hasTimedOut returns true when ctx exceeded the timeout, rather than being canceled together with its parent
+func hasTimedOut(parent, ctx context.Context) bool {
+	return parent.Err() == nil && ctx.Err() == context.DeadlineExceeded
+}
+
+// timeoutBody releases the timeout of the request once closed
+type timeoutBody struct {
+	io.ReadCloser
+
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && hasTimedOut(b.parent, b.ctx) {
+		return n, timeoutError{phase: "reading body"}
+	}
+
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+// timeoutError is worded as the errors of http.Client.Timeout, so that the timed out requests are reported as before
+type timeoutError struct {
+	phase string
+}
+
+func (e timeoutError) Error() string {
+	return "net/http: request canceled (Client.Timeout exceeded while " + e.phase + ")"
+}
+
+func (e timeoutError) Timeout() bool {
+	return true
+}
+
+func (e timeoutError) Temporary() bool {
+	return true
+}
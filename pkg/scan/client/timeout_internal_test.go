@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportTimeoutShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithTimeoutDecorator(nil, time.Second)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportTimeoutShouldFailWithInvalidTimeout(t *testing.T) {
+	transport, err := decorateTransportWithTimeoutDecorator(http.DefaultTransport, 0)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestTimeoutShouldFailTheRequestsAwaitingTheHeadersTooLong(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}),
+	)
+	defer testServer.Close()
+
+	transport, err := decorateTransportWithTimeoutDecorator(http.DefaultTransport, 10*time.Millisecond)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+	assert.NoError(t, err)
+
+	res, err := transport.RoundTrip(req) //nolint:bodyclose
+	assert.Nil(t, res)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Client.Timeout exceeded while awaiting headers")
+
+	netErr, ok := err.(net.Error)
+	assert.True(t, ok)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestTimeoutShouldFailTheRequestsReadingTheBodyTooLong(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+		}),
+	)
+	defer testServer.Close()
+
+	transport, err := decorateTransportWithTimeoutDecorator(http.DefaultTransport, 50*time.Millisecond)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+	assert.NoError(t, err)
+
+	res, err := transport.RoundTrip(req)
+	assert.NoError(t, err)
+
+	defer res.Body.Close() //nolint:errcheck
+
+	_, err = ioutil.ReadAll(res.Body)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Client.Timeout exceeded while reading body")
+}
+
+func TestTimeoutShouldNotReportTheCanceledRequestsAsTimedOut(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}),
+	)
+	defer testServer.Close()
+
+	transport, err := decorateTransportWithTimeoutDecorator(http.DefaultTransport, time.Second)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, testServer.URL, nil)
+	assert.NoError(t, err)
+
+	res, err := transport.RoundTrip(req) //nolint:bodyclose
+	assert.Nil(t, res)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "Client.Timeout")
+}
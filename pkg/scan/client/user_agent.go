@@ -19,7 +19,10 @@ type userAgentTransportDecorator struct {
 }
 
 func (u *userAgentTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
-	r.Header.Set("User-Agent", u.userAgent)
+	// a user agent provided via the request headers is preserved unless one was explicitly configured
+	if u.userAgent != "" || r.Header.Get("User-Agent") == "" {
+		r.Header.Set("User-Agent", u.userAgent)
+	}
 
 	return u.decorated.RoundTrip(r)
 }
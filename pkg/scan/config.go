@@ -3,23 +3,120 @@ package scan
 import (
 	"net/http"
 	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
+	"github.com/stefanoj3/dirstalk/pkg/scan/template"
 )
 
 // Config represents the configuration needed to perform a scan
 type Config struct {
 	DictionaryPath                      string
 	DictionaryTimeoutInMilliseconds     int
+	Shuffle                             bool
+	Seed                                int64
+	Extensions                          []string
+	AppendQueries                       []string
+	TrailingSlash                       string
 	HTTPMethods                         []string
 	HTTPStatusesToIgnore                []int
+	HeadFirst                           bool
+	HTTPStatusesToInclude               []int
+	HTTPStatusesToExclude               []int
+	ContentLengthsToIgnore              []int64
+	ContentLengthRangesToIgnore         []ContentLengthRange
+	MatchRegex                          *regexp.Regexp
+	MaxBodySize                         int64
+	HeaderMatchers                      []HeaderMatcher
+	ContentTypesToMatch                 []*regexp.Regexp
+	ContentTypesToExclude               []*regexp.Regexp
+	FilterTimeAbove                     time.Duration
+	DedupResults                        bool
 	Threads                             int
+	PerHostThreads                      int
+	MaxIdleConns                        int
+	MaxConnsPerHost                     int
 	TimeoutInMilliseconds               int
 	CacheRequests                       bool
+	FollowRedirects                     bool
+	MaxRedirects                        int
 	ScanDepth                           int
+	RecursionStrategy                   string
+	Scope                               string
+	ScopeDomains                        []string
+	ExcludePaths                        urlpath.Patterns
+	ShouldProbeBackups                  bool
+	BackupSuffixes                      []string
 	Socks5Url                           *url.URL
+	HTTPProxyUrl                        *url.URL
+	ReplayProxyUrl                      *url.URL
 	UserAgent                           string
+	UserAgents                          []string
 	UseCookieJar                        bool
 	Cookies                             []*http.Cookie
+	FileCookies                         []*http.Cookie
 	Headers                             map[string]string
+	HostHeader                          string
+	VHostScan                           bool
+	RequestTemplate                     *template.Template
+	SecondDictionaryPath                string
+	TemplateMode                        string
+	BasicAuthUsername                   string
+	BasicAuthPassword                   string
+	NTLMDomain                          string
+	NTLMUsername                        string
+	NTLMPassword                        string
+	BearerToken                         string
+	Body                                []byte
+	RequestsPerSecond                   int
+	DelayInMilliseconds                 int
+	JitterPercentage                    int
+	Retries                             int
+	RetryWaitInMilliseconds             int
+	ShouldSkipAdaptiveThrottle          bool
 	Out                                 string
+	OutJSON                             string
+	OutCSV                              string
+	OutHTML                             string
+	JSONLines                           bool
+	WebhookURL                          string
+	WebhookStatuses                     []int
+	SlackWebhookURL                     string
+	SaveResponsesDir                    string
+	MaxSaveBytes                        int64
+	ResumeFrom                          string
 	ShouldSkipSSLCertificatesValidation bool
+	ShouldSkipTLSHostnameVerification   bool
+	ClientCertificatePath               string
+	ClientKeyPath                       string
+	CACertificatePath                   string
+	ForceHTTP2                          bool
+	DisableHTTP2                        bool
+	Resolver                            string
+	PreferIPv6                          bool
+	ShouldSkipWildcardDetection         bool
+	BaselineRequest                     bool
+	DiffThreshold                       int64
+	OnWAF                               string
+	WAFWindow                           int
+	WAFThreshold                        int
+	WAFPause                            time.Duration
+	ShouldHideProgress                  bool
+	ProgressInterval                    time.Duration
+	ShouldHideBanner                    bool
+	ShouldDisableColors                 bool
+	Quiet                               bool
+	DryRun                              bool
+	SkipConfirmation                    bool
+	StdinTargets                        bool
+	MaxRequests                         int64
+	MaxDuration                         time.Duration
+	ExitOnMatch                         bool
+	WatchInterval                       time.Duration
+	WatchDiff                           bool
+
+	// SecondDictionary contains the words replacing the template.SecondPlaceholder of the RequestTemplate,
+	// it is loaded from SecondDictionaryPath before starting the scan
+	SecondDictionary []string
 }
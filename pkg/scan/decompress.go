@@ -0,0 +1,108 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// decompressBody makes the body of the responses encoded with gzip or deflate readable decompressed, like the
+// http package does for the gzip responses to the requests it compressed: the ContentLength is set to -1, as the
+// one reported by the server is the compressed length, and Uncompressed is set. The headers are left untouched.
+// When the body turns out not to be encoded as declared it is left readable as it was sent.
+func decompressBody(l *logrus.Entry, res *http.Response) {
+	if res.Uncompressed {
+		return
+	}
+
+	var newDecoder func(*bufio.Reader) (io.Reader, error)
+
+	switch strings.ToLower(strings.TrimSpace(res.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		newDecoder = func(r *bufio.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		}
+	case "deflate":
+		newDecoder = newDeflateDecoder
+	default:
+		return
+	}
+
+	raw := &recordingReader{Reader: res.Body, recording: true}
+	body := bufio.NewReader(raw)
+
+	// the responses without a body (EG the ones to HEAD requests) have nothing to decompress
+	if _, err := body.Peek(1); err != nil {
+		return
+	}
+
+	decoder, err := newDecoder(body)
+	if err != nil {
+		l.WithError(err).Warn("failed to decompress the response body")
+
+		// the bytes consumed by the buffer and by the decoder are read again, followed by the rest of the body
+		res.Body = &readerBody{Reader: io.MultiReader(&raw.recorded, res.Body), body: res.Body}
+
+		return
+	}
+
+	raw.stopRecording()
+
+	res.Body = &readerBody{Reader: decoder, body: res.Body}
+	res.ContentLength = -1
+	res.Uncompressed = true
+}
+
+// newDeflateDecoder returns a decoder of the deflate encoding, which is meant to be a zlib stream but some servers
+// send the raw deflate stream instead
+func newDeflateDecoder(r *bufio.Reader) (io.Reader, error) {
+	header, err := r.Peek(2)
+	if err == nil && isZlibHeader(header) {
+		return zlib.NewReader(r)
+	}
+
+	return flate.NewReader(r), nil
+}
+
+// isZlibHeader reports whether the bytes are a zlib header: the deflate compression method and a valid checksum
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// recordingReader keeps a copy of the bytes read, until stopped
+type recordingReader struct {
+	io.Reader
+	recorded  bytes.Buffer
+	recording bool
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if r.recording {
+		_, _ = r.recorded.Write(p[:n])
+	}
+
+	return n, err
+}
+
+func (r *recordingReader) stopRecording() {
+	r.recording = false
+	r.recorded = bytes.Buffer{}
+}
+
+// readerBody reads from Reader and closes the original body of the response
+type readerBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *readerBody) Close() error {
+	return b.body.Close()
+}
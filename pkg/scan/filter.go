@@ -1,5 +1,27 @@
 package scan
 
+import "regexp"
+
 type ResultFilter interface {
 	ShouldIgnore(Result) bool
 }
+
+// ResultFilterFunc allows to use a function as a ResultFilter, it returns true for the results to ignore
+type ResultFilterFunc func(Result) bool
+
+func (f ResultFilterFunc) ShouldIgnore(result Result) bool {
+	return f(result)
+}
+
+// ContentLengthRange represents a range of lengths of a response body, both ends included
+type ContentLengthRange struct {
+	From int64
+	To   int64
+}
+
+// HeaderMatcher represents a header a response must have, when Value is not nil at least one of the values of the
+// header must match it
+type HeaderMatcher struct {
+	Name  string
+	Value *regexp.Regexp
+}
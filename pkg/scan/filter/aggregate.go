@@ -0,0 +1,24 @@
+package filter
+
+import (
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+func NewAggregateResultFilter(filters ...scan.ResultFilter) AggregateResultFilter {
+	return AggregateResultFilter{filters: filters}
+}
+
+// AggregateResultFilter ignores the results that at least one of the filters it is composed of would ignore
+type AggregateResultFilter struct {
+	filters []scan.ResultFilter
+}
+
+func (f AggregateResultFilter) ShouldIgnore(result scan.Result) bool {
+	for _, resultFilter := range f.filters {
+		if resultFilter.ShouldIgnore(result) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,27 @@
+package filter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateResultFilter(t *testing.T) {
+	sut := filter.NewAggregateResultFilter(
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		filter.NewContentLengthResultFilter([]int64{1234}, nil),
+	)
+
+	assert.True(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusNotFound, ContentLength: 10}))
+	assert.True(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusOK, ContentLength: 1234}))
+	assert.False(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusOK, ContentLength: 10}))
+}
+
+func TestAggregateResultFilterWithoutFiltersShouldNotIgnoreAnything(t *testing.T) {
+	sut := filter.NewAggregateResultFilter()
+
+	assert.False(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusNotFound}))
+}
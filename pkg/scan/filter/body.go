@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"mime"
+	"regexp"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// utf8Charset is the name of the utf-8 encoding in the htmlindex, the bodies already encoded with it are matched
+// as they are
+const utf8Charset = "utf-8"
+
+func NewBodyRegexResultFilter(regex *regexp.Regexp) BodyRegexResultFilter {
+	return BodyRegexResultFilter{regex: regex}
+}
+
+// BodyRegexResultFilter ignores all the results having a body not matching the regex, only the part of the body
+// kept by the scanner is matched (see scan.WithBodyKept).
+// The body is decoded to utf-8 first, according to the charset of its Content-Type (see decodeBody).
+type BodyRegexResultFilter struct {
+	regex *regexp.Regexp
+}
+
+func (f BodyRegexResultFilter) ShouldIgnore(result scan.Result) bool {
+	return !f.regex.Match(decodeBody(result.Body, result.Header.Get("Content-Type")))
+}
+
+// decodeBody returns the body decoded to utf-8 from the charset of the content type (EG
+// `text/html; charset=ISO-8859-1`), the charsets are resolved as the browsers do (see
+// https://encoding.spec.whatwg.org/#names-and-labels); the body is returned as it is when the content type
+// has no charset or the charset is unknown
+func decodeBody(body []byte, contentType string) []byte {
+	if len(body) == 0 || contentType == "" {
+		return body
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["charset"] == "" {
+		return body
+	}
+
+	encoding, err := htmlindex.Get(params["charset"])
+	if err != nil {
+		return body
+	}
+
+	if name, err := htmlindex.Name(encoding); err != nil || name == utf8Charset {
+		return body
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return body
+	}
+
+	return decoded
+}
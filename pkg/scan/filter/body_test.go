@@ -0,0 +1,72 @@
+package filter_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyRegexResultFilter(t *testing.T) {
+	testCases := []struct {
+		regex          string
+		body           string
+		expectedResult bool
+	}{
+		{regex: "(?i)index of", body: "<title>Index of /backup</title>", expectedResult: false},
+		{regex: "(?i)index of", body: "<title>Welcome</title>", expectedResult: true},
+		{regex: "stack trace", body: "", expectedResult: true},
+		{regex: "^$", body: "", expectedResult: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.regex+" "+tc.body, func(t *testing.T) {
+			t.Parallel()
+
+			actual := filter.NewBodyRegexResultFilter(regexp.MustCompile(tc.regex)).
+				ShouldIgnore(scan.Result{Body: []byte(tc.body)})
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
+
+func TestBodyRegexResultFilterShouldMatchTheBodyDecodedFromItsCharset(t *testing.T) {
+	testCases := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedResult bool
+	}{
+		{name: "iso-8859-1", contentType: "text/html; charset=ISO-8859-1", body: "Caf\xe9 ferm\xe9", expectedResult: false},
+		{name: "quoted charset", contentType: `text/html; charset="latin1"`, body: "Caf\xe9 ferm\xe9", expectedResult: false},
+		{name: "shift_jis", contentType: "text/plain; charset=Shift_JIS", body: "\x83J\x83t\x83F", expectedResult: false},
+		{name: "utf-8", contentType: "text/html; charset=utf-8", body: "Café fermé", expectedResult: false},
+		{name: "no charset", contentType: "text/html", body: "Café fermé", expectedResult: false},
+		{name: "unknown charset", contentType: "text/html; charset=unknown", body: "Café fermé", expectedResult: false},
+		{name: "invalid content type", contentType: "text/html; charset", body: "Café fermé", expectedResult: false},
+		{name: "not decoded", contentType: "text/html", body: "Caf\xe9 ferm\xe9", expectedResult: true},
+	}
+
+	regex := regexp.MustCompile("(Café fermé|カフェ)")
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := filter.NewBodyRegexResultFilter(regex).ShouldIgnore(
+				scan.Result{
+					Body:   []byte(tc.body),
+					Header: http.Header{"Content-Type": []string{tc.contentType}},
+				},
+			)
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
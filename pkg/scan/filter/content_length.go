@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+func NewContentLengthResultFilter(
+	contentLengthsToIgnore []int64,
+	contentLengthRangesToIgnore []scan.ContentLengthRange,
+) ContentLengthResultFilter {
+	contentLengthsToIgnoreMap := make(map[int64]struct{}, len(contentLengthsToIgnore))
+	for _, contentLengthToIgnore := range contentLengthsToIgnore {
+		contentLengthsToIgnoreMap[contentLengthToIgnore] = struct{}{}
+	}
+
+	return ContentLengthResultFilter{
+		contentLengthsToIgnoreMap:   contentLengthsToIgnoreMap,
+		contentLengthRangesToIgnore: contentLengthRangesToIgnore,
+	}
+}
+
+// ContentLengthResultFilter ignores the results having a body of one of the specified lengths or
+// within one of the specified ranges
+type ContentLengthResultFilter struct {
+	contentLengthsToIgnoreMap   map[int64]struct{}
+	contentLengthRangesToIgnore []scan.ContentLengthRange
+}
+
+func (f ContentLengthResultFilter) ShouldIgnore(result scan.Result) bool {
+	// the length of the body is unknown, there is nothing to compare
+	if result.ContentLength < 0 {
+		return false
+	}
+
+	if _, found := f.contentLengthsToIgnoreMap[result.ContentLength]; found {
+		return true
+	}
+
+	for _, r := range f.contentLengthRangesToIgnore {
+		if result.ContentLength >= r.From && result.ContentLength <= r.To {
+			return true
+		}
+	}
+
+	return false
+}
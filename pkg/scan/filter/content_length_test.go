@@ -0,0 +1,81 @@
+package filter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentLengthResultFilter(t *testing.T) {
+	testCases := []struct {
+		contentLengthsToIgnore      []int64
+		contentLengthRangesToIgnore []scan.ContentLengthRange
+		result                      scan.Result
+		expectedResult              bool
+	}{
+		{
+			contentLengthsToIgnore: []int64{0, 1234},
+			result:                 scan.Result{ContentLength: 1234},
+			expectedResult:         true,
+		},
+		{
+			contentLengthsToIgnore: []int64{0, 1234},
+			result:                 scan.Result{ContentLength: 0},
+			expectedResult:         true,
+		},
+		{
+			contentLengthsToIgnore: []int64{0, 1234},
+			result:                 scan.Result{ContentLength: 1235},
+			expectedResult:         false,
+		},
+		{
+			contentLengthRangesToIgnore: []scan.ContentLengthRange{{From: 100, To: 200}},
+			result:                      scan.Result{ContentLength: 100},
+			expectedResult:              true,
+		},
+		{
+			contentLengthRangesToIgnore: []scan.ContentLengthRange{{From: 100, To: 200}},
+			result:                      scan.Result{ContentLength: 200},
+			expectedResult:              true,
+		},
+		{
+			contentLengthRangesToIgnore: []scan.ContentLengthRange{{From: 100, To: 200}},
+			result:                      scan.Result{ContentLength: 201},
+			expectedResult:              false,
+		},
+		{
+			contentLengthsToIgnore:      []int64{0},
+			contentLengthRangesToIgnore: []scan.ContentLengthRange{{From: 0, To: 200}},
+			result:                      scan.Result{ContentLength: -1},
+			expectedResult:              false,
+		},
+		{
+			result:         scan.Result{ContentLength: 10},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		scenario := fmt.Sprintf(
+			"ignored: %v, ignored ranges: %v, result: %d",
+			tc.contentLengthsToIgnore,
+			tc.contentLengthRangesToIgnore,
+			tc.result.ContentLength,
+		)
+
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual := filter.NewContentLengthResultFilter(
+				tc.contentLengthsToIgnore,
+				tc.contentLengthRangesToIgnore,
+			).ShouldIgnore(tc.result)
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package filter
+
+import (
+	"regexp"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+func NewContentTypeResultFilter(contentTypesToMatch, contentTypesToExclude []*regexp.Regexp) ContentTypeResultFilter {
+	return ContentTypeResultFilter{
+		contentTypesToMatch:   contentTypesToMatch,
+		contentTypesToExclude: contentTypesToExclude,
+	}
+}
+
+// ContentTypeResultFilter ignores the results whose Content-Type doesn't match any of the contentTypesToMatch
+// (when specified) and the ones whose Content-Type matches any of the contentTypesToExclude
+type ContentTypeResultFilter struct {
+	contentTypesToMatch   []*regexp.Regexp
+	contentTypesToExclude []*regexp.Regexp
+}
+
+func (f ContentTypeResultFilter) ShouldIgnore(result scan.Result) bool {
+	contentType := result.Header.Get("Content-Type")
+
+	if len(f.contentTypesToMatch) > 0 && !matchAny(f.contentTypesToMatch, contentType) {
+		return true
+	}
+
+	return matchAny(f.contentTypesToExclude, contentType)
+}
+
+func matchAny(regexes []*regexp.Regexp, s string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
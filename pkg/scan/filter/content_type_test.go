@@ -0,0 +1,83 @@
+package filter_test
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentTypeResultFilter(t *testing.T) {
+	html := regexp.MustCompile("(?i)text/html")
+	json := regexp.MustCompile("^application/(.+\\+)?json")
+	image := regexp.MustCompile("^image/")
+
+	testCases := []struct {
+		contentType           string
+		contentTypesToMatch   []*regexp.Regexp
+		contentTypesToExclude []*regexp.Regexp
+		expectedResult        bool
+	}{
+		{
+			contentType:    "text/html; charset=utf-8",
+			expectedResult: false,
+		},
+		{
+			contentType:         "text/html; charset=utf-8",
+			contentTypesToMatch: []*regexp.Regexp{html},
+			expectedResult:      false,
+		},
+		{
+			contentType:         "application/problem+json",
+			contentTypesToMatch: []*regexp.Regexp{html, json},
+			expectedResult:      false,
+		},
+		{
+			contentType:         "image/png",
+			contentTypesToMatch: []*regexp.Regexp{html, json},
+			expectedResult:      true,
+		},
+		{
+			contentType:         "",
+			contentTypesToMatch: []*regexp.Regexp{html},
+			expectedResult:      true,
+		},
+		{
+			contentType:           "image/png",
+			contentTypesToExclude: []*regexp.Regexp{image},
+			expectedResult:        true,
+		},
+		{
+			contentType:           "",
+			contentTypesToExclude: []*regexp.Regexp{image},
+			expectedResult:        false,
+		},
+		{
+			contentType:           "TEXT/HTML",
+			contentTypesToMatch:   []*regexp.Regexp{html},
+			contentTypesToExclude: []*regexp.Regexp{regexp.MustCompile("HTML")},
+			expectedResult:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(fmt.Sprintf("%s %v %v", tc.contentType, tc.contentTypesToMatch, tc.contentTypesToExclude), func(t *testing.T) {
+			t.Parallel()
+
+			result := scan.Result{Header: http.Header{}}
+			if tc.contentType != "" {
+				result.Header.Set("Content-Type", tc.contentType)
+			}
+
+			actual := filter.NewContentTypeResultFilter(tc.contentTypesToMatch, tc.contentTypesToExclude).
+				ShouldIgnore(result)
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+func NewDuplicateResultFilter() *DuplicateResultFilter {
+	return &DuplicateResultFilter{
+		resources: make(map[resourceIdentity]scan.Target),
+		reporters: make(map[string]scan.Target),
+		redirects: make(map[string]scan.Target),
+	}
+}
+
+// DuplicateResultFilter ignores the results of a resource already reported by another target: the ones having the
+// same final url (regardless of its trailing slash) and the same body, the redirects to an url already reported
+// and the results of an url a redirect already reported points to (EG /admin/ after /admin redirecting to it, in
+// either order). The first target reporting a resource keeps being accepted, so the filter can be invoked more than
+// once for the same result; it is safe for concurrent use.
+type DuplicateResultFilter struct {
+	mx sync.Mutex
+
+	// resources maps each resource reported to the target reporting it, reporters maps the urls reported and
+	// redirects the urls the redirects reported point to
+	resources map[resourceIdentity]scan.Target
+	reporters map[string]scan.Target
+	redirects map[string]scan.Target
+}
+
+type resourceIdentity struct {
+	url      string
+	bodyHash string
+}
+
+func (f *DuplicateResultFilter) ShouldIgnore(result scan.Result) bool {
+	resourceURL := normalizeResourceURL(result.URL)
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	location, isRedirect := redirectLocation(result)
+	if isRedirect {
+		if reporter, found := f.reporters[location]; found && reporter != result.Target {
+			return true
+		}
+	}
+
+	if redirecting, found := f.redirects[resourceURL]; found && redirecting != result.Target {
+		return true
+	}
+
+	identity := resourceIdentity{url: resourceURL, bodyHash: result.BodyHash}
+
+	if reporter, found := f.resources[identity]; found {
+		return reporter != result.Target
+	}
+
+	f.resources[identity] = result.Target
+
+	if _, found := f.reporters[resourceURL]; !found {
+		f.reporters[resourceURL] = result.Target
+	}
+
+	if _, found := f.redirects[location]; isRedirect && !found {
+		f.redirects[location] = result.Target
+	}
+
+	return false
+}
+
+// redirectLocation returns the normalized url the result redirects to, if any
+func redirectLocation(result scan.Result) (string, bool) {
+	if result.StatusCode < http.StatusMultipleChoices || result.StatusCode >= http.StatusBadRequest ||
+		result.Location == "" {
+		return "", false
+	}
+
+	location, err := result.URL.Parse(result.Location)
+	if err != nil {
+		return "", false
+	}
+
+	return normalizeResourceURL(*location), true
+}
+
+// normalizeResourceURL returns the url without its fragment and the trailing slash of its path, the host in lower case
+func normalizeResourceURL(u url.URL) string {
+	u.Fragment = ""
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+
+	return u.String()
+}
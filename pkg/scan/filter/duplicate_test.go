@@ -0,0 +1,107 @@
+package filter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateResultFilterShouldIgnoreTheSameResourceReportedByAnotherTarget(t *testing.T) {
+	sut := filter.NewDuplicateResultFilter()
+
+	admin := scan.Result{
+		Target:     scan.Target{Path: "admin", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/admin"),
+		BodyHash:   "hash",
+	}
+	assert.False(t, sut.ShouldIgnore(admin))
+	assert.False(t, sut.ShouldIgnore(admin), "the target reporting the resource first should keep being accepted")
+
+	adminWithSlash := scan.Result{
+		Target:     scan.Target{Path: "admin/", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/admin/"),
+		BodyHash:   "hash",
+	}
+	assert.True(t, sut.ShouldIgnore(adminWithSlash))
+
+	adminWithDifferentBody := adminWithSlash
+	adminWithDifferentBody.BodyHash = "other-hash"
+	assert.False(t, sut.ShouldIgnore(adminWithDifferentBody))
+
+	adminWithQuery := admin
+	adminWithQuery.Target.Query = "debug=1"
+	adminWithQuery.URL = *test.MustParseURL(t, "http://mysite/admin?debug=1")
+	assert.False(t, sut.ShouldIgnore(adminWithQuery))
+
+	// EG /adm following the redirect to /admin
+	redirected := admin
+	redirected.Target = scan.Target{Path: "adm", Method: http.MethodGet}
+	assert.True(t, sut.ShouldIgnore(redirected))
+}
+
+func TestDuplicateResultFilterShouldIgnoreTheRedirectsToAResourceAlreadyReported(t *testing.T) {
+	sut := filter.NewDuplicateResultFilter()
+
+	assert.False(t, sut.ShouldIgnore(scan.Result{
+		Target:     scan.Target{Path: "admin/", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/admin/"),
+		BodyHash:   "hash",
+	}))
+
+	redirect := scan.Result{
+		Target:     scan.Target{Path: "admin", Method: http.MethodGet},
+		StatusCode: http.StatusMovedPermanently,
+		URL:        *test.MustParseURL(t, "http://mysite/admin"),
+		Location:   "/admin/",
+		BodyHash:   "redirect-hash",
+	}
+	assert.True(t, sut.ShouldIgnore(redirect))
+
+	redirect.Location = "http://MYSITE/admin/#top"
+	assert.True(t, sut.ShouldIgnore(redirect))
+
+	redirect.Location = "/login"
+	assert.False(t, sut.ShouldIgnore(redirect))
+
+	notRedirect := redirect
+	notRedirect.StatusCode = http.StatusOK
+	notRedirect.Location = "/admin/"
+	notRedirect.Target.Path = "other"
+	notRedirect.URL = *test.MustParseURL(t, "http://mysite/other")
+	assert.False(t, sut.ShouldIgnore(notRedirect))
+}
+
+func TestDuplicateResultFilterShouldIgnoreTheResourceARedirectAlreadyReportedPointsTo(t *testing.T) {
+	sut := filter.NewDuplicateResultFilter()
+
+	redirect := scan.Result{
+		Target:     scan.Target{Path: "admin", Method: http.MethodGet},
+		StatusCode: http.StatusMovedPermanently,
+		URL:        *test.MustParseURL(t, "http://mysite/admin"),
+		Location:   "/admin/",
+		BodyHash:   "redirect-hash",
+	}
+	assert.False(t, sut.ShouldIgnore(redirect))
+	assert.False(t, sut.ShouldIgnore(redirect), "the redirect reported first should keep being accepted")
+
+	assert.True(t, sut.ShouldIgnore(scan.Result{
+		Target:     scan.Target{Path: "admin/", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/admin/"),
+		BodyHash:   "hash",
+	}))
+
+	assert.False(t, sut.ShouldIgnore(scan.Result{
+		Target:     scan.Target{Path: "login", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/login"),
+		BodyHash:   "hash",
+	}))
+}
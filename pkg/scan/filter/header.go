@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"net/http"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+func NewHeaderResultFilter(headerMatchers []scan.HeaderMatcher) HeaderResultFilter {
+	return HeaderResultFilter{headerMatchers: headerMatchers}
+}
+
+// HeaderResultFilter ignores all the results not matching every one of the header matchers
+type HeaderResultFilter struct {
+	headerMatchers []scan.HeaderMatcher
+}
+
+func (f HeaderResultFilter) ShouldIgnore(result scan.Result) bool {
+	for _, m := range f.headerMatchers {
+		if !matchHeader(m, result) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchHeader(m scan.HeaderMatcher, result scan.Result) bool {
+	values, found := result.Header[http.CanonicalHeaderKey(m.Name)]
+	if !found {
+		return false
+	}
+
+	if m.Value == nil {
+		return true
+	}
+
+	for _, value := range values {
+		if m.Value.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
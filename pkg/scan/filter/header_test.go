@@ -0,0 +1,65 @@
+package filter_test
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderResultFilter(t *testing.T) {
+	header := http.Header{
+		"Server":       []string{"nginx/1.17.4"},
+		"Set-Cookie":   []string{"a=1", "session=abc"},
+		"X-Powered-By": []string{"PHP/7.3"},
+	}
+
+	testCases := []struct {
+		headerMatchers []scan.HeaderMatcher
+		expectedResult bool
+	}{
+		{
+			headerMatchers: []scan.HeaderMatcher{{Name: "x-powered-by"}},
+			expectedResult: false,
+		},
+		{
+			headerMatchers: []scan.HeaderMatcher{{Name: "WWW-Authenticate"}},
+			expectedResult: true,
+		},
+		{
+			headerMatchers: []scan.HeaderMatcher{{Name: "Server", Value: regexp.MustCompile("^nginx")}},
+			expectedResult: false,
+		},
+		{
+			headerMatchers: []scan.HeaderMatcher{{Name: "Server", Value: regexp.MustCompile("apache")}},
+			expectedResult: true,
+		},
+		{
+			headerMatchers: []scan.HeaderMatcher{{Name: "Set-Cookie", Value: regexp.MustCompile("^session=")}},
+			expectedResult: false,
+		},
+		{
+			headerMatchers: []scan.HeaderMatcher{{Name: "Server"}, {Name: "WWW-Authenticate"}},
+			expectedResult: true,
+		},
+		{
+			headerMatchers: []scan.HeaderMatcher{},
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(fmt.Sprintf("%v", tc.headerMatchers), func(t *testing.T) {
+			t.Parallel()
+
+			actual := filter.NewHeaderResultFilter(tc.headerMatchers).ShouldIgnore(scan.Result{Header: header})
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
@@ -21,3 +21,22 @@ func (f HTTPStatusResultFilter) ShouldIgnore(result scan.Result) bool {
 	_, found := f.httpStatusesToIgnoreMap[result.StatusCode]
 	return found
 }
+
+func NewHTTPStatusToIncludeResultFilter(httpStatusesToInclude []int) HTTPStatusToIncludeResultFilter {
+	httpStatusesToIncludeMap := make(map[int]struct{}, len(httpStatusesToInclude))
+	for _, statusToInclude := range httpStatusesToInclude {
+		httpStatusesToIncludeMap[statusToInclude] = struct{}{}
+	}
+
+	return HTTPStatusToIncludeResultFilter{httpStatusesToIncludeMap: httpStatusesToIncludeMap}
+}
+
+// HTTPStatusToIncludeResultFilter ignores all the results having a status different from the ones specified
+type HTTPStatusToIncludeResultFilter struct {
+	httpStatusesToIncludeMap map[int]struct{}
+}
+
+func (f HTTPStatusToIncludeResultFilter) ShouldIgnore(result scan.Result) bool {
+	_, found := f.httpStatusesToIncludeMap[result.StatusCode]
+	return !found
+}
@@ -53,6 +53,43 @@ func TestHTTPStatusResultFilter(t *testing.T) {
 	}
 }
 
+func TestHTTPStatusToIncludeResultFilter(t *testing.T) {
+	testCases := []struct {
+		statusCodesToInclude []int
+		result               scan.Result
+		expectedResult       bool
+	}{
+		{
+			statusCodesToInclude: []int{http.StatusOK, http.StatusMovedPermanently},
+			result:               scan.Result{StatusCode: http.StatusOK},
+			expectedResult:       false,
+		},
+		{
+			statusCodesToInclude: []int{http.StatusOK, http.StatusMovedPermanently},
+			result:               scan.Result{StatusCode: http.StatusNotFound},
+			expectedResult:       true,
+		},
+		{
+			statusCodesToInclude: []int{},
+			result:               scan.Result{StatusCode: http.StatusOK},
+			expectedResult:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		scenario := fmt.Sprintf("included: %v, result: %d", tc.statusCodesToInclude, tc.result.StatusCode)
+
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual := filter.NewHTTPStatusToIncludeResultFilter(tc.statusCodesToInclude).ShouldIgnore(tc.result)
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
+
 func TestHTTPStatusResultFilterShouldWorkConcurrently(_ *testing.T) {
 	sut := filter.NewHTTPStatusResultFilter(nil)
 
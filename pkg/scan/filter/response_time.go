@@ -0,0 +1,21 @@
+package filter
+
+import (
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+func NewResponseTimeResultFilter(threshold time.Duration) ResponseTimeResultFilter {
+	return ResponseTimeResultFilter{threshold: threshold}
+}
+
+// ResponseTimeResultFilter ignores the results whose response time doesn't exceed the threshold, to find the
+// slow responses (EG of blind injections or heavy backend operations)
+type ResponseTimeResultFilter struct {
+	threshold time.Duration
+}
+
+func (f ResponseTimeResultFilter) ShouldIgnore(result scan.Result) bool {
+	return result.Duration <= f.threshold
+}
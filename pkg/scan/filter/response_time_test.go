@@ -0,0 +1,32 @@
+package filter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseTimeResultFilter(t *testing.T) {
+	testCases := []struct {
+		duration       time.Duration
+		expectedResult bool
+	}{
+		{duration: 0, expectedResult: true},
+		{duration: 500 * time.Millisecond, expectedResult: true},
+		{duration: time.Second, expectedResult: true},
+		{duration: time.Second + time.Millisecond, expectedResult: false},
+		{duration: 10 * time.Second, expectedResult: false},
+	}
+
+	sut := filter.NewResponseTimeResultFilter(time.Second)
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.duration.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expectedResult, sut.ShouldIgnore(scan.Result{Duration: tc.duration}))
+		})
+	}
+}
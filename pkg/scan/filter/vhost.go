@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewVHostResultFilter builds a filter from the results obtained requesting a host that is not supposed
+// to exist (see vhost.BaselineDetector)
+func NewVHostResultFilter(baselineResults []scan.Result) VHostResultFilter {
+	fingerprints := make(map[vhostFingerprint]struct{}, len(baselineResults))
+	for _, r := range baselineResults {
+		fingerprints[vhostFingerprintForResult(r)] = struct{}{}
+	}
+
+	return VHostResultFilter{fingerprints: fingerprints}
+}
+
+// VHostResultFilter ignores the results having the same status and length as the response the server gives
+// for a host that does not exist, as they are most likely served by its default virtual host
+type VHostResultFilter struct {
+	fingerprints map[vhostFingerprint]struct{}
+}
+
+func (f VHostResultFilter) ShouldIgnore(result scan.Result) bool {
+	_, found := f.fingerprints[vhostFingerprintForResult(result)]
+	return found
+}
+
+// vhostFingerprint doesn't include the body hash, as pages often include the requested host
+type vhostFingerprint struct {
+	method        string
+	statusCode    int
+	contentLength int64
+}
+
+func vhostFingerprintForResult(r scan.Result) vhostFingerprint {
+	return vhostFingerprint{
+		method:        r.Target.Method,
+		statusCode:    r.StatusCode,
+		contentLength: r.ContentLength,
+	}
+}
@@ -0,0 +1,38 @@
+package filter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVHostResultFilter(t *testing.T) {
+	baselineResult := scan.Result{
+		Target:        scan.Target{Method: http.MethodGet, Host: "8a5b1c3f2e.example.com"},
+		StatusCode:    http.StatusOK,
+		ContentLength: 10,
+		BodyHash:      "abc",
+	}
+
+	sut := filter.NewVHostResultFilter([]scan.Result{baselineResult})
+
+	sameResponse := baselineResult
+	sameResponse.Target.Host = "admin.example.com"
+	sameResponse.BodyHash = "def"
+	assert.True(t, sut.ShouldIgnore(sameResponse))
+
+	differentMethod := sameResponse
+	differentMethod.Target.Method = http.MethodPost
+	assert.False(t, sut.ShouldIgnore(differentMethod))
+
+	differentStatus := sameResponse
+	differentStatus.StatusCode = http.StatusMovedPermanently
+	assert.False(t, sut.ShouldIgnore(differentStatus))
+
+	differentLength := sameResponse
+	differentLength.ContentLength = 11
+	assert.False(t, sut.ShouldIgnore(differentLength))
+}
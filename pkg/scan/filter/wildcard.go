@@ -0,0 +1,43 @@
+package filter
+
+import (
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewWildcardResultFilter builds a filter from the results obtained requesting paths that are not supposed
+// to exist (see wildcard.Detector)
+func NewWildcardResultFilter(wildcardResults []scan.Result) WildcardResultFilter {
+	fingerprints := make(map[wildcardFingerprint]struct{}, len(wildcardResults))
+	for _, r := range wildcardResults {
+		fingerprints[fingerprintForResult(r)] = struct{}{}
+	}
+
+	return WildcardResultFilter{fingerprints: fingerprints}
+}
+
+// WildcardResultFilter ignores the results that are indistinguishable from the response the server
+// gives for paths that do not exist (EG a server replying 200 with the same page to any request)
+type WildcardResultFilter struct {
+	fingerprints map[wildcardFingerprint]struct{}
+}
+
+func (f WildcardResultFilter) ShouldIgnore(result scan.Result) bool {
+	_, found := f.fingerprints[fingerprintForResult(result)]
+	return found
+}
+
+type wildcardFingerprint struct {
+	method        string
+	statusCode    int
+	contentLength int64
+	bodyHash      string
+}
+
+func fingerprintForResult(r scan.Result) wildcardFingerprint {
+	return wildcardFingerprint{
+		method:        r.Target.Method,
+		statusCode:    r.StatusCode,
+		contentLength: r.ContentLength,
+		bodyHash:      r.BodyHash,
+	}
+}
@@ -0,0 +1,47 @@
+package filter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWildcardResultFilter(t *testing.T) {
+	wildcardResult := scan.Result{
+		Target:        scan.Target{Path: "/8a5b1c3f2e", Method: http.MethodGet},
+		StatusCode:    http.StatusOK,
+		ContentLength: 10,
+		BodyHash:      "abc",
+	}
+
+	sut := filter.NewWildcardResultFilter([]scan.Result{wildcardResult})
+
+	sameResponse := wildcardResult
+	sameResponse.Target.Path = "/home"
+	assert.True(t, sut.ShouldIgnore(sameResponse))
+
+	differentMethod := sameResponse
+	differentMethod.Target.Method = http.MethodPost
+	assert.False(t, sut.ShouldIgnore(differentMethod))
+
+	differentStatus := sameResponse
+	differentStatus.StatusCode = http.StatusMovedPermanently
+	assert.False(t, sut.ShouldIgnore(differentStatus))
+
+	differentLength := sameResponse
+	differentLength.ContentLength = 11
+	assert.False(t, sut.ShouldIgnore(differentLength))
+
+	differentBody := sameResponse
+	differentBody.BodyHash = "def"
+	assert.False(t, sut.ShouldIgnore(differentBody))
+}
+
+func TestWildcardResultFilterWithoutWildcardResultsShouldNotIgnoreAnything(t *testing.T) {
+	sut := filter.NewWildcardResultFilter(nil)
+
+	assert.False(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusOK}))
+}
@@ -0,0 +1,101 @@
+package scan
+
+import "net/url"
+
+// Option configures the Scanner being created by NewScanner
+type Option func(*Scanner)
+
+// WithFilter adds a filter function to the scanner, shouldIgnore returns true for the results to ignore (see
+// WithResultFilter)
+func WithFilter(shouldIgnore func(Result) bool) Option {
+	return WithResultFilter(ResultFilterFunc(shouldIgnore))
+}
+
+// WithResultFilter adds a filter to the scanner, the filters are invoked in order, starting with the one passed to
+// NewScanner and followed by the ones of the options: once a filter ignores a result the following ones are not
+// invoked for it. The results ignored are neither reported nor processed further (EG they are not scanned
+// recursively). The filters are invoked by the goroutines performing the requests, so they must be safe
+// for concurrent use.
+func WithResultFilter(resultFilter ResultFilter) Option {
+	return func(s *Scanner) {
+		s.resultFilters = append(s.resultFilters, resultFilter)
+	}
+}
+
+// WithTargetCompletedHandler registers a function invoked every time a target provided by the producer has been
+// completely processed, including everything found recursively starting from it; it is not invoked
+// for the targets being processed when the scan is canceled, as they may not have been processed completely.
+func WithTargetCompletedHandler(handler func(Target)) Option {
+	return func(s *Scanner) {
+		s.targetCompletedHandlers = append(s.targetCompletedHandlers, handler)
+	}
+}
+
+// WithResponseHandler registers a function invoked with the result of each response received before the filters
+// are applied, including the responses then ignored by them. It is invoked by the goroutine that performed the
+// request, so it must be safe for concurrent use; while it runs no other request is performed by that goroutine.
+func WithResponseHandler(handler func(Result)) Option {
+	return func(s *Scanner) {
+		s.responseHandlers = append(s.responseHandlers, handler)
+	}
+}
+
+// WithResponseAcceptedHandler registers a function invoked with each result accepted by the filters before it is
+// reported, while it still carries the headers and the body kept by the scanner (see WithBodyKept). It is invoked
+// by the goroutine that performed the request, so it must be safe for concurrent use.
+func WithResponseAcceptedHandler(handler func(Result)) Option {
+	return func(s *Scanner) {
+		s.responseAcceptedHandlers = append(s.responseAcceptedHandlers, handler)
+	}
+}
+
+// WithScope makes the scanner ignore the redirects pointing to urls out of scope, they are logged
+// but not requested
+func WithScope(isInScope func(u *url.URL) bool) Option {
+	return func(s *Scanner) {
+		s.isInScope = isInScope
+	}
+}
+
+// WithRecursionStrategy sets the order in which the directories found are scanned, one of RecursionStrategies
+// (RecursionBreadthFirst by default)
+func WithRecursionStrategy(strategy string) Option {
+	return func(s *Scanner) {
+		s.recursionStrategy = strategy
+	}
+}
+
+// WithRequestTemplate makes the scanner build the request of each target from the template, replacing its
+// placeholders with the words of the target; the path of the targets is ignored
+func WithRequestTemplate(requestTemplate RequestTemplate) Option {
+	return func(s *Scanner) {
+		s.requestTemplate = requestTemplate
+	}
+}
+
+// WithBodyKept makes the scanner keep the first maxBodySize bytes of the response body in the results, so that the
+// result filters can inspect it; the body is still read up to the limit of WithBodyReadLimit (completely by
+// default) to compute its length and hash
+func WithBodyKept(maxBodySize int64) Option {
+	return func(s *Scanner) {
+		s.maxBodySize = maxBodySize
+	}
+}
+
+// WithBodyReadLimit makes the scanner read at most maxBodySize bytes of each response body, so that a huge body
+// (or a compression bomb) can't keep a worker busy; the rest of the body is not read. The results having a longer
+// body are marked as truncated (see Result.BodyTruncated).
+func WithBodyReadLimit(maxBodySize int64) Option {
+	return func(s *Scanner) {
+		s.maxBodyReadSize = maxBodySize
+	}
+}
+
+// WithRequestsLimit makes the scanner stop performing requests once maxRequests have been performed,
+// onLimitReached is invoked once, the first time a request is not performed because of the limit
+func WithRequestsLimit(maxRequests int64, onLimitReached func()) Option {
+	return func(s *Scanner) {
+		s.maxRequests = maxRequests
+		s.onLimitReached = onLimitReached
+	}
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// ResultSaver is implemented by every saver of this package
+type ResultSaver interface {
+	Save(scan.Result) error
+	Close() error
+}
+
+func NewAggregateSaver(savers ...ResultSaver) AggregateSaver {
+	return AggregateSaver{savers: savers}
+}
+
+// AggregateSaver saves each result with all the savers it is composed of
+type AggregateSaver struct {
+	savers []ResultSaver
+}
+
+func (a AggregateSaver) Save(r scan.Result) error {
+	for _, s := range a.savers {
+		if err := s.Save(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes all the savers, also when some of them fail to close; the first error encountered is returned
+func (a AggregateSaver) Close() error {
+	var firstErr error
+
+	for _, s := range a.savers {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = errors.Wrap(err, "failed to close saver")
+		}
+	}
+
+	return firstErr
+}
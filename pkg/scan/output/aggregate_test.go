@@ -0,0 +1,41 @@
+package output_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateSaverShouldSaveWithAllTheSavers(t *testing.T) {
+	t.Parallel()
+
+	first, second := &bufferWriteCloser{}, &bufferWriteCloser{}
+
+	sut := output.NewAggregateSaver(output.NewJSONSaver(first), output.NewJSONSaver(second))
+
+	assert.NoError(t, sut.Save(scan.Result{}))
+	assert.NoError(t, sut.Close())
+
+	assert.NotEmpty(t, first.String())
+	assert.Equal(t, first.String(), second.String())
+	assert.True(t, first.closed)
+	assert.True(t, second.closed)
+}
+
+func TestAggregateSaverShouldCloseAllTheSaversWhenOneFails(t *testing.T) {
+	t.Parallel()
+
+	failing, err := output.NewFileSaver("/root/123/bla.txt")
+	assert.Error(t, err)
+
+	buffer := &bufferWriteCloser{}
+
+	sut := output.NewAggregateSaver(failing, output.NewJSONSaver(buffer))
+
+	err = sut.Close()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "writeCloser is nil")
+	assert.True(t, buffer.closed)
+}
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// csvHeader is the first row of the CSV output, the duration is expressed in milliseconds; the host is set only
+// when scanning the virtual hosts and the words only when scanning with a request template
+var csvHeader = []string{"URL", "Method", "Status", "Length", "Location", "Duration", "Host", "Word", "SecondWord"}
+
+func NewCSVFileSaver(path string) (*CSVSaver, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return &CSVSaver{}, errors.Wrapf(err, "failed to create file `%s` for csv output", path)
+	}
+
+	return NewCSVSaver(file)
+}
+
+// NewCSVSaver creates a CSVSaver and writes the header row
+func NewCSVSaver(writeCloser io.WriteCloser) (*CSVSaver, error) {
+	s := &CSVSaver{writeCloser: writeCloser, writer: csv.NewWriter(writeCloser)}
+
+	if err := s.write(csvHeader); err != nil {
+		_ = writeCloser.Close()
+		return &CSVSaver{}, errors.Wrap(err, "CSVSaver: failed to write header")
+	}
+
+	return s, nil
+}
+
+// CSVSaver writes the results as CSV, one row per result; each row is written as soon as the result is saved
+type CSVSaver struct {
+	writeCloser io.WriteCloser
+	writer      *csv.Writer
+}
+
+func (s *CSVSaver) Save(r scan.Result) error {
+	if s.writeCloser == nil {
+		return errNilWriteCloser
+	}
+
+	record := []string{
+		r.URL.String(),
+		r.Target.Method,
+		strconv.Itoa(r.StatusCode),
+		strconv.FormatInt(r.ContentLength, 10),
+		r.Location,
+		strconv.FormatInt(r.Duration.Milliseconds(), 10),
+		r.Target.Host,
+		r.Target.Word,
+		r.Target.SecondWord,
+	}
+
+	return errors.Wrapf(s.write(record), "CSVSaver: failed to write result: %s", r.URL.String())
+}
+
+func (s *CSVSaver) write(record []string) error {
+	if err := s.writer.Write(record); err != nil {
+		return err
+	}
+
+	s.writer.Flush()
+
+	return s.writer.Error()
+}
+
+func (s *CSVSaver) Close() error {
+	if s.writeCloser == nil {
+		return errNilWriteCloser
+	}
+
+	return s.writeCloser.Close()
+}
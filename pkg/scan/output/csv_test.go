@@ -0,0 +1,60 @@
+package output_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVSaverShouldWriteTheHeaderAndOneRowPerResult(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bufferWriteCloser{}
+
+	sut, err := output.NewCSVSaver(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "URL,Method,Status,Length,Location,Duration,Host,Word,SecondWord\n", buffer.String())
+
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:        scan.Target{Path: "/home", Method: http.MethodGet, Host: "admin.localhost"},
+		StatusCode:    http.StatusFound,
+		URL:           *test.MustParseURL(t, "http://localhost/home"),
+		ContentLength: 10,
+		Location:      `/login?next=/home,"quoted"`,
+		Duration:      time.Millisecond * 21,
+	}))
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:     scan.Target{Path: "/", Method: http.MethodPost, Word: "1", SecondWord: "admin"},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://localhost/?id=1&user=admin"),
+		Duration:   time.Millisecond * 3,
+	}))
+	assert.NoError(t, sut.Close())
+
+	expected := "URL,Method,Status,Length,Location,Duration,Host,Word,SecondWord\n" +
+		`http://localhost/home,GET,302,10,"/login?next=/home,""quoted""",21,admin.localhost,,` + "\n" +
+		`http://localhost/?id=1&user=admin,POST,200,0,,3,,1,admin` + "\n"
+	assert.Equal(t, expected, buffer.String())
+	assert.True(t, buffer.closed)
+}
+
+func TestCSVFileSaverShouldErrWhenInvalidPath(t *testing.T) {
+	t.Parallel()
+
+	sut, err := output.NewCSVFileSaver("/root/123/bla.csv")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create file")
+
+	err = sut.Save(scan.Result{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "writeCloser is nil")
+
+	err = sut.Close()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "writeCloser is nil")
+}
@@ -0,0 +1,143 @@
+package output
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// HTMLReportInfo contains the information about the scan shown in the HTML report
+type HTMLReportInfo struct {
+	Targets       []string
+	Dictionary    string
+	Duration      time.Duration
+	RequestsCount int64
+}
+
+type htmlReport struct {
+	HTMLReportInfo
+	Results []htmlReportResult
+}
+
+type htmlReportResult struct {
+	JSONResult
+	StatusClass string
+}
+
+func NewHTMLFileSaver(path string, reportInfo func() HTMLReportInfo) (*HTMLSaver, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return &HTMLSaver{}, errors.Wrapf(err, "failed to create file `%s` for html output", path)
+	}
+
+	return NewHTMLSaver(file, reportInfo), nil
+}
+
+func NewHTMLSaver(writeCloser io.WriteCloser, reportInfo func() HTMLReportInfo) *HTMLSaver {
+	return &HTMLSaver{writeCloser: writeCloser, reportInfo: reportInfo}
+}
+
+// HTMLSaver renders a standalone HTML report of the results, as the report contains a table of all the results
+// they are kept in memory and the report is written when the saver is closed
+type HTMLSaver struct {
+	writeCloser io.WriteCloser
+	reportInfo  func() HTMLReportInfo
+	results     []htmlReportResult
+}
+
+func (s *HTMLSaver) Save(r scan.Result) error {
+	if s.writeCloser == nil {
+		return errNilWriteCloser
+	}
+
+	s.results = append(
+		s.results,
+		htmlReportResult{
+			JSONResult:  NewJSONResult(r),
+			StatusClass: strconv.Itoa(r.StatusCode/100) + "xx",
+		},
+	)
+
+	return nil
+}
+
+func (s *HTMLSaver) Close() error {
+	if s.writeCloser == nil {
+		return errNilWriteCloser
+	}
+
+	report := htmlReport{HTMLReportInfo: s.reportInfo(), Results: s.results}
+
+	renderErr := htmlReportTemplate.Execute(s.writeCloser, report)
+	closeErr := s.writeCloser.Close()
+
+	if renderErr != nil {
+		return errors.Wrap(renderErr, "HTMLSaver: failed to render report")
+	}
+
+	return closeErr
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>dirstalk report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+th { background: #eee; cursor: pointer; user-select: none; }
+dt { font-weight: bold; }
+.status-2xx { color: #1a7f37; }
+.status-3xx { color: #0969da; }
+.status-4xx { color: #9a6700; }
+.status-5xx { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>dirstalk report</h1>
+<dl>
+<dt>Targets</dt>
+{{range .Targets}}<dd>{{.}}</dd>
+{{end}}<dt>Dictionary</dt>
+<dd>{{.Dictionary}}</dd>
+<dt>Duration</dt>
+<dd>{{.Duration}}</dd>
+<dt>Total requests</dt>
+<dd>{{.RequestsCount}}</dd>
+<dt>Results</dt>
+<dd>{{len .Results}}</dd>
+</dl>
+<table id="results">
+<thead>
+<tr><th>URL</th><th>Method</th><th>Status</th><th>Length</th><th>Location</th><th>Response time (ms)</th><th>Host</th><th>Word</th><th>Second word</th></tr>
+</thead>
+<tbody>
+{{range .Results}}<tr class="status-{{.StatusClass}}"><td>{{.URL}}</td><td>{{.Method}}</td><td>{{.StatusCode}}</td><td>{{.ContentLength}}</td><td>{{.Location}}</td><td>{{.ResponseTimeMs}}</td><td>{{.Host}}</td><td>{{.Word}}</td><td>{{.SecondWord}}</td></tr>
+{{end}}</tbody>
+</table>
+<script>
+document.querySelectorAll("#results th").forEach(function (th, column) {
+  var ascending = true;
+  th.addEventListener("click", function () {
+    var tbody = document.querySelector("#results tbody");
+    var rows = Array.prototype.slice.call(tbody.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[column].textContent, y = b.cells[column].textContent;
+      var cmp = isNaN(x) || isNaN(y) || x === "" || y === "" ? x.localeCompare(y) : x - y;
+      return ascending ? cmp : -cmp;
+    });
+    ascending = !ascending;
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
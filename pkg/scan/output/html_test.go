@@ -0,0 +1,74 @@
+package output_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLSaverShouldRenderTheReportWhenClosed(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bufferWriteCloser{}
+
+	sut := output.NewHTMLSaver(buffer, func() output.HTMLReportInfo {
+		return output.HTMLReportInfo{
+			Targets:       []string{"http://localhost/"},
+			Dictionary:    "mydictionary.txt",
+			Duration:      time.Second * 3,
+			RequestsCount: 1234,
+		}
+	})
+
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:     scan.Target{Path: "/admin", Method: http.MethodGet, Host: "admin.localhost"},
+		StatusCode: http.StatusForbidden,
+		URL:        *test.MustParseURL(t, "http://localhost/admin"),
+	}))
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:     scan.Target{Path: "/<script>alert(1)</script>", Method: http.MethodGet, Word: "w1", SecondWord: "w2"},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://localhost/%3Cscript%3Ealert(1)%3C/script%3E"),
+		Location:   "<b>location</b>",
+	}))
+
+	assert.Empty(t, buffer.String(), "the report should be written only when the saver is closed")
+
+	assert.NoError(t, sut.Close())
+	assert.True(t, buffer.closed)
+
+	report := buffer.String()
+
+	assert.Contains(t, report, "<dd>http://localhost/</dd>")
+	assert.Contains(t, report, "<dd>mydictionary.txt</dd>")
+	assert.Contains(t, report, "<dd>3s</dd>")
+	assert.Contains(t, report, "<dd>1234</dd>")
+	assert.Contains(t, report, `<tr class="status-4xx"><td>http://localhost/admin</td><td>GET</td><td>403</td>`)
+	assert.Contains(t, report, "<td>admin.localhost</td><td></td><td></td></tr>")
+	assert.Contains(t, report, "<td>w1</td><td>w2</td></tr>")
+	assert.Contains(t, report, `<tr class="status-2xx">`)
+	assert.Contains(t, report, "&lt;b&gt;location&lt;/b&gt;")
+	assert.NotContains(t, report, "<b>location</b>")
+	assert.NotContains(t, report, "<script>alert(1)</script>")
+}
+
+func TestHTMLFileSaverShouldErrWhenInvalidPath(t *testing.T) {
+	t.Parallel()
+
+	sut, err := output.NewHTMLFileSaver("/root/123/bla.html", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create file")
+
+	err = sut.Save(scan.Result{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "writeCloser is nil")
+
+	err = sut.Close()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "writeCloser is nil")
+}
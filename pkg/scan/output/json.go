@@ -0,0 +1,122 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// JSONResult is the representation of a result in the JSON output, the name of the fields is part of the
+// output schema and should not be changed
+type JSONResult struct {
+	URL            string `json:"url"`
+	Method         string `json:"method"`
+	StatusCode     int    `json:"status_code"`
+	ContentLength  int64  `json:"content_length"`
+	Location       string `json:"location"`
+	ResponseTimeMs int64  `json:"response_time_ms"`
+
+	// Host is the Host header of the request, set only when scanning the virtual hosts
+	Host string `json:"host,omitempty"`
+
+	// Word is the dictionary word replacing the placeholders, set only when scanning with a request template
+	Word string `json:"word,omitempty"`
+
+	// SecondWord is the word of the second dictionary, set only when the request template has two injection points
+	SecondWord string `json:"second_word,omitempty"`
+
+	// BodyTruncated is set only when the body is longer than the bytes read (see --max-body-size)
+	BodyTruncated bool `json:"body_truncated,omitempty"`
+}
+
+func NewJSONResult(r scan.Result) JSONResult {
+	return JSONResult{
+		URL:            r.URL.String(),
+		Method:         r.Target.Method,
+		StatusCode:     r.StatusCode,
+		ContentLength:  r.ContentLength,
+		Location:       r.Location,
+		ResponseTimeMs: r.Duration.Milliseconds(),
+		Host:           r.Target.Host,
+		Word:           r.Target.Word,
+		SecondWord:     r.Target.SecondWord,
+		BodyTruncated:  r.BodyTruncated,
+	}
+}
+
+func NewJSONFileSaver(path string) (*JSONSaver, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return &JSONSaver{}, errors.Wrapf(err, "failed to create file `%s` for json output", path)
+	}
+
+	return NewJSONSaver(file), nil
+}
+
+func NewJSONSaver(writeCloser io.WriteCloser) *JSONSaver {
+	return &JSONSaver{writeCloser: writeCloser, writer: bufio.NewWriter(writeCloser)}
+}
+
+// JSONSaver writes the results as a JSON array, one element per result; the array is written incrementally
+// and it is terminated when the saver is closed
+type JSONSaver struct {
+	writeCloser io.WriteCloser
+	writer      *bufio.Writer
+	savedCount  int
+}
+
+func (s *JSONSaver) Save(r scan.Result) error {
+	if s.writeCloser == nil {
+		return errNilWriteCloser
+	}
+
+	rawResult, err := json.Marshal(NewJSONResult(r))
+	if err != nil {
+		return errors.Wrap(err, "JSONSaver: failed to convert result")
+	}
+
+	separator := ",\n"
+	if s.savedCount == 0 {
+		separator = "[\n"
+	}
+
+	if _, err := s.writer.WriteString(separator); err != nil {
+		return errors.Wrap(err, "JSONSaver: failed to write result")
+	}
+
+	if _, err := s.writer.Write(rawResult); err != nil {
+		return errors.Wrapf(err, "JSONSaver: failed to write result: %s", rawResult)
+	}
+
+	s.savedCount++
+
+	return nil
+}
+
+func (s *JSONSaver) Close() error {
+	if s.writeCloser == nil {
+		return errNilWriteCloser
+	}
+
+	end := "\n]\n"
+	if s.savedCount == 0 {
+		end = "[]\n"
+	}
+
+	_, writeErr := s.writer.WriteString(end)
+	if writeErr == nil {
+		writeErr = s.writer.Flush()
+	}
+
+	closeErr := s.writeCloser.Close()
+
+	if writeErr != nil {
+		return errors.Wrap(writeErr, "JSONSaver: failed to write the end of the output")
+	}
+
+	return closeErr
+}
@@ -0,0 +1,125 @@
+package output_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+type bufferWriteCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferWriteCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestJSONSaverShouldWriteAnArrayOfResults(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bufferWriteCloser{}
+	sut := output.NewJSONSaver(buffer)
+
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:        scan.Target{Path: "/home", Method: http.MethodGet},
+		StatusCode:    http.StatusMovedPermanently,
+		URL:           *test.MustParseURL(t, "http://localhost/home"),
+		ContentLength: 10,
+		Location:      "/home/",
+		Duration:      time.Millisecond * 15,
+	}))
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:        scan.Target{Path: "/admin", Method: http.MethodPost},
+		StatusCode:    http.StatusOK,
+		URL:           *test.MustParseURL(t, "http://localhost/admin"),
+		ContentLength: 3,
+	}))
+	assert.NoError(t, sut.Close())
+
+	expected := `[
+{"url":"http://localhost/home","method":"GET","status_code":301,"content_length":10,"location":"/home/","response_time_ms":15},
+{"url":"http://localhost/admin","method":"POST","status_code":200,"content_length":3,"location":"","response_time_ms":0}
+]
+`
+	assert.Equal(t, expected, buffer.String())
+	assert.True(t, buffer.closed)
+}
+
+func TestJSONSaverShouldWriteTheHostOfTheResultsOfTheVirtualHostsScan(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bufferWriteCloser{}
+	sut := output.NewJSONSaver(buffer)
+
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:        scan.Target{Method: http.MethodGet, Host: "admin.example.com"},
+		StatusCode:    http.StatusOK,
+		URL:           *test.MustParseURL(t, "http://10.0.0.1/"),
+		ContentLength: 3,
+	}))
+	assert.NoError(t, sut.Close())
+
+	expected := `[
+{"url":"http://10.0.0.1/","method":"GET","status_code":200,"content_length":3,"location":"","response_time_ms":0,"host":"admin.example.com"}
+]
+`
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestJSONSaverShouldWriteWhetherTheBodyIsTruncated(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bufferWriteCloser{}
+	sut := output.NewJSONSaver(buffer)
+
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:        scan.Target{Method: http.MethodGet},
+		StatusCode:    http.StatusOK,
+		URL:           *test.MustParseURL(t, "http://10.0.0.1/backup.zip"),
+		ContentLength: -1,
+		BodyTruncated: true,
+	}))
+	assert.NoError(t, sut.Close())
+
+	expected := `[
+{"url":"http://10.0.0.1/backup.zip","method":"GET","status_code":200,"content_length":-1,"location":"","response_time_ms":0,"body_truncated":true}
+]
+`
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestJSONSaverShouldWriteAnEmptyArrayWhenThereAreNoResults(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bufferWriteCloser{}
+	sut := output.NewJSONSaver(buffer)
+
+	assert.NoError(t, sut.Close())
+
+	assert.Equal(t, "[]\n", buffer.String())
+	assert.True(t, buffer.closed)
+}
+
+func TestJSONFileSaverShouldErrWhenInvalidPath(t *testing.T) {
+	t.Parallel()
+
+	sut, err := output.NewJSONFileSaver("/root/123/bla.json")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create file")
+
+	err = sut.Save(scan.Result{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "writeCloser is nil")
+
+	err = sut.Close()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "writeCloser is nil")
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewJSONLinesSaver creates a JSONLinesSaver writing to w, EG the standard output
+func NewJSONLinesSaver(w io.Writer) *JSONLinesSaver {
+	return &JSONLinesSaver{writer: w}
+}
+
+// JSONLinesSaver writes each result as soon as it is saved, as a JSON object (see JSONResult) on its own line;
+// it can be used by multiple goroutines, the lines are never interleaved
+type JSONLinesSaver struct {
+	writer io.Writer
+	mx     sync.Mutex
+}
+
+func (s *JSONLinesSaver) Save(r scan.Result) error {
+	if s.writer == nil {
+		return errNilWriteCloser
+	}
+
+	rawResult, err := json.Marshal(NewJSONResult(r))
+	if err != nil {
+		return errors.Wrap(err, "JSONLinesSaver: failed to convert result")
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	// the line is written at once, so that a reader never gets a partial object
+	_, err = s.writer.Write(append(rawResult, '\n'))
+
+	return errors.Wrapf(err, "JSONLinesSaver: failed to write result: %s", rawResult)
+}
+
+// Close does nothing, the writer is not owned by the saver
+func (s *JSONLinesSaver) Close() error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLinesSaverShouldWriteAnObjectPerLine(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	sut := output.NewJSONLinesSaver(buffer)
+
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:        scan.Target{Path: "/home", Method: http.MethodGet},
+		StatusCode:    http.StatusMovedPermanently,
+		URL:           *test.MustParseURL(t, "http://localhost/home"),
+		ContentLength: 10,
+		Location:      "/home/",
+		Duration:      time.Millisecond * 15,
+	}))
+	assert.NoError(t, sut.Save(scan.Result{
+		Target:        scan.Target{Path: "/admin", Method: http.MethodPost},
+		StatusCode:    http.StatusOK,
+		URL:           *test.MustParseURL(t, "http://localhost/admin"),
+		ContentLength: 3,
+	}))
+	assert.NoError(t, sut.Close())
+
+	expected := `{"url":"http://localhost/home","method":"GET","status_code":301,"content_length":10,"location":"/home/","response_time_ms":15}
+{"url":"http://localhost/admin","method":"POST","status_code":200,"content_length":3,"location":"","response_time_ms":0}
+`
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestJSONLinesSaverShouldNotInterleaveTheLinesOfConcurrentSaves(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	sut := output.NewJSONLinesSaver(buffer)
+
+	const savesCount = 50
+
+	wg := sync.WaitGroup{}
+	wg.Add(savesCount)
+
+	for i := 0; i < savesCount; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, sut.Save(scan.Result{
+				Target:     scan.Target{Path: "/" + strconv.Itoa(i), Method: http.MethodGet},
+				StatusCode: http.StatusOK,
+				URL:        *test.MustParseURL(t, "http://localhost/"+strconv.Itoa(i)),
+			}))
+		}(i)
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(t, lines, savesCount)
+
+	for _, line := range lines {
+		var r output.JSONResult
+		assert.NoError(t, json.Unmarshal([]byte(line), &r))
+		assert.Equal(t, http.StatusOK, r.StatusCode)
+	}
+}
@@ -0,0 +1,183 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// maxResponseFileNameLength keeps the names of the files of the responses within the limits of the filesystems
+const maxResponseFileNameLength = 200
+
+// ErrResponsesLimitReached is returned when saving a response would exceed the amount of bytes that can be saved
+var ErrResponsesLimitReached = errors.New("the limit of bytes of the saved responses has been reached")
+
+// unsafeFileNameChars matches the characters not allowed in the names of the files of the responses, the path
+// separators among them
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// NewResponseSaver creates dir (if needed) and returns a saver writing the responses in it,
+// when maxBytes is greater than 0 the responses are saved until their total size reaches it
+func NewResponseSaver(dir string, maxBytes int64) (*ResponseSaver, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory `%s` for the responses", dir)
+	}
+
+	return &ResponseSaver{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// ResponseSaver writes each response to its own file: a metadata header (url, method, status and headers)
+// followed by an empty line and the body. It is safe for concurrent use.
+type ResponseSaver struct {
+	dir      string
+	maxBytes int64
+
+	mx           sync.Mutex
+	savedBytes   int64
+	limitReached bool
+}
+
+// MaxBytes returns the maximum amount of bytes that can be saved, 0 when not limited
+func (s *ResponseSaver) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Save writes the response of the result, which must still carry its headers and body;
+// once the limit of bytes is reached no other response is saved
+func (s *ResponseSaver) Save(r scan.Result) error {
+	content := responseFileContent(r)
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.limitReached {
+		return ErrResponsesLimitReached
+	}
+
+	if s.maxBytes > 0 && s.savedBytes+int64(len(content)) > s.maxBytes {
+		s.limitReached = true
+		return ErrResponsesLimitReached
+	}
+
+	file, err := s.createFile(responseFileName(r))
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to write the response of %s", r.URL.String())
+	}
+
+	s.savedBytes += int64(len(content))
+
+	return nil
+}
+
+// createFile creates a new file named after name in the directory, adding a numeric suffix to the name
+// when a file with the same name exists (EG the same path requested with different methods)
+func (s *ResponseSaver) createFile(name string) (*os.File, error) {
+	for i := 1; ; i++ {
+		fileName := name
+		if i > 1 {
+			fileName += "_" + strconv.Itoa(i)
+		}
+
+		path := filepath.Join(s.dir, fileName)
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create file `%s` for the response", path)
+		}
+
+		return file, nil
+	}
+}
+
+// responseFileName returns the name of the file of the response made of the method, the host and the path of the
+// request; the unsafe characters are replaced, so that the name can't point outside the directory (EG via ../)
+func responseFileName(r scan.Result) string {
+	host := r.URL.Host
+	if r.Target.Host != "" {
+		host = r.Target.Host
+	}
+
+	name := r.Target.Method + "_" + host + r.URL.Path
+	if r.URL.RawQuery != "" {
+		name += "_" + r.URL.RawQuery
+	}
+
+	// the requests of a template may differ only by their headers or body
+	for _, word := range r.Target.Words() {
+		name += "_" + word
+	}
+
+	name = unsafeFileNameChars.ReplaceAllString(name, "_")
+
+	if len(name) > maxResponseFileNameLength {
+		name = name[:maxResponseFileNameLength]
+	}
+
+	return name
+}
+
+func responseFileContent(r scan.Result) []byte {
+	buf := &bytes.Buffer{}
+
+	_, _ = fmt.Fprintf(buf, "url: %s\n", r.URL.String())
+	_, _ = fmt.Fprintf(buf, "method: %s\n", r.Target.Method)
+
+	if r.Target.Host != "" {
+		_, _ = fmt.Fprintf(buf, "host: %s\n", r.Target.Host)
+	}
+
+	if r.Target.Word != "" {
+		_, _ = fmt.Fprintf(buf, "word: %s\n", r.Target.Word)
+	}
+
+	if r.Target.SecondWord != "" {
+		_, _ = fmt.Fprintf(buf, "second-word: %s\n", r.Target.SecondWord)
+	}
+
+	_, _ = fmt.Fprintf(buf, "status: %d\n", r.StatusCode)
+
+	if r.BodyTruncated {
+		_, _ = fmt.Fprintln(buf, "body-truncated: true")
+	}
+
+	_, _ = fmt.Fprintln(buf, "headers:")
+
+	keys := make([]string, 0, len(r.Header))
+	for key := range r.Header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range r.Header[key] {
+			_, _ = fmt.Fprintf(buf, "  %s: %s\n", key, value)
+		}
+	}
+
+	_, _ = fmt.Fprintln(buf)
+	_, _ = buf.Write(r.Body)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,120 @@
+package output_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseSaverShouldWriteTheMetadataAndTheBody(t *testing.T) {
+	dir := mustCreateTempDir(t)
+	defer removeDir(dir)
+
+	sut, err := output.NewResponseSaver(filepath.Join(dir, "responses"), 0)
+	assert.NoError(t, err)
+
+	err = sut.Save(scan.Result{
+		Target:     scan.Target{Path: "/home/index.php", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite:8080/home/index.php?page=1"),
+		Header:     http.Header{"X-Second": []string{"2"}, "Content-Type": []string{"text/html"}},
+		Body:       []byte("<html>home</html>"),
+	})
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "responses", "GET_mysite_8080_home_index.php_page_1"))
+	assert.NoError(t, err)
+
+	expected := "url: http://mysite:8080/home/index.php?page=1\n" +
+		"method: GET\n" +
+		"status: 200\n" +
+		"headers:\n" +
+		"  Content-Type: text/html\n" +
+		"  X-Second: 2\n" +
+		"\n" +
+		"<html>home</html>"
+	assert.Equal(t, expected, string(content))
+}
+
+func TestResponseSaverShouldNotWriteOutsideTheDirectory(t *testing.T) {
+	dir := mustCreateTempDir(t)
+	defer removeDir(dir)
+
+	sut, err := output.NewResponseSaver(dir, 0)
+	assert.NoError(t, err)
+
+	u := *test.MustParseURL(t, "http://mysite/")
+	u.Path = "/../../etc/passwd"
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, sut.Save(scan.Result{
+			Target:     scan.Target{Path: u.Path, Method: http.MethodGet, Host: "../vhost"},
+			StatusCode: http.StatusOK,
+			URL:        u,
+		}))
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+
+	// the second response for the same path doesn't overwrite the first one
+	assert.Len(t, files, 2)
+	assert.Equal(t, "GET_.._vhost_.._.._etc_passwd", files[0].Name())
+	assert.Equal(t, "GET_.._vhost_.._.._etc_passwd_2", files[1].Name())
+}
+
+func TestResponseSaverShouldStopOnceTheLimitIsReached(t *testing.T) {
+	dir := mustCreateTempDir(t)
+	defer removeDir(dir)
+
+	sut, err := output.NewResponseSaver(dir, 200)
+	assert.NoError(t, err)
+
+	newResult := func(path string, body []byte) scan.Result {
+		return scan.Result{
+			Target:     scan.Target{Path: path, Method: http.MethodGet},
+			StatusCode: http.StatusOK,
+			URL:        *test.MustParseURL(t, "http://mysite"+path),
+			Body:       body,
+		}
+	}
+
+	assert.NoError(t, sut.Save(newResult("/home", []byte("home"))))
+	assert.Equal(t, output.ErrResponsesLimitReached, sut.Save(newResult("/big", make([]byte, 200))))
+
+	// once the limit is reached no other response is saved, even if it would fit
+	assert.Equal(t, output.ErrResponsesLimitReached, sut.Save(newResult("/about", []byte("about"))))
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestResponseSaverShouldErrWhenTheDirectoryCannotBeCreated(t *testing.T) {
+	file := test.MustWriteTempFile(t, []byte("not a directory"))
+	defer removeDir(file)
+
+	_, err := output.NewResponseSaver(filepath.Join(file, "responses"), 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create directory")
+}
+
+func mustCreateTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "dirstalk")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err.Error())
+	}
+
+	return dir
+}
+
+func removeDir(dir string) {
+	_ = os.RemoveAll(dir) //nolint:errcheck
+}
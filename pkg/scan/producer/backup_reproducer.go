@@ -0,0 +1,80 @@
+package producer
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewBackupReProducer returns a reproducer that goes deeper on the results like the decorated reproducer and,
+// for each file found, also produces its variants with the given backup suffixes (EG /index.php.bak)
+func NewBackupReProducer(
+	reproducer scan.ReProducer,
+	suffixes []string,
+) *BackupReProducer {
+	return &BackupReProducer{
+		reproducer: reproducer,
+		suffixes:   suffixes,
+	}
+}
+
+type BackupReProducer struct {
+	reproducer scan.ReProducer
+	suffixes   []string
+}
+
+func (r *BackupReProducer) Reproduce(ctx context.Context) func(r scan.Result) <-chan scan.Target {
+	reproduce := r.reproducer.Reproduce(ctx)
+	resultRegistry := sync.Map{}
+
+	return func(result scan.Result) <-chan scan.Target {
+		targets := reproduce(result)
+
+		// only the files are probed (the folders have no extension), the backups found are not probed again
+		if !urlpath.HasExtension(result.Target.Path) || r.isBackup(result.Target.Path) {
+			return targets
+		}
+
+		// the same file can be found more than once (EG via different redirects)
+		_, inRegistry := resultRegistry.LoadOrStore(result.Target.Method+" "+registryKey(result.Target.Path), nil)
+		if inRegistry {
+			return targets
+		}
+
+		resultChannel := make(chan scan.Target, defaultChannelBuffer)
+
+		go func() {
+			defer close(resultChannel)
+
+			for target := range targets {
+				resultChannel <- target
+			}
+
+			for _, suffix := range r.suffixes {
+				backupTarget := result.Target
+				backupTarget.Path += suffix
+				// the redirects of the backups are not followed
+				backupTarget.Depth = 0
+				// the backup is the same whatever the query string of the file found with
+				backupTarget.Query = ""
+
+				resultChannel <- backupTarget
+			}
+		}()
+
+		return resultChannel
+	}
+}
+
+func (r *BackupReProducer) isBackup(p string) bool {
+	for _, suffix := range r.suffixes {
+		if strings.HasSuffix(p, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
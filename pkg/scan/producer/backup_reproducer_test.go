@@ -0,0 +1,95 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupReProducerShouldProduceTheBackupsOfTheFiles(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewBackupReProducer(
+		producer.NewReProducer(
+			producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/index.php"}, 1),
+		),
+		[]string{".bak", "~"},
+	)
+
+	reproducer := sut.Reproduce(context.Background())
+
+	result := scan.Result{
+		Target: scan.Target{Path: "/admin/login.php", Method: http.MethodPost, Depth: 1, Query: "debug=1"},
+	}
+
+	reproduced := make([]scan.Target, 0, 2)
+	for target := range reproducer(result) {
+		reproduced = append(reproduced, target)
+	}
+
+	expected := []scan.Target{
+		{Path: "/admin/login.php.bak", Method: http.MethodPost, Depth: 0},
+		{Path: "/admin/login.php~", Method: http.MethodPost, Depth: 0},
+	}
+	assert.Equal(t, expected, reproduced)
+}
+
+func TestBackupReProducerShouldNotProduceTheBackupsOfTheFolders(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewBackupReProducer(
+		producer.NewReProducer(
+			producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/index.php"}, 1),
+		),
+		[]string{".bak"},
+	)
+
+	reproducer := sut.Reproduce(context.Background())
+
+	reproduced := make([]scan.Target, 0, 1)
+	for target := range reproducer(scan.Result{Target: scan.Target{Path: "/admin", Method: http.MethodGet, Depth: 1}}) {
+		reproduced = append(reproduced, target)
+	}
+
+	// the folder is scanned recursively as usual
+	assert.Equal(t, []scan.Target{{Path: "/admin/index.php", Method: http.MethodGet, Depth: 0}}, reproduced)
+}
+
+func TestBackupReProducerShouldProduceTheBackupsOfAFileOnlyOnce(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewBackupReProducer(
+		producer.NewReProducer(
+			producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/index.php"}, 1),
+		),
+		[]string{".bak"},
+	)
+
+	reproducer := sut.Reproduce(context.Background())
+
+	results := []scan.Result{
+		{Target: scan.Target{Path: "/index.php", Method: http.MethodGet}},
+		{Target: scan.Target{Path: "index.php", Method: http.MethodGet}},
+		{Target: scan.Target{Path: "/index.php", Method: http.MethodHead}},
+		// the backups found are not probed again
+		{Target: scan.Target{Path: "/index.php.bak", Method: http.MethodGet}},
+	}
+
+	reproduced := make([]scan.Target, 0, 2)
+
+	for _, result := range results {
+		for target := range reproducer(result) {
+			reproduced = append(reproduced, target)
+		}
+	}
+
+	expected := []scan.Target{
+		{Path: "/index.php.bak", Method: http.MethodGet, Depth: 0},
+		{Path: "/index.php.bak", Method: http.MethodHead, Depth: 0},
+	}
+	assert.Equal(t, expected, reproduced)
+}
@@ -3,25 +3,36 @@ package producer
 import (
 	"context"
 
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
 	"github.com/stefanoj3/dirstalk/pkg/scan"
 )
 
 func NewDictionaryProducer(
 	methods []string,
-	dictionary []string,
+	entries []string,
+	depth int,
+) *DictionaryProducer {
+	return NewDictionarySourceProducer(methods, dictionary.Words(entries), depth)
+}
+
+// NewDictionarySourceProducer is like NewDictionaryProducer, the entries are read from the source every time the
+// targets are produced (EG streaming them from a file)
+func NewDictionarySourceProducer(
+	methods []string,
+	source dictionary.Source,
 	depth int,
 ) *DictionaryProducer {
 	return &DictionaryProducer{
-		methods:    methods,
-		dictionary: dictionary,
-		depth:      depth,
+		methods: methods,
+		source:  source,
+		depth:   depth,
 	}
 }
 
 type DictionaryProducer struct {
-	methods    []string
-	dictionary []string
-	depth      int
+	methods []string
+	source  dictionary.Source
+	depth   int
 }
 
 func (p *DictionaryProducer) Produce(ctx context.Context) <-chan scan.Target {
@@ -30,7 +41,7 @@ func (p *DictionaryProducer) Produce(ctx context.Context) <-chan scan.Target {
 	go func() {
 		defer close(targets)
 
-		for _, entry := range p.dictionary {
+		for entry := range p.source.Entries(ctx) {
 			for _, method := range p.methods {
 				select {
 				case <-ctx.Done():
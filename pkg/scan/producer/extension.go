@@ -0,0 +1,83 @@
+package producer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewExtensionProducer returns a producer that, for each target produced by the decorated producer,
+// will also produce one target per extension, with the extension appended to the path
+func NewExtensionProducer(
+	producer scan.Producer,
+	extensions []string,
+) *ExtensionProducer {
+	return &ExtensionProducer{
+		producer:   producer,
+		extensions: extensions,
+	}
+}
+
+type ExtensionProducer struct {
+	producer   scan.Producer
+	extensions []string
+}
+
+func (p *ExtensionProducer) Produce(ctx context.Context) <-chan scan.Target {
+	targets := make(chan scan.Target, 10)
+
+	go func() {
+		defer close(targets)
+
+		source := p.producer.Produce(ctx)
+
+		// when canceled, the decorated producer must be drained to let it terminate
+		defer func() {
+			for range source {
+			}
+		}()
+
+		// the dictionary may already contain some of the paths generated (eg "config" and "config.php")
+		alreadyProduced := newRecentTargets(recentTargetsSize)
+
+		produce := func(target scan.Target) bool {
+			if !alreadyProduced.add(target) {
+				return true
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case targets <- target:
+				return true
+			}
+		}
+
+		for target := range source {
+			if !produce(target) {
+				return
+			}
+
+			// no point in appending an extension to a folder
+			if strings.HasSuffix(target.Path, "/") {
+				continue
+			}
+
+			for _, extension := range p.extensions {
+				if strings.HasSuffix(target.Path, "."+extension) {
+					continue
+				}
+
+				extendedTarget := target
+				extendedTarget.Path += "." + extension
+
+				if !produce(extendedTarget) {
+					return
+				}
+			}
+		}
+	}()
+
+	return targets
+}
@@ -0,0 +1,120 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtensionProducerShouldProduceTargetsWithExtensions(t *testing.T) {
+	t.Parallel()
+
+	const depth = 2
+
+	sut := producer.NewExtensionProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet},
+			[]string{"/config", "/config.php", "/admin/", "/index.bak"},
+			depth,
+		),
+		[]string{"php", "bak"},
+	)
+
+	results := make([]scan.Target, 0, 10)
+
+	for r := range sut.Produce(context.Background()) {
+		results = append(results, r)
+	}
+
+	expectedResults := []scan.Target{
+		{
+			Depth:  depth,
+			Path:   "/config",
+			Method: http.MethodGet,
+		},
+		{
+			Depth:  depth,
+			Path:   "/config.php",
+			Method: http.MethodGet,
+		},
+		{
+			Depth:  depth,
+			Path:   "/config.bak",
+			Method: http.MethodGet,
+		},
+		{
+			Depth:  depth,
+			Path:   "/config.php.bak",
+			Method: http.MethodGet,
+		},
+		{
+			Depth:  depth,
+			Path:   "/admin/",
+			Method: http.MethodGet,
+		},
+		{
+			Depth:  depth,
+			Path:   "/index.bak",
+			Method: http.MethodGet,
+		},
+		{
+			Depth:  depth,
+			Path:   "/index.bak.php",
+			Method: http.MethodGet,
+		},
+	}
+
+	assert.Equal(t, expectedResults, results)
+}
+
+func TestExtensionProducerWithoutExtensionsShouldProduceTheOriginalTargets(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewExtensionProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet, http.MethodPost},
+			[]string{"/home", "/about"},
+			1,
+		),
+		nil,
+	)
+
+	resultsCount := 0
+
+	for range sut.Produce(context.Background()) {
+		resultsCount++
+	}
+
+	assert.Equal(t, 4, resultsCount)
+}
+
+func TestExtensionProducerCanBeCanceled(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewExtensionProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
+			[]string{"/home", "/about", "/index", "/search", "/tomato"},
+			1,
+		),
+		[]string{"php", "bak", "old"},
+	)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+
+	producerChannel := sut.Produce(ctx)
+
+	cancelFunc()
+
+	resultsCount := 0
+
+	for range producerChannel {
+		resultsCount++
+	}
+
+	assert.True(t, resultsCount < 80)
+}
@@ -0,0 +1,66 @@
+package producer
+
+import (
+	"context"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewQueryProducer returns a producer that, for each target produced by the decorated producer,
+// will also produce one target per query string, with the query string appended to the request
+func NewQueryProducer(
+	producer scan.Producer,
+	queries []string,
+) *QueryProducer {
+	return &QueryProducer{
+		producer: producer,
+		queries:  queries,
+	}
+}
+
+type QueryProducer struct {
+	producer scan.Producer
+	queries  []string
+}
+
+func (p *QueryProducer) Produce(ctx context.Context) <-chan scan.Target {
+	targets := make(chan scan.Target, 10)
+
+	go func() {
+		defer close(targets)
+
+		source := p.producer.Produce(ctx)
+
+		// when canceled, the decorated producer must be drained to let it terminate
+		defer func() {
+			for range source {
+			}
+		}()
+
+		produce := func(target scan.Target) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case targets <- target:
+				return true
+			}
+		}
+
+		for target := range source {
+			if !produce(target) {
+				return
+			}
+
+			for _, query := range p.queries {
+				queryTarget := target
+				queryTarget.Query = query
+
+				if !produce(queryTarget) {
+					return
+				}
+			}
+		}
+	}()
+
+	return targets
+}
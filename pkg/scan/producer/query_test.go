@@ -0,0 +1,91 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryProducerShouldProduceTargetsWithQueries(t *testing.T) {
+	t.Parallel()
+
+	const depth = 2
+
+	sut := producer.NewQueryProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet},
+			[]string{"/admin", "/index.php"},
+			depth,
+		),
+		[]string{"debug=1", "id=1&user=admin"},
+	)
+
+	results := make([]scan.Target, 0, 6)
+
+	for r := range sut.Produce(context.Background()) {
+		results = append(results, r)
+	}
+
+	expectedResults := []scan.Target{
+		{Depth: depth, Path: "/admin", Method: http.MethodGet},
+		{Depth: depth, Path: "/admin", Method: http.MethodGet, Query: "debug=1"},
+		{Depth: depth, Path: "/admin", Method: http.MethodGet, Query: "id=1&user=admin"},
+		{Depth: depth, Path: "/index.php", Method: http.MethodGet},
+		{Depth: depth, Path: "/index.php", Method: http.MethodGet, Query: "debug=1"},
+		{Depth: depth, Path: "/index.php", Method: http.MethodGet, Query: "id=1&user=admin"},
+	}
+
+	assert.Equal(t, expectedResults, results)
+}
+
+func TestQueryProducerWithoutQueriesShouldProduceTheOriginalTargets(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewQueryProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet, http.MethodPost},
+			[]string{"/home", "/about"},
+			1,
+		),
+		nil,
+	)
+
+	resultsCount := 0
+
+	for range sut.Produce(context.Background()) {
+		resultsCount++
+	}
+
+	assert.Equal(t, 4, resultsCount)
+}
+
+func TestQueryProducerCanBeCanceled(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewQueryProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
+			[]string{"/home", "/about", "/index", "/search", "/tomato"},
+			1,
+		),
+		[]string{"debug=1", "id=1", "test=1"},
+	)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+
+	producerChannel := sut.Produce(ctx)
+
+	cancelFunc()
+
+	resultsCount := 0
+
+	for range producerChannel {
+		resultsCount++
+	}
+
+	assert.True(t, resultsCount < 80)
+}
@@ -0,0 +1,43 @@
+package producer
+
+import "github.com/stefanoj3/dirstalk/pkg/scan"
+
+// recentTargetsSize is the amount of targets remembered to skip the duplicates, so that the memory used
+// doesn't grow with the dictionary
+const recentTargetsSize = 10000
+
+func newRecentTargets(size int) *recentTargets {
+	return &recentTargets{
+		targets:  make(map[scan.Target]struct{}, size),
+		order:    make([]scan.Target, 0, size),
+		capacity: size,
+	}
+}
+
+// recentTargets remembers the last targets produced, the oldest one is forgotten once the capacity is reached;
+// the duplicates in a dictionary are usually close to each other (EG "config" and "config.php" in a sorted one)
+type recentTargets struct {
+	targets  map[scan.Target]struct{}
+	order    []scan.Target
+	next     int
+	capacity int
+}
+
+// add returns false when the target is among the recent ones, otherwise it is remembered
+func (r *recentTargets) add(target scan.Target) bool {
+	if _, ok := r.targets[target]; ok {
+		return false
+	}
+
+	if len(r.order) < r.capacity {
+		r.order = append(r.order, target)
+	} else {
+		delete(r.targets, r.order[r.next])
+		r.order[r.next] = target
+		r.next = (r.next + 1) % r.capacity
+	}
+
+	r.targets[target] = struct{}{}
+
+	return true
+}
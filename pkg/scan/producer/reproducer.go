@@ -2,6 +2,8 @@ package producer
 
 import (
 	"context"
+	"path"
+	"strings"
 	"sync"
 
 	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
@@ -43,17 +45,18 @@ func (r *ReProducer) buildReproducer(ctx context.Context) func(result scan.Resul
 				return
 			}
 
-			_, inRegistry := resultRegistry.Load(result.Target.Path)
+			// "/home", "home" and "/home/" are the same folder, there is no point in scanning it more than once
+			_, inRegistry := resultRegistry.LoadOrStore(registryKey(result.Target.Path), nil)
 			if inRegistry {
 				return
 			}
-			resultRegistry.Store(result.Target.Path, nil)
 
 			for target := range r.producer.Produce(ctx) {
 				newTarget := result.Target
 				newTarget.Depth--
 				newTarget.Path = urlpath.Join(newTarget.Path, target.Path)
 				newTarget.Method = target.Method
+				newTarget.Query = target.Query
 
 				resultChannel <- newTarget
 			}
@@ -62,3 +65,7 @@ func (r *ReProducer) buildReproducer(ctx context.Context) func(result scan.Resul
 		return resultChannel
 	}
 }
+
+func registryKey(p string) string {
+	return "/" + strings.Trim(path.Clean("/"+p), "/")
+}
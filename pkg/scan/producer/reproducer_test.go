@@ -87,6 +87,30 @@ func TestNewReProducer(t *testing.T) {
 	assert.Len(t, targets, 0)
 }
 
+func TestReProducerShouldUseTheQueriesOfTheProducedTargets(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewReProducer(
+		producer.NewQueryProducer(
+			producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/home"}, 1),
+			[]string{"debug=1"},
+		),
+	)
+
+	result := scan.Result{Target: scan.Target{Path: "/admin", Method: http.MethodGet, Depth: 1, Query: "id=1"}}
+
+	targets := make([]scan.Target, 0, 2)
+	for tar := range sut.Reproduce(context.Background())(result) {
+		targets = append(targets, tar)
+	}
+
+	expectedTargets := []scan.Target{
+		{Path: "/admin/home", Method: http.MethodGet, Depth: 0},
+		{Path: "/admin/home", Method: http.MethodGet, Depth: 0, Query: "debug=1"},
+	}
+	assert.Equal(t, expectedTargets, targets)
+}
+
 func TestReProducerShouldProduceNothingForDepthZero(t *testing.T) {
 	t.Parallel()
 
@@ -122,6 +146,43 @@ func TestReProducerShouldProduceNothingForDepthZero(t *testing.T) {
 	assert.Len(t, targets, 0)
 }
 
+func TestReProducerShouldReproduceEquivalentPathsOnlyOnce(t *testing.T) {
+	t.Parallel()
+
+	methods := []string{http.MethodGet}
+	dictionary := []string{"/home", "/about"}
+
+	dictionaryProducer := producer.NewDictionaryProducer(methods, dictionary, 1)
+
+	sut := producer.NewReProducer(dictionaryProducer)
+
+	reproducerFunc := sut.Reproduce(context.Background())
+
+	targets := make([]scan.Target, 0, 10)
+
+	for _, p := range []string{"/home", "home", "/home/", "home/"} {
+		result := scan.NewResult(
+			scan.Target{
+				Path:   p,
+				Method: http.MethodGet,
+				Depth:  1,
+			},
+			&http.Response{
+				StatusCode: http.StatusOK,
+				Request: &http.Request{
+					URL: test.MustParseURL(t, "http://mysite/home"),
+				},
+			},
+		)
+
+		for tar := range reproducerFunc(result) {
+			targets = append(targets, tar)
+		}
+	}
+
+	assert.Len(t, targets, 2)
+}
+
 func BenchmarkReProducer(b *testing.B) {
 	methods := []string{http.MethodGet, http.MethodPost}
 	dictionary := []string{"/home", "/about"}
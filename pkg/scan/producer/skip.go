@@ -0,0 +1,54 @@
+package producer
+
+import (
+	"context"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewSkipProducer returns a producer that produces the targets produced by the decorated producer,
+// except the ones for which shouldSkip returns true
+func NewSkipProducer(
+	producer scan.Producer,
+	shouldSkip func(scan.Target) bool,
+) *SkipProducer {
+	return &SkipProducer{
+		producer:   producer,
+		shouldSkip: shouldSkip,
+	}
+}
+
+type SkipProducer struct {
+	producer   scan.Producer
+	shouldSkip func(scan.Target) bool
+}
+
+func (p *SkipProducer) Produce(ctx context.Context) <-chan scan.Target {
+	targets := make(chan scan.Target, 10)
+
+	go func() {
+		defer close(targets)
+
+		source := p.producer.Produce(ctx)
+
+		// when canceled, the decorated producer must be drained to let it terminate
+		defer func() {
+			for range source {
+			}
+		}()
+
+		for target := range source {
+			if p.shouldSkip(target) {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case targets <- target:
+			}
+		}
+	}()
+
+	return targets
+}
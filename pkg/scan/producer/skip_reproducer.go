@@ -0,0 +1,39 @@
+package producer
+
+import (
+	"context"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// NewSkipReProducer returns a reproducer that goes deeper on the results like the decorated reproducer,
+// except on the ones for which shouldSkip returns true
+func NewSkipReProducer(
+	reproducer scan.ReProducer,
+	shouldSkip func(scan.Result) bool,
+) *SkipReProducer {
+	return &SkipReProducer{
+		reproducer: reproducer,
+		shouldSkip: shouldSkip,
+	}
+}
+
+type SkipReProducer struct {
+	reproducer scan.ReProducer
+	shouldSkip func(scan.Result) bool
+}
+
+func (r *SkipReProducer) Reproduce(ctx context.Context) func(r scan.Result) <-chan scan.Target {
+	reproduce := r.reproducer.Reproduce(ctx)
+
+	return func(result scan.Result) <-chan scan.Target {
+		if !r.shouldSkip(result) {
+			return reproduce(result)
+		}
+
+		targets := make(chan scan.Target)
+		close(targets)
+
+		return targets
+	}
+}
@@ -0,0 +1,40 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipReProducerShouldNotGoDeeperOnTheResultsToSkip(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewSkipReProducer(
+		producer.NewReProducer(
+			producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/index.php"}, 1),
+		),
+		func(result scan.Result) bool {
+			return result.Target.Path == "/static/"
+		},
+	)
+
+	reproducer := sut.Reproduce(context.Background())
+
+	skipped := make([]scan.Target, 0)
+	for target := range reproducer(scan.Result{Target: scan.Target{Path: "/static/", Method: http.MethodGet, Depth: 1}}) {
+		skipped = append(skipped, target)
+	}
+
+	assert.Empty(t, skipped)
+
+	reproduced := make([]scan.Target, 0, 1)
+	for target := range reproducer(scan.Result{Target: scan.Target{Path: "/admin/", Method: http.MethodGet, Depth: 1}}) {
+		reproduced = append(reproduced, target)
+	}
+
+	assert.Equal(t, []scan.Target{{Path: "/admin/index.php", Method: http.MethodGet, Depth: 0}}, reproduced)
+}
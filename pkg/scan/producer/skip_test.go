@@ -0,0 +1,40 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipProducerShouldNotProduceTheTargetsToSkip(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewSkipProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet, http.MethodPost},
+			[]string{"/home", "/admin"},
+			1,
+		),
+		func(target scan.Target) bool {
+			return target.Path == "/home" && target.Method == http.MethodGet
+		},
+	)
+
+	results := make([]scan.Target, 0, 3)
+
+	for r := range sut.Produce(context.Background()) {
+		results = append(results, r)
+	}
+
+	expectedResults := []scan.Target{
+		{Path: "/home", Method: http.MethodPost, Depth: 1},
+		{Path: "/admin", Method: http.MethodGet, Depth: 1},
+		{Path: "/admin", Method: http.MethodPost, Depth: 1},
+	}
+
+	assert.Equal(t, expectedResults, results)
+}
@@ -0,0 +1,139 @@
+package producer
+
+import (
+	"context"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// The modes available to combine the words of the two dictionaries of a request template
+const (
+	// TemplateModeClusterBomb requests every combination of the words of the two dictionaries
+	TemplateModeClusterBomb = "clusterbomb"
+	// TemplateModePitchfork requests the words of the two dictionaries in parallel, the first word of a
+	// dictionary with the first of the other one and so on, until the shortest dictionary is exhausted
+	TemplateModePitchfork = "pitchfork"
+)
+
+// TemplateModes contains all the modes available
+var TemplateModes = []string{TemplateModeClusterBomb, TemplateModePitchfork}
+
+// IsValidTemplateMode returns true when the mode is one of the available ones
+func IsValidTemplateMode(mode string) bool {
+	for _, m := range TemplateModes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewTemplateProducer returns a producer of a target per word, when secondWords is not empty its words are
+// combined with the ones of words according to the mode (one of TemplateModes)
+func NewTemplateProducer(
+	method string,
+	words []string,
+	secondWords []string,
+	mode string,
+) *TemplateProducer {
+	return NewTemplateSourceProducer(method, dictionary.Words(words), secondWords, mode)
+}
+
+// NewTemplateSourceProducer is like NewTemplateProducer, the words are read from the source every time the
+// targets are produced (EG streaming them from a file) while the ones of secondWords are held in memory
+func NewTemplateSourceProducer(
+	method string,
+	words dictionary.Source,
+	secondWords []string,
+	mode string,
+) *TemplateProducer {
+	return &TemplateProducer{
+		method:      method,
+		words:       words,
+		secondWords: secondWords,
+		mode:        mode,
+	}
+}
+
+// TemplateProducer produces a target for each word, to be requested replacing the placeholders of the request
+// template with it (see scan.WithRequestTemplate)
+type TemplateProducer struct {
+	method      string
+	words       dictionary.Source
+	secondWords []string
+	mode        string
+}
+
+// Count returns the amount of targets produced, iterating the words
+func (p *TemplateProducer) Count() int64 {
+	var words int64
+	for range p.words.Entries(context.Background()) {
+		words++
+	}
+
+	secondWords := int64(len(p.secondWords))
+
+	switch {
+	case secondWords == 0:
+		return words
+	case p.mode == TemplateModePitchfork:
+		if secondWords < words {
+			return secondWords
+		}
+
+		return words
+	default:
+		return words * secondWords
+	}
+}
+
+func (p *TemplateProducer) Produce(ctx context.Context) <-chan scan.Target {
+	targets := make(chan scan.Target, 10)
+
+	go func() {
+		defer close(targets)
+
+		// the targets have no depth: the request template always addresses the same resource, nothing is nested in it
+		produce := func(target scan.Target) bool {
+			target.Method = p.method
+
+			select {
+			case <-ctx.Done():
+				return false
+			case targets <- target:
+				return true
+			}
+		}
+
+		// the words are not read anymore once a dictionary is exhausted
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		i := 0
+
+		for word := range p.words.Entries(ctx) {
+			switch {
+			case len(p.secondWords) == 0:
+				if !produce(scan.Target{Word: word}) {
+					return
+				}
+			case p.mode == TemplateModePitchfork:
+				if i >= len(p.secondWords) || !produce(scan.Target{Word: word, SecondWord: p.secondWords[i]}) {
+					return
+				}
+			default:
+				for _, secondWord := range p.secondWords {
+					if !produce(scan.Target{Word: word, SecondWord: secondWord}) {
+						return
+					}
+				}
+			}
+
+			i++
+		}
+	}()
+
+	return targets
+}
@@ -0,0 +1,85 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateProducerShouldProduceATargetPerWord(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewTemplateProducer(http.MethodPost, []string{"admin", "guest"}, nil, producer.TemplateModeClusterBomb)
+
+	results := make([]scan.Target, 0, 2)
+
+	for r := range sut.Produce(context.Background()) {
+		results = append(results, r)
+	}
+
+	expectedResults := []scan.Target{
+		{Method: http.MethodPost, Word: "admin"},
+		{Method: http.MethodPost, Word: "guest"},
+	}
+
+	assert.Equal(t, expectedResults, results)
+	assert.Equal(t, int64(2), sut.Count())
+}
+
+func TestTemplateProducerShouldCombineTheWordsAccordingToTheMode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		mode            string
+		secondWords     []string
+		expectedResults []scan.Target
+	}{
+		{
+			mode:        producer.TemplateModeClusterBomb,
+			secondWords: []string{"1234", "secret"},
+			expectedResults: []scan.Target{
+				{Method: http.MethodPost, Word: "admin", SecondWord: "1234"},
+				{Method: http.MethodPost, Word: "admin", SecondWord: "secret"},
+				{Method: http.MethodPost, Word: "guest", SecondWord: "1234"},
+				{Method: http.MethodPost, Word: "guest", SecondWord: "secret"},
+			},
+		},
+		{
+			mode:        producer.TemplateModePitchfork,
+			secondWords: []string{"1234", "secret", "unused"},
+			expectedResults: []scan.Target{
+				{Method: http.MethodPost, Word: "admin", SecondWord: "1234"},
+				{Method: http.MethodPost, Word: "guest", SecondWord: "secret"},
+			},
+		},
+		{
+			mode:        producer.TemplateModePitchfork,
+			secondWords: []string{"1234"},
+			expectedResults: []scan.Target{
+				{Method: http.MethodPost, Word: "admin", SecondWord: "1234"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.mode, func(t *testing.T) {
+			t.Parallel()
+
+			sut := producer.NewTemplateProducer(http.MethodPost, []string{"admin", "guest"}, tc.secondWords, tc.mode)
+
+			results := make([]scan.Target, 0, len(tc.expectedResults))
+
+			for r := range sut.Produce(context.Background()) {
+				results = append(results, r)
+			}
+
+			assert.Equal(t, tc.expectedResults, results)
+			assert.Equal(t, int64(len(tc.expectedResults)), sut.Count())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package producer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// The modes available to decide whether the paths are requested with a trailing slash
+const (
+	// TrailingSlashKeep requests the paths as they are
+	TrailingSlashKeep = "keep"
+	// TrailingSlashBoth requests the paths both with and without a trailing slash
+	TrailingSlashBoth = "both"
+	// TrailingSlashWith requests the paths with a trailing slash
+	TrailingSlashWith = "with"
+	// TrailingSlashWithout requests the paths without a trailing slash
+	TrailingSlashWithout = "without"
+)
+
+// TrailingSlashModes contains all the modes available
+var TrailingSlashModes = []string{TrailingSlashKeep, TrailingSlashBoth, TrailingSlashWith, TrailingSlashWithout}
+
+// IsValidTrailingSlashMode returns true when the mode is one of the available ones
+func IsValidTrailingSlashMode(mode string) bool {
+	for _, m := range TrailingSlashModes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewTrailingSlashProducer returns a producer that adds or removes the trailing slash of the paths of the targets
+// produced by the decorated producer according to the mode (one of TrailingSlashModes); the slash is never added
+// to the paths having an extension, as they are files
+func NewTrailingSlashProducer(
+	producer scan.Producer,
+	mode string,
+) *TrailingSlashProducer {
+	return &TrailingSlashProducer{
+		producer: producer,
+		mode:     mode,
+	}
+}
+
+type TrailingSlashProducer struct {
+	producer scan.Producer
+	mode     string
+}
+
+func (p *TrailingSlashProducer) Produce(ctx context.Context) <-chan scan.Target {
+	targets := make(chan scan.Target, 10)
+
+	go func() {
+		defer close(targets)
+
+		source := p.producer.Produce(ctx)
+
+		// when canceled, the decorated producer must be drained to let it terminate
+		defer func() {
+			for range source {
+			}
+		}()
+
+		// the dictionary may contain both the forms of a path (eg "admin" and "admin/")
+		alreadyProduced := newRecentTargets(recentTargetsSize)
+
+		produce := func(target scan.Target) bool {
+			if !alreadyProduced.add(target) {
+				return true
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case targets <- target:
+				return true
+			}
+		}
+
+		for target := range source {
+			for _, path := range p.paths(target.Path) {
+				slashTarget := target
+				slashTarget.Path = path
+
+				if !produce(slashTarget) {
+					return
+				}
+			}
+		}
+	}()
+
+	return targets
+}
+
+// paths returns the paths to request for the given one, in order
+func (p *TrailingSlashProducer) paths(path string) []string {
+	withoutSlash := strings.TrimRight(path, "/")
+
+	// the root is requested as it is
+	if withoutSlash == "" {
+		return []string{path}
+	}
+
+	if p.mode == TrailingSlashWithout {
+		return []string{withoutSlash}
+	}
+
+	// no point in requesting a file as a folder
+	if urlpath.HasExtension(withoutSlash) {
+		return []string{path}
+	}
+
+	switch p.mode {
+	case TrailingSlashWith:
+		return []string{withoutSlash + "/"}
+	case TrailingSlashBoth:
+		return []string{withoutSlash, withoutSlash + "/"}
+	default:
+		return []string{path}
+	}
+}
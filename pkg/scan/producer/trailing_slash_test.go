@@ -0,0 +1,110 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrailingSlashProducerShouldProduceThePathsAccordingToTheMode(t *testing.T) {
+	t.Parallel()
+
+	dictionary := []string{"admin", "admin/", "backup/", "index.php", "config.php/", "/"}
+
+	testCases := []struct {
+		mode          string
+		expectedPaths []string
+	}{
+		{
+			mode:          producer.TrailingSlashKeep,
+			expectedPaths: []string{"admin", "admin/", "backup/", "index.php", "config.php/", "/"},
+		},
+		{
+			mode:          producer.TrailingSlashBoth,
+			expectedPaths: []string{"admin", "admin/", "backup", "backup/", "index.php", "config.php/", "/"},
+		},
+		{
+			mode:          producer.TrailingSlashWith,
+			expectedPaths: []string{"admin/", "backup/", "index.php", "config.php/", "/"},
+		},
+		{
+			mode:          producer.TrailingSlashWithout,
+			expectedPaths: []string{"admin", "backup", "index.php", "config.php", "/"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.mode, func(t *testing.T) {
+			t.Parallel()
+
+			sut := producer.NewTrailingSlashProducer(
+				producer.NewDictionaryProducer([]string{http.MethodGet}, dictionary, 1),
+				tc.mode,
+			)
+
+			paths := make([]string, 0, len(tc.expectedPaths))
+			for target := range sut.Produce(context.Background()) {
+				assert.Equal(t, http.MethodGet, target.Method)
+				assert.Equal(t, 1, target.Depth)
+
+				paths = append(paths, target.Path)
+			}
+
+			assert.Equal(t, tc.expectedPaths, paths)
+		})
+	}
+}
+
+func TestTrailingSlashProducerShouldNotDedupeTheDifferentMethods(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewTrailingSlashProducer(
+		producer.NewDictionaryProducer([]string{http.MethodGet, http.MethodPost}, []string{"admin/"}, 0),
+		producer.TrailingSlashBoth,
+	)
+
+	targets := make([]scan.Target, 0, 4)
+	for target := range sut.Produce(context.Background()) {
+		targets = append(targets, target)
+	}
+
+	expectedTargets := []scan.Target{
+		{Path: "admin", Method: http.MethodGet},
+		{Path: "admin/", Method: http.MethodGet},
+		{Path: "admin", Method: http.MethodPost},
+		{Path: "admin/", Method: http.MethodPost},
+	}
+	assert.Equal(t, expectedTargets, targets)
+}
+
+func TestTrailingSlashProducerCanBeCanceled(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewTrailingSlashProducer(
+		producer.NewDictionaryProducer(
+			[]string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
+			[]string{"/home", "/about", "/index", "/search", "/tomato"},
+			1,
+		),
+		producer.TrailingSlashBoth,
+	)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+
+	producerChannel := sut.Produce(ctx)
+
+	cancelFunc()
+
+	resultsCount := 0
+
+	for range producerChannel {
+		resultsCount++
+	}
+
+	assert.True(t, resultsCount < 40)
+}
@@ -0,0 +1,59 @@
+package producer
+
+import (
+	"context"
+
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+func NewVHostProducer(
+	methods []string,
+	hosts []string,
+) *VHostProducer {
+	return NewVHostSourceProducer(methods, dictionary.Words(hosts))
+}
+
+// NewVHostSourceProducer is like NewVHostProducer, the hosts are read from the source every time the targets
+// are produced (EG streaming them from a file)
+func NewVHostSourceProducer(
+	methods []string,
+	hosts dictionary.Source,
+) *VHostProducer {
+	return &VHostProducer{
+		methods: methods,
+		hosts:   hosts,
+	}
+}
+
+// VHostProducer produces a target for each host and method, all of them requesting the url being scanned:
+// only their Host header changes
+type VHostProducer struct {
+	methods []string
+	hosts   dictionary.Source
+}
+
+func (p *VHostProducer) Produce(ctx context.Context) <-chan scan.Target {
+	targets := make(chan scan.Target, 10)
+
+	go func() {
+		defer close(targets)
+
+		for host := range p.hosts.Entries(ctx) {
+			for _, method := range p.methods {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+					// a virtual host is reported as it answers, its redirects are not followed (the target has no depth)
+					targets <- scan.Target{
+						Method: method,
+						Host:   host,
+					}
+				}
+			}
+		}
+	}()
+
+	return targets
+}
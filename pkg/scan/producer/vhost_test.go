@@ -0,0 +1,35 @@
+package producer_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVHostProducerShouldProduce(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewVHostProducer(
+		[]string{http.MethodGet, http.MethodPost},
+		[]string{"admin.example.com", "dev.example.com"},
+	)
+
+	results := make([]scan.Target, 0, 4)
+
+	for r := range sut.Produce(context.Background()) {
+		results = append(results, r)
+	}
+
+	expectedResults := []scan.Target{
+		{Method: http.MethodGet, Host: "admin.example.com"},
+		{Method: http.MethodPost, Host: "admin.example.com"},
+		{Method: http.MethodGet, Host: "dev.example.com"},
+		{Method: http.MethodPost, Host: "dev.example.com"},
+	}
+
+	assert.Equal(t, expectedResults, results)
+}
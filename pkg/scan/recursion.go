@@ -0,0 +1,147 @@
+package scan
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// RecursionBreadthFirst scans the directories found once the targets found before them have been taken,
+	// level by level: the whole dictionary first, then the directories found with it and so on
+	RecursionBreadthFirst = "bfs"
+	// RecursionDepthFirst scans the directories found before anything else, reaching the nested ones sooner
+	RecursionDepthFirst = "dfs"
+)
+
+// RecursionStrategies contains all the recursion strategies available
+var RecursionStrategies = []string{RecursionBreadthFirst, RecursionDepthFirst}
+
+// IsValidRecursionStrategy returns true when the strategy is one of the available ones
+func IsValidRecursionStrategy(strategy string) bool {
+	for _, s := range RecursionStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rootTarget tracks a target of the producer until it has been completely processed, including everything found
+// recursively starting from it; pending is accessed atomically
+type rootTarget struct {
+	pending int64
+	target  Target
+}
+
+func newRootTarget(target Target) *rootTarget {
+	return &rootTarget{pending: 1, target: target}
+}
+
+func (r *rootTarget) add() {
+	atomic.AddInt64(&r.pending, 1)
+}
+
+// done returns true when nothing is pending anymore for the target
+func (r *rootTarget) done() bool {
+	return atomic.AddInt64(&r.pending, -1) == 0
+}
+
+// pendingTargets are the targets of a directory found (or of the producer) not yet taken by the workers
+type pendingTargets struct {
+	targets <-chan Target
+
+	// root is the target of the producer the directory has been found from, nil for the producer
+	root *rootTarget
+
+	// removed is guarded by the mutex of the queue
+	removed bool
+}
+
+// workQueue holds the pending targets of the scan: with the breadth first strategy they are taken in the order
+// they have been added (FIFO), with the depth first one the last ones added are taken first (LIFO)
+type workQueue struct {
+	depthFirst bool
+
+	mx      sync.Mutex
+	cond    *sync.Cond
+	pending []*pendingTargets
+
+	// busy is the amount of workers taking or processing a target, which may add more pending targets
+	busy int
+}
+
+func newWorkQueue(producerTargets <-chan Target, recursionStrategy string) *workQueue {
+	q := &workQueue{
+		depthFirst: recursionStrategy == RecursionDepthFirst,
+		pending:    []*pendingTargets{{targets: producerTargets}},
+	}
+
+	q.cond = sync.NewCond(&q.mx)
+
+	return q
+}
+
+// next returns the pending targets to take the next target from, waiting while the queue is empty and other
+// workers may add to it; it returns false once the queue is empty and nothing can be added anymore.
+// Every successful invocation must be followed by one of done.
+func (q *workQueue) next() (*pendingTargets, bool) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	for len(q.pending) == 0 && q.busy > 0 {
+		q.cond.Wait()
+	}
+
+	if len(q.pending) == 0 {
+		return nil, false
+	}
+
+	q.busy++
+
+	if q.depthFirst {
+		return q.pending[len(q.pending)-1], true
+	}
+
+	return q.pending[0], true
+}
+
+// done signals that the target taken from the pending targets returned by next has been processed
+// (or that none could be taken)
+func (q *workQueue) done() {
+	q.mx.Lock()
+	q.busy--
+	q.mx.Unlock()
+
+	q.cond.Broadcast()
+}
+
+func (q *workQueue) add(p *pendingTargets) {
+	q.mx.Lock()
+	q.pending = append(q.pending, p)
+	q.mx.Unlock()
+
+	q.cond.Broadcast()
+}
+
+// remove removes the pending targets once all of them have been taken, it returns false when they have already
+// been removed by another worker
+func (q *workQueue) remove(p *pendingTargets) bool {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	if p.removed {
+		return false
+	}
+
+	p.removed = true
+
+	for i, pending := range q.pending {
+		if pending == p {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,94 @@
+package replay
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// queueSize is the amount of requests that can be waiting to be replayed before Notify blocks
+const queueSize = 100
+
+func NewNotifier(httpClient scan.Doer, proxyURL string, logger *logrus.Logger) *Notifier {
+	n := &Notifier{
+		httpClient: httpClient,
+		proxyURL:   proxyURL,
+		logger:     logger,
+		results:    make(chan scan.Result, queueSize),
+		done:       make(chan struct{}),
+	}
+
+	go n.replay()
+
+	return n
+}
+
+// Notifier replays the request of each result through a proxy (EG Burp Suite) using a client independent of
+// the one of the scan; the requests are replayed in the background, one at a time, and the failures are logged
+// without interrupting the scan
+type Notifier struct {
+	httpClient scan.Doer
+	proxyURL   string
+	logger     *logrus.Logger
+
+	results chan scan.Result
+	done    chan struct{}
+}
+
+// Notify queues the replay of the request of the result
+func (n *Notifier) Notify(_ url.URL, r scan.Result) {
+	n.results <- r
+}
+
+// Close waits for the queued requests to be replayed, Notify must not be invoked afterwards
+func (n *Notifier) Close() {
+	close(n.results)
+	<-n.done
+}
+
+func (n *Notifier) replay() {
+	defer close(n.done)
+
+	for r := range n.results {
+		l := n.logger.WithFields(logrus.Fields{"proxy": n.proxyURL, "url": r.URL.String(), "method": r.Target.Method})
+		if r.Target.Host != "" {
+			l = l.WithField("host", r.Target.Host)
+		}
+
+		replay(n.httpClient, r, l)
+	}
+}
+
+// replay performs again the request of the result, the failures are logged
+func replay(httpClient scan.Doer, r scan.Result, l *logrus.Entry) {
+	req, err := http.NewRequest(r.Target.Method, r.URL.String(), nil)
+	if err != nil {
+		l.WithError(err).Warn("failed to build the request to replay")
+		return
+	}
+
+	if r.Target.Host != "" {
+		req.Host = r.Target.Host
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		l.WithError(err).Warn("failed to replay the request through the proxy")
+		return
+	}
+
+	// the proxy records the response once it is read
+	if _, err := io.Copy(ioutil.Discard, res.Body); err != nil {
+		l.WithError(err).Warn("failed to read the response of the replayed request")
+	}
+
+	if err := res.Body.Close(); err != nil {
+		l.WithError(err).Warn("failed to close the response body of the replayed request")
+	}
+
+	l.WithField("status-code", res.StatusCode).Debug("Request replayed through the proxy")
+}
@@ -0,0 +1,79 @@
+package replay_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/replay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierShouldReplayTheRequestsThroughTheProxy(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	proxyServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer proxyServer.Close()
+
+	c := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(test.MustParseURL(t, proxyServer.URL))}}
+
+	sut := replay.NewNotifier(c, proxyServer.URL, logger)
+
+	target := test.MustParseURL(t, "http://mysite/")
+
+	sut.Notify(*target, scan.Result{
+		Target:     scan.Target{Path: "/home", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/home?debug=1"),
+	})
+	sut.Notify(*target, scan.Result{
+		Target:     scan.Target{Path: "/", Method: http.MethodDelete, Host: "admin.mysite"},
+		StatusCode: http.StatusForbidden,
+		URL:        *test.MustParseURL(t, "http://mysite/"),
+	})
+	sut.Close()
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "http://mysite/home?debug=1", r.RequestURI)
+		assert.Equal(t, "mysite", r.Host)
+	})
+	serverAssertion.At(1, func(r http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		// as for the scan through the http proxy, the virtual host is the one the request is sent to
+		assert.Equal(t, "http://admin.mysite/", r.RequestURI)
+		assert.Equal(t, "admin.mysite", r.Host)
+	})
+}
+
+func TestNotifierShouldLogTheFailuresWithoutStopping(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	proxyServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	proxyServer.Close()
+
+	c := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(test.MustParseURL(t, proxyServer.URL))}}
+
+	sut := replay.NewNotifier(c, proxyServer.URL, logger)
+
+	for i := 0; i < 2; i++ {
+		sut.Notify(*test.MustParseURL(t, "http://mysite/"), scan.Result{
+			Target:     scan.Target{Path: "/home", Method: http.MethodGet},
+			StatusCode: http.StatusOK,
+			URL:        *test.MustParseURL(t, "http://mysite/home"),
+		})
+	}
+	sut.Close()
+
+	assert.Equal(t, 0, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "failed to replay the request through the proxy")
+	assert.NotContains(t, loggerBuffer.String(), "Request replayed through the proxy")
+}
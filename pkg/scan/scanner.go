@@ -1,11 +1,19 @@
 package scan
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
@@ -17,6 +25,39 @@ type Target struct {
 	Path   string
 	Method string
 	Depth  int
+
+	// Host is the Host header of the request, when empty the host of the url is used
+	Host string `json:",omitempty"`
+
+	// Query is the encoded query string of the request, appended to the one of the url being scanned (if any)
+	Query string `json:",omitempty"`
+
+	// Word is the dictionary word replacing the placeholders of the request template, set only when scanning
+	// with a template (see WithRequestTemplate)
+	Word string `json:",omitempty"`
+
+	// SecondWord is the word of the second dictionary, set only when scanning with a template having two
+	// injection points
+	SecondWord string `json:",omitempty"`
+}
+
+// Words returns the words of the target replacing the placeholders of the request template, in order
+func (t Target) Words() []string {
+	if t.SecondWord != "" {
+		return []string{t.Word, t.SecondWord}
+	}
+
+	if t.Word != "" {
+		return []string{t.Word}
+	}
+
+	return nil
+}
+
+// RequestTemplate builds the requests of the targets scanned with a template, replacing its placeholders with
+// the words of the target
+type RequestTemplate interface {
+	NewRequest(ctx context.Context, baseURL url.URL, words ...string) (*http.Request, error)
 }
 
 // Result represents the result of the scan of a single URL
@@ -24,14 +65,45 @@ type Result struct {
 	Target     Target
 	StatusCode int
 	URL        url.URL
+
+	// ContentLength is the length of the response body, when not reported by the server it is computed by reading
+	// the body; the compressed bodies (gzip and deflate) are decompressed first; -1 when unknown
+	ContentLength int64
+
+	// Location is the value of the Location header of the response, it is where the server redirects to
+	Location string
+
+	// Header contains the headers of the response, it is available to the result filters and the handlers of the
+	// accepted responses, it is discarded before the result is reported
+	Header http.Header `json:"-"`
+
+	// BodyHash is the hex encoded sha256 of the response body, used to compare responses (it is not saved in the
+	// output as it would be of little use to the reader)
+	BodyHash string `json:"-"`
+
+	// Duration is the time taken to perform the request and read the response body, the waits of the client
+	// (EG the rate limit, the delays or the retries) excluded
+	Duration time.Duration `json:"-"`
+
+	// Body contains the beginning of the response body, it is kept only when requested (see WithBodyKept)
+	// for the result filters and the handlers of the accepted responses, it is discarded before the result
+	// is reported
+	Body []byte `json:"-"`
+
+	// BodyTruncated is true when the response body is longer than the bytes read by the scanner (see
+	// WithBodyReadLimit): BodyHash is the one of the part read
+	BodyTruncated bool `json:",omitempty"`
 }
 
 // NewResult creates a new instance of the Result entity based on the Target and Response
 func NewResult(target Target, response *http.Response) Result {
 	return Result{
-		Target:     target,
-		StatusCode: response.StatusCode,
-		URL:        *response.Request.URL,
+		Target:        target,
+		StatusCode:    response.StatusCode,
+		URL:           *response.Request.URL,
+		ContentLength: response.ContentLength,
+		Location:      response.Header.Get("Location"),
+		Header:        response.Header,
 	}
 }
 
@@ -41,53 +113,127 @@ func NewScanner(
 	reproducer ReProducer,
 	resultFilter ResultFilter,
 	logger *logrus.Logger,
+	opts ...Option,
 ) *Scanner {
-	return &Scanner{
-		httpClient:   httpClient,
-		producer:     producer,
-		reproducer:   reproducer,
-		resultFilter: resultFilter,
-		logger:       logger,
+	s := &Scanner{
+		httpClient:    httpClient,
+		producer:      producer,
+		reproducer:    reproducer,
+		resultFilters: []ResultFilter{resultFilter},
+		logger:        logger,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 type Scanner struct {
-	httpClient   Doer
-	producer     Producer
-	reproducer   ReProducer
-	resultFilter ResultFilter
-	logger       *logrus.Logger
+	// requestsCount, reservedRequests and counters are accessed atomically, they are the first fields to guarantee
+	// their 64-bit alignment
+	requestsCount    int64
+	reservedRequests int64
+	counters         counters
+
+	httpClient Doer
+	producer   Producer
+	reproducer ReProducer
+	logger     *logrus.Logger
+
+	// resultFilters are the filter passed to NewScanner followed by the ones added via the options, in order
+	resultFilters []ResultFilter
+
+	targetCompletedHandlers  []func(Target)
+	responseHandlers         []func(Result)
+	responseAcceptedHandlers []func(Result)
+
+	// isInScope is nil when the scan is not restricted to any host
+	isInScope func(u *url.URL) bool
+
+	// recursionStrategy is one of RecursionStrategies, breadth first when empty
+	recursionStrategy string
+
+	// requestTemplate is nil when the requests are made of the paths of the targets
+	requestTemplate RequestTemplate
+
+	// maxBodySize is the amount of bytes of the response body kept in the results, 0 when not kept
+	maxBodySize int64
+
+	// maxBodyReadSize is the amount of bytes of the response body read, 0 when the whole body is read
+	maxBodyReadSize int64
+
+	// maxRequests is 0 when the amount of requests is not limited
+	maxRequests        int64
+	onLimitReached     func()
+	limitReachedSignal sync.Once
+
+	// resumed is closed when the paused scan is resumed, it is nil while the scan is not paused
+	resumed   chan struct{}
+	resumedMx sync.Mutex
+}
+
+// Pause stops the scan from performing new requests until Resume is invoked, the requests in flight are completed;
+// it is safe for concurrent use
+func (s *Scanner) Pause() {
+	s.resumedMx.Lock()
+	defer s.resumedMx.Unlock()
+
+	if s.resumed == nil {
+		s.resumed = make(chan struct{})
+	}
+}
+
+// Resume resumes the scan paused via Pause, it is safe for concurrent use
+func (s *Scanner) Resume() {
+	s.resumedMx.Lock()
+	defer s.resumedMx.Unlock()
+
+	if s.resumed != nil {
+		close(s.resumed)
+		s.resumed = nil
+	}
+}
+
+// IsPaused returns true while the scan is paused
+func (s *Scanner) IsPaused() bool {
+	s.resumedMx.Lock()
+	defer s.resumedMx.Unlock()
+
+	return s.resumed != nil
 }
 
 func (s *Scanner) Scan(ctx context.Context, baseURL *url.URL, workers int) <-chan Result {
 	resultChannel := make(chan Result, workers)
 
-	u := normalizeBaseURL(*baseURL)
+	run := &scanRun{
+		baseURL:    normalizeBaseURL(*baseURL),
+		reproducer: s.reproducer.Reproduce(ctx),
+		queue:      newWorkQueue(s.producer.Produce(ctx), s.recursionStrategy),
+		results:    resultChannel,
+	}
 
 	wg := sync.WaitGroup{}
 
-	producerChannel := s.producer.Produce(ctx)
-	reproducer := s.reproducer.Reproduce(ctx)
-
 	wg.Add(workers)
 
 	for i := 0; i < workers; i++ {
 		go func() {
 			defer wg.Done()
 
-			for {
-				select {
-				case <-ctx.Done():
-					s.logger.Debug("terminating worker: context cancellation")
-				case target, ok := <-producerChannel:
-					if !ok {
-						s.logger.Debug("terminating worker: producer channel closed")
-						return
-					}
-
-					s.processTarget(u, target, reproducer, resultChannel)
+			for ctx.Err() == nil {
+				pending, ok := run.queue.next()
+				if !ok {
+					s.logger.Debug("terminating worker: no target left")
+					return
 				}
+
+				s.processNext(ctx, run, pending)
+				run.queue.done()
 			}
+
+			s.logger.Debug("terminating worker: context cancellation")
 		}()
 	}
 
@@ -99,73 +245,366 @@ func (s *Scanner) Scan(ctx context.Context, baseURL *url.URL, workers int) <-cha
 	return resultChannel
 }
 
-func (s *Scanner) processTarget(
-	baseURL url.URL,
-	target Target,
-	reproducer func(r Result) <-chan Target,
-	results chan<- Result,
-) {
+// scanRun holds what is shared by the workers of a scan
+type scanRun struct {
+	baseURL    url.URL
+	reproducer func(r Result) <-chan Target
+	queue      *workQueue
+	results    chan<- Result
+}
+
+// processNext takes the next target from the pending targets and processes it, when none is left they are
+// removed from the queue
+func (s *Scanner) processNext(ctx context.Context, run *scanRun, pending *pendingTargets) {
+	root := pending.root
+
+	// the target is counted before being taken, otherwise the worker finding the directory drained could see
+	// nothing pending for the root while the last target is still about to be processed
+	if root != nil {
+		root.add()
+	}
+
+	var (
+		target Target
+		ok     bool
+	)
+
+	select {
+	case <-ctx.Done():
+		return
+	case target, ok = <-pending.targets:
+	}
+
+	if !ok {
+		if run.queue.remove(pending) && root != nil {
+			s.releaseRootTarget(ctx, root)
+		}
+
+		if root != nil {
+			s.releaseRootTarget(ctx, root)
+		}
+
+		return
+	}
+
+	// both the cases can be ready at the same time, the cancellation has to take precedence
+	if ctx.Err() != nil {
+		return
+	}
+
+	if root == nil {
+		root = newRootTarget(target)
+	}
+
+	s.processTarget(ctx, run, target, root)
+	s.releaseRootTarget(ctx, root)
+}
+
+// releaseRootTarget invokes the handlers of the completed targets once nothing is pending for the root target,
+// unless the scan has been canceled as it may not have been processed completely
+func (s *Scanner) releaseRootTarget(ctx context.Context, root *rootTarget) {
+	if !root.done() || ctx.Err() != nil {
+		return
+	}
+
+	for _, handler := range s.targetCompletedHandlers {
+		handler(root.target)
+	}
+}
+
+// RequestsCount returns the amount of requests performed so far, the ones skipped because redundant excluded
+func (s *Scanner) RequestsCount() int64 {
+	return atomic.LoadInt64(&s.requestsCount)
+}
+
+func (s *Scanner) processTarget(ctx context.Context, run *scanRun, target Target, root *rootTarget) {
 	l := s.logger.WithFields(logrus.Fields{
 		"method": target.Method,
 		"depth":  target.Depth,
 		"path":   target.Path,
 	})
 
-	l.Debug("Working")
+	if target.Host != "" {
+		l = l.WithField("host", target.Host)
+	}
 
-	u := buildURL(baseURL, target)
+	if target.Word != "" {
+		l = l.WithField("word", target.Word)
+	}
+
+	if target.SecondWord != "" {
+		l = l.WithField("second-word", target.SecondWord)
+	}
+
+	l.Debug("Working")
 
-	req, err := http.NewRequest(target.Method, u.String(), nil)
+	req, err := s.newRequest(ctx, run.baseURL, target)
 	if err != nil {
 		l.WithError(err).Error("failed to build request")
 		return
 	}
 
-	s.processRequest(l, req, target, results, reproducer, baseURL)
+	s.processRequest(ctx, l, req, target, run, root)
+}
+
+// newRequest builds the request of the target, from the request template when the scanner uses one
+func (s *Scanner) newRequest(ctx context.Context, baseURL url.URL, target Target) (*http.Request, error) {
+	if s.requestTemplate != nil {
+		return s.requestTemplate.NewRequest(ctx, baseURL, target.Words()...)
+	}
+
+	u := buildURL(baseURL, target)
+
+	req, err := http.NewRequestWithContext(ctx, target.Method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Host != "" {
+		req.Host = target.Host
+	}
+
+	return req, nil
+}
+
+// traceResponseTime returns the request tracing when a connection is obtained for it, and a function returning the
+// time elapsed since then: the connection is obtained once the client is done waiting (EG for the rate limit), and
+// when the request is retried or redirected the last attempt is timed
+func traceResponseTime(req *http.Request) (*http.Request, func() time.Duration) {
+	start := time.Now().UnixNano()
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			atomic.StoreInt64(&start, time.Now().UnixNano())
+		},
+	}
+
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), func() time.Duration {
+		return time.Duration(time.Now().UnixNano() - atomic.LoadInt64(&start))
+	}
 }
 
 func (s *Scanner) processRequest(
+	ctx context.Context,
 	l *logrus.Entry,
 	req *http.Request,
 	target Target,
-	results chan<- Result,
-	reproducer func(r Result) <-chan Target,
-	baseURL url.URL,
+	run *scanRun,
+	root *rootTarget,
 ) {
+	if !s.waitWhilePaused(ctx) {
+		l.Debug("skipping, the scan has been canceled while paused")
+		return
+	}
+
+	if !s.reserveRequest() {
+		l.Debug("skipping, the requests limit has been reached")
+		return
+	}
+
+	req, responseTime := traceResponseTime(req)
+
 	res, err := s.httpClient.Do(req)
 	if err != nil && strings.Contains(err.Error(), client.ErrRequestRedundant.Error()) {
+		s.releaseRequest()
 		l.WithError(err).Debug("skipping, request was already made")
+
+		return
+	}
+
+	atomic.AddInt64(&s.requestsCount, 1)
+
+	// the request has been interrupted by the cancellation of the scan, it is not a failure
+	if err != nil && ctx.Err() != nil {
+		l.WithError(err).Debug("request canceled")
+		return
+	}
+
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		s.counters.addError()
+		l.WithError(err).Warn("request timed out")
+
 		return
 	}
 
 	if err != nil {
+		s.counters.addError()
 		l.WithError(err).Error("failed to perform request")
+
 		return
 	}
 
+	s.counters.addResponse(res.StatusCode)
+
+	// the length and the body matched by the filters are the ones of the decompressed body
+	decompressBody(l, res)
+
+	contentLength, bodyHash, body, truncated := readBody(l, res, s.maxBodySize, s.maxBodyReadSize)
+
+	result := NewResult(target, res)
+	result.BodyHash = bodyHash
+	result.Body = body
+	result.BodyTruncated = truncated
+	result.Duration = responseTime()
+
+	if result.ContentLength < 0 {
+		result.ContentLength = contentLength
+	}
+
 	if err := res.Body.Close(); err != nil {
 		l.WithError(err).Warn("failed to close response body")
 	}
 
-	result := NewResult(target, res)
+	l = l.WithFields(logrus.Fields{
+		"url":            req.URL.String(),
+		"status-code":    result.StatusCode,
+		"content-length": result.ContentLength,
+		"duration":       result.Duration.String(),
+		"protocol":       res.Proto,
+	})
+
+	if l.Logger.IsLevelEnabled(logrus.TraceLevel) {
+		l.WithField("headers", res.Header).Trace("Response headers")
+	}
 
-	if s.resultFilter.ShouldIgnore(result) {
+	for _, handler := range s.responseHandlers {
+		handler(result)
+	}
+
+	if s.shouldIgnore(result) {
+		l.Debug("Response ignored by the filters")
 		return
 	}
 
-	results <- result
+	l.Debug("Response accepted by the filters")
+
+	for _, handler := range s.responseAcceptedHandlers {
+		handler(result)
+	}
+
+	// the body and the headers are not needed by the filters and the handlers anymore, they would just take memory
+	result.Body = nil
+	result.Header = nil
+
+	run.results <- result
 
-	redirectTarget, shouldRedirect := s.shouldRedirect(l, req, res, target.Depth)
+	redirectTarget, shouldRedirect := s.shouldRedirect(l, run.baseURL, req, res, target.Depth)
 	if shouldRedirect {
-		s.processTarget(baseURL, redirectTarget, reproducer, results)
+		s.processTarget(ctx, run, redirectTarget, root)
 	}
 
-	for newTarget := range reproducer(result) {
-		s.processTarget(baseURL, newTarget, reproducer, results)
+	// the targets found starting from the result are scanned according to the recursion strategy, until all of
+	// them have been taken they are pending for the root target
+	root.add()
+	run.queue.add(&pendingTargets{targets: run.reproducer(result), root: root})
+}
+
+// waitWhilePaused blocks until the scan is resumed, it returns false when ctx is done meanwhile
+func (s *Scanner) waitWhilePaused(ctx context.Context) bool {
+	s.resumedMx.Lock()
+	resumed := s.resumed
+	s.resumedMx.Unlock()
+
+	if resumed == nil {
+		return true
+	}
+
+	select {
+	case <-resumed:
+		return true
+	case <-ctx.Done():
+		return false
 	}
 }
 
-func (s *Scanner) shouldRedirect(l *logrus.Entry, req *http.Request, res *http.Response, targetDepth int) (Target, bool) {
+// reserveRequest returns false when the request can't be performed as the requests limit has been reached
+func (s *Scanner) reserveRequest() bool {
+	if s.maxRequests <= 0 {
+		return true
+	}
+
+	if atomic.AddInt64(&s.reservedRequests, 1) <= s.maxRequests {
+		return true
+	}
+
+	s.limitReachedSignal.Do(s.onLimitReached)
+
+	return false
+}
+
+// releaseRequest gives back a reserved request that has not been performed
+func (s *Scanner) releaseRequest() {
+	if s.maxRequests > 0 {
+		atomic.AddInt64(&s.reservedRequests, -1)
+	}
+}
+
+// shouldIgnore invokes the filters in order, stopping at the first one ignoring the result
+func (s *Scanner) shouldIgnore(result Result) bool {
+	for _, resultFilter := range s.resultFilters {
+		if resultFilter.ShouldIgnore(result) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readBody reads the response body returning its length, its hash and its first maxBodySize bytes;
+// the length is needed when the server doesn't specify the Content-Length (EG chunked or compressed responses).
+// When maxReadSize is not 0 only the first maxReadSize bytes are read: if the body is longer it is reported
+// as truncated, the hash is the one of the part read and the length is unknown (-1).
+func readBody(l *logrus.Entry, res *http.Response, maxBodySize, maxReadSize int64) (int64, string, []byte, bool) {
+	h := sha256.New()
+	body := &limitedBuffer{limit: maxBodySize}
+
+	var reader io.Reader = res.Body
+	if maxReadSize > 0 {
+		// one more byte is read to know whether the body is longer than the limit
+		reader = io.LimitReader(res.Body, maxReadSize+1)
+	}
+
+	contentLength, err := io.Copy(io.MultiWriter(h, body), reader)
+	if err != nil {
+		l.WithError(err).Warn("failed to read response body")
+		return -1, "", nil, false
+	}
+
+	if maxReadSize > 0 && contentLength > maxReadSize {
+		l.WithField("max-body-size", maxReadSize).Debug("The response body is truncated")
+		return -1, hex.EncodeToString(h.Sum(nil)), body.Bytes(), true
+	}
+
+	return contentLength, hex.EncodeToString(h.Sum(nil)), body.Bytes(), false
+}
+
+// limitedBuffer is a writer keeping only the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int64
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - int64(b.Len()); remaining > 0 {
+		if int64(len(p)) > remaining {
+			_, _ = b.Buffer.Write(p[:remaining])
+		} else {
+			_, _ = b.Buffer.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
+
+// shouldRedirect returns the target to request following the redirect of the response, relative to baseURL like the
+// other targets; the redirects pointing outside of it can't be followed
+func (s *Scanner) shouldRedirect(
+	l *logrus.Entry,
+	baseURL url.URL,
+	req *http.Request,
+	res *http.Response,
+	targetDepth int,
+) (Target, bool) {
 	if targetDepth == 0 {
 		l.Debug("depth is 0, not following any redirect")
 		return Target{}, false
@@ -210,18 +649,34 @@ func (s *Scanner) shouldRedirect(l *logrus.Entry, req *http.Request, res *http.R
 		return Target{}, false
 	}
 
+	// the location may be relative to the url requested
+	redirectURL := req.URL.ResolveReference(u)
+
+	if s.isInScope != nil && !s.isInScope(redirectURL) {
+		l.WithField("location", location).Info("Out of scope redirect, it will not be requested")
+		return Target{}, false
+	}
+
+	// the targets are relative to the url being scanned, the redirects to other hosts can't be followed
 	if u.Host != "" && u.Host != req.Host {
 		l.Debug("skipping redirect, pointing to a different host")
 		return Target{}, false
 	}
 
+	if !strings.HasPrefix(redirectURL.Path, baseURL.Path) {
+		l.WithField("location", location).Debug("skipping redirect, pointing outside of the path being scanned")
+		return Target{}, false
+	}
+
 	return Target{
-		Path:   u.Path,
+		Path:   "/" + strings.TrimPrefix(redirectURL.Path, baseURL.Path),
 		Method: redirectMethod,
 		Depth:  targetDepth - 1,
 	}, true
 }
 
+// normalizeBaseURL makes the path of the url being scanned end with a slash, so that the targets are appended to it
+// (EG http://site/app is scanned as http://site/app/)
 func normalizeBaseURL(baseURL url.URL) url.URL {
 	if strings.HasSuffix(baseURL.Path, "/") {
 		return baseURL
@@ -229,11 +684,36 @@ func normalizeBaseURL(baseURL url.URL) url.URL {
 
 	baseURL.Path += "/"
 
+	if baseURL.RawPath != "" {
+		baseURL.RawPath += "/"
+	}
+
 	return baseURL
 }
 
+// TargetURL returns the url requested by the scanner for the given target when scanning baseURL
+func TargetURL(baseURL url.URL, target Target) url.URL {
+	return buildURL(normalizeBaseURL(baseURL), target)
+}
+
+// buildURL appends the path of the target to the one of the base url, which must end with a slash
 func buildURL(baseURL url.URL, target Target) url.URL {
+	// the escaped path is set only when the path contains characters that would be escaped differently (EG %2F),
+	// it would be ignored once not matching the path anymore
+	if baseURL.RawPath != "" {
+		targetPath := url.URL{Path: target.Path}
+		baseURL.RawPath = urlpath.Join(baseURL.RawPath, targetPath.EscapedPath())
+	}
+
 	baseURL.Path = urlpath.Join(baseURL.Path, target.Path)
 
+	switch {
+	case target.Query == "":
+	case baseURL.RawQuery == "":
+		baseURL.RawQuery = target.Query
+	default:
+		baseURL.RawQuery += "&" + target.Query
+	}
+
 	return baseURL
 }
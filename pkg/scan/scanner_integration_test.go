@@ -1,8 +1,19 @@
 package scan_test
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +25,9 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// emptyBodyHash is the sha256 of an empty body
+const emptyBodyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
 func TestScanningWithEmptyProducerWillProduceNoResults(t *testing.T) {
 	logger, _ := test.NewLogger()
 
@@ -49,14 +63,10 @@ func TestScannerWillLogAnErrorWithInvalidDictionary(t *testing.T) {
 	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		1000,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
 		test.MustParseURL(t, testServer.URL),
 	)
 	assert.NoError(t, err)
@@ -103,14 +113,10 @@ func TestScannerWillNotRedirectIfStatusCodeIsInvalid(t *testing.T) {
 	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		1000,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
 		test.MustParseURL(t, testServer.URL),
 	)
 	assert.NoError(t, err)
@@ -127,6 +133,9 @@ func TestScannerWillNotRedirectIfStatusCodeIsInvalid(t *testing.T) {
 	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 10)
 
 	for r := range resultsChannel {
+		assert.True(t, r.Duration > 0)
+		r.Duration = 0 // it depends on the environment, so it is excluded from the comparison
+
 		results = append(results, r)
 	}
 
@@ -135,6 +144,8 @@ func TestScannerWillNotRedirectIfStatusCodeIsInvalid(t *testing.T) {
 			Target:     scan.Target{Path: "/home", Method: http.MethodGet, Depth: 3},
 			StatusCode: http.StatusOK,
 			URL:        *test.MustParseURL(t, testServer.URL+"/home"),
+			Location:   "/potato",
+			BodyHash:   emptyBodyHash,
 		},
 	}
 
@@ -172,14 +183,10 @@ func TestScannerWillChangeMethodForRedirect(t *testing.T) {
 	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		1000,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
 		test.MustParseURL(t, testServer.URL),
 	)
 	assert.NoError(t, err)
@@ -196,6 +203,9 @@ func TestScannerWillChangeMethodForRedirect(t *testing.T) {
 	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
 
 	for r := range resultsChannel {
+		assert.True(t, r.Duration > 0)
+		r.Duration = 0 // it depends on the environment, so it is excluded from the comparison
+
 		results = append(results, r)
 	}
 
@@ -204,11 +214,14 @@ func TestScannerWillChangeMethodForRedirect(t *testing.T) {
 			Target:     scan.Target{Path: "/home", Method: http.MethodPatch, Depth: 3},
 			StatusCode: http.StatusMovedPermanently,
 			URL:        *test.MustParseURL(t, testServer.URL+"/home"),
+			Location:   "/potato",
+			BodyHash:   emptyBodyHash,
 		},
 		{
 			Target:     scan.Target{Path: "/potato", Method: http.MethodGet, Depth: 2},
 			StatusCode: http.StatusCreated,
 			URL:        *test.MustParseURL(t, testServer.URL+"/potato"),
+			BodyHash:   emptyBodyHash,
 		},
 	}
 
@@ -218,6 +231,82 @@ func TestScannerWillChangeMethodForRedirect(t *testing.T) {
 	assert.Equal(t, 4, serverAssertion.Len())
 }
 
+func TestScannerShouldPreserveThePathOfTheURLBeingScanned(t *testing.T) {
+	testCases := []struct {
+		basePath string
+	}{
+		{basePath: "/app/v1"},
+		{basePath: "/app/v1/"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.basePath, func(t *testing.T) {
+			logger, loggerBuffer := test.NewLogger()
+
+			prod := producer.NewDictionaryProducer(
+				[]string{http.MethodGet},
+				[]string{"home", "/about", "admin"},
+				1,
+			)
+
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/app/v1/home":
+						http.Redirect(w, r, "/app/v1/potato", http.StatusMovedPermanently)
+					case "/app/v1/about":
+						http.Redirect(w, r, "contact", http.StatusMovedPermanently)
+					case "/app/v1/admin":
+						http.Redirect(w, r, "/login", http.StatusMovedPermanently)
+					case "/app/v1/potato", "/app/v1/contact":
+						w.WriteHeader(http.StatusOK)
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				}),
+			)
+			defer testServer.Close()
+
+			c, err := client.NewClientFromConfig(
+				client.Config{
+					TimeoutInMilliseconds: 1000,
+				},
+				test.MustParseURL(t, testServer.URL),
+			)
+			assert.NoError(t, err)
+
+			sut := scan.NewScanner(
+				c,
+				prod,
+				producer.NewReProducer(prod),
+				filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+				logger,
+			)
+
+			found := make([]string, 0, 5)
+			for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL+tc.basePath), 1) {
+				found = append(found, r.URL.Path)
+			}
+
+			sort.Strings(found)
+
+			assert.Equal(
+				t,
+				[]string{"/app/v1/about", "/app/v1/admin", "/app/v1/contact", "/app/v1/home", "/app/v1/potato"},
+				found,
+			)
+
+			serverAssertion.Range(func(_ int, r http.Request) {
+				assert.True(t, strings.HasPrefix(r.URL.Path, "/app/v1/"), r.URL.Path)
+				assert.NotContains(t, r.URL.Path, "/app/v1/app/")
+			})
+
+			assert.Contains(t, loggerBuffer.String(), "skipping redirect, pointing outside of the path being scanned")
+		})
+	}
+}
+
 func TestScannerWhenOutOfDepthWillNotFollowRedirect(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
@@ -240,14 +329,10 @@ func TestScannerWhenOutOfDepthWillNotFollowRedirect(t *testing.T) {
 	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		1000,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
 		test.MustParseURL(t, testServer.URL),
 	)
 	assert.NoError(t, err)
@@ -264,6 +349,9 @@ func TestScannerWhenOutOfDepthWillNotFollowRedirect(t *testing.T) {
 	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
 
 	for r := range resultsChannel {
+		assert.True(t, r.Duration > 0)
+		r.Duration = 0 // it depends on the environment, so it is excluded from the comparison
+
 		results = append(results, r)
 	}
 
@@ -272,6 +360,8 @@ func TestScannerWhenOutOfDepthWillNotFollowRedirect(t *testing.T) {
 			Target:     scan.Target{Path: "/home", Method: http.MethodPatch, Depth: 0},
 			StatusCode: http.StatusMovedPermanently,
 			URL:        *test.MustParseURL(t, testServer.URL+"/home"),
+			Location:   "/potato",
+			BodyHash:   emptyBodyHash,
 		},
 	}
 
@@ -306,14 +396,10 @@ func TestScannerWillSkipRedirectWhenLocationHostIsDifferent(t *testing.T) {
 	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		1000,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
 		test.MustParseURL(t, testServer.URL),
 	)
 	assert.NoError(t, err)
@@ -330,6 +416,9 @@ func TestScannerWillSkipRedirectWhenLocationHostIsDifferent(t *testing.T) {
 	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
 
 	for r := range resultsChannel {
+		assert.True(t, r.Duration > 0)
+		r.Duration = 0 // it depends on the environment, so it is excluded from the comparison
+
 		results = append(results, r)
 	}
 
@@ -338,6 +427,8 @@ func TestScannerWillSkipRedirectWhenLocationHostIsDifferent(t *testing.T) {
 			Target:     scan.Target{Path: "/home", Method: http.MethodPatch, Depth: 3},
 			StatusCode: http.StatusMovedPermanently,
 			URL:        *test.MustParseURL(t, testServer.URL+"/home"),
+			Location:   "http://gibberish/potato",
+			BodyHash:   emptyBodyHash,
 		},
 	}
 
@@ -349,6 +440,73 @@ func TestScannerWillSkipRedirectWhenLocationHostIsDifferent(t *testing.T) {
 	assert.Equal(t, 2, serverAssertion.Len())
 }
 
+func TestScannerWillNotRequestRedirectsOutOfScope(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodPatch},
+		[]string{"/home"},
+		3,
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				http.Redirect(w, r, "http://gibberish/potato", http.StatusMovedPermanently)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+		scan.WithScope(func(u *url.URL) bool { return u.Hostname() != "gibberish" }),
+	)
+
+	results := make([]scan.Result, 0, 2)
+	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+
+	for r := range resultsChannel {
+		assert.True(t, r.Duration > 0)
+		r.Duration = 0 // it depends on the environment, so it is excluded from the comparison
+
+		results = append(results, r)
+	}
+
+	expectedResults := []scan.Result{
+		{
+			Target:     scan.Target{Path: "/home", Method: http.MethodPatch, Depth: 3},
+			StatusCode: http.StatusMovedPermanently,
+			URL:        *test.MustParseURL(t, testServer.URL+"/home"),
+			Location:   "http://gibberish/potato",
+			BodyHash:   emptyBodyHash,
+		},
+	}
+
+	assert.Equal(t, expectedResults, results)
+
+	loggerBufferAsString := loggerBuffer.String()
+	assert.Contains(t, loggerBufferAsString, "Out of scope redirect, it will not be requested")
+	assert.NotContains(t, loggerBufferAsString, "error")
+	assert.Equal(t, 2, serverAssertion.Len())
+}
+
 func TestScannerWillIgnoreRequestRedundantError(t *testing.T) {
 	logger, loggerBuffer := test.NewLogger()
 
@@ -366,14 +524,10 @@ func TestScannerWillIgnoreRequestRedundantError(t *testing.T) {
 	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		1000,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
 		test.MustParseURL(t, testServer.URL),
 	)
 	assert.NoError(t, err)
@@ -401,34 +555,131 @@ func TestScannerWillIgnoreRequestRedundantError(t *testing.T) {
 	assert.Equal(t, 1, serverAssertion.Len())
 }
 
-func TestCanCancelScanUsingContext(t *testing.T) {
+func TestScannerWillWarnAndContinueWhenARequestTimesOut(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/slow", "/home"},
+		0,
+	)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/slow" {
+				time.Sleep(time.Millisecond * 200)
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 50,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	results := make([]scan.Result, 0, 1)
+	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+
+	for r := range resultsChannel {
+		results = append(results, r)
+	}
+
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "/home", results[0].URL.Path)
+
+	loggerBufferAsString := loggerBuffer.String()
+	assert.Contains(t, loggerBufferAsString, "level=warning msg=\"request timed out\"")
+	assert.Contains(t, loggerBufferAsString, "Client.Timeout exceeded")
+	assert.NotContains(t, loggerBufferAsString, "failed to perform request")
+}
+
+func TestScannerWillReportContentLengthForHeadRequests(t *testing.T) {
 	logger, _ := test.NewLogger()
 
 	prod := producer.NewDictionaryProducer(
-		[]string{http.MethodGet, http.MethodPatch, http.MethodDelete, http.MethodPost, http.MethodPut},
-		[]string{"/home", "/index", "/about", "/search", "/jobs", "robots.txt", "/subscription", "/orders"},
-		200000000,
+		[]string{http.MethodHead},
+		[]string{"/home"},
+		0,
 	)
 
 	testServer, serverAssertion := test.NewServerWithAssertion(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("hello")) //nolint:errcheck
 		}),
 	)
 	defer testServer.Close()
 
-	// the depth of the dictionary and the fact that the server returns always a
-	// http.StatusOK should keep this test running forever in case the cancellation would not work
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	results := make([]scan.Result, 0, 1)
+	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+
+	for r := range resultsChannel {
+		results = append(results, r)
+	}
+
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, http.StatusOK, results[0].StatusCode)
+	assert.Equal(t, int64(5), results[0].ContentLength)
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+	})
+}
+
+func TestScannerShouldTimeTheWholeResponseExcludingTheWaitsOfTheClient(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/home"}, 0)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+
+			// the time taken to send the body counts as well
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte("hello")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
 
 	c, err := client.NewClientFromConfig(
-		1000,
-		nil,
-		"",
-		false,
-		nil,
-		nil,
-		true,
-		false,
+		client.Config{
+			TimeoutInMilliseconds: 2000,
+			DelayInMilliseconds:   500,
+		},
 		test.MustParseURL(t, testServer.URL),
 	)
 	assert.NoError(t, err)
@@ -441,31 +692,1217 @@ func TestCanCancelScanUsingContext(t *testing.T) {
 		logger,
 	)
 
-	ctx := context.Background()
-	ctx, cancelFunc := context.WithCancel(ctx)
+	results := make([]scan.Result, 0, 1)
 
-	resultsChannel := sut.Scan(ctx, test.MustParseURL(t, testServer.URL), 100)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
 
-	go func() {
-		time.Sleep(50 * time.Millisecond)
-		cancelFunc()
-	}()
+	assert.Equal(t, 1, len(results))
+	assert.True(t, results[0].Duration >= 200*time.Millisecond, results[0].Duration.String())
+	assert.True(t, results[0].Duration < 500*time.Millisecond, results[0].Duration.String())
+}
 
-	done := make(chan struct{})
+func TestScannerWillComputeContentLengthWhenNotReportedByTheServer(t *testing.T) {
+	testCases := []struct {
+		method                string
+		expectedContentLength int64
+	}{
+		{
+			method:                http.MethodGet,
+			expectedContentLength: 10,
+		},
+		{
+			// there is no body for HEAD requests
+			method:                http.MethodHead,
+			expectedContentLength: 0,
+		},
+	}
 
-	go func() {
-		for range resultsChannel {
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.method, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			prod := producer.NewDictionaryProducer(
+				[]string{tc.method},
+				[]string{"/home"},
+				0,
+			)
+
+			testServer, _ := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+
+					// flushing before writing the body forces a chunked response, without Content-Length
+					w.(http.Flusher).Flush()
+
+					_, _ = w.Write([]byte("hello")) //nolint:errcheck
+					w.(http.Flusher).Flush()
+					_, _ = w.Write([]byte("world")) //nolint:errcheck
+				}),
+			)
+			defer testServer.Close()
+
+			c, err := client.NewClientFromConfig(
+				client.Config{
+					TimeoutInMilliseconds: 1000,
+				},
+				test.MustParseURL(t, testServer.URL),
+			)
+			assert.NoError(t, err)
+
+			sut := scan.NewScanner(
+				c,
+				prod,
+				producer.NewReProducer(prod),
+				filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+				logger,
+			)
+
+			results := make([]scan.Result, 0, 1)
+			resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+
+			for r := range resultsChannel {
+				results = append(results, r)
+			}
 
-		}
-		done <- struct{}{}
-	}()
+			assert.Equal(t, 1, len(results))
+			assert.Equal(t, tc.expectedContentLength, results[0].ContentLength)
+		})
+	}
+}
 
-	select {
-	case <-done:
-		t.Log("result channel closed")
-	case <-time.After(time.Second * 8):
-		t.Fatalf("the scan should have terminated by now, something is wrong with the context cancellation")
+func TestScannerWillIgnoreResultsBasedOnContentLength(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/about", "/contacts"},
+		1,
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				_, _ = w.Write([]byte("not the custom not found page")) //nolint:errcheck
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("not found")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewContentLengthResultFilter([]int64{9}, nil),
+		logger,
+	)
+
+	results := make([]scan.Result, 0, 1)
+	resultsChannel := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+
+	for r := range resultsChannel {
+		results = append(results, r)
 	}
 
-	assert.True(t, serverAssertion.Len() > 1)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "/home", results[0].Target.Path)
+
+	// only the results not ignored are scanned further
+	assert.Equal(t, 6, serverAssertion.Len())
+}
+
+func TestScannerWillNotifyTheCompletionOfTheTargetsAfterTheirResults(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/about"},
+		1,
+	)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	var (
+		completedTargets []scan.Target
+		resultsCount     int
+	)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+		scan.WithTargetCompletedHandler(func(target scan.Target) {
+			completedTargets = append(completedTargets, target)
+		}),
+		// with depth first each target is completed, including its directory, before the next one is taken:
+		// with a single worker the targets are completed in the same order they are produced
+		scan.WithRecursionStrategy(scan.RecursionDepthFirst),
+	)
+
+	for range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		resultsCount++
+	}
+
+	assert.Equal(t, 1, resultsCount)
+
+	expectedTargets := []scan.Target{
+		{Path: "/home", Method: http.MethodGet, Depth: 1},
+		{Path: "/about", Method: http.MethodGet, Depth: 1},
+	}
+	assert.Equal(t, expectedTargets, completedTargets)
+}
+
+func TestScannerShouldScanTheDirectoriesFoundAccordingToTheRecursionStrategy(t *testing.T) {
+	testCases := []struct {
+		strategy                string
+		expectedPaths           []string
+		expectedCompletedTarget []string
+	}{
+		{
+			strategy: scan.RecursionBreadthFirst,
+			expectedPaths: []string{
+				"/home", "/about",
+				"/home/home", "/home/about",
+				"/about/home", "/about/about",
+			},
+			expectedCompletedTarget: []string{"/home", "/about"},
+		},
+		{
+			strategy: scan.RecursionDepthFirst,
+			expectedPaths: []string{
+				"/home",
+				"/home/home", "/home/home/home", "/home/home/about",
+				"/home/about", "/home/about/home", "/home/about/about",
+				"/about",
+				"/about/home", "/about/home/home", "/about/home/about",
+				"/about/about", "/about/about/home", "/about/about/about",
+			},
+			expectedCompletedTarget: []string{"/home", "/about"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.strategy, func(t *testing.T) {
+			logger, _ := test.NewLogger()
+
+			depth := 1
+			if tc.strategy == scan.RecursionDepthFirst {
+				depth = 2
+			}
+
+			prod := producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"home", "about"}, depth)
+
+			testServer, serverAssertion := test.NewServerWithAssertion(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			)
+			defer testServer.Close()
+
+			c, err := client.NewClientFromConfig(
+				client.Config{
+					TimeoutInMilliseconds: 1000,
+				},
+				test.MustParseURL(t, testServer.URL),
+			)
+			assert.NoError(t, err)
+
+			completedTargets := make([]string, 0, 2)
+
+			sut := scan.NewScanner(
+				c,
+				prod,
+				producer.NewReProducer(prod),
+				filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+				logger,
+				scan.WithRecursionStrategy(tc.strategy),
+				scan.WithTargetCompletedHandler(func(target scan.Target) {
+					completedTargets = append(completedTargets, "/"+target.Path)
+				}),
+			)
+
+			// with a single worker the requests are performed in the order the targets are taken
+			for range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+			}
+
+			paths := make([]string, 0, len(tc.expectedPaths))
+			serverAssertion.Range(func(_ int, r http.Request) {
+				paths = append(paths, r.URL.Path)
+			})
+
+			assert.Equal(t, tc.expectedPaths, paths)
+			assert.Equal(t, tc.expectedCompletedTarget, completedTargets)
+		})
+	}
+}
+
+func TestScannerShouldCompleteTheTargetsOnceTheirDirectoriesHaveBeenScannedWithManyWorkers(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	dictionary := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		dictionary = append(dictionary, "dir"+strconv.Itoa(i))
+	}
+
+	prod := producer.NewDictionaryProducer([]string{http.MethodGet}, dictionary, 1)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	for _, strategy := range scan.RecursionStrategies {
+		mx := sync.Mutex{}
+		requested := make(map[string]struct{})
+		completed := make(map[string]struct{})
+
+		sut := scan.NewScanner(
+			c,
+			prod,
+			producer.NewReProducer(prod),
+			filter.NewAggregateResultFilter(),
+			logger,
+			scan.WithRecursionStrategy(strategy),
+			scan.WithTargetCompletedHandler(func(target scan.Target) {
+				mx.Lock()
+				defer mx.Unlock()
+
+				// everything found starting from the target must have been requested already
+				for _, entry := range dictionary {
+					_, found := requested["/"+target.Path+"/"+entry]
+					assert.True(t, found, strategy+": /"+target.Path+"/"+entry+" not requested yet")
+				}
+
+				completed[target.Path] = struct{}{}
+			}),
+			// invoked before the result is reported, unlike the results read from the channel
+			scan.WithResponseAcceptedHandler(func(r scan.Result) {
+				mx.Lock()
+				defer mx.Unlock()
+
+				requested[r.URL.Path] = struct{}{}
+			}),
+		)
+
+		for range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 5) {
+		}
+
+		assert.Len(t, requested, 20+20*20, strategy)
+		assert.Len(t, completed, 20, strategy)
+	}
+
+	assert.Equal(t, 2*(20+20*20), serverAssertion.Len())
+}
+
+func TestScannerShouldReadTheBodiesUpToTheLimitAndReportThemAsTruncated(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/small":
+				_, _ = w.Write([]byte("small page")) //nolint:errcheck
+			case "/announced":
+				w.Header().Set("Content-Length", "1000")
+				_, _ = w.Write(bytes.Repeat([]byte("a"), 1000)) //nolint:errcheck
+			case "/endless":
+				// the body never ends, the scanner would hit the timeout if it read it completely
+				chunk := bytes.Repeat([]byte("b"), 1024)
+				for r.Context().Err() == nil {
+					if _, err := w.Write(chunk); err != nil {
+						return
+					}
+				}
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/small", "/announced", "/endless"},
+		0,
+	)
+
+	bodies := make(map[string][]byte)
+	mx := sync.Mutex{}
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+		scan.WithBodyReadLimit(100),
+		scan.WithBodyKept(50),
+		scan.WithResponseAcceptedHandler(func(r scan.Result) {
+			mx.Lock()
+			defer mx.Unlock()
+
+			bodies[r.URL.Path] = r.Body
+		}),
+	)
+
+	results := make(map[string]scan.Result)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results[r.URL.Path] = r
+	}
+
+	assert.Len(t, results, 3)
+
+	assert.False(t, results["/small"].BodyTruncated)
+	assert.Equal(t, int64(10), results["/small"].ContentLength)
+	assert.Equal(t, []byte("small page"), bodies["/small"])
+
+	// the length reported by the server is kept
+	assert.True(t, results["/announced"].BodyTruncated)
+	assert.Equal(t, int64(1000), results["/announced"].ContentLength)
+	assert.Len(t, bodies["/announced"], 50)
+
+	assert.True(t, results["/endless"].BodyTruncated)
+	assert.Equal(t, int64(-1), results["/endless"].ContentLength)
+	assert.Equal(t, bytes.Repeat([]byte("b"), 50), bodies["/endless"])
+}
+
+func TestScannerShouldPassTheAcceptedResponsesToTheHandlersWithTheirHeadersAndBody(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/about"},
+		0,
+	)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				w.Header().Set("X-Page", "home")
+				_, _ = w.Write([]byte("home page")) //nolint:errcheck
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	accepted := make(chan scan.Result, 10)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+		scan.WithBodyKept(4),
+		scan.WithResponseAcceptedHandler(func(r scan.Result) {
+			accepted <- r
+		}),
+	)
+
+	results := make([]scan.Result, 0)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
+
+	close(accepted)
+
+	assert.Len(t, results, 1)
+	assert.Nil(t, results[0].Body)
+	assert.Nil(t, results[0].Header)
+
+	acceptedResults := make([]scan.Result, 0)
+	for r := range accepted {
+		acceptedResults = append(acceptedResults, r)
+	}
+
+	assert.Len(t, acceptedResults, 1)
+	assert.Equal(t, "/home", acceptedResults[0].URL.Path)
+	assert.Equal(t, []byte("home"), acceptedResults[0].Body)
+	assert.Equal(t, "home", acceptedResults[0].Header.Get("X-Page"))
+}
+
+func TestScannerShouldPassAllTheResponsesToTheHandlersIncludingTheIgnoredOnes(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/about"},
+		0,
+	)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	responses := make(chan scan.Result, 10)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+		scan.WithResponseHandler(func(r scan.Result) {
+			responses <- r
+		}),
+	)
+
+	results := make([]scan.Result, 0)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
+
+	close(responses)
+
+	assert.Len(t, results, 1)
+
+	statuses := make(map[string]int)
+	for r := range responses {
+		statuses[r.URL.Path] = r.StatusCode
+	}
+
+	assert.Equal(t, map[string]int{"/home": http.StatusOK, "/about": http.StatusNotFound}, statuses)
+}
+
+func TestScannerShouldInvokeTheFiltersOfTheOptionsInOrderUntilOneIgnoresTheResult(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/about", "/admin"},
+		0,
+	)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/admin" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	mx := sync.Mutex{}
+	invocations := make(map[string][]string)
+
+	recordingFilter := func(name, pathToIgnore string) func(scan.Result) bool {
+		return func(r scan.Result) bool {
+			mx.Lock()
+			defer mx.Unlock()
+
+			invocations[r.URL.Path] = append(invocations[r.URL.Path], name)
+
+			return r.URL.Path == pathToIgnore
+		}
+	}
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+		scan.WithFilter(recordingFilter("first", "/home")),
+		scan.WithResultFilter(scan.ResultFilterFunc(recordingFilter("second", "/about"))),
+	)
+
+	results := make([]scan.Result, 0)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
+
+	assert.Len(t, results, 0)
+
+	// the filter passed to NewScanner comes first: /admin is ignored by it and never reaches the options
+	assert.Equal(
+		t,
+		map[string][]string{
+			"/home":  {"first"},
+			"/about": {"first", "second"},
+		},
+		invocations,
+	)
+}
+
+func TestScannerShouldDecompressTheCompressedBodies(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	content := strings.Repeat("compressed page content ", 20)
+
+	compress := func(newWriter func(w io.Writer) io.WriteCloser) []byte {
+		buf := &bytes.Buffer{}
+
+		w := newWriter(buf)
+		_, err := w.Write([]byte(content))
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+
+		return buf.Bytes()
+	}
+
+	bodies := map[string][]byte{
+		"/gzip": compress(func(w io.Writer) io.WriteCloser {
+			return gzip.NewWriter(w)
+		}),
+		"/deflate": compress(func(w io.Writer) io.WriteCloser {
+			return zlib.NewWriter(w)
+		}),
+		"/raw-deflate": compress(func(w io.Writer) io.WriteCloser {
+			fw, err := flate.NewWriter(w, flate.DefaultCompression)
+			assert.NoError(t, err)
+
+			return fw
+		}),
+	}
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, found := bodies[r.URL.Path]
+			if !found {
+				_, _ = w.Write([]byte(content)) //nolint:errcheck
+				return
+			}
+
+			encoding := "gzip"
+			if strings.HasSuffix(r.URL.Path, "deflate") {
+				encoding = "deflate"
+			}
+
+			w.Header().Set("Content-Encoding", encoding)
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			_, _ = w.Write(body) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	// the http package decompresses the gzip responses only when it sets the Accept-Encoding itself
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			Headers:               map[string]string{"Accept-Encoding": "gzip, deflate"},
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/gzip", "/deflate", "/raw-deflate", "/plain"},
+		0,
+	)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewBodyRegexResultFilter(regexp.MustCompile("^compressed page content")),
+		logger,
+		scan.WithBodyKept(1024),
+	)
+
+	results := make([]scan.Result, 0, 4)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
+
+	assert.Len(t, results, 4)
+
+	for _, r := range results {
+		assert.Equal(t, int64(len(content)), r.ContentLength, r.URL.Path)
+	}
+}
+
+func TestScannerShouldKeepTheReportedLengthOfTheCompressedResponsesWithoutBody(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("Content-Length", "123")
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			Headers:               map[string]string{"Accept-Encoding": "gzip"},
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	prod := producer.NewDictionaryProducer([]string{http.MethodHead}, []string{"/home"}, 0)
+
+	sut := scan.NewScanner(c, prod, producer.NewReProducer(prod), filter.NewAggregateResultFilter(), logger)
+
+	results := make([]scan.Result, 0, 1)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(123), results[0].ContentLength)
+}
+
+func TestScannerShouldKeepTheBodyOfTheResponsesNotCompressedAsDeclared(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	content := "plain page content, sent as if it was compressed"
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write([]byte(content)) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			Headers:               map[string]string{"Accept-Encoding": "gzip"},
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	prod := producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/home"}, 0)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewBodyRegexResultFilter(regexp.MustCompile("^plain page content")),
+		logger,
+		scan.WithBodyKept(1024),
+	)
+
+	results := make([]scan.Result, 0, 1)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(len(content)), results[0].ContentLength)
+}
+
+func TestScannerShouldStopPerformingRequestsOnceTheLimitIsReached(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/index", "/about", "/search", "/jobs", "/orders"},
+		0,
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	limitReachedCount := 0
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+		scan.WithRequestsLimit(3, func() {
+			limitReachedCount++
+			cancelFunc()
+		}),
+	)
+
+	resultsCount := 0
+	for range sut.Scan(ctx, test.MustParseURL(t, testServer.URL), 1) {
+		resultsCount++
+	}
+
+	assert.Equal(t, 3, resultsCount)
+	assert.Equal(t, 3, serverAssertion.Len())
+	assert.Equal(t, int64(3), sut.RequestsCount())
+	assert.Equal(t, 1, limitReachedCount)
+}
+
+func TestScannerShouldNotPerformRequestsWhilePaused(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/index", "/about"},
+		0,
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	sut.Pause()
+	assert.True(t, sut.IsPaused())
+
+	results := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 3)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 0, serverAssertion.Len())
+
+	sut.Resume()
+	assert.False(t, sut.IsPaused())
+
+	count := 0
+	for range results {
+		count++
+	}
+
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestScannerShouldStopWhenCanceledWhilePaused(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/index", "/about"},
+		0,
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	sut.Pause()
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	results := sut.Scan(ctx, test.MustParseURL(t, testServer.URL), 3)
+
+	time.Sleep(50 * time.Millisecond)
+	cancelFunc()
+
+	for range results {
+		t.Fatal("no results expected")
+	}
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestCanCancelScanUsingContext(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet, http.MethodPatch, http.MethodDelete, http.MethodPost, http.MethodPut},
+		[]string{"/home", "/index", "/about", "/search", "/jobs", "robots.txt", "/subscription", "/orders"},
+		200000000,
+	)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	// the depth of the dictionary and the fact that the server returns always a
+	// http.StatusOK should keep this test running forever in case the cancellation would not work
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+			CacheRequests:         true,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	ctx := context.Background()
+	ctx, cancelFunc := context.WithCancel(ctx)
+
+	resultsChannel := sut.Scan(ctx, test.MustParseURL(t, testServer.URL), 100)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancelFunc()
+	}()
+
+	done := make(chan struct{})
+
+	go func() {
+		for range resultsChannel {
+
+		}
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-done:
+		t.Log("result channel closed")
+	case <-time.After(time.Second * 8):
+		t.Fatalf("the scan should have terminated by now, something is wrong with the context cancellation")
+	}
+
+	assert.True(t, serverAssertion.Len() > 1)
+}
+
+func TestScannerShouldAbortTheRequestsInFlightWhenTheContextIsCanceled(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/home", "/index", "/about"}, 0)
+
+	requestReceived := make(chan struct{}, 3)
+	unblockServer := make(chan struct{})
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestReceived <- struct{}{}
+			<-unblockServer
+		}),
+	)
+	defer testServer.Close()
+	defer close(unblockServer)
+
+	// the timeout is much longer than the test, the requests can terminate only because of the cancellation
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 60000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+
+	resultsChannel := sut.Scan(ctx, test.MustParseURL(t, testServer.URL), 3)
+
+	<-requestReceived
+	cancelFunc()
+
+	done := make(chan struct{})
+
+	go func() {
+		for range resultsChannel {
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("the scan should have terminated by now, the requests in flight have not been aborted")
+	}
+
+	assert.NotContains(t, loggerBuffer.String(), "failed to perform request")
+}
+
+func TestScannerShouldCountTheResponsesByStatusAndTheErrors(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/index", "/about", "/admin", "/login", "/old", "/error"},
+		0,
+	)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/home":
+				w.WriteHeader(http.StatusOK)
+			case "/admin", "/login":
+				w.WriteHeader(http.StatusForbidden)
+			case "/old":
+				w.WriteHeader(http.StatusMovedPermanently)
+			case "/error":
+				// closing the connection without a response makes the request fail
+				conn, _, err := w.(http.Hijacker).Hijack()
+				assert.NoError(t, err)
+				_ = conn.Close()
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	for range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 2) {
+	}
+
+	assert.Equal(
+		t,
+		scan.Stats{
+			Requests:               7,
+			ResponsesByStatusClass: [5]int64{0, 1, 1, 4, 0},
+			ResponsesByStatusCode: map[int]int64{
+				http.StatusOK:               1,
+				http.StatusMovedPermanently: 1,
+				http.StatusForbidden:        2,
+				http.StatusNotFound:         2,
+			},
+			Errors: 1,
+		},
+		sut.Stats(),
+	)
+}
+
+func TestScannerShouldMatchOnlyTheBeginningOfTheBodyKept(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	prod := producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/home", "/index", "/about"}, 0)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/home":
+				_, _ = w.Write([]byte("Index of /home"))
+			case "/index":
+				_, _ = w.Write([]byte(strings.Repeat("a", 100) + "Index of /index"))
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := scan.NewScanner(
+		c,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewBodyRegexResultFilter(regexp.MustCompile("Index of")),
+		logger,
+		scan.WithBodyKept(50),
+	)
+
+	results := make([]scan.Result, 0)
+	for r := range sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL), 1) {
+		results = append(results, r)
+	}
+
+	assert.Equal(t, 3, serverAssertion.Len())
+
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "/home", results[0].Target.Path)
+		assert.Equal(t, int64(14), results[0].ContentLength)
+		assert.Nil(t, results[0].Body, "the body should not be reported")
+	}
+}
+
+func TestTargetURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		baseURL     string
+		targetPath  string
+		targetQuery string
+		expectedURL string
+	}{
+		{baseURL: "http://localhost", targetPath: "home", expectedURL: "http://localhost/home"},
+		{baseURL: "http://localhost/", targetPath: "/home", expectedURL: "http://localhost/home"},
+		{baseURL: "http://localhost/app", targetPath: "/home/index.php", expectedURL: "http://localhost/app/home/index.php"},
+		{baseURL: "http://localhost/app", targetPath: "admin/", expectedURL: "http://localhost/app/admin/"},
+		{baseURL: "http://localhost/app/", targetPath: "admin", expectedURL: "http://localhost/app/admin"},
+		{baseURL: "http://localhost/app/", targetPath: "/admin/", expectedURL: "http://localhost/app/admin/"},
+		{baseURL: "http://localhost/app/v1", targetPath: "users", expectedURL: "http://localhost/app/v1/users"},
+		{baseURL: "http://localhost/app/v1/", targetPath: "/users/", expectedURL: "http://localhost/app/v1/users/"},
+		{baseURL: "http://localhost/app//v1/", targetPath: "users", expectedURL: "http://localhost/app/v1/users"},
+		{baseURL: "http://localhost/app?a=b", targetPath: "users", expectedURL: "http://localhost/app/users?a=b"},
+		{baseURL: "http://localhost/a%2Fb", targetPath: "c d", expectedURL: "http://localhost/a%2Fb/c%20d"},
+		{
+			baseURL:     "http://localhost/app/",
+			targetPath:  "admin",
+			targetQuery: "debug=1",
+			expectedURL: "http://localhost/app/admin?debug=1",
+		},
+		{
+			baseURL:     "http://localhost/app?a=b",
+			targetPath:  "admin",
+			targetQuery: "debug=1",
+			expectedURL: "http://localhost/app/admin?a=b&debug=1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.baseURL+" "+tc.targetPath+" "+tc.targetQuery, func(t *testing.T) {
+			t.Parallel()
+
+			baseURL := *test.MustParseURL(t, tc.baseURL)
+
+			u := scan.TargetURL(baseURL, scan.Target{Path: tc.targetPath, Method: http.MethodGet, Query: tc.targetQuery})
+			assert.Equal(t, tc.expectedURL, u.String())
+
+			assert.Equal(t, *test.MustParseURL(t, tc.baseURL), baseURL, "the base url should not be modified")
+		})
+	}
 }
@@ -0,0 +1,69 @@
+package scope
+
+import (
+	"net/url"
+	"strings"
+)
+
+// The modes available to define which hosts are in the scope of a scan
+const (
+	// ModeHost restricts the scan to the host of the target (and to the additional domains)
+	ModeHost = "host"
+	// ModeSubdomains extends ModeHost to the subdomains of the hosts in scope
+	ModeSubdomains = "subdomains"
+	// ModeAny does not restrict the scan to any host
+	ModeAny = "any"
+)
+
+// Modes contains all the modes available
+var Modes = []string{ModeHost, ModeSubdomains, ModeAny}
+
+// IsValidMode returns true when the mode is one of the available ones
+func IsValidMode(mode string) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewScope returns the scope of the scan of the given target, the domains are additional hosts in scope
+func NewScope(mode string, target *url.URL, domains []string) *Scope {
+	hosts := make([]string, 0, len(domains)+1)
+	hosts = append(hosts, strings.ToLower(target.Hostname()))
+
+	for _, domain := range domains {
+		hosts = append(hosts, strings.ToLower(domain))
+	}
+
+	return &Scope{mode: mode, hosts: hosts}
+}
+
+// Scope decides which urls can be requested during the scan of a target
+type Scope struct {
+	mode  string
+	hosts []string
+}
+
+// Contains returns true when the host of the url is in scope, the port is not taken into account
+func (s *Scope) Contains(u *url.URL) bool {
+	if s.mode == ModeAny {
+		return true
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	for _, h := range s.hosts {
+		if host == h {
+			return true
+		}
+
+		if s.mode == ModeSubdomains && strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	return false
+}
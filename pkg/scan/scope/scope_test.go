@@ -0,0 +1,54 @@
+package scope_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/scope"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeContains(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		mode     string
+		domains  []string
+		url      string
+		expected bool
+	}{
+		{mode: scope.ModeHost, url: "http://example.com/admin", expected: true},
+		{mode: scope.ModeHost, url: "https://EXAMPLE.com:8080/admin", expected: true},
+		{mode: scope.ModeHost, url: "http://www.example.com/admin", expected: false},
+		{mode: scope.ModeHost, url: "http://evil.com/admin", expected: false},
+		{mode: scope.ModeHost, domains: []string{"cdn.example.org"}, url: "http://cdn.example.org/", expected: true},
+		{mode: scope.ModeHost, domains: []string{"cdn.example.org"}, url: "http://a.cdn.example.org/", expected: false},
+		{mode: scope.ModeSubdomains, url: "http://www.example.com/admin", expected: true},
+		{mode: scope.ModeSubdomains, url: "http://notexample.com/admin", expected: false},
+		{mode: scope.ModeSubdomains, domains: []string{"Example.org"}, url: "http://a.example.org/", expected: true},
+		{mode: scope.ModeAny, url: "http://evil.com/admin", expected: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.mode+" "+tc.url, func(t *testing.T) {
+			t.Parallel()
+
+			sut := scope.NewScope(tc.mode, test.MustParseURL(t, "http://example.com:8080/"), tc.domains)
+
+			assert.Equal(t, tc.expected, sut.Contains(test.MustParseURL(t, tc.url)))
+		})
+	}
+}
+
+func TestIsValidMode(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range scope.Modes {
+		assert.True(t, scope.IsValidMode(mode))
+	}
+
+	assert.False(t, scope.IsValidMode("domain"))
+	assert.False(t, scope.IsValidMode(""))
+}
@@ -0,0 +1,201 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// ErrDictionaryChanged is returned when resuming the scan of a target with a dictionary different from the one
+// used when the state was saved, as the entries completed would not be meaningful anymore
+var ErrDictionaryChanged = errors.New("the dictionary is different from the one used when the state was saved")
+
+// DictionaryChecksum returns the checksum identifying the dictionary in the state
+func DictionaryChecksum(dictionary []string) string {
+	h := sha256.Sum256([]byte(strings.Join(dictionary, "\n")))
+
+	return hex.EncodeToString(h[:])
+}
+
+// DictionaryEntriesChecksum returns the same checksum of DictionaryChecksum, reading the entries of the dictionary
+// from the channel until it is closed (EG while streaming them from a file)
+func DictionaryEntriesChecksum(entries <-chan string) string {
+	h := sha256.New()
+
+	first := true
+	for entry := range entries {
+		if !first {
+			_, _ = io.WriteString(h, "\n")
+		}
+
+		_, _ = io.WriteString(h, entry)
+		first = false
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CompletedTarget is a dictionary entry that has been completely scanned with the given method (and query string)
+type CompletedTarget struct {
+	Path   string `json:"path"`
+	Method string `json:"method"`
+	Query  string `json:"query,omitempty"`
+}
+
+type targetState struct {
+	DictionaryChecksum string            `json:"dictionary_checksum"`
+	Completed          []CompletedTarget `json:"completed"`
+	ShuffleSeed        int64             `json:"shuffle_seed,omitempty"`
+
+	completed map[CompletedTarget]struct{}
+}
+
+// State keeps track of the dictionary entries completely scanned for each target; it is safe for concurrent use
+type State struct {
+	mx      sync.Mutex
+	targets map[string]*targetState
+}
+
+func NewState() *State {
+	return &State{targets: make(map[string]*targetState)}
+}
+
+// Load reads the state saved in the file, an empty state is returned when the file doesn't exist
+func Load(path string) (*State, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewState(), nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read state file `%s`", path)
+	}
+
+	s := NewState()
+	if err := json.Unmarshal(content, &s.targets); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode state file `%s`", path)
+	}
+
+	for _, t := range s.targets {
+		t.completed = make(map[CompletedTarget]struct{}, len(t.Completed))
+		for _, c := range t.Completed {
+			t.completed[c] = struct{}{}
+		}
+	}
+
+	return s, nil
+}
+
+// Target returns the state of the scan of the given url, ErrDictionaryChanged is returned when the state
+// was saved using a different dictionary
+func (s *State) Target(url string, dictionaryChecksum string) (*TargetState, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	t, ok := s.targets[url]
+	if !ok {
+		t = &targetState{DictionaryChecksum: dictionaryChecksum, completed: make(map[CompletedTarget]struct{})}
+		s.targets[url] = t
+	}
+
+	if t.DictionaryChecksum != dictionaryChecksum {
+		return nil, errors.Wrapf(ErrDictionaryChanged, "cannot resume the scan of %s", url)
+	}
+
+	return &TargetState{state: s, target: t}, nil
+}
+
+// Save writes the state to the file; the state is written to a temporary file that is then renamed,
+// so that the file is never left half written
+func (s *State) Save(path string) error {
+	s.mx.Lock()
+	content, err := json.Marshal(s.targets)
+	s.mx.Unlock()
+
+	if err != nil {
+		return errors.Wrap(err, "failed to encode state")
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary state file")
+	}
+
+	_, writeErr := tmpFile.Write(content)
+	closeErr := tmpFile.Close()
+
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+
+	if writeErr == nil {
+		writeErr = os.Rename(tmpFile.Name(), path)
+	}
+
+	if writeErr != nil {
+		_ = os.Remove(tmpFile.Name())
+		return errors.Wrapf(writeErr, "failed to write state file `%s`", path)
+	}
+
+	return nil
+}
+
+// TargetState keeps track of the dictionary entries completely scanned for a single target
+type TargetState struct {
+	state  *State
+	target *targetState
+}
+
+// MarkCompleted records the given target as completely scanned
+func (t *TargetState) MarkCompleted(target scan.Target) {
+	t.state.mx.Lock()
+	defer t.state.mx.Unlock()
+
+	c := CompletedTarget{Path: target.Path, Method: target.Method, Query: target.Query}
+	if _, ok := t.target.completed[c]; ok {
+		return
+	}
+
+	t.target.completed[c] = struct{}{}
+	t.target.Completed = append(t.target.Completed, c)
+}
+
+// IsCompleted returns true when the target has already been completely scanned
+func (t *TargetState) IsCompleted(target scan.Target) bool {
+	t.state.mx.Lock()
+	defer t.state.mx.Unlock()
+
+	_, ok := t.target.completed[CompletedTarget{Path: target.Path, Method: target.Method, Query: target.Query}]
+
+	return ok
+}
+
+// ShuffleSeed returns the seed the dictionary was shuffled with when the state was saved, so that the scan is
+// resumed in the same order; when none was saved the given seed is recorded and returned
+func (t *TargetState) ShuffleSeed(seed int64) int64 {
+	t.state.mx.Lock()
+	defer t.state.mx.Unlock()
+
+	if t.target.ShuffleSeed == 0 {
+		t.target.ShuffleSeed = seed
+	}
+
+	return t.target.ShuffleSeed
+}
+
+// CompletedCount returns the amount of targets completely scanned
+func (t *TargetState) CompletedCount() int {
+	t.state.mx.Lock()
+	defer t.state.mx.Unlock()
+
+	return len(t.target.Completed)
+}
@@ -0,0 +1,102 @@
+package state_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateShouldBeSavedAndLoaded(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "dirstalk-state")
+	assert.NoError(t, err)
+
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	path := filepath.Join(dir, "state.json")
+	checksum := state.DictionaryChecksum([]string{"home", "admin"})
+
+	sut, err := state.Load(path)
+	assert.NoError(t, err)
+
+	targetState, err := sut.Target("http://localhost/", checksum)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, targetState.CompletedCount())
+
+	targetState.MarkCompleted(scan.Target{Path: "home", Method: http.MethodGet, Depth: 3})
+	targetState.MarkCompleted(scan.Target{Path: "home", Method: http.MethodGet, Depth: 3})
+	assert.Equal(t, int64(42), targetState.ShuffleSeed(42))
+
+	assert.NoError(t, sut.Save(path))
+
+	loaded, err := state.Load(path)
+	assert.NoError(t, err)
+
+	loadedTargetState, err := loaded.Target("http://localhost/", checksum)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, loadedTargetState.CompletedCount())
+	assert.True(t, loadedTargetState.IsCompleted(scan.Target{Path: "home", Method: http.MethodGet}))
+	assert.False(t, loadedTargetState.IsCompleted(scan.Target{Path: "home", Method: http.MethodPost}))
+	assert.False(t, loadedTargetState.IsCompleted(scan.Target{Path: "admin", Method: http.MethodGet}))
+	assert.False(t, loadedTargetState.IsCompleted(scan.Target{Path: "home", Method: http.MethodGet, Query: "debug=1"}))
+	assert.Equal(t, int64(42), loadedTargetState.ShuffleSeed(7), "the seed saved should be kept")
+
+	otherTargetState, err := loaded.Target("http://127.0.0.1/", checksum)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, otherTargetState.CompletedCount())
+	assert.Equal(t, int64(7), otherTargetState.ShuffleSeed(7))
+}
+
+func TestStateShouldErrWhenTheDictionaryChanged(t *testing.T) {
+	t.Parallel()
+
+	sut := state.NewState()
+
+	_, err := sut.Target("http://localhost/", state.DictionaryChecksum([]string{"home"}))
+	assert.NoError(t, err)
+
+	_, err = sut.Target("http://localhost/", state.DictionaryChecksum([]string{"home", "admin"}))
+	assert.Error(t, err)
+	assert.Equal(t, state.ErrDictionaryChanged, errors.Cause(err))
+}
+
+func TestLoadStateShouldErrForInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	file, err := ioutil.TempFile("", "dirstalk-state")
+	assert.NoError(t, err)
+
+	defer os.Remove(file.Name()) //nolint:errcheck
+
+	_, err = file.WriteString("not json")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	_, err = state.Load(file.Name())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode state file")
+}
+
+func TestDictionaryEntriesChecksumShouldMatchTheChecksumOfTheDictionary(t *testing.T) {
+	t.Parallel()
+
+	for _, dictionary := range [][]string{{}, {"home"}, {"home", "admin", ""}} {
+		entries := make(chan string, len(dictionary))
+		for _, entry := range dictionary {
+			entries <- entry
+		}
+
+		close(entries)
+
+		assert.Equal(t, state.DictionaryChecksum(dictionary), state.DictionaryEntriesChecksum(entries))
+	}
+}
@@ -0,0 +1,89 @@
+package scan
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// statusClassesCount is the amount of the classes of the http status codes, from 1xx to 5xx
+const statusClassesCount = 5
+
+// counters holds the counters updated by the workers of the scanner, the int64 fields are accessed atomically
+// while responsesByStatusCode is guarded by mux
+type counters struct {
+	responsesByStatusClass [statusClassesCount]int64
+	errors                 int64
+
+	responsesByStatusCode map[int]int64
+	mux                   sync.Mutex
+}
+
+func (c *counters) addResponse(statusCode int) {
+	c.mux.Lock()
+	if c.responsesByStatusCode == nil {
+		c.responsesByStatusCode = make(map[int]int64)
+	}
+	c.responsesByStatusCode[statusCode]++
+	c.mux.Unlock()
+
+	class := statusCode/100 - 1
+	if class < 0 || class >= statusClassesCount {
+		return
+	}
+
+	atomic.AddInt64(&c.responsesByStatusClass[class], 1)
+}
+
+func (c *counters) statusCodes() map[int]int64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	statusCodes := make(map[int]int64, len(c.responsesByStatusCode))
+	for statusCode, count := range c.responsesByStatusCode {
+		statusCodes[statusCode] = count
+	}
+
+	return statusCodes
+}
+
+func (c *counters) addError() {
+	atomic.AddInt64(&c.errors, 1)
+}
+
+// Stats is a snapshot of the counters of a scan
+type Stats struct {
+	// Requests is the amount of requests performed, the ones skipped because redundant excluded
+	Requests int64
+
+	// ResponsesByStatusClass contains the amount of responses received for each class of status codes,
+	// the first element is for 1xx and the last one for 5xx.
+	// Responses with a status code out of these classes are not included
+	ResponsesByStatusClass [statusClassesCount]int64
+
+	// ResponsesByStatusCode contains the amount of responses received for each status code
+	ResponsesByStatusCode map[int]int64
+
+	// Errors is the amount of requests that failed or timed out, the ones canceled together with the scan excluded
+	Errors int64
+}
+
+// StatusClass returns the name of the class of status codes at the given index of ResponsesByStatusClass
+func (s Stats) StatusClass(index int) string {
+	return strconv.Itoa(index+1) + "xx"
+}
+
+// Stats returns a snapshot of the counters of the scan, it can be invoked while the scan is running
+func (s *Scanner) Stats() Stats {
+	stats := Stats{
+		Requests:              s.RequestsCount(),
+		ResponsesByStatusCode: s.counters.statusCodes(),
+		Errors:                atomic.LoadInt64(&s.counters.errors),
+	}
+
+	for i := range stats.ResponsesByStatusClass {
+		stats.ResponsesByStatusClass[i] = atomic.LoadInt64(&s.counters.responsesByStatusClass[i])
+	}
+
+	return stats
+}
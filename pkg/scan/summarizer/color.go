@@ -0,0 +1,37 @@
+package summarizer
+
+import (
+	"strconv"
+)
+
+// the ANSI escape sequences to color the status codes
+const (
+	colorReset  = "\x1b[0m"
+	colorGreen  = "\x1b[32m"
+	colorCyan   = "\x1b[36m"
+	colorYellow = "\x1b[33m"
+	colorRed    = "\x1b[31m"
+)
+
+// colorStatusCode returns the status code wrapped in the color of its class: green for 2xx, cyan for 3xx,
+// yellow for 4xx and red for 5xx, the others are not colored
+func colorStatusCode(statusCode int) string {
+	code := strconv.Itoa(statusCode)
+
+	var color string
+
+	switch statusCode / 100 {
+	case 2:
+		color = colorGreen
+	case 3:
+		color = colorCyan
+	case 4:
+		color = colorYellow
+	case 5:
+		color = colorRed
+	default:
+		return code
+	}
+
+	return color + code + colorReset
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stefanoj3/dirstalk/pkg/scan"
@@ -28,9 +31,17 @@ type ResultSummarizer struct {
 	logger      *logrus.Logger
 	results     []scan.Result
 	resultMap   map[string]struct{}
+	colors      bool
 	mux         sync.RWMutex
 }
 
+// WithColors makes the summarizer color the status codes it prints, according to their class
+func (s *ResultSummarizer) WithColors() *ResultSummarizer {
+	s.colors = true
+
+	return s
+}
+
 func (s *ResultSummarizer) Add(result scan.Result) {
 	s.mux.Lock()
 	defer s.mux.Unlock()
@@ -53,23 +64,127 @@ func (s *ResultSummarizer) Summarize() {
 	defer s.mux.Unlock()
 
 	sort.Slice(s.results, func(i, j int) bool {
-		return s.results[i].Target.Path < s.results[j].Target.Path
+		if s.results[i].Target.Path != s.results[j].Target.Path {
+			return s.results[i].Target.Path < s.results[j].Target.Path
+		}
+
+		if s.results[i].Target.Host != s.results[j].Target.Host {
+			return s.results[i].Target.Host < s.results[j].Target.Host
+		}
+
+		if s.results[i].Target.Word != s.results[j].Target.Word {
+			return s.results[i].Target.Word < s.results[j].Target.Word
+		}
+
+		return s.results[i].Target.SecondWord < s.results[j].Target.SecondWord
 	})
 
 	s.printSummary()
 	s.printTree()
 
 	for _, r := range s.results {
-		_, _ = fmt.Fprintln(
-			s.logger.Out,
-			fmt.Sprintf(
-				"%s [%d] [%s]",
-				r.URL.String(),
-				r.StatusCode,
-				r.Target.Method,
-			),
+		line := fmt.Sprintf(
+			"%s [%s] [%s]",
+			r.URL.String(),
+			s.statusCode(r.StatusCode),
+			r.Target.Method,
 		)
+
+		if len(r.Target.Host) > 0 {
+			line += " [Host: " + r.Target.Host + "]"
+		}
+
+		if len(r.Target.Word) > 0 {
+			line += " [Word: " + strings.Join(r.Target.Words(), ", ") + "]"
+		}
+
+		if r.BodyTruncated {
+			line += " [body truncated]"
+		}
+
+		if len(r.Location) > 0 {
+			line += " -> " + r.Location
+		}
+
+		_, _ = fmt.Fprintln(s.logger.Out, line)
+	}
+}
+
+// SummarizeStats prints a summary of the counters of the scan of a target, the discovered paths are the results
+// added so far
+func (s *ResultSummarizer) SummarizeStats(stats scan.Stats, duration time.Duration) {
+	s.mux.RLock()
+	discoveredPaths := len(s.results)
+	s.mux.RUnlock()
+
+	byStatusClass := make([]string, 0, len(stats.ResponsesByStatusClass))
+
+	for i, count := range stats.ResponsesByStatusClass {
+		if count > 0 {
+			byStatusClass = append(byStatusClass, fmt.Sprintf("%s: %d", stats.StatusClass(i), count))
+		}
+	}
+
+	if len(byStatusClass) == 0 {
+		byStatusClass = append(byStatusClass, "none")
+	}
+
+	byStatusCode := make([]string, 0, len(stats.ResponsesByStatusCode))
+	for _, statusCode := range sortStatusCodesByCount(stats.ResponsesByStatusCode) {
+		byStatusCode = append(
+			byStatusCode,
+			fmt.Sprintf("%s: %d", s.statusCode(statusCode), stats.ResponsesByStatusCode[statusCode]),
+		)
+	}
+
+	if len(byStatusCode) == 0 {
+		byStatusCode = append(byStatusCode, "none")
+	}
+
+	requestsPerSecond := 0.0
+	if duration > 0 {
+		requestsPerSecond = float64(stats.Requests) / duration.Seconds()
 	}
+
+	lines := []string{
+		"Summary:",
+		fmt.Sprintf("  Total requests:       %d", stats.Requests),
+		fmt.Sprintf("  Responses:            %s", strings.Join(byStatusClass, ", ")),
+		fmt.Sprintf("  Status codes:         %s", strings.Join(byStatusCode, ", ")),
+		fmt.Sprintf("  Discovered paths:     %d", discoveredPaths),
+		fmt.Sprintf("  Errors:               %d", stats.Errors),
+		fmt.Sprintf("  Duration:             %s", duration.Round(time.Millisecond)),
+		fmt.Sprintf("  Requests per second:  %.2f", requestsPerSecond),
+	}
+
+	_, _ = fmt.Fprintln(s.logger.Out, strings.Join(lines, "\n"))
+}
+
+// sortStatusCodesByCount returns the status codes sorted from the most frequent one, the ones with the same count
+// are sorted by status code
+func sortStatusCodesByCount(counts map[int]int64) []int {
+	statusCodes := make([]int, 0, len(counts))
+	for statusCode := range counts {
+		statusCodes = append(statusCodes, statusCode)
+	}
+
+	sort.Slice(statusCodes, func(i, j int) bool {
+		if counts[statusCodes[i]] != counts[statusCodes[j]] {
+			return counts[statusCodes[i]] > counts[statusCodes[j]]
+		}
+
+		return statusCodes[i] < statusCodes[j]
+	})
+
+	return statusCodes
+}
+
+func (s *ResultSummarizer) statusCode(statusCode int) string {
+	if !s.colors {
+		return strconv.Itoa(statusCode)
+	}
+
+	return colorStatusCode(statusCode)
 }
 
 func (s *ResultSummarizer) printSummary() {
@@ -90,8 +205,29 @@ func (s *ResultSummarizer) log(result scan.Result) {
 		"status-code": statusCode,
 		"method":      result.Target.Method,
 		"url":         result.URL.String(),
+		"duration":    result.Duration.String(),
 	})
 
+	if len(result.Target.Host) > 0 {
+		l = l.WithField("host", result.Target.Host)
+	}
+
+	if len(result.Target.Word) > 0 {
+		l = l.WithField("word", result.Target.Word)
+	}
+
+	if len(result.Target.SecondWord) > 0 {
+		l = l.WithField("second-word", result.Target.SecondWord)
+	}
+
+	if len(result.Location) > 0 {
+		l = l.WithField("location", result.Location)
+	}
+
+	if result.BodyTruncated {
+		l = l.WithField("body-truncated", true)
+	}
+
 	if statusCode >= http.StatusInternalServerError {
 		l.Warn(breakingText)
 	} else {
@@ -100,5 +236,12 @@ func (s *ResultSummarizer) log(result scan.Result) {
 }
 
 func keyForResult(result scan.Result) string {
-	return fmt.Sprintf("%s~%s", result.URL.String(), result.Target.Method)
+	return fmt.Sprintf(
+		"%s~%s~%s~%s~%s",
+		result.URL.String(),
+		result.Target.Method,
+		result.Target.Host,
+		result.Target.Word,
+		result.Target.SecondWord,
+	)
 }
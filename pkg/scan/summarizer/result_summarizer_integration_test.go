@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stefanoj3/dirstalk/pkg/common/test"
@@ -262,6 +263,28 @@ func TestResultSummarizerShouldLogResults(t *testing.T) {
 				`url="http://mysite/gibberish"`,
 			},
 		},
+		{
+			result: scan.NewResult(
+				scan.Target{
+					Method: http.MethodGet,
+					Path:   "/old",
+				},
+				&http.Response{
+					StatusCode: http.StatusMovedPermanently,
+					Header:     http.Header{"Location": []string{"/new"}},
+					Request: &http.Request{
+						URL: test.MustParseURL(t, "http://mysite/old"),
+					},
+				},
+			),
+			expectedToContain: []string{
+				"Found",
+				"location=/new",
+				"method=GET",
+				"status-code=301",
+				`url="http://mysite/old"`,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -280,3 +303,225 @@ func TestResultSummarizerShouldLogResults(t *testing.T) {
 		})
 	}
 }
+
+func TestResultSummarizerShouldShowWhereTheResultsRedirectTo(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sut := summarizer.NewResultSummarizer(tree.NewResultTreeProducer(), logger)
+
+	sut.Add(
+		scan.NewResult(
+			scan.Target{
+				Method: http.MethodGet,
+				Path:   "/old",
+			},
+			&http.Response{
+				StatusCode: http.StatusMovedPermanently,
+				Header:     http.Header{"Location": []string{"http://mysite/new"}},
+				Request: &http.Request{
+					URL: test.MustParseURL(t, "http://mysite/old"),
+				},
+			},
+		),
+	)
+
+	sut.Summarize()
+
+	assert.Contains(t, loggerBuffer.String(), "http://mysite/old [301] [GET] -> http://mysite/new\n")
+}
+
+func TestResultSummarizerShouldShowTheHostOfTheResults(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sut := summarizer.NewResultSummarizer(tree.NewResultTreeProducer(), logger)
+
+	for _, host := range []string{"dev.example.com", "admin.example.com"} {
+		sut.Add(
+			scan.NewResult(
+				scan.Target{
+					Method: http.MethodGet,
+					Host:   host,
+				},
+				&http.Response{
+					StatusCode: http.StatusOK,
+					Request: &http.Request{
+						URL: test.MustParseURL(t, "http://10.0.0.1/"),
+					},
+				},
+			),
+		)
+	}
+
+	sut.Summarize()
+
+	assert.Contains(t, loggerBuffer.String(), "2 results found")
+	assert.Contains(
+		t,
+		loggerBuffer.String(),
+		"http://10.0.0.1/ [200] [GET] [Host: admin.example.com]\nhttp://10.0.0.1/ [200] [GET] [Host: dev.example.com]\n",
+	)
+}
+
+func TestResultSummarizerShouldShowTheWordOfTheResults(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sut := summarizer.NewResultSummarizer(tree.NewResultTreeProducer(), logger)
+
+	// the requests of a template may differ only by their headers, the results are not deduplicated by their url
+	for _, word := range []string{"guest", "admin"} {
+		sut.Add(
+			scan.NewResult(
+				scan.Target{
+					Method: http.MethodGet,
+					Word:   word,
+				},
+				&http.Response{
+					StatusCode: http.StatusOK,
+					Request: &http.Request{
+						URL: test.MustParseURL(t, "http://mysite/api"),
+					},
+				},
+			),
+		)
+	}
+
+	sut.Summarize()
+
+	assert.Contains(t, loggerBuffer.String(), "2 results found")
+	assert.Contains(
+		t,
+		loggerBuffer.String(),
+		"http://mysite/api [200] [GET] [Word: admin]\nhttp://mysite/api [200] [GET] [Word: guest]\n",
+	)
+}
+
+func TestResultSummarizerShouldSummarizeTheStatsOfTheScan(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sut := summarizer.NewResultSummarizer(tree.NewResultTreeProducer(), logger)
+
+	sut.Add(
+		scan.NewResult(
+			scan.Target{
+				Method: http.MethodGet,
+				Path:   "/home",
+			},
+			&http.Response{
+				StatusCode: http.StatusOK,
+				Request: &http.Request{
+					URL: test.MustParseURL(t, "http://mysite/home"),
+				},
+			},
+		),
+	)
+
+	sut.SummarizeStats(
+		scan.Stats{
+			Requests:               10,
+			ResponsesByStatusClass: [5]int64{0, 1, 0, 7, 0},
+			ResponsesByStatusCode: map[int]int64{
+				http.StatusOK:        1,
+				http.StatusForbidden: 5,
+				http.StatusNotFound:  1,
+				http.StatusGone:      1,
+			},
+			Errors: 2,
+		},
+		time.Second*4,
+	)
+
+	summary := loggerBuffer.String()
+
+	assert.Contains(t, summary, "Total requests:       10\n")
+	assert.Contains(t, summary, "Responses:            2xx: 1, 4xx: 7\n")
+	assert.Contains(t, summary, "Status codes:         403: 5, 200: 1, 404: 1, 410: 1\n")
+	assert.Contains(t, summary, "Discovered paths:     1\n")
+	assert.Contains(t, summary, "Errors:               2\n")
+	assert.Contains(t, summary, "Duration:             4s\n")
+	assert.Contains(t, summary, "Requests per second:  2.50\n")
+}
+
+func TestResultSummarizerWithColorsShouldColorTheStatusCodes(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sut := summarizer.NewResultSummarizer(tree.NewResultTreeProducer(), logger).WithColors()
+
+	for path, statusCode := range map[string]int{
+		"/home":   http.StatusOK,
+		"/old":    http.StatusMovedPermanently,
+		"/admin":  http.StatusForbidden,
+		"/broken": http.StatusInternalServerError,
+	} {
+		sut.Add(
+			scan.NewResult(
+				scan.Target{
+					Method: http.MethodGet,
+					Path:   path,
+				},
+				&http.Response{
+					StatusCode: statusCode,
+					Request: &http.Request{
+						URL: test.MustParseURL(t, "http://mysite"+path),
+					},
+				},
+			),
+		)
+	}
+
+	sut.Summarize()
+
+	sut.SummarizeStats(
+		scan.Stats{
+			Requests:              2,
+			ResponsesByStatusCode: map[int]int64{http.StatusOK: 1, http.StatusSwitchingProtocols: 1},
+		},
+		time.Second,
+	)
+
+	output := loggerBuffer.String()
+
+	assert.Contains(t, output, "http://mysite/home [\x1b[32m200\x1b[0m] [GET]\n")
+	assert.Contains(t, output, "http://mysite/old [\x1b[36m301\x1b[0m] [GET]\n")
+	assert.Contains(t, output, "http://mysite/admin [\x1b[33m403\x1b[0m] [GET]\n")
+	assert.Contains(t, output, "http://mysite/broken [\x1b[31m500\x1b[0m] [GET]\n")
+	assert.Contains(t, output, "Status codes:         101: 1, \x1b[32m200\x1b[0m: 1\n")
+}
+
+func TestResultSummarizerShouldShowBothTheWordsOfTheResults(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sut := summarizer.NewResultSummarizer(tree.NewResultTreeProducer(), logger)
+
+	for _, secondWord := range []string{"secret", "1234"} {
+		sut.Add(
+			scan.NewResult(
+				scan.Target{
+					Method:     http.MethodPost,
+					Word:       "admin",
+					SecondWord: secondWord,
+				},
+				&http.Response{
+					StatusCode: http.StatusOK,
+					Request: &http.Request{
+						URL: test.MustParseURL(t, "http://mysite/login"),
+					},
+				},
+			),
+		)
+	}
+
+	sut.Summarize()
+
+	assert.Contains(t, loggerBuffer.String(), "2 results found")
+	assert.Contains(
+		t,
+		loggerBuffer.String(),
+		"http://mysite/login [200] [POST] [Word: admin, 1234]\nhttp://mysite/login [200] [POST] [Word: admin, secret]\n",
+	)
+}
@@ -0,0 +1,208 @@
+package template
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The placeholders are replaced by the dictionary words in the request line, the headers and the body of the
+// template; when preceded by a backslash (EG \FUZZ) they are sent as they are, without the backslash
+const (
+	// Placeholder is replaced by the word of the first dictionary
+	Placeholder = "FUZZ"
+	// FirstPlaceholder is replaced by the word of the first dictionary, like Placeholder
+	FirstPlaceholder = "FUZZ1"
+	// SecondPlaceholder is replaced by the word of the second dictionary
+	SecondPlaceholder = "FUZZ2"
+)
+
+// Parse parses a raw http request (EG one saved from Burp Suite) containing the placeholder:
+//
+//	POST /api/FUZZ?debug=1 HTTP/1.1
+//	Host: example.com
+//	Content-Type: application/json
+//
+//	{"user":"FUZZ"}
+//
+// the body is what follows the first empty line, it is sent as it is
+func Parse(raw []byte) (*Template, error) {
+	head, body := splitHead(string(raw))
+
+	lines := strings.Split(head, "\n")
+
+	requestLine := strings.Fields(lines[0])
+	if len(requestLine) != 2 && len(requestLine) != 3 {
+		return nil, errors.Errorf("invalid request line: %s", strings.TrimSpace(lines[0]))
+	}
+
+	t := &Template{method: requestLine[0], requestTarget: requestLine[1], body: body}
+
+	for _, line := range lines[1:] {
+		line = strings.TrimRight(line, "\r")
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			return nil, errors.Errorf("header is in invalid format: %s", line)
+		}
+
+		t.headers = append(t.headers, header{name: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1])})
+	}
+
+	t.placeholders = t.findPlaceholders()
+	if len(t.placeholders) == 0 {
+		return nil, errors.Errorf("the request template doesn't contain the %s placeholder", Placeholder)
+	}
+
+	// the request target may be invalid regardless of the words
+	if _, err := t.NewRequest(context.Background(), url.URL{Scheme: "http", Host: "localhost"}, "a", "a"); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Template is a raw http request where the placeholders are replaced by the dictionary words; the scheme and the
+// address the requests are sent to are the ones of the url being scanned
+type Template struct {
+	method        string
+	requestTarget string
+	headers       []header
+	body          string
+
+	// placeholders are the ones found in the template
+	placeholders map[string]bool
+}
+
+type header struct {
+	name  string
+	value string
+}
+
+// Method returns the method of the requests
+func (t *Template) Method() string {
+	return t.method
+}
+
+// Contains returns true when the placeholder is found in the template
+func (t *Template) Contains(placeholder string) bool {
+	return t.placeholders[placeholder]
+}
+
+// NewRequest returns the request of the template for the given words, sent to the scheme and the address of
+// baseURL: the first word replaces Placeholder and FirstPlaceholder, the second one SecondPlaceholder. The path
+// and the query are the ones of the request line, the words are inserted as they are.
+func (t *Template) NewRequest(ctx context.Context, baseURL url.URL, words ...string) (*http.Request, error) {
+	replace := func(placeholder string) string {
+		i := 0
+		if placeholder == SecondPlaceholder {
+			i = 1
+		}
+
+		if i >= len(words) {
+			return ""
+		}
+
+		return words[i]
+	}
+
+	requestTarget, err := url.Parse(substitute(t.requestTarget, replace))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid request target")
+	}
+
+	u := baseURL
+	u.Path, u.RawPath, u.RawQuery = requestTarget.Path, requestTarget.RawPath, requestTarget.RawQuery
+
+	var body io.Reader
+	if t.body != "" {
+		body = strings.NewReader(substitute(t.body, replace))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, t.method, u.String(), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+
+	for _, h := range t.headers {
+		value := substitute(h.value, replace)
+
+		switch http.CanonicalHeaderKey(h.name) {
+		case "Host":
+			req.Host = value
+		case "Content-Length":
+			// computed from the body, that changes with the words
+		default:
+			req.Header.Add(h.name, value)
+		}
+	}
+
+	return req, nil
+}
+
+func (t *Template) findPlaceholders() map[string]bool {
+	parts := []string{t.requestTarget, t.body}
+	for _, h := range t.headers {
+		parts = append(parts, h.value)
+	}
+
+	placeholders := make(map[string]bool)
+
+	for _, part := range parts {
+		substitute(part, func(placeholder string) string {
+			placeholders[placeholder] = true
+			return placeholder
+		})
+	}
+
+	return placeholders
+}
+
+// substitute replaces the placeholders of s with the value returned by replace and the escaped placeholders
+// with the placeholder itself
+func substitute(s string, replace func(placeholder string) string) string {
+	var b strings.Builder
+
+	for {
+		i := strings.Index(s, Placeholder)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+
+		placeholder := Placeholder
+		if rest := s[i+len(Placeholder):]; strings.HasPrefix(rest, "1") || strings.HasPrefix(rest, "2") {
+			placeholder += rest[:1]
+		}
+
+		if i > 0 && s[i-1] == '\\' {
+			b.WriteString(s[:i-1])
+			b.WriteString(placeholder)
+		} else {
+			b.WriteString(s[:i])
+			b.WriteString(replace(placeholder))
+		}
+
+		s = s[i+len(placeholder):]
+	}
+}
+
+// splitHead splits the raw request at the first empty line, returning the request line with the headers and the body
+func splitHead(raw string) (string, string) {
+	raw = strings.TrimLeft(raw, "\r\n")
+
+	end, separatorLength := strings.Index(raw, "\n\n"), 2
+	if i := strings.Index(raw, "\r\n\r\n"); i >= 0 && (end < 0 || i < end) {
+		end, separatorLength = i, 4
+	}
+
+	if end < 0 {
+		return strings.TrimRight(raw, "\r\n"), ""
+	}
+
+	return raw[:end], raw[end+separatorLength:]
+}
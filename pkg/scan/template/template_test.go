@@ -0,0 +1,156 @@
+package template_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateShouldReplaceThePlaceholdersWithTheWord(t *testing.T) {
+	t.Parallel()
+
+	sut, err := template.Parse([]byte(
+		"POST /api/FUZZ?debug=1&name=FUZZ HTTP/1.1\r\n" +
+			"Host: mysite.com\r\n" +
+			"X-Custom: prefix-FUZZ\r\n" +
+			"Content-Type: application/json\r\n" +
+			"Content-Length: 15\r\n" +
+			"\r\n" +
+			`{"user":"FUZZ"}`,
+	))
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, sut.Method())
+
+	req, err := sut.NewRequest(context.Background(), *test.MustParseURL(t, "https://10.0.0.1/app/"), "admin")
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "https://10.0.0.1/api/admin?debug=1&name=admin", req.URL.String())
+	assert.Equal(t, "mysite.com", req.Host)
+	assert.Equal(t, "prefix-admin", req.Header.Get("X-Custom"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, int64(16), req.ContentLength)
+
+	body, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"user":"admin"}`, string(body))
+}
+
+func TestTemplateShouldSendTheEscapedPlaceholdersAsTheyAre(t *testing.T) {
+	t.Parallel()
+
+	sut, err := template.Parse([]byte("GET /FUZZ HTTP/1.1\nX-Custom: \\FUZZ-FUZZ\n"))
+	assert.NoError(t, err)
+
+	req, err := sut.NewRequest(context.Background(), *test.MustParseURL(t, "http://mysite/"), "admin")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "http://mysite/admin", req.URL.String())
+	assert.Equal(t, "FUZZ-admin", req.Header.Get("X-Custom"))
+	assert.Nil(t, req.Body)
+}
+
+func TestTemplateShouldReplaceTheNumberedPlaceholdersWithTheirWord(t *testing.T) {
+	t.Parallel()
+
+	sut, err := template.Parse([]byte(
+		"POST /login?user=FUZZ1 HTTP/1.1\n" +
+			"X-Custom: FUZZ-\\FUZZ2\n" +
+			"\n" +
+			"user=FUZZ1&token=FUZZ",
+	))
+	assert.NoError(t, err)
+
+	assert.True(t, sut.Contains(template.Placeholder))
+	assert.True(t, sut.Contains(template.FirstPlaceholder))
+	assert.False(t, sut.Contains(template.SecondPlaceholder), "the escaped placeholders should not count")
+
+	req, err := sut.NewRequest(context.Background(), *test.MustParseURL(t, "http://mysite/"), "admin", "secret")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "http://mysite/login?user=admin", req.URL.String())
+	assert.Equal(t, "admin-FUZZ2", req.Header.Get("X-Custom"))
+
+	body, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "user=admin&token=admin", string(body))
+
+	sut, err = template.Parse([]byte("GET /FUZZ1/FUZZ2"))
+	assert.NoError(t, err)
+	assert.True(t, sut.Contains(template.SecondPlaceholder))
+
+	req, err = sut.NewRequest(context.Background(), *test.MustParseURL(t, "http://mysite/"), "admin", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://mysite/admin/secret", req.URL.String())
+}
+
+func TestTemplateShouldUseTheHostOfTheUrlWithoutAHostHeader(t *testing.T) {
+	t.Parallel()
+
+	sut, err := template.Parse([]byte("GET /index.php?page=FUZZ"))
+	assert.NoError(t, err)
+
+	req, err := sut.NewRequest(context.Background(), *test.MustParseURL(t, "http://mysite:8080/"), "home")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "http://mysite:8080/index.php?page=home", req.URL.String())
+	assert.Equal(t, "mysite:8080", req.Host)
+}
+
+func TestParseShouldFailForInvalidTemplates(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		raw           string
+		expectedError string
+	}{
+		{
+			name:          "empty",
+			raw:           "",
+			expectedError: "invalid request line",
+		},
+		{
+			name:          "invalid request line",
+			raw:           "GET\nX-Custom: FUZZ\n",
+			expectedError: "invalid request line: GET",
+		},
+		{
+			name:          "invalid header",
+			raw:           "GET /FUZZ HTTP/1.1\ngibberish\n",
+			expectedError: "header is in invalid format: gibberish",
+		},
+		{
+			name:          "no placeholder",
+			raw:           "GET /home HTTP/1.1\nHost: mysite\n\nbody",
+			expectedError: "the request template doesn't contain the FUZZ placeholder",
+		},
+		{
+			name:          "only escaped placeholders",
+			raw:           "GET /\\FUZZ HTTP/1.1\n",
+			expectedError: "the request template doesn't contain the FUZZ placeholder",
+		},
+		{
+			name:          "invalid request target",
+			raw:           "GET /FUZZ%zz HTTP/1.1\n",
+			expectedError: "invalid request target",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := template.Parse([]byte(tc.raw))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
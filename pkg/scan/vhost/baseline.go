@@ -0,0 +1,85 @@
+package vhost
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+)
+
+const randomLabelLength = 16
+
+func NewBaselineDetector(
+	httpClient scan.Doer,
+	methods []string,
+	logger *logrus.Logger,
+) *BaselineDetector {
+	return &BaselineDetector{
+		httpClient: httpClient,
+		methods:    methods,
+		logger:     logger,
+	}
+}
+
+// BaselineDetector finds out how the server replies to requests for a virtual host that does not exist, usually
+// with its default virtual host: the hosts replying in the same way are not virtual hosts of the server
+type BaselineDetector struct {
+	httpClient scan.Doer
+	methods    []string
+	logger     *logrus.Logger
+}
+
+// Detect requests the url with a random host, that is not supposed to exist, and returns a result for each method;
+// it fails if the server doesn't reply to all the requests, as the virtual hosts can't be told apart without it
+func (d *BaselineDetector) Detect(ctx context.Context, baseURL *url.URL, workers int) ([]scan.Result, error) {
+	host, err := randomHost(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	prod := producer.NewVHostProducer(d.methods, []string{host})
+
+	// the baseline must not be filtered, all the responses are needed to compare the hosts with it
+	s := scan.NewScanner(
+		d.httpClient,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewAggregateResultFilter(),
+		d.logger,
+	)
+
+	results := make([]scan.Result, 0, len(d.methods))
+	for r := range s.Scan(ctx, baseURL, workers) {
+		results = append(results, r)
+	}
+
+	if len(results) != len(d.methods) && ctx.Err() == nil {
+		return nil, errors.Errorf("the server did not reply to the requests for the random host %s", host)
+	}
+
+	return results, nil
+}
+
+// randomHost returns a random subdomain of the host of the url, so that a server replying to all its subdomains
+// is detected; when the host is an IP a random name is returned instead
+func randomHost(u *url.URL) (string, error) {
+	b := make([]byte, randomLabelLength/2)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate random host")
+	}
+
+	label := hex.EncodeToString(b)
+
+	if net.ParseIP(u.Hostname()) != nil {
+		return label + ".invalid", nil
+	}
+
+	return label + "." + u.Hostname(), nil
+}
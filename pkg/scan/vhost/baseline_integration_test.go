@@ -0,0 +1,93 @@
+package vhost_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/vhost"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineDetectorShouldReturnTheResultsForARandomHost(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	u := test.MustParseURL(t, "http://localhost:"+test.MustParseURL(t, testServer.URL).Port()+"/")
+
+	c, err := client.NewClientFromConfig(client.Config{TimeoutInMilliseconds: 1000}, u)
+	assert.NoError(t, err)
+
+	sut := vhost.NewBaselineDetector(c, []string{http.MethodGet, http.MethodPost}, logger)
+
+	results, err := sut.Detect(context.Background(), u, 1)
+	assert.NoError(t, err)
+
+	// the baseline is not filtered, the 404 responses are returned too
+	assert.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, http.StatusNotFound, r.StatusCode)
+		assert.True(t, strings.HasSuffix(r.Target.Host, ".localhost"))
+	}
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.True(t, strings.HasSuffix(r.Host, ".localhost"))
+		assert.Equal(t, "/", r.URL.Path)
+	})
+}
+
+func TestBaselineDetectorShouldUseARandomNameForIPs(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{TimeoutInMilliseconds: 1000},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := vhost.NewBaselineDetector(c, []string{http.MethodGet}, logger)
+
+	results, err := sut.Detect(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+	assert.NoError(t, err)
+
+	assert.Len(t, results, 1)
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.True(t, strings.HasSuffix(r.Host, ".invalid"))
+	})
+}
+
+func TestBaselineDetectorShouldFailWhenTheServerDoesNotReply(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	u := test.MustParseURL(t, testServer.URL)
+
+	testServer.Close()
+
+	c, err := client.NewClientFromConfig(client.Config{TimeoutInMilliseconds: 1000}, u)
+	assert.NoError(t, err)
+
+	_, err = vhost.NewBaselineDetector(c, []string{http.MethodGet}, logger).Detect(context.Background(), u, 1)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the server did not reply to the requests for the random host")
+}
@@ -0,0 +1,137 @@
+package waf
+
+import (
+	"sync"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+const (
+	// ActionWarn only warns when a block page is detected
+	ActionWarn = "warn"
+	// ActionPause pauses the scan for a while when a block page is detected
+	ActionPause = "pause"
+	// ActionAbort stops the scan when a block page is detected
+	ActionAbort = "abort"
+)
+
+// Actions contains all the actions available when a block page is detected
+var Actions = []string{ActionWarn, ActionPause, ActionAbort}
+
+// IsValidAction returns true when the action is one of the available ones
+func IsValidAction(action string) bool {
+	for _, a := range Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Signature identifies the responses looking the same
+type Signature struct {
+	StatusCode    int
+	ContentLength int64
+}
+
+func NewDetector(window, threshold int) *Detector {
+	return &Detector{
+		window:    window,
+		threshold: threshold,
+		responses: make([]Signature, 0, window),
+		counts:    make(map[Signature]int),
+		detected:  make(map[Signature]struct{}),
+	}
+}
+
+// Detector detects a WAF (or anything alike) starting to reply to the requests with the same block page: once
+// threshold percent of the last window responses have the same status and length a block page is detected.
+// The responses making up the majority of the first window (EG the not found page of the site) are the usual
+// ones of the site, they are never detected as a block page.
+type Detector struct {
+	window    int
+	threshold int
+
+	mx sync.Mutex
+
+	// responses are the signatures of the last responses, next is where the one of the next response goes
+	// once the window is full
+	responses []Signature
+	next      int
+	counts    map[Signature]int
+
+	// usual is nil until the first window is full
+	usual map[Signature]struct{}
+
+	// detected contains the block pages already detected, they are detected again only after going below the
+	// threshold
+	detected map[Signature]struct{}
+}
+
+// Observe records the response of the result, it returns the signature of the block page and true when one is
+// detected; it is safe for concurrent use
+func (d *Detector) Observe(result scan.Result) (Signature, bool) {
+	signature := Signature{StatusCode: result.StatusCode, ContentLength: result.ContentLength}
+
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	if len(d.responses) < d.window {
+		d.responses = append(d.responses, signature)
+	} else {
+		d.counts[d.responses[d.next]]--
+		d.responses[d.next] = signature
+		d.next = (d.next + 1) % d.window
+	}
+
+	d.counts[signature]++
+
+	if len(d.responses) < d.window {
+		return Signature{}, false
+	}
+
+	if d.usual == nil {
+		d.usual = make(map[Signature]struct{})
+
+		for s, count := range d.counts {
+			if count*2 > d.window {
+				d.usual[s] = struct{}{}
+			}
+		}
+	}
+
+	for s := range d.detected {
+		if !d.reachesThreshold(s) {
+			delete(d.detected, s)
+		}
+	}
+
+	if _, ok := d.usual[signature]; ok {
+		return Signature{}, false
+	}
+
+	if _, ok := d.detected[signature]; ok || !d.reachesThreshold(signature) {
+		return Signature{}, false
+	}
+
+	d.detected[signature] = struct{}{}
+
+	return signature, true
+}
+
+// Reset forgets the responses observed (but not the usual ones), so that a block page is detected again if it is
+// still returned once the window is full again (EG after pausing the scan)
+func (d *Detector) Reset() {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	d.responses = d.responses[:0]
+	d.next = 0
+	d.counts = make(map[Signature]int)
+	d.detected = make(map[Signature]struct{})
+}
+
+func (d *Detector) reachesThreshold(signature Signature) bool {
+	return d.counts[signature]*100 >= d.threshold*d.window
+}
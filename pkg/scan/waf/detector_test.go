@@ -0,0 +1,127 @@
+package waf_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/waf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectorShouldDetectASpikeOfTheSameResponse(t *testing.T) {
+	t.Parallel()
+
+	sut := waf.NewDetector(10, 80)
+
+	for i := 0; i < 10; i++ {
+		_, detected := sut.Observe(result(http.StatusNotFound, 100))
+		assert.False(t, detected)
+	}
+
+	for i := 0; i < 7; i++ {
+		_, detected := sut.Observe(result(http.StatusForbidden, 50))
+		assert.False(t, detected)
+	}
+
+	signature, detected := sut.Observe(result(http.StatusForbidden, 50))
+	assert.True(t, detected)
+	assert.Equal(t, waf.Signature{StatusCode: http.StatusForbidden, ContentLength: 50}, signature)
+
+	// the same block page is not detected again while it is still above the threshold
+	_, detected = sut.Observe(result(http.StatusForbidden, 50))
+	assert.False(t, detected)
+}
+
+func TestDetectorShouldNotDetectTheUsualResponsesOfTheSite(t *testing.T) {
+	t.Parallel()
+
+	sut := waf.NewDetector(10, 80)
+
+	for i := 0; i < 30; i++ {
+		_, detected := sut.Observe(result(http.StatusNotFound, 100))
+		assert.False(t, detected)
+	}
+}
+
+func TestDetectorShouldNotDetectDifferentResponses(t *testing.T) {
+	t.Parallel()
+
+	sut := waf.NewDetector(10, 80)
+
+	for i := 0; i < 30; i++ {
+		_, detected := sut.Observe(result(http.StatusNotFound, int64(i)))
+		assert.False(t, detected)
+	}
+
+	for i := 0; i < 7; i++ {
+		_, detected := sut.Observe(result(http.StatusForbidden, 50))
+		assert.False(t, detected)
+	}
+}
+
+func TestDetectorShouldDetectTheBlockPageAgainAfterGoingBelowTheThreshold(t *testing.T) {
+	t.Parallel()
+
+	sut := waf.NewDetector(4, 75)
+
+	detections := 0
+
+	responses := []scan.Result{
+		result(http.StatusNotFound, 1),
+		result(http.StatusNotFound, 2),
+		result(http.StatusNotFound, 3),
+		result(http.StatusForbidden, 50),
+		result(http.StatusForbidden, 50),
+		result(http.StatusForbidden, 50), // detected
+		result(http.StatusForbidden, 50),
+		result(http.StatusNotFound, 4),
+		result(http.StatusNotFound, 5), // below the threshold
+		result(http.StatusForbidden, 50),
+		result(http.StatusForbidden, 50),
+		result(http.StatusForbidden, 50), // detected again
+	}
+
+	for _, r := range responses {
+		if _, detected := sut.Observe(r); detected {
+			detections++
+		}
+	}
+
+	assert.Equal(t, 2, detections)
+}
+
+func TestDetectorShouldDetectTheBlockPageAgainAfterBeingReset(t *testing.T) {
+	t.Parallel()
+
+	sut := waf.NewDetector(2, 100)
+
+	sut.Observe(result(http.StatusNotFound, 1))
+	sut.Observe(result(http.StatusNotFound, 2))
+	sut.Observe(result(http.StatusForbidden, 50))
+
+	_, detected := sut.Observe(result(http.StatusForbidden, 50))
+	assert.True(t, detected)
+
+	sut.Reset()
+
+	_, detected = sut.Observe(result(http.StatusForbidden, 50))
+	assert.False(t, detected)
+
+	_, detected = sut.Observe(result(http.StatusForbidden, 50))
+	assert.True(t, detected)
+}
+
+func TestIsValidAction(t *testing.T) {
+	t.Parallel()
+
+	for _, action := range waf.Actions {
+		assert.True(t, waf.IsValidAction(action))
+	}
+
+	assert.False(t, waf.IsValidAction("ignore"))
+}
+
+func result(statusCode int, contentLength int64) scan.Result {
+	return scan.Result{StatusCode: statusCode, ContentLength: contentLength}
+}
@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// queueSize is the amount of notifications that can be waiting to be sent before Notify blocks
+const queueSize = 100
+
+// Payload is the JSON body of the notifications, the name of the fields is part of the webhook schema
+// and should not be changed
+type Payload struct {
+	Target string `json:"target"`
+	URL    string `json:"url"`
+	Path   string `json:"path"`
+	Method string `json:"method"`
+	Status int    `json:"status"`
+	Length int64  `json:"length"`
+
+	// Host is the Host header of the request, set only when scanning the virtual hosts
+	Host string `json:"host,omitempty"`
+}
+
+func newPayload(target url.URL, r scan.Result) Payload {
+	return Payload{
+		Target: target.String(),
+		URL:    r.URL.String(),
+		Path:   r.URL.Path,
+		Method: r.Target.Method,
+		Status: r.StatusCode,
+		Length: r.ContentLength,
+		Host:   r.Target.Host,
+	}
+}
+
+func NewNotifier(
+	httpClient scan.Doer,
+	webhookURL string,
+	resultFilter scan.ResultFilter,
+	logger *logrus.Logger,
+) *Notifier {
+	n := &Notifier{
+		httpClient:    httpClient,
+		webhookURL:    webhookURL,
+		resultFilter:  resultFilter,
+		logger:        logger,
+		notifications: make(chan Payload, queueSize),
+		done:          make(chan struct{}),
+	}
+
+	go n.send()
+
+	return n
+}
+
+// Notifier POSTs a notification to the webhook for each result not ignored by its filter; the notifications are sent
+// in the background, one at a time, and the failures are logged without interrupting the scan
+type Notifier struct {
+	httpClient   scan.Doer
+	webhookURL   string
+	resultFilter scan.ResultFilter
+	logger       *logrus.Logger
+
+	notifications chan Payload
+	done          chan struct{}
+}
+
+// Notify queues the notification of the result found scanning target, unless the result is ignored by the filter
+func (n *Notifier) Notify(target url.URL, r scan.Result) {
+	if n.resultFilter.ShouldIgnore(r) {
+		return
+	}
+
+	n.notifications <- newPayload(target, r)
+}
+
+// Close waits for the queued notifications to be sent, Notify must not be invoked afterwards
+func (n *Notifier) Close() {
+	close(n.notifications)
+	<-n.done
+}
+
+func (n *Notifier) send() {
+	defer close(n.done)
+
+	for payload := range n.notifications {
+		post(
+			n.httpClient,
+			n.webhookURL,
+			payload,
+			n.logger.WithFields(logrus.Fields{"webhook": n.webhookURL, "url": payload.URL}),
+		)
+	}
+}
+
+// post sends the notification to the webhook as JSON, the failures are logged
+func post(httpClient scan.Doer, webhookURL string, notification interface{}, l *logrus.Entry) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		l.WithError(err).Warn("failed to encode the webhook notification")
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		l.WithError(err).Warn("failed to build the webhook notification")
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		l.WithError(err).Warn("failed to send the webhook notification")
+		return
+	}
+
+	if err := res.Body.Close(); err != nil {
+		l.WithError(err).Warn("failed to close the response body of the webhook")
+	}
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		l.WithField("status-code", res.StatusCode).Warn("the webhook rejected the notification")
+		return
+	}
+
+	l.Debug("Webhook notified")
+}
@@ -0,0 +1,119 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stefanoj3/dirstalk/pkg/scan/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierShouldPostThePayloadOfTheResultsNotIgnored(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	payloads := make(chan webhook.Payload, 10)
+
+	webhookServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			var payload webhook.Payload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+
+			payloads <- payload
+		}),
+	)
+	defer webhookServer.Close()
+
+	sut := webhook.NewNotifier(
+		http.DefaultClient,
+		webhookServer.URL,
+		filter.NewHTTPStatusToIncludeResultFilter([]int{http.StatusOK}),
+		logger,
+	)
+
+	target := test.MustParseURL(t, "http://mysite/")
+
+	sut.Notify(*target, scan.Result{
+		Target:        scan.Target{Path: "/home", Method: http.MethodGet},
+		StatusCode:    http.StatusOK,
+		URL:           *test.MustParseURL(t, "http://mysite/home"),
+		ContentLength: 10,
+	})
+	sut.Notify(*target, scan.Result{
+		Target:     scan.Target{Path: "/admin", Method: http.MethodGet},
+		StatusCode: http.StatusForbidden,
+		URL:        *test.MustParseURL(t, "http://mysite/admin"),
+	})
+	sut.Close()
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	})
+
+	expectedPayload := webhook.Payload{
+		Target: "http://mysite/",
+		URL:    "http://mysite/home",
+		Path:   "/home",
+		Method: http.MethodGet,
+		Status: http.StatusOK,
+		Length: 10,
+	}
+	assert.Equal(t, expectedPayload, <-payloads)
+}
+
+func TestNotifierShouldLogTheFailuresWithoutStopping(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	webhookServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer webhookServer.Close()
+
+	sut := webhook.NewNotifier(http.DefaultClient, webhookServer.URL, filter.NewAggregateResultFilter(), logger)
+
+	target := test.MustParseURL(t, "http://mysite/")
+
+	for _, path := range []string{"/home", "/about"} {
+		sut.Notify(*target, scan.Result{
+			Target:     scan.Target{Path: path, Method: http.MethodGet},
+			StatusCode: http.StatusOK,
+			URL:        *test.MustParseURL(t, "http://mysite"+path),
+		})
+	}
+	sut.Close()
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	assert.Contains(t, loggerBuffer.String(), "the webhook rejected the notification")
+	assert.Contains(t, loggerBuffer.String(), "status-code=500")
+}
+
+func TestNotifierShouldLogTheWebhookBeingUnreachable(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	webhookServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	webhookServer.Close()
+
+	sut := webhook.NewNotifier(http.DefaultClient, webhookServer.URL, filter.NewAggregateResultFilter(), logger)
+
+	sut.Notify(*test.MustParseURL(t, "http://mysite/"), scan.Result{
+		Target:     scan.Target{Path: "/home", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/home"),
+	})
+	sut.Close()
+
+	assert.Contains(t, loggerBuffer.String(), "failed to send the webhook notification")
+}
@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// maxSlackBatchSize is the maximum amount of results in a message, it keeps the message within the limit slack
+// imposes on the amount of blocks (50)
+const maxSlackBatchSize = 20
+
+// maxSlackSectionLength is the maximum length of the text of a section block accepted by slack, the results of a
+// target not fitting in a block are listed in the following ones
+const maxSlackSectionLength = 3000
+
+// SlackMessage is the body of the messages sent to the slack incoming webhooks, Text is shown in the notifications
+// while Blocks is the content of the message
+type SlackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+type SlackBlock struct {
+	Type string    `json:"type"`
+	Text SlackText `json:"text"`
+}
+
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func NewSlackNotifier(
+	httpClient scan.Doer,
+	webhookURL string,
+	resultFilter scan.ResultFilter,
+	logger *logrus.Logger,
+	interval time.Duration,
+) *SlackNotifier {
+	n := &SlackNotifier{
+		httpClient:    httpClient,
+		webhookURL:    webhookURL,
+		resultFilter:  resultFilter,
+		logger:        logger,
+		interval:      interval,
+		notifications: make(chan Payload, queueSize),
+		done:          make(chan struct{}),
+	}
+
+	go n.send()
+
+	return n
+}
+
+// SlackNotifier posts the results not ignored by its filter to a slack incoming webhook; to avoid hitting the rate
+// limits the results are batched in a single message every interval, or as soon as the batch is full
+type SlackNotifier struct {
+	httpClient   scan.Doer
+	webhookURL   string
+	resultFilter scan.ResultFilter
+	logger       *logrus.Logger
+	interval     time.Duration
+
+	notifications chan Payload
+	done          chan struct{}
+}
+
+// Notify queues the result found scanning target for the next message, unless the result is ignored by the filter
+func (n *SlackNotifier) Notify(target url.URL, r scan.Result) {
+	if n.resultFilter.ShouldIgnore(r) {
+		return
+	}
+
+	n.notifications <- newPayload(target, r)
+}
+
+// Close sends the results still waiting for a message, Notify must not be invoked afterwards
+func (n *SlackNotifier) Close() {
+	close(n.notifications)
+	<-n.done
+}
+
+func (n *SlackNotifier) send() {
+	defer close(n.done)
+
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	batch := make([]Payload, 0, maxSlackBatchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		post(
+			n.httpClient,
+			n.webhookURL,
+			NewSlackMessage(batch),
+			// the url of the slack webhooks embeds its secret, so it is not logged
+			n.logger.WithField("results", len(batch)),
+		)
+
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case payload, ok := <-n.notifications:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, payload)
+			if len(batch) == maxSlackBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// NewSlackMessage builds the message listing the results, grouped by the target they were found scanning
+func NewSlackMessage(payloads []Payload) SlackMessage {
+	text := fmt.Sprintf("dirstalk found %d result(s)", len(payloads))
+
+	// the targets are kept in the order their first result was found
+	targets := make([]string, 0)
+	lines := make(map[string][]string)
+
+	for _, p := range payloads {
+		if _, found := lines[p.Target]; !found {
+			targets = append(targets, p.Target)
+		}
+
+		line := fmt.Sprintf("• %s [%d] [%s]", slackLink(p.URL, p.Path), p.Status, p.Method)
+		if p.Host != "" {
+			line += fmt.Sprintf(" [Host: %s]", slackEscape(p.Host))
+		}
+
+		lines[p.Target] = append(lines[p.Target], line)
+	}
+
+	blocks := []SlackBlock{
+		{Type: "section", Text: SlackText{Type: "mrkdwn", Text: "*" + text + "*"}},
+	}
+
+	for _, target := range targets {
+		for _, section := range slackSections("Target "+slackLink(target, target), lines[target]) {
+			blocks = append(blocks, SlackBlock{Type: "section", Text: SlackText{Type: "mrkdwn", Text: section}})
+		}
+	}
+
+	return SlackMessage{Text: text, Blocks: blocks}
+}
+
+// slackSections joins the header and the lines in as few texts as possible, each one within maxSlackSectionLength;
+// a line longer than that is truncated
+func slackSections(header string, lines []string) []string {
+	sections := make([]string, 0, 1)
+	section := truncateText(header, maxSlackSectionLength)
+
+	for _, line := range lines {
+		line = truncateText(line, maxSlackSectionLength)
+
+		if utf8.RuneCountInString(section)+1+utf8.RuneCountInString(line) > maxSlackSectionLength {
+			sections = append(sections, section)
+			section = line
+
+			continue
+		}
+
+		section += "\n" + line
+	}
+
+	return append(sections, section)
+}
+
+// truncateText cuts s to at most maxLength characters, marking with an ellipsis that it has been truncated
+func truncateText(s string, maxLength int) string {
+	if utf8.RuneCountInString(s) <= maxLength {
+		return s
+	}
+
+	return string([]rune(s)[:maxLength-1]) + "…"
+}
+
+// slackLink formats a clickable link to u, the pipe is percent encoded as it separates the url from the text
+func slackLink(u, text string) string {
+	return fmt.Sprintf("<%s|%s>", slackEscape(strings.Replace(u, "|", "%7C", -1)), slackEscape(text))
+}
+
+// slackEscape escapes the characters having a special meaning in the slack formatting
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,168 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stefanoj3/dirstalk/pkg/scan/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackNotifierShouldBatchTheResultsInOneMessage(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	messages := make(chan webhook.SlackMessage, 10)
+
+	webhookServer, serverAssertion := test.NewServerWithAssertion(newSlackHandler(t, messages))
+	defer webhookServer.Close()
+
+	sut := webhook.NewSlackNotifier(
+		http.DefaultClient,
+		webhookServer.URL,
+		filter.NewHTTPStatusToIncludeResultFilter([]int{http.StatusOK}),
+		logger,
+		time.Hour,
+	)
+
+	target := test.MustParseURL(t, "http://mysite/")
+
+	for _, path := range []string{"/home", "/about"} {
+		sut.Notify(*target, scan.Result{
+			Target:     scan.Target{Path: path, Method: http.MethodGet},
+			StatusCode: http.StatusOK,
+			URL:        *test.MustParseURL(t, "http://mysite"+path),
+		})
+	}
+	sut.Notify(*target, scan.Result{
+		Target:     scan.Target{Path: "/admin", Method: http.MethodGet},
+		StatusCode: http.StatusForbidden,
+		URL:        *test.MustParseURL(t, "http://mysite/admin"),
+	})
+	sut.Close()
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	})
+
+	message := <-messages
+	assert.Equal(t, "dirstalk found 2 result(s)", message.Text)
+	assert.Len(t, message.Blocks, 2)
+	assert.Contains(t, message.Blocks[1].Text.Text, "<http://mysite/home|/home>")
+	assert.Contains(t, message.Blocks[1].Text.Text, "<http://mysite/about|/about>")
+	assert.NotContains(t, message.Blocks[1].Text.Text, "/admin")
+}
+
+func TestSlackNotifierShouldSendAMessageEveryInterval(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	messages := make(chan webhook.SlackMessage, 10)
+
+	webhookServer, _ := test.NewServerWithAssertion(newSlackHandler(t, messages))
+	defer webhookServer.Close()
+
+	sut := webhook.NewSlackNotifier(
+		http.DefaultClient,
+		webhookServer.URL,
+		filter.NewAggregateResultFilter(),
+		logger,
+		10*time.Millisecond,
+	)
+	defer sut.Close()
+
+	sut.Notify(*test.MustParseURL(t, "http://mysite/"), scan.Result{
+		Target:     scan.Target{Path: "/home", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/home"),
+	})
+
+	select {
+	case message := <-messages:
+		assert.Equal(t, "dirstalk found 1 result(s)", message.Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the message was not sent before closing the notifier")
+	}
+}
+
+func TestSlackNotifierShouldSplitTheResultsExceedingTheBatchSize(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	messages := make(chan webhook.SlackMessage, 10)
+
+	webhookServer, serverAssertion := test.NewServerWithAssertion(newSlackHandler(t, messages))
+	defer webhookServer.Close()
+
+	sut := webhook.NewSlackNotifier(
+		http.DefaultClient,
+		webhookServer.URL,
+		filter.NewAggregateResultFilter(),
+		logger,
+		time.Hour,
+	)
+
+	target := test.MustParseURL(t, "http://mysite/")
+
+	for i := 0; i < 25; i++ {
+		path := "/" + strconv.Itoa(i)
+
+		sut.Notify(*target, scan.Result{
+			Target:     scan.Target{Path: path, Method: http.MethodGet},
+			StatusCode: http.StatusOK,
+			URL:        *test.MustParseURL(t, "http://mysite"+path),
+		})
+	}
+	sut.Close()
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	assert.Equal(t, "dirstalk found 20 result(s)", (<-messages).Text)
+	assert.Equal(t, "dirstalk found 5 result(s)", (<-messages).Text)
+}
+
+func TestSlackNotifierShouldLogTheRejectedMessages(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	webhookServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}),
+	)
+	defer webhookServer.Close()
+
+	sut := webhook.NewSlackNotifier(
+		http.DefaultClient,
+		webhookServer.URL,
+		filter.NewAggregateResultFilter(),
+		logger,
+		time.Hour,
+	)
+
+	sut.Notify(*test.MustParseURL(t, "http://mysite/"), scan.Result{
+		Target:     scan.Target{Path: "/home", Method: http.MethodGet},
+		StatusCode: http.StatusOK,
+		URL:        *test.MustParseURL(t, "http://mysite/home"),
+	})
+	sut.Close()
+
+	assert.Contains(t, loggerBuffer.String(), "the webhook rejected the notification")
+	assert.Contains(t, loggerBuffer.String(), "status-code=429")
+}
+
+func newSlackHandler(t *testing.T, messages chan<- webhook.SlackMessage) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var message webhook.SlackMessage
+		assert.NoError(t, json.Unmarshal(body, &message))
+
+		messages <- message
+	})
+}
@@ -0,0 +1,105 @@
+package webhook_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSlackMessageShouldGroupTheResultsByTarget(t *testing.T) {
+	message := webhook.NewSlackMessage([]webhook.Payload{
+		{Target: "http://mysite/", URL: "http://mysite/home", Path: "/home", Method: http.MethodGet, Status: 200},
+		{Target: "http://other/", URL: "http://other/", Path: "/", Method: http.MethodGet, Status: 200, Host: "dev.other"},
+		{Target: "http://mysite/", URL: "http://mysite/admin", Path: "/admin", Method: http.MethodPost, Status: 403},
+	})
+
+	expected := webhook.SlackMessage{
+		Text: "dirstalk found 3 result(s)",
+		Blocks: []webhook.SlackBlock{
+			{Type: "section", Text: webhook.SlackText{Type: "mrkdwn", Text: "*dirstalk found 3 result(s)*"}},
+			{
+				Type: "section",
+				Text: webhook.SlackText{
+					Type: "mrkdwn",
+					Text: "Target <http://mysite/|http://mysite/>\n" +
+						"• <http://mysite/home|/home> [200] [GET]\n" +
+						"• <http://mysite/admin|/admin> [403] [POST]",
+				},
+			},
+			{
+				Type: "section",
+				Text: webhook.SlackText{
+					Type: "mrkdwn",
+					Text: "Target <http://other/|http://other/>\n" +
+						"• <http://other/|/> [200] [GET] [Host: dev.other]",
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, message)
+}
+
+func TestNewSlackMessageShouldEscapeTheFormattingCharacters(t *testing.T) {
+	message := webhook.NewSlackMessage([]webhook.Payload{
+		{
+			Target: "http://mysite/",
+			URL:    "http://mysite/a|b?x=1&y=<2>",
+			Path:   "/a|b",
+			Method: http.MethodGet,
+			Status: 200,
+		},
+	})
+
+	assert.Len(t, message.Blocks, 2)
+	assert.Equal(
+		t,
+		"Target <http://mysite/|http://mysite/>\n• <http://mysite/a%7Cb?x=1&amp;y=&lt;2&gt;|/a|b> [200] [GET]",
+		message.Blocks[1].Text.Text,
+	)
+}
+
+func TestNewSlackMessageShouldSplitTheResultsOfATargetExceedingTheLengthOfABlock(t *testing.T) {
+	payloads := make([]webhook.Payload, 0, 21)
+
+	for i := 0; i < 20; i++ {
+		path := "/" + strings.Repeat(string(rune('a'+i)), 200)
+		payloads = append(
+			payloads,
+			webhook.Payload{Target: "http://mysite/", URL: "http://mysite" + path, Path: path, Method: "GET", Status: 200},
+		)
+	}
+
+	longPath := "/" + strings.Repeat("z", 4000)
+	payloads = append(
+		payloads,
+		webhook.Payload{Target: "http://mysite/", URL: "http://mysite" + longPath, Path: longPath, Status: 200},
+	)
+
+	message := webhook.NewSlackMessage(payloads)
+
+	assert.True(t, len(message.Blocks) > 2)
+	assert.True(t, strings.HasPrefix(message.Blocks[1].Text.Text, "Target <http://mysite/|http://mysite/>\n"))
+
+	texts := make([]string, 0, len(message.Blocks)-1)
+
+	for _, block := range message.Blocks[1:] {
+		assert.True(t, utf8.RuneCountInString(block.Text.Text) <= 3000, block.Text.Text)
+
+		texts = append(texts, block.Text.Text)
+	}
+
+	// every result is listed once, in order
+	lines := strings.Split(strings.Join(texts, "\n"), "\n")[1:]
+	assert.Len(t, lines, len(payloads))
+
+	for i, p := range payloads[:20] {
+		assert.Equal(t, "• <"+p.URL+"|"+p.Path+"> [200] [GET]", lines[i])
+	}
+
+	assert.True(t, strings.HasSuffix(lines[20], "…"))
+}
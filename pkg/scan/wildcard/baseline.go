@@ -0,0 +1,147 @@
+package wildcard
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+)
+
+func NewBaselineResultFilter(
+	ctx context.Context,
+	httpClient scan.Doer,
+	diffThreshold int64,
+	logger *logrus.Logger,
+) *BaselineResultFilter {
+	return &BaselineResultFilter{
+		ctx:           ctx,
+		httpClient:    httpClient,
+		diffThreshold: diffThreshold,
+		logger:        logger,
+		baselines:     make(map[baselineKey]*baseline),
+	}
+}
+
+// BaselineResultFilter ignores the results that don't differ meaningfully from the responses the server gives for
+// paths that do not exist in the same directory; the baseline of each directory is established, requesting a few
+// random paths in it, the first time a result is found there. It is safe for concurrent use.
+type BaselineResultFilter struct {
+	ctx           context.Context
+	httpClient    scan.Doer
+	diffThreshold int64
+	logger        *logrus.Logger
+
+	mx        sync.Mutex
+	baselines map[baselineKey]*baseline
+}
+
+type baselineKey struct {
+	directory string
+	method    string
+}
+
+type baseline struct {
+	once    sync.Once
+	results []scan.Result
+}
+
+// ShouldIgnore ignores the result when a response of the baseline of its directory has the same status and either
+// the same body or a length differing at most by the threshold
+func (f *BaselineResultFilter) ShouldIgnore(result scan.Result) bool {
+	for _, b := range f.baseline(result) {
+		if b.StatusCode != result.StatusCode {
+			continue
+		}
+
+		if b.BodyHash == result.BodyHash || abs(b.ContentLength-result.ContentLength) <= f.diffThreshold {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *BaselineResultFilter) baseline(result scan.Result) []scan.Result {
+	directory := directoryURL(result.URL)
+	key := baselineKey{directory: directory.String(), method: result.Target.Method}
+
+	f.mx.Lock()
+	b, found := f.baselines[key]
+	if !found {
+		b = &baseline{}
+		f.baselines[key] = b
+	}
+	f.mx.Unlock()
+
+	// the results found in the same directory wait for its baseline to be established
+	b.once.Do(func() {
+		b.results = f.establish(directory, result.Target.Method)
+	})
+
+	return b.results
+}
+
+func (f *BaselineResultFilter) establish(directory url.URL, method string) []scan.Result {
+	l := f.logger.WithFields(logrus.Fields{"directory": directory.String(), "method": method})
+
+	paths, err := randomPaths()
+	if err != nil {
+		l.WithError(err).Warn("Failed to establish the baseline, the results will not be compared with it")
+		return nil
+	}
+
+	// the baseline is the response to the missing pages themselves, so their redirects are not followed
+	prod := producer.NewDictionaryProducer([]string{method}, paths, 0)
+
+	// the baseline must not be filtered, all the responses are needed to compare the results with it
+	s := scan.NewScanner(
+		f.httpClient,
+		prod,
+		producer.NewReProducer(prod),
+		filter.NewAggregateResultFilter(),
+		f.logger,
+	)
+
+	results := make([]scan.Result, 0, len(paths))
+	for r := range s.Scan(f.ctx, &directory, len(paths)) {
+		results = append(results, r)
+	}
+
+	for _, r := range results {
+		l.WithFields(logrus.Fields{
+			"status":         r.StatusCode,
+			"content-length": r.ContentLength,
+		}).Debug("Baseline response established")
+	}
+
+	return results
+}
+
+// directoryURL returns the url of the directory containing the resource u points to (EG /a/ for both /a/b and /a/b/)
+func directoryURL(u url.URL) url.URL {
+	p := strings.TrimSuffix(u.Path, "/")
+
+	u.Path = p[:strings.LastIndex(p, "/")+1]
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
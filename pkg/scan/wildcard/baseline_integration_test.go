@@ -0,0 +1,128 @@
+package wildcard_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/wildcard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineResultFilterShouldIgnoreTheResultsSimilarToTheBaseline(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("not found: " + r.URL.Path)) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := wildcard.NewBaselineResultFilter(context.Background(), c, 20, logger)
+
+	// the random paths are 16 characters long, the baseline bodies are 28 bytes long
+	testCases := []struct {
+		result         scan.Result
+		expectedIgnore bool
+	}{
+		{
+			result: newResult(t, testServer.URL+"/admin", http.StatusOK, 17, "hash"),
+			// within the threshold
+			expectedIgnore: true,
+		},
+		{
+			result:         newResult(t, testServer.URL+"/admin", http.StatusOK, 1234, "hash"),
+			expectedIgnore: false,
+		},
+		{
+			result:         newResult(t, testServer.URL+"/admin", http.StatusForbidden, 17, "hash"),
+			expectedIgnore: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+		t.Run(tc.result.URL.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expectedIgnore, sut.ShouldIgnore(tc.result))
+		})
+	}
+
+	// the baseline of the directory is established only once
+	assert.Equal(t, 3, serverAssertion.Len())
+}
+
+func TestBaselineResultFilterShouldEstablishABaselinePerDirectory(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := wildcard.NewBaselineResultFilter(context.Background(), c, 0, logger)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.True(t, sut.ShouldIgnore(newResult(t, testServer.URL+"/api/users", http.StatusUnauthorized, 0, "")))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.False(t, sut.ShouldIgnore(newResult(t, testServer.URL+"/internal", http.StatusUnauthorized, 0, "")))
+	assert.True(t, sut.ShouldIgnore(newResult(t, testServer.URL+"/api/v1/", http.StatusUnauthorized, 0, "")))
+
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	directories := make(map[string]int)
+	serverAssertion.Range(func(_ int, r http.Request) {
+		directories[r.URL.Path[:strings.LastIndex(strings.TrimSuffix(r.URL.Path, "/"), "/")+1]]++
+	})
+	assert.Equal(t, map[string]int{"/api/": 3, "/": 3}, directories)
+}
+
+func newResult(t *testing.T, rawURL string, statusCode int, contentLength int64, bodyHash string) scan.Result {
+	u := test.MustParseURL(t, rawURL)
+
+	return scan.Result{
+		Target:        scan.Target{Path: u.Path, Method: http.MethodGet},
+		StatusCode:    statusCode,
+		URL:           *u,
+		ContentLength: contentLength,
+		BodyHash:      bodyHash,
+	}
+}
@@ -0,0 +1,81 @@
+package wildcard
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/producer"
+)
+
+const randomPathLength = 16
+
+func NewDetector(
+	httpClient scan.Doer,
+	methods []string,
+	resultFilter scan.ResultFilter,
+	logger *logrus.Logger,
+) *Detector {
+	return &Detector{
+		httpClient:   httpClient,
+		methods:      methods,
+		resultFilter: resultFilter,
+		logger:       logger,
+	}
+}
+
+// Detector finds out how the server replies to requests for paths that do not exist; some servers
+// reply 200 (or redirect) to any request, making every path requested look like a result
+type Detector struct {
+	httpClient   scan.Doer
+	methods      []string
+	resultFilter scan.ResultFilter
+	logger       *logrus.Logger
+}
+
+// Detect requests a few random paths, that are not supposed to exist, and returns the results obtained;
+// no result is returned when the server replies in a way that is already ignored by the result filter (EG 404)
+func (d *Detector) Detect(ctx context.Context, baseURL *url.URL, workers int) ([]scan.Result, error) {
+	paths, err := randomPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	// the random paths don't exist, scanning deeper or following their redirects would only find more of them
+	prod := producer.NewDictionaryProducer(d.methods, paths, 0)
+
+	s := scan.NewScanner(
+		d.httpClient,
+		prod,
+		producer.NewReProducer(prod),
+		d.resultFilter,
+		d.logger,
+	)
+
+	results := make([]scan.Result, 0, len(paths)*len(d.methods))
+	for r := range s.Scan(ctx, baseURL, workers) {
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// randomPaths builds both a file-like and a folder-like path, as servers may reply differently to them
+func randomPaths() ([]string, error) {
+	paths := make([]string, 0, 3)
+
+	for _, suffix := range []string{"", "", "/"} {
+		b := make([]byte, randomPathLength/2)
+		if _, err := rand.Read(b); err != nil {
+			return nil, errors.Wrap(err, "failed to generate random path")
+		}
+
+		paths = append(paths, hex.EncodeToString(b)+suffix)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,89 @@
+package wildcard_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stefanoj3/dirstalk/pkg/scan/wildcard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectorShouldReturnTheResultsForNonExistingPaths(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("welcome")) //nolint:errcheck
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := wildcard.NewDetector(
+		c,
+		[]string{http.MethodGet, http.MethodPost},
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	results, err := sut.Detect(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+	assert.NoError(t, err)
+
+	assert.Len(t, results, 6)
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	for _, r := range results {
+		assert.Equal(t, http.StatusOK, r.StatusCode)
+		assert.Equal(t, int64(7), r.ContentLength)
+		assert.NotEmpty(t, r.BodyHash)
+	}
+
+	paths := make(map[string]struct{})
+	serverAssertion.Range(func(_ int, r http.Request) {
+		paths[r.URL.Path] = struct{}{}
+	})
+	assert.Len(t, paths, 3)
+}
+
+func TestDetectorShouldReturnNoResultsWhenTheServerRepliesNotFound(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		client.Config{
+			TimeoutInMilliseconds: 1000,
+		},
+		test.MustParseURL(t, testServer.URL),
+	)
+	assert.NoError(t, err)
+
+	sut := wildcard.NewDetector(
+		c,
+		[]string{http.MethodGet},
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}),
+		logger,
+	)
+
+	results, err := sut.Detect(context.Background(), test.MustParseURL(t, testServer.URL), 1)
+	assert.NoError(t, err)
+
+	assert.Len(t, results, 0)
+	assert.Equal(t, 3, serverAssertion.Len())
+}